@@ -0,0 +1,1485 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: api/question/v1/question.proto
+
+package questionv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type QuestionStatus int32
+
+const (
+	QuestionStatus_QUESTION_STATUS_UNSPECIFIED QuestionStatus = 0
+	QuestionStatus_QUESTION_STATUS_OPEN        QuestionStatus = 1
+	QuestionStatus_QUESTION_STATUS_CLOSED      QuestionStatus = 2
+	QuestionStatus_QUESTION_STATUS_LOCKED      QuestionStatus = 3
+	QuestionStatus_QUESTION_STATUS_ARCHIVED    QuestionStatus = 4
+)
+
+// Enum value maps for QuestionStatus.
+var (
+	QuestionStatus_name = map[int32]string{
+		0: "QUESTION_STATUS_UNSPECIFIED",
+		1: "QUESTION_STATUS_OPEN",
+		2: "QUESTION_STATUS_CLOSED",
+		3: "QUESTION_STATUS_LOCKED",
+		4: "QUESTION_STATUS_ARCHIVED",
+	}
+	QuestionStatus_value = map[string]int32{
+		"QUESTION_STATUS_UNSPECIFIED": 0,
+		"QUESTION_STATUS_OPEN":        1,
+		"QUESTION_STATUS_CLOSED":      2,
+		"QUESTION_STATUS_LOCKED":      3,
+		"QUESTION_STATUS_ARCHIVED":    4,
+	}
+)
+
+func (x QuestionStatus) Enum() *QuestionStatus {
+	p := new(QuestionStatus)
+	*p = x
+	return p
+}
+
+func (x QuestionStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QuestionStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_question_v1_question_proto_enumTypes[0].Descriptor()
+}
+
+func (QuestionStatus) Type() protoreflect.EnumType {
+	return &file_api_question_v1_question_proto_enumTypes[0]
+}
+
+func (x QuestionStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QuestionStatus.Descriptor instead.
+func (QuestionStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{0}
+}
+
+type QuestionSort int32
+
+const (
+	// Сначала новые.
+	QuestionSort_QUESTION_SORT_UNSPECIFIED QuestionSort = 0
+	QuestionSort_QUESTION_SORT_NEWEST      QuestionSort = 1
+	QuestionSort_QUESTION_SORT_OLDEST      QuestionSort = 2
+	// Сначала вопросы с наибольшим числом ответов.
+	QuestionSort_QUESTION_SORT_ANSWERS QuestionSort = 3
+	// Сначала вопросы с самым свежим ответом.
+	QuestionSort_QUESTION_SORT_ACTIVITY QuestionSort = 4
+	// Сначала вопросы с наибольшим рейтингом.
+	QuestionSort_QUESTION_SORT_VOTES QuestionSort = 5
+)
+
+// Enum value maps for QuestionSort.
+var (
+	QuestionSort_name = map[int32]string{
+		0: "QUESTION_SORT_UNSPECIFIED",
+		1: "QUESTION_SORT_NEWEST",
+		2: "QUESTION_SORT_OLDEST",
+		3: "QUESTION_SORT_ANSWERS",
+		4: "QUESTION_SORT_ACTIVITY",
+		5: "QUESTION_SORT_VOTES",
+	}
+	QuestionSort_value = map[string]int32{
+		"QUESTION_SORT_UNSPECIFIED": 0,
+		"QUESTION_SORT_NEWEST":      1,
+		"QUESTION_SORT_OLDEST":      2,
+		"QUESTION_SORT_ANSWERS":     3,
+		"QUESTION_SORT_ACTIVITY":    4,
+		"QUESTION_SORT_VOTES":       5,
+	}
+)
+
+func (x QuestionSort) Enum() *QuestionSort {
+	p := new(QuestionSort)
+	*p = x
+	return p
+}
+
+func (x QuestionSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QuestionSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_question_v1_question_proto_enumTypes[1].Descriptor()
+}
+
+func (QuestionSort) Type() protoreflect.EnumType {
+	return &file_api_question_v1_question_proto_enumTypes[1]
+}
+
+func (x QuestionSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QuestionSort.Descriptor instead.
+func (QuestionSort) EnumDescriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{1}
+}
+
+type Question struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Пусто для анонимных вопросов.
+	AuthorId string `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Title    string `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	// Исходный текст в Markdown.
+	Body string `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	// Очищенный HTML, построенный из body.
+	BodyHtml    string                 `protobuf:"bytes,5,opt,name=body_html,json=bodyHtml,proto3" json:"body_html,omitempty"`
+	Status      QuestionStatus         `protobuf:"varint,6,opt,name=status,proto3,enum=question.v1.QuestionStatus" json:"status,omitempty"`
+	CloseReason string                 `protobuf:"bytes,7,opt,name=close_reason,json=closeReason,proto3" json:"close_reason,omitempty"`
+	ClosedBy    string                 `protobuf:"bytes,8,opt,name=closed_by,json=closedBy,proto3" json:"closed_by,omitempty"`
+	ClosedAt    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=closed_at,json=closedAt,proto3" json:"closed_at,omitempty"`
+	// ID исходного вопроса, если этот закрыт как его дубликат.
+	DuplicateOf      *uint64 `protobuf:"varint,10,opt,name=duplicate_of,json=duplicateOf,proto3,oneof" json:"duplicate_of,omitempty"`
+	AcceptedAnswerId *uint64 `protobuf:"varint,11,opt,name=accepted_answer_id,json=acceptedAnswerId,proto3,oneof" json:"accepted_answer_id,omitempty"`
+	Score            int64   `protobuf:"varint,12,opt,name=score,proto3" json:"score,omitempty"`
+	// Увеличивается при каждом изменении вопроса; передайте ее в Update и Delete, чтобы не потерять чужие изменения.
+	Version        int64                  `protobuf:"varint,13,opt,name=version,proto3" json:"version,omitempty"`
+	AnswerCount    int64                  `protobuf:"varint,14,opt,name=answer_count,json=answerCount,proto3" json:"answer_count,omitempty"`
+	CommentCount   int64                  `protobuf:"varint,15,opt,name=comment_count,json=commentCount,proto3" json:"comment_count,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	LastActivityAt *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=last_activity_at,json=lastActivityAt,proto3" json:"last_activity_at,omitempty"`
+	// Ответы в порядке создания; заполняется только в GetQuestion.
+	Answers []*Answer `protobuf:"bytes,19,rep,name=answers,proto3" json:"answers,omitempty"`
+	// Теги в нижнем регистре в порядке, в котором их указал автор.
+	Tags          []string `protobuf:"bytes,20,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Question) Reset() {
+	*x = Question{}
+	mi := &file_api_question_v1_question_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Question) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Question) ProtoMessage() {}
+
+func (x *Question) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Question.ProtoReflect.Descriptor instead.
+func (*Question) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Question) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Question) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Question) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Question) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Question) GetBodyHtml() string {
+	if x != nil {
+		return x.BodyHtml
+	}
+	return ""
+}
+
+func (x *Question) GetStatus() QuestionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return QuestionStatus_QUESTION_STATUS_UNSPECIFIED
+}
+
+func (x *Question) GetCloseReason() string {
+	if x != nil {
+		return x.CloseReason
+	}
+	return ""
+}
+
+func (x *Question) GetClosedBy() string {
+	if x != nil {
+		return x.ClosedBy
+	}
+	return ""
+}
+
+func (x *Question) GetClosedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return nil
+}
+
+func (x *Question) GetDuplicateOf() uint64 {
+	if x != nil && x.DuplicateOf != nil {
+		return *x.DuplicateOf
+	}
+	return 0
+}
+
+func (x *Question) GetAcceptedAnswerId() uint64 {
+	if x != nil && x.AcceptedAnswerId != nil {
+		return *x.AcceptedAnswerId
+	}
+	return 0
+}
+
+func (x *Question) GetScore() int64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Question) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Question) GetAnswerCount() int64 {
+	if x != nil {
+		return x.AnswerCount
+	}
+	return 0
+}
+
+func (x *Question) GetCommentCount() int64 {
+	if x != nil {
+		return x.CommentCount
+	}
+	return 0
+}
+
+func (x *Question) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Question) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Question) GetLastActivityAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActivityAt
+	}
+	return nil
+}
+
+func (x *Question) GetAnswers() []*Answer {
+	if x != nil {
+		return x.Answers
+	}
+	return nil
+}
+
+func (x *Question) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type Answer struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	QuestionId uint64                 `protobuf:"varint,2,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	AuthorId   string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// Исходный текст в Markdown.
+	Text string `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	// Очищенный HTML, построенный из text.
+	TextHtml      string                 `protobuf:"bytes,5,opt,name=text_html,json=textHtml,proto3" json:"text_html,omitempty"`
+	Version       int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	CommentCount  int64                  `protobuf:"varint,7,opt,name=comment_count,json=commentCount,proto3" json:"comment_count,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Answer) Reset() {
+	*x = Answer{}
+	mi := &file_api_question_v1_question_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Answer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Answer) ProtoMessage() {}
+
+func (x *Answer) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Answer.ProtoReflect.Descriptor instead.
+func (*Answer) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Answer) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Answer) GetQuestionId() uint64 {
+	if x != nil {
+		return x.QuestionId
+	}
+	return 0
+}
+
+func (x *Answer) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *Answer) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Answer) GetTextHtml() string {
+	if x != nil {
+		return x.TextHtml
+	}
+	return ""
+}
+
+func (x *Answer) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Answer) GetCommentCount() int64 {
+	if x != nil {
+		return x.CommentCount
+	}
+	return 0
+}
+
+func (x *Answer) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Answer) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type SimilarQuestion struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Question *Question              `protobuf:"bytes,1,opt,name=question,proto3" json:"question,omitempty"`
+	// Похожесть заголовков от 0 до 1.
+	Similarity    float64 `protobuf:"fixed64,2,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimilarQuestion) Reset() {
+	*x = SimilarQuestion{}
+	mi := &file_api_question_v1_question_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimilarQuestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarQuestion) ProtoMessage() {}
+
+func (x *SimilarQuestion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarQuestion.ProtoReflect.Descriptor instead.
+func (*SimilarQuestion) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SimilarQuestion) GetQuestion() *Question {
+	if x != nil {
+		return x.Question
+	}
+	return nil
+}
+
+func (x *SimilarQuestion) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+type CreateQuestionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Title string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Body  string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	// Отклонить вопрос, если есть похожие.
+	Strict bool `protobuf:"varint,3,opt,name=strict,proto3" json:"strict,omitempty"`
+	// До 5 тегов: строчные латинские буквы, цифры и символы . + # -, до 35 символов.
+	Tags          []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateQuestionRequest) Reset() {
+	*x = CreateQuestionRequest{}
+	mi := &file_api_question_v1_question_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateQuestionRequest) ProtoMessage() {}
+
+func (x *CreateQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateQuestionRequest.ProtoReflect.Descriptor instead.
+func (*CreateQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateQuestionRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateQuestionRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CreateQuestionRequest) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+func (x *CreateQuestionRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type CreateQuestionResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Question *Question              `protobuf:"bytes,1,opt,name=question,proto3" json:"question,omitempty"`
+	// Существующие вопросы с похожим заголовком.
+	PossibleDuplicates []*SimilarQuestion `protobuf:"bytes,2,rep,name=possible_duplicates,json=possibleDuplicates,proto3" json:"possible_duplicates,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CreateQuestionResponse) Reset() {
+	*x = CreateQuestionResponse{}
+	mi := &file_api_question_v1_question_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateQuestionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateQuestionResponse) ProtoMessage() {}
+
+func (x *CreateQuestionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateQuestionResponse.ProtoReflect.Descriptor instead.
+func (*CreateQuestionResponse) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateQuestionResponse) GetQuestion() *Question {
+	if x != nil {
+		return x.Question
+	}
+	return nil
+}
+
+func (x *CreateQuestionResponse) GetPossibleDuplicates() []*SimilarQuestion {
+	if x != nil {
+		return x.PossibleDuplicates
+	}
+	return nil
+}
+
+type GetQuestionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuestionRequest) Reset() {
+	*x = GetQuestionRequest{}
+	mi := &file_api_question_v1_question_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuestionRequest) ProtoMessage() {}
+
+func (x *GetQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuestionRequest.ProtoReflect.Descriptor instead.
+func (*GetQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetQuestionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListQuestionsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Statuses []QuestionStatus       `protobuf:"varint,1,rep,packed,name=statuses,proto3,enum=question.v1.QuestionStatus" json:"statuses,omitempty"`
+	// Создан не раньше этого времени.
+	CreatedAfter *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	// Создан раньше этого времени.
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	AuthorId      string                 `protobuf:"bytes,4,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	HasAnswers    *bool                  `protobuf:"varint,5,opt,name=has_answers,json=hasAnswers,proto3,oneof" json:"has_answers,omitempty"`
+	MinAnswers    *int64                 `protobuf:"varint,6,opt,name=min_answers,json=minAnswers,proto3,oneof" json:"min_answers,omitempty"`
+	MaxAnswers    *int64                 `protobuf:"varint,7,opt,name=max_answers,json=maxAnswers,proto3,oneof" json:"max_answers,omitempty"`
+	// Текст без учета регистра в заголовке или тексте вопроса.
+	Text string       `protobuf:"bytes,8,opt,name=text,proto3" json:"text,omitempty"`
+	Sort QuestionSort `protobuf:"varint,9,opt,name=sort,proto3,enum=question.v1.QuestionSort" json:"sort,omitempty"`
+	// Теги, которые должны быть у вопроса все сразу.
+	Tags          []string `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQuestionsRequest) Reset() {
+	*x = ListQuestionsRequest{}
+	mi := &file_api_question_v1_question_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuestionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuestionsRequest) ProtoMessage() {}
+
+func (x *ListQuestionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuestionsRequest.ProtoReflect.Descriptor instead.
+func (*ListQuestionsRequest) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListQuestionsRequest) GetStatuses() []QuestionStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListQuestionsRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListQuestionsRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *ListQuestionsRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *ListQuestionsRequest) GetHasAnswers() bool {
+	if x != nil && x.HasAnswers != nil {
+		return *x.HasAnswers
+	}
+	return false
+}
+
+func (x *ListQuestionsRequest) GetMinAnswers() int64 {
+	if x != nil && x.MinAnswers != nil {
+		return *x.MinAnswers
+	}
+	return 0
+}
+
+func (x *ListQuestionsRequest) GetMaxAnswers() int64 {
+	if x != nil && x.MaxAnswers != nil {
+		return *x.MaxAnswers
+	}
+	return 0
+}
+
+func (x *ListQuestionsRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ListQuestionsRequest) GetSort() QuestionSort {
+	if x != nil {
+		return x.Sort
+	}
+	return QuestionSort_QUESTION_SORT_UNSPECIFIED
+}
+
+func (x *ListQuestionsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type ListQuestionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Questions     []*Question            `protobuf:"bytes,1,rep,name=questions,proto3" json:"questions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQuestionsResponse) Reset() {
+	*x = ListQuestionsResponse{}
+	mi := &file_api_question_v1_question_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuestionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuestionsResponse) ProtoMessage() {}
+
+func (x *ListQuestionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuestionsResponse.ProtoReflect.Descriptor instead.
+func (*ListQuestionsResponse) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListQuestionsResponse) GetQuestions() []*Question {
+	if x != nil {
+		return x.Questions
+	}
+	return nil
+}
+
+type UpdateQuestionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title *string                `protobuf:"bytes,2,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	Body  *string                `protobuf:"bytes,3,opt,name=body,proto3,oneof" json:"body,omitempty"`
+	// Если задана, вопрос изменяется, только если он все еще в этой версии.
+	Version       *int64 `protobuf:"varint,4,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateQuestionRequest) Reset() {
+	*x = UpdateQuestionRequest{}
+	mi := &file_api_question_v1_question_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateQuestionRequest) ProtoMessage() {}
+
+func (x *UpdateQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateQuestionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateQuestionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateQuestionRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateQuestionRequest) GetBody() string {
+	if x != nil && x.Body != nil {
+		return *x.Body
+	}
+	return ""
+}
+
+func (x *UpdateQuestionRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeleteQuestionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Если задана, вопрос удаляется, только если он все еще в этой версии.
+	Version       *int64 `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteQuestionRequest) Reset() {
+	*x = DeleteQuestionRequest{}
+	mi := &file_api_question_v1_question_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteQuestionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteQuestionRequest) ProtoMessage() {}
+
+func (x *DeleteQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteQuestionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteQuestionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteQuestionRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeleteQuestionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteQuestionResponse) Reset() {
+	*x = DeleteQuestionResponse{}
+	mi := &file_api_question_v1_question_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteQuestionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteQuestionResponse) ProtoMessage() {}
+
+func (x *DeleteQuestionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteQuestionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteQuestionResponse) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{10}
+}
+
+type CreateAnswerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId    uint64                 `protobuf:"varint,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAnswerRequest) Reset() {
+	*x = CreateAnswerRequest{}
+	mi := &file_api_question_v1_question_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAnswerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAnswerRequest) ProtoMessage() {}
+
+func (x *CreateAnswerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAnswerRequest.ProtoReflect.Descriptor instead.
+func (*CreateAnswerRequest) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateAnswerRequest) GetQuestionId() uint64 {
+	if x != nil {
+		return x.QuestionId
+	}
+	return 0
+}
+
+func (x *CreateAnswerRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type GetAnswerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAnswerRequest) Reset() {
+	*x = GetAnswerRequest{}
+	mi := &file_api_question_v1_question_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAnswerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAnswerRequest) ProtoMessage() {}
+
+func (x *GetAnswerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAnswerRequest.ProtoReflect.Descriptor instead.
+func (*GetAnswerRequest) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetAnswerRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type UpdateAnswerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Text  string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	// Если задана, ответ изменяется, только если он все еще в этой версии.
+	Version       *int64 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAnswerRequest) Reset() {
+	*x = UpdateAnswerRequest{}
+	mi := &file_api_question_v1_question_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAnswerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAnswerRequest) ProtoMessage() {}
+
+func (x *UpdateAnswerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAnswerRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAnswerRequest) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateAnswerRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateAnswerRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *UpdateAnswerRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeleteAnswerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Если задана, ответ удаляется, только если он все еще в этой версии.
+	Version       *int64 `protobuf:"varint,2,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAnswerRequest) Reset() {
+	*x = DeleteAnswerRequest{}
+	mi := &file_api_question_v1_question_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAnswerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAnswerRequest) ProtoMessage() {}
+
+func (x *DeleteAnswerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAnswerRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAnswerRequest) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteAnswerRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteAnswerRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type DeleteAnswerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAnswerResponse) Reset() {
+	*x = DeleteAnswerResponse{}
+	mi := &file_api_question_v1_question_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAnswerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAnswerResponse) ProtoMessage() {}
+
+func (x *DeleteAnswerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_question_v1_question_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAnswerResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAnswerResponse) Descriptor() ([]byte, []int) {
+	return file_api_question_v1_question_proto_rawDescGZIP(), []int{15}
+}
+
+var File_api_question_v1_question_proto protoreflect.FileDescriptor
+
+const file_api_question_v1_question_proto_rawDesc = "" +
+	"\n" +
+	"\x1eapi/question/v1/question.proto\x12\vquestion.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa6\x06\n" +
+	"\bQuestion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\tR\bauthorId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\x12\x1b\n" +
+	"\tbody_html\x18\x05 \x01(\tR\bbodyHtml\x123\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x1b.question.v1.QuestionStatusR\x06status\x12!\n" +
+	"\fclose_reason\x18\a \x01(\tR\vcloseReason\x12\x1b\n" +
+	"\tclosed_by\x18\b \x01(\tR\bclosedBy\x127\n" +
+	"\tclosed_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\bclosedAt\x12&\n" +
+	"\fduplicate_of\x18\n" +
+	" \x01(\x04H\x00R\vduplicateOf\x88\x01\x01\x121\n" +
+	"\x12accepted_answer_id\x18\v \x01(\x04H\x01R\x10acceptedAnswerId\x88\x01\x01\x12\x14\n" +
+	"\x05score\x18\f \x01(\x03R\x05score\x12\x18\n" +
+	"\aversion\x18\r \x01(\x03R\aversion\x12!\n" +
+	"\fanswer_count\x18\x0e \x01(\x03R\vanswerCount\x12#\n" +
+	"\rcomment_count\x18\x0f \x01(\x03R\fcommentCount\x129\n" +
+	"\n" +
+	"created_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12D\n" +
+	"\x10last_activity_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastActivityAt\x12-\n" +
+	"\aanswers\x18\x13 \x03(\v2\x13.question.v1.AnswerR\aanswers\x12\x12\n" +
+	"\x04tags\x18\x14 \x03(\tR\x04tagsB\x0f\n" +
+	"\r_duplicate_ofB\x15\n" +
+	"\x13_accepted_answer_id\"\xbc\x02\n" +
+	"\x06Answer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1f\n" +
+	"\vquestion_id\x18\x02 \x01(\x04R\n" +
+	"questionId\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\x12\x12\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\x12\x1b\n" +
+	"\ttext_html\x18\x05 \x01(\tR\btextHtml\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x12#\n" +
+	"\rcomment_count\x18\a \x01(\x03R\fcommentCount\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"d\n" +
+	"\x0fSimilarQuestion\x121\n" +
+	"\bquestion\x18\x01 \x01(\v2\x15.question.v1.QuestionR\bquestion\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x02 \x01(\x01R\n" +
+	"similarity\"m\n" +
+	"\x15CreateQuestionRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\x12\x16\n" +
+	"\x06strict\x18\x03 \x01(\bR\x06strict\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\"\x9a\x01\n" +
+	"\x16CreateQuestionResponse\x121\n" +
+	"\bquestion\x18\x01 \x01(\v2\x15.question.v1.QuestionR\bquestion\x12M\n" +
+	"\x13possible_duplicates\x18\x02 \x03(\v2\x1c.question.v1.SimilarQuestionR\x12possibleDuplicates\"$\n" +
+	"\x12GetQuestionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xe9\x03\n" +
+	"\x14ListQuestionsRequest\x127\n" +
+	"\bstatuses\x18\x01 \x03(\x0e2\x1b.question.v1.QuestionStatusR\bstatuses\x12?\n" +
+	"\rcreated_after\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x1b\n" +
+	"\tauthor_id\x18\x04 \x01(\tR\bauthorId\x12$\n" +
+	"\vhas_answers\x18\x05 \x01(\bH\x00R\n" +
+	"hasAnswers\x88\x01\x01\x12$\n" +
+	"\vmin_answers\x18\x06 \x01(\x03H\x01R\n" +
+	"minAnswers\x88\x01\x01\x12$\n" +
+	"\vmax_answers\x18\a \x01(\x03H\x02R\n" +
+	"maxAnswers\x88\x01\x01\x12\x12\n" +
+	"\x04text\x18\b \x01(\tR\x04text\x12-\n" +
+	"\x04sort\x18\t \x01(\x0e2\x19.question.v1.QuestionSortR\x04sort\x12\x12\n" +
+	"\x04tags\x18\n" +
+	" \x03(\tR\x04tagsB\x0e\n" +
+	"\f_has_answersB\x0e\n" +
+	"\f_min_answersB\x0e\n" +
+	"\f_max_answers\"L\n" +
+	"\x15ListQuestionsResponse\x123\n" +
+	"\tquestions\x18\x01 \x03(\v2\x15.question.v1.QuestionR\tquestions\"\x99\x01\n" +
+	"\x15UpdateQuestionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x19\n" +
+	"\x05title\x18\x02 \x01(\tH\x00R\x05title\x88\x01\x01\x12\x17\n" +
+	"\x04body\x18\x03 \x01(\tH\x01R\x04body\x88\x01\x01\x12\x1d\n" +
+	"\aversion\x18\x04 \x01(\x03H\x02R\aversion\x88\x01\x01B\b\n" +
+	"\x06_titleB\a\n" +
+	"\x05_bodyB\n" +
+	"\n" +
+	"\b_version\"R\n" +
+	"\x15DeleteQuestionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1d\n" +
+	"\aversion\x18\x02 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"\x18\n" +
+	"\x16DeleteQuestionResponse\"J\n" +
+	"\x13CreateAnswerRequest\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\x04R\n" +
+	"questionId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"\"\n" +
+	"\x10GetAnswerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"d\n" +
+	"\x13UpdateAnswerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"P\n" +
+	"\x13DeleteAnswerRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1d\n" +
+	"\aversion\x18\x02 \x01(\x03H\x00R\aversion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_version\"\x16\n" +
+	"\x14DeleteAnswerResponse*\xa1\x01\n" +
+	"\x0eQuestionStatus\x12\x1f\n" +
+	"\x1bQUESTION_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14QUESTION_STATUS_OPEN\x10\x01\x12\x1a\n" +
+	"\x16QUESTION_STATUS_CLOSED\x10\x02\x12\x1a\n" +
+	"\x16QUESTION_STATUS_LOCKED\x10\x03\x12\x1c\n" +
+	"\x18QUESTION_STATUS_ARCHIVED\x10\x04*\xb1\x01\n" +
+	"\fQuestionSort\x12\x1d\n" +
+	"\x19QUESTION_SORT_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14QUESTION_SORT_NEWEST\x10\x01\x12\x18\n" +
+	"\x14QUESTION_SORT_OLDEST\x10\x02\x12\x19\n" +
+	"\x15QUESTION_SORT_ANSWERS\x10\x03\x12\x1a\n" +
+	"\x16QUESTION_SORT_ACTIVITY\x10\x04\x12\x17\n" +
+	"\x13QUESTION_SORT_VOTES\x10\x052\xd7\x05\n" +
+	"\x0fQuestionService\x12Y\n" +
+	"\x0eCreateQuestion\x12\".question.v1.CreateQuestionRequest\x1a#.question.v1.CreateQuestionResponse\x12E\n" +
+	"\vGetQuestion\x12\x1f.question.v1.GetQuestionRequest\x1a\x15.question.v1.Question\x12V\n" +
+	"\rListQuestions\x12!.question.v1.ListQuestionsRequest\x1a\".question.v1.ListQuestionsResponse\x12K\n" +
+	"\x0eUpdateQuestion\x12\".question.v1.UpdateQuestionRequest\x1a\x15.question.v1.Question\x12Y\n" +
+	"\x0eDeleteQuestion\x12\".question.v1.DeleteQuestionRequest\x1a#.question.v1.DeleteQuestionResponse\x12E\n" +
+	"\fCreateAnswer\x12 .question.v1.CreateAnswerRequest\x1a\x13.question.v1.Answer\x12?\n" +
+	"\tGetAnswer\x12\x1d.question.v1.GetAnswerRequest\x1a\x13.question.v1.Answer\x12E\n" +
+	"\fUpdateAnswer\x12 .question.v1.UpdateAnswerRequest\x1a\x13.question.v1.Answer\x12S\n" +
+	"\fDeleteAnswer\x12 .question.v1.DeleteAnswerRequest\x1a!.question.v1.DeleteAnswerResponseBAZ?github.com/shenikar/question-service/api/question/v1;questionv1b\x06proto3"
+
+var (
+	file_api_question_v1_question_proto_rawDescOnce sync.Once
+	file_api_question_v1_question_proto_rawDescData []byte
+)
+
+func file_api_question_v1_question_proto_rawDescGZIP() []byte {
+	file_api_question_v1_question_proto_rawDescOnce.Do(func() {
+		file_api_question_v1_question_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_question_v1_question_proto_rawDesc), len(file_api_question_v1_question_proto_rawDesc)))
+	})
+	return file_api_question_v1_question_proto_rawDescData
+}
+
+var file_api_question_v1_question_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_question_v1_question_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_api_question_v1_question_proto_goTypes = []any{
+	(QuestionStatus)(0),            // 0: question.v1.QuestionStatus
+	(QuestionSort)(0),              // 1: question.v1.QuestionSort
+	(*Question)(nil),               // 2: question.v1.Question
+	(*Answer)(nil),                 // 3: question.v1.Answer
+	(*SimilarQuestion)(nil),        // 4: question.v1.SimilarQuestion
+	(*CreateQuestionRequest)(nil),  // 5: question.v1.CreateQuestionRequest
+	(*CreateQuestionResponse)(nil), // 6: question.v1.CreateQuestionResponse
+	(*GetQuestionRequest)(nil),     // 7: question.v1.GetQuestionRequest
+	(*ListQuestionsRequest)(nil),   // 8: question.v1.ListQuestionsRequest
+	(*ListQuestionsResponse)(nil),  // 9: question.v1.ListQuestionsResponse
+	(*UpdateQuestionRequest)(nil),  // 10: question.v1.UpdateQuestionRequest
+	(*DeleteQuestionRequest)(nil),  // 11: question.v1.DeleteQuestionRequest
+	(*DeleteQuestionResponse)(nil), // 12: question.v1.DeleteQuestionResponse
+	(*CreateAnswerRequest)(nil),    // 13: question.v1.CreateAnswerRequest
+	(*GetAnswerRequest)(nil),       // 14: question.v1.GetAnswerRequest
+	(*UpdateAnswerRequest)(nil),    // 15: question.v1.UpdateAnswerRequest
+	(*DeleteAnswerRequest)(nil),    // 16: question.v1.DeleteAnswerRequest
+	(*DeleteAnswerResponse)(nil),   // 17: question.v1.DeleteAnswerResponse
+	(*timestamppb.Timestamp)(nil),  // 18: google.protobuf.Timestamp
+}
+var file_api_question_v1_question_proto_depIdxs = []int32{
+	0,  // 0: question.v1.Question.status:type_name -> question.v1.QuestionStatus
+	18, // 1: question.v1.Question.closed_at:type_name -> google.protobuf.Timestamp
+	18, // 2: question.v1.Question.created_at:type_name -> google.protobuf.Timestamp
+	18, // 3: question.v1.Question.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 4: question.v1.Question.last_activity_at:type_name -> google.protobuf.Timestamp
+	3,  // 5: question.v1.Question.answers:type_name -> question.v1.Answer
+	18, // 6: question.v1.Answer.created_at:type_name -> google.protobuf.Timestamp
+	18, // 7: question.v1.Answer.updated_at:type_name -> google.protobuf.Timestamp
+	2,  // 8: question.v1.SimilarQuestion.question:type_name -> question.v1.Question
+	2,  // 9: question.v1.CreateQuestionResponse.question:type_name -> question.v1.Question
+	4,  // 10: question.v1.CreateQuestionResponse.possible_duplicates:type_name -> question.v1.SimilarQuestion
+	0,  // 11: question.v1.ListQuestionsRequest.statuses:type_name -> question.v1.QuestionStatus
+	18, // 12: question.v1.ListQuestionsRequest.created_after:type_name -> google.protobuf.Timestamp
+	18, // 13: question.v1.ListQuestionsRequest.created_before:type_name -> google.protobuf.Timestamp
+	1,  // 14: question.v1.ListQuestionsRequest.sort:type_name -> question.v1.QuestionSort
+	2,  // 15: question.v1.ListQuestionsResponse.questions:type_name -> question.v1.Question
+	5,  // 16: question.v1.QuestionService.CreateQuestion:input_type -> question.v1.CreateQuestionRequest
+	7,  // 17: question.v1.QuestionService.GetQuestion:input_type -> question.v1.GetQuestionRequest
+	8,  // 18: question.v1.QuestionService.ListQuestions:input_type -> question.v1.ListQuestionsRequest
+	10, // 19: question.v1.QuestionService.UpdateQuestion:input_type -> question.v1.UpdateQuestionRequest
+	11, // 20: question.v1.QuestionService.DeleteQuestion:input_type -> question.v1.DeleteQuestionRequest
+	13, // 21: question.v1.QuestionService.CreateAnswer:input_type -> question.v1.CreateAnswerRequest
+	14, // 22: question.v1.QuestionService.GetAnswer:input_type -> question.v1.GetAnswerRequest
+	15, // 23: question.v1.QuestionService.UpdateAnswer:input_type -> question.v1.UpdateAnswerRequest
+	16, // 24: question.v1.QuestionService.DeleteAnswer:input_type -> question.v1.DeleteAnswerRequest
+	6,  // 25: question.v1.QuestionService.CreateQuestion:output_type -> question.v1.CreateQuestionResponse
+	2,  // 26: question.v1.QuestionService.GetQuestion:output_type -> question.v1.Question
+	9,  // 27: question.v1.QuestionService.ListQuestions:output_type -> question.v1.ListQuestionsResponse
+	2,  // 28: question.v1.QuestionService.UpdateQuestion:output_type -> question.v1.Question
+	12, // 29: question.v1.QuestionService.DeleteQuestion:output_type -> question.v1.DeleteQuestionResponse
+	3,  // 30: question.v1.QuestionService.CreateAnswer:output_type -> question.v1.Answer
+	3,  // 31: question.v1.QuestionService.GetAnswer:output_type -> question.v1.Answer
+	3,  // 32: question.v1.QuestionService.UpdateAnswer:output_type -> question.v1.Answer
+	17, // 33: question.v1.QuestionService.DeleteAnswer:output_type -> question.v1.DeleteAnswerResponse
+	25, // [25:34] is the sub-list for method output_type
+	16, // [16:25] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_api_question_v1_question_proto_init() }
+func file_api_question_v1_question_proto_init() {
+	if File_api_question_v1_question_proto != nil {
+		return
+	}
+	file_api_question_v1_question_proto_msgTypes[0].OneofWrappers = []any{}
+	file_api_question_v1_question_proto_msgTypes[6].OneofWrappers = []any{}
+	file_api_question_v1_question_proto_msgTypes[8].OneofWrappers = []any{}
+	file_api_question_v1_question_proto_msgTypes[9].OneofWrappers = []any{}
+	file_api_question_v1_question_proto_msgTypes[13].OneofWrappers = []any{}
+	file_api_question_v1_question_proto_msgTypes[14].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_question_v1_question_proto_rawDesc), len(file_api_question_v1_question_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_question_v1_question_proto_goTypes,
+		DependencyIndexes: file_api_question_v1_question_proto_depIdxs,
+		EnumInfos:         file_api_question_v1_question_proto_enumTypes,
+		MessageInfos:      file_api_question_v1_question_proto_msgTypes,
+	}.Build()
+	File_api_question_v1_question_proto = out.File
+	file_api_question_v1_question_proto_goTypes = nil
+	file_api_question_v1_question_proto_depIdxs = nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/config"
+	"github.com/shenikar/question-service/internal/exporter"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/repository"
+)
+
+// exportExtensions - форматы выгрузки, соответствующие расширениям файлов.
+var exportExtensions = map[string]exporter.Format{
+	".ndjson": exporter.FormatNDJSON,
+	".jsonl":  exporter.FormatNDJSON,
+	".json":   exporter.FormatJSON,
+	".csv":    exporter.FormatCSV,
+	".md":     exporter.FormatMarkdown,
+}
+
+// runExport выполняет подкоманду export: выгружает вопросы в файл (или stdout).
+// Файл сначала пишется во временный рядом с ним и заменяет его, только если выгрузка завершилась успешно.
+func runExport(cfg *config.Config, appLogger *logrus.Logger, args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: main export [flags]")
+		fmt.Fprintln(flags.Output(), "Exports questions with answers to a file or stdout.")
+		flags.PrintDefaults()
+	}
+	output := flags.String("o", "-", "output file, - for stdout")
+	formatName := flags.String("format", "",
+		"output format: ndjson, json, csv or markdown (default: by file extension, otherwise ndjson)")
+	compress := flags.Bool("gzip", false, "compress the output with gzip (default: true for .gz files)")
+	batchSize := flags.Int("batch-size", exporter.DefaultBatchSize, "questions read from the storage at once")
+	filters := url.Values{}
+	for _, f := range []struct {
+		param, usage string
+		repeatable   bool
+	}{
+		{query.ParamStatus, "question statuses, comma-separated", false},
+		{query.ParamCreatedAfter, "created at or after this time (RFC 3339 or YYYY-MM-DD)", false},
+		{query.ParamCreatedBefore, "created before this time (RFC 3339 or YYYY-MM-DD)", false},
+		{query.ParamAuthor, "author user ID", false},
+		{query.ParamText, "text contained in the title or body", false},
+		{query.ParamTag, "tags the question must all have, comma-separated or repeated", true},
+	} {
+		flags.Func(strings.ReplaceAll(f.param, "_", "-"), f.usage, func(value string) error {
+			if f.repeatable {
+				filters.Add(f.param, value)
+			} else {
+				filters.Set(f.param, value)
+			}
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	spec, err := query.ParseQuestionSpec(filters)
+	if err != nil {
+		appLogger.Error(err)
+		return exitUsage
+	}
+	opts := exporter.Options{Spec: spec, BatchSize: *batchSize, Format: exporter.FormatNDJSON}
+	name := strings.ToLower(*output)
+	if strings.HasSuffix(name, ".gz") {
+		name = strings.TrimSuffix(name, ".gz")
+		if !isFlagSet(flags, "gzip") {
+			*compress = true
+		}
+	}
+	if *formatName != "" {
+		if opts.Format, err = exporter.ParseFormat(*formatName); err != nil {
+			appLogger.Error(err)
+			return exitUsage
+		}
+	} else if format, ok := exportExtensions[filepath.Ext(name)]; ok {
+		opts.Format = format
+	}
+
+	st := openStorage(cfg, appLogger)
+	defer st.close()
+
+	if *output == "-" {
+		if err := export(st.repo, appLogger, os.Stdout, opts, *compress); err != nil {
+			appLogger.Errorf("Export failed: %v", err)
+			return exitFailed
+		}
+		return exitOK
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(*output), "."+filepath.Base(*output)+".*")
+	if err != nil {
+		appLogger.Errorf("Failed to create export file: %v", err)
+		return exitFailed
+	}
+	err = export(st.repo, appLogger, file, opts, *compress)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), *output)
+	}
+	if err != nil {
+		appLogger.Errorf("Export failed: %v", err)
+		if removeErr := os.Remove(file.Name()); removeErr != nil && !os.IsNotExist(removeErr) {
+			appLogger.Warnf("Failed to remove temporary export file: %v", removeErr)
+		}
+		return exitFailed
+	}
+	return exitOK
+}
+
+// export пишет выгрузку в w, при необходимости сжимая ее.
+func export(
+	repo repository.Repository, appLogger *logrus.Logger, w io.Writer, opts exporter.Options, compress bool,
+) error {
+	if !compress {
+		_, err := exporter.New(repo, appLogger).Export(w, opts)
+		return err
+	}
+	gz := gzip.NewWriter(w)
+	if _, err := exporter.New(repo, appLogger).Export(gz, opts); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// isFlagSet сообщает, передан ли флаг name явно.
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
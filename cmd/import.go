@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/config"
+	"github.com/shenikar/question-service/internal/importer"
+)
+
+// Коды завершения подкоманд.
+const (
+	exitOK     = 0
+	exitFailed = 1
+	exitUsage  = 2
+)
+
+// importExtensions - форматы импорта, соответствующие расширениям файлов.
+var importExtensions = map[string]importer.Format{
+	".ndjson": importer.FormatNDJSON,
+	".jsonl":  importer.FormatNDJSON,
+	".json":   importer.FormatJSON,
+	".csv":    importer.FormatCSV,
+}
+
+// runImport выполняет подкоманду import: импортирует вопросы из файла (или stdin, если файл "-")
+// и печатает отчет в формате JSON. Возвращает код завершения: 1, если хотя бы одна запись
+// не импортирована из-за ошибки или файл не удалось дочитать.
+func runImport(cfg *config.Config, appLogger *logrus.Logger, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: main import [flags] FILE")
+		fmt.Fprintln(flags.Output(), "Imports questions with answers from FILE (- reads stdin).")
+		flags.PrintDefaults()
+	}
+	formatName := flags.String("format", "", "input format: ndjson, json or csv (default: by file extension)")
+	dryRun := flags.Bool("dry-run", false, "only validate the records, do not save them")
+	batchSize := flags.Int("batch-size", importer.DefaultBatchSize, "questions saved in one transaction")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	path := flags.Arg(0)
+
+	opts := importer.Options{DryRun: *dryRun, BatchSize: *batchSize}
+	if *formatName != "" {
+		format, err := importer.ParseFormat(*formatName)
+		if err != nil {
+			appLogger.Error(err)
+			return exitUsage
+		}
+		opts.Format = format
+	} else if format, ok := importExtensions[strings.ToLower(filepath.Ext(path))]; ok {
+		opts.Format = format
+	} else {
+		appLogger.Errorf("Cannot detect the format of %q, use -format", path)
+		return exitUsage
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			appLogger.Errorf("Failed to open import file: %v", err)
+			return exitFailed
+		}
+		defer file.Close()
+		input = file
+	}
+
+	st := openStorage(cfg, appLogger)
+	defer st.close()
+
+	report, err := importer.New(st.repo, appLogger).Import(input, opts)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if encodeErr := enc.Encode(report); encodeErr != nil {
+		appLogger.Errorf("Failed to write import report: %v", encodeErr)
+	}
+	if err != nil || report.Failed > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
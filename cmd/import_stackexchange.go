@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/config"
+	"github.com/shenikar/question-service/internal/stackexchange"
+)
+
+// runImportStackExchange выполняет подкоманду import-stackexchange: импортирует Users.xml и Posts.xml
+// из каталога дампа StackExchange и печатает отчет в формате JSON. Возвращает код завершения: 1,
+// если хотя бы одна запись не импортирована из-за ошибки или импорт остановлен.
+func runImportStackExchange(cfg *config.Config, appLogger *logrus.Logger, args []string) int {
+	flags := flag.NewFlagSet("import-stackexchange", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: main import-stackexchange -source NAME [flags] DIR")
+		fmt.Fprintln(flags.Output(),
+			"Imports questions and answers from Users.xml and Posts.xml in the dump directory DIR.")
+		flags.PrintDefaults()
+	}
+	source := flags.String("source", "",
+		"name of the imported site, e.g. its address; re-runs with the same name skip imported posts")
+	batchSize := flags.Int("batch-size", stackexchange.DefaultBatchSize, "posts saved in one transaction")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 || *source == "" {
+		flags.Usage()
+		return exitUsage
+	}
+	dir := flags.Arg(0)
+
+	users, err := os.Open(filepath.Join(dir, "Users.xml"))
+	if err != nil {
+		appLogger.Errorf("Failed to open dump file: %v", err)
+		return exitFailed
+	}
+	defer users.Close()
+	posts, err := os.Open(filepath.Join(dir, "Posts.xml"))
+	if err != nil {
+		appLogger.Errorf("Failed to open dump file: %v", err)
+		return exitFailed
+	}
+	defer posts.Close()
+
+	st := openStorage(cfg, appLogger)
+	defer st.close()
+
+	report, err := stackexchange.New(st.repo, appLogger).Import(users, posts,
+		stackexchange.Options{Source: *source, BatchSize: *batchSize})
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if encodeErr := enc.Encode(report); encodeErr != nil {
+		appLogger.Errorf("Failed to write import report: %v", encodeErr)
+	}
+	if err != nil || len(report.Failures) > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
@@ -1,58 +1,217 @@
 package main
 
 import (
-	_ "github.com/shenikar/question-service/docs"
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	_ "github.com/shenikar/question-service/docs/v1"
 	"github.com/shenikar/question-service/internal/config"
+	"github.com/shenikar/question-service/internal/cors"
 	"github.com/shenikar/question-service/internal/db"
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/exporter"
+	"github.com/shenikar/question-service/internal/graphql"
+	"github.com/shenikar/question-service/internal/grpcapi"
 	"github.com/shenikar/question-service/internal/handler"
+	"github.com/shenikar/question-service/internal/idempotency"
+	"github.com/shenikar/question-service/internal/importer"
 	"github.com/shenikar/question-service/internal/logger"
+	"github.com/shenikar/question-service/internal/notification"
+	"github.com/shenikar/question-service/internal/outbox"
+	"github.com/shenikar/question-service/internal/ratelimit"
 	"github.com/shenikar/question-service/internal/repository"
 	"github.com/shenikar/question-service/internal/router"
 	"github.com/shenikar/question-service/internal/server"
 	"github.com/shenikar/question-service/internal/service"
+	"github.com/shenikar/question-service/internal/webhook"
 )
 
 // @title API Сервиса Вопросов
 // @version 1.0
 // @description Это пример сервера для сервиса вопросов.
 // @host localhost:8080
-// @BasePath /
+// @BasePath /api/v1
 func main() {
 	// Инициализация логгера
 	appLogger := logger.NewLogger()
 
+	// Подкоманды; без подкоманды запускается сервер
+	command := "serve"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+	if command != "serve" {
+		// Подкоманды печатают результат в stdout
+		appLogger.SetOutput(os.Stderr)
+	}
+
 	// Загрузка конфигурации
 	cfg, err := config.Load(appLogger)
 	if err != nil {
 		appLogger.Fatalf("Error loading .env file: %v", err)
 	}
 
-	// Подключение к базе данных
-	gormDB, sqlDB, err := db.Connect(cfg, appLogger)
-	if err != nil {
-		appLogger.Fatalf("failed to connect database: %v", err)
+	switch command {
+	case "serve":
+		serve(cfg, appLogger)
+	case "import":
+		os.Exit(runImport(cfg, appLogger, os.Args[2:]))
+	case "export":
+		os.Exit(runExport(cfg, appLogger, os.Args[2:]))
+	case "import-stackexchange":
+		os.Exit(runImportStackExchange(cfg, appLogger, os.Args[2:]))
+	default:
+		appLogger.Fatalf("Unknown command %q: must be serve, import, import-stackexchange or export", command)
 	}
-	defer func() {
-		if err := sqlDB.Close(); err != nil {
-			appLogger.Errorf("Error closing database connection: %v", err)
-		}
-	}()
+}
 
-	// Инициализация репозитория
-	repo := repository.NewRepository(gormDB, appLogger)
+// serve запускает HTTP- и gRPC-серверы.
+func serve(cfg *config.Config, appLogger *logrus.Logger) {
+	st := openStorage(cfg, appLogger)
+	defer st.close()
 
-	// Инициализация сервисов
-	s := service.NewService(repo, appLogger)
+	// Инициализация сервисов; hub доставляет события об ответах потокам событий вопросов,
+	// broadcaster - соединениям WebSocket, dispatcher - подпискам на webhook, notifier создает уведомления
+	hub := events.NewHub(events.DefaultReplaySize, events.DefaultClientBuffer)
+	broadcaster := events.NewBroadcaster(events.DefaultClientBuffer)
+	dispatcher := webhook.NewDispatcher(st.webhooks, handler.EventData, appLogger, webhook.DefaultQueueSize)
+	notifier := notification.NewNotifier(st.notifications, st.repo, appLogger, notification.DefaultQueueSize)
+
+	// Доставки webhook и события из outbox отправляются, а истекшие ключи идемпотентности удаляются
+	// в фоне до остановки сервера
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		webhook.NewWorker(st.webhooks, webhook.Options{}, appLogger).Run(workerCtx)
+	}()
+	purgeDone := make(chan struct{})
+	go func() {
+		defer close(purgeDone)
+		idempotency.PurgeExpired(workerCtx, st.idempotency, cfg.IdempotencySweepInterval, appLogger)
+	}()
+
+	// В PostgreSQL сервис записывает события в outbox в транзакции изменения, а получателям их передает relay;
+	// в памяти транзакций нет, поэтому события передаются получателям сразу
+	var publisher service.Publisher = service.Publishers{hub, broadcaster, dispatcher, notifier}
+	relayDone := make(chan struct{})
+	if st.outbox != nil {
+		sinks := outboxSinks(cfg.OutboxSinks, service.Publishers{hub, broadcaster}, dispatcher, notifier, appLogger)
+		relay := outbox.NewRelay(st.outbox, sinks, outbox.Options{Retention: cfg.OutboxRetention}, appLogger)
+		publisher = relay
+		go func() {
+			defer close(relayDone)
+			relay.Run(workerCtx)
+		}()
+	} else {
+		close(relayDone)
+	}
+	s := service.NewService(st.repo, appLogger, publisher)
 
 	// Инициализация обработчиков
-	h := handler.NewHandler(s, appLogger)
+	h := handler.NewHandler(s, appLogger, cfg.MaxBodyBytes)
+	admin := handler.NewAdminHandler(importer.New(st.repo, appLogger), exporter.New(st.repo, appLogger),
+		appLogger, cfg.MaxImportBytes)
+	gql := graphql.NewHandler(s, appLogger, cfg.MaxBodyBytes, cfg.IsDevelopment())
+	ev := handler.NewEventsHandler(s, hub, appLogger, handler.DefaultHeartbeatInterval)
+	// Соединения WebSocket разрешены тем же источникам, что и запросы CORS
+	c := cors.New(cfg.CORS)
+	ws := handler.NewWebSocketHandler(broadcaster, c.OriginAllowed, appLogger, handler.DefaultPingInterval)
+	wh := handler.NewWebhookHandler(st.webhooks, appLogger, cfg.MaxBodyBytes)
+	fd := handler.NewFeedHandler(s, cfg.PublicURL, router.PrefixV1, appLogger)
+	nt := handler.NewNotificationHandler(st.notifications, appLogger)
 
 	// Настройка роутера
-	r := router.NewRouter(h)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(),
+		ratelimit.PerMinute(cfg.ReadRateLimit), ratelimit.PerMinute(cfg.WriteRateLimit), cfg.TrustedProxies, appLogger)
+	// Ключи анонимных запросов ограничиваются тем же IP-адресом клиента, по которому ограничивается частота
+	idem := idempotency.NewMiddleware(st.idempotency, cfg.IdempotencyTTL, cfg.MaxBodyBytes, appLogger).
+		WithClientIP(limiter.ClientIP)
+	r := router.NewRouter(h, admin, gql, ev, ws, wh, fd, nt, idem, limiter, c)
 
 	// Инициализация и запуск сервера
-	srv := server.NewServer(r, appLogger)
-	if err := srv.Run(); err != nil {
+	srv := server.NewServer(r, appLogger).WithGRPC(cfg.GRPCAddr, grpcapi.NewServer(s, appLogger))
+	// Открытые потоки событий закрываются при остановке, иначе они задержали бы ее до таймаута;
+	// соединения WebSocket Shutdown не ждет, но клиенты должны узнать об остановке
+	srv.OnShutdown(hub.Close)
+	srv.OnShutdown(broadcaster.Close)
+	err := srv.Run()
+	// События, полученные до остановки, записываются в журнал доставок; их отправит следующий запуск.
+	// Уведомления по событиям из очереди создаются до выхода
+	dispatcher.Close()
+	notifier.Close()
+	stopWorker()
+	<-workerDone
+	<-purgeDone
+	<-relayDone
+	if err != nil {
 		appLogger.Fatalf("Server stopped with error: %v", err)
 	}
 }
+
+// outboxSinks возвращает получателей событий outbox, выбранных в конфигурации.
+func outboxSinks(
+	names []string, bus service.Publisher, dispatcher *webhook.Dispatcher, notifier *notification.Notifier,
+	appLogger *logrus.Logger,
+) map[string]outbox.Sink {
+	sinks := make(map[string]outbox.Sink, len(names))
+	for _, name := range names {
+		switch name {
+		case config.OutboxSinkBus:
+			sinks[name] = outbox.BusSink(bus)
+		case config.OutboxSinkWebhook:
+			sinks[name] = dispatcher
+		case config.OutboxSinkNotifications:
+			sinks[name] = notifier
+		case config.OutboxSinkLog:
+			sinks[name] = outbox.LogSink(appLogger)
+		}
+	}
+	return sinks
+}
+
+// storage - хранилища, открытые по конфигурации.
+type storage struct {
+	repo          repository.Repository
+	idempotency   idempotency.Store
+	webhooks      webhook.Store
+	notifications notification.Store
+	// outbox - сообщения outbox; nil для хранилища в памяти, где события не записываются в outbox.
+	outbox outbox.Store
+	// close закрывает подключение к базе данных.
+	close func()
+}
+
+// openStorage открывает хранилище, выбранное в конфигурации.
+func openStorage(cfg *config.Config, appLogger *logrus.Logger) storage {
+	if cfg.Storage == config.StorageMemory {
+		appLogger.Warn("Using in-memory storage, all data will be lost on restart")
+		return storage{
+			repo:          repository.NewMemoryRepository(appLogger),
+			idempotency:   idempotency.NewMemoryStore(),
+			webhooks:      webhook.NewMemoryStore(),
+			notifications: notification.NewMemoryStore(),
+			close:         func() {},
+		}
+	}
+
+	// Подключение к базе данных
+	gormDB, sqlDB, err := db.Connect(cfg, appLogger)
+	if err != nil {
+		appLogger.Fatalf("failed to connect database: %v", err)
+	}
+	return storage{
+		repo:          repository.NewRepository(gormDB, appLogger),
+		idempotency:   idempotency.NewStore(gormDB),
+		webhooks:      webhook.NewStore(gormDB),
+		notifications: notification.NewStore(gormDB),
+		outbox:        outbox.NewStore(gormDB),
+		close: func() {
+			if err := sqlDB.Close(); err != nil {
+				appLogger.Errorf("Error closing database connection: %v", err)
+			}
+		},
+	}
+}
@@ -1,285 +0,0 @@
-// Package docs Code generated by swaggo/swag. DO NOT EDIT
-package docs
-
-import "github.com/swaggo/swag"
-
-const docTemplate = `{
-    "schemes": {{ marshal .Schemes }},
-    "swagger": "2.0",
-    "info": {
-        "description": "{{escape .Description}}",
-        "title": "{{.Title}}",
-        "contact": {},
-        "version": "{{.Version}}"
-    },
-    "host": "{{.Host}}",
-    "basePath": "{{.BasePath}}",
-    "paths": {
-        "/answers/{id}": {
-            "get": {
-                "description": "Get an answer by its ID",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "answers"
-                ],
-                "summary": "Get an answer by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Answer ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Answer"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Delete an answer by its ID",
-                "tags": [
-                    "answers"
-                ],
-                "summary": "Delete an answer by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Answer ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
-            }
-        },
-        "/questions": {
-            "get": {
-                "description": "Get a list of all questions",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "questions"
-                ],
-                "summary": "Get all questions",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Question"
-                            }
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Create a new question with the input payload",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "questions"
-                ],
-                "summary": "Create a new question",
-                "parameters": [
-                    {
-                        "description": "Question to create",
-                        "name": "question",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Question"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.Question"
-                        }
-                    }
-                }
-            }
-        },
-        "/questions/{id}": {
-            "get": {
-                "description": "Get a question by its ID",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "questions"
-                ],
-                "summary": "Get a question by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Question ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Question"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Delete a question by its ID",
-                "tags": [
-                    "questions"
-                ],
-                "summary": "Delete a question by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Question ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
-            }
-        },
-        "/questions/{id}/answers": {
-            "post": {
-                "description": "Create an answer for a specific question",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "answers"
-                ],
-                "summary": "Create an answer for a question",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Question ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Answer to create",
-                        "name": "answer",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Answer"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.Answer"
-                        }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "models.Answer": {
-            "type": "object",
-            "required": [
-                "text"
-            ],
-            "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "questionID": {
-                    "type": "integer"
-                },
-                "text": {
-                    "type": "string",
-                    "maxLength": 500,
-                    "minLength": 3
-                },
-                "userID": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.Question": {
-            "type": "object",
-            "required": [
-                "text"
-            ],
-            "properties": {
-                "answers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Answer"
-                    }
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "text": {
-                    "type": "string",
-                    "maxLength": 500,
-                    "minLength": 3
-                }
-            }
-        }
-    }
-}`
-
-// SwaggerInfo holds exported Swagger Info so clients can modify it
-var SwaggerInfo = &swag.Spec{
-	Version:          "1.0",
-	Host:             "localhost:8080",
-	BasePath:         "/",
-	Schemes:          []string{},
-	Title:            "Question Service API",
-	Description:      "This is a sample server for a question service.",
-	InfoInstanceName: "swagger",
-	SwaggerTemplate:  docTemplate,
-	LeftDelim:        "{{",
-	RightDelim:       "}}",
-}
-
-func init() {
-	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
-}
@@ -0,0 +1,86 @@
+// Package auth извлекает пользователя и его роль из заголовков запроса.
+// Аутентификацию выполняет шлюз перед сервисом, поэтому сервис доверяет
+// заголовкам X-User-ID и X-User-Role и только проверяет их формат.
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Заголовки, которые выставляет шлюз для аутентифицированных запросов.
+const (
+	HeaderUserID   = "X-User-ID"
+	HeaderUserRole = "X-User-Role"
+)
+
+// Роли пользователей.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+)
+
+// Identity описывает пользователя, выполняющего запрос.
+type Identity struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+type contextKey struct{}
+
+// Middleware сохраняет пользователя из заголовков запроса в контексте.
+// Запросы без X-User-ID считаются анонимными, некорректный X-User-ID отклоняется с 401.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawID := r.Header.Get(HeaderUserID)
+		if rawID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, err := uuid.Parse(rawID)
+		if err != nil {
+			http.Error(w, "Invalid "+HeaderUserID+" header", http.StatusUnauthorized)
+			return
+		}
+
+		role := r.Header.Get(HeaderUserRole)
+		if role == "" {
+			role = RoleUser
+		}
+
+		ctx := WithIdentity(r.Context(), Identity{UserID: userID, Role: role})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireRole пропускает только аутентифицированных пользователей с указанной ролью.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, ok := FromContext(r.Context())
+			if !ok {
+				http.Error(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+			if identity.Role != role {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithIdentity возвращает копию контекста с указанным пользователем.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext возвращает пользователя из контекста запроса.
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(Identity)
+	return identity, ok
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareStoresIdentity(t *testing.T) {
+	userID := uuid.New()
+	var got Identity
+	var found bool
+	handler := Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got, found = FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderUserID, userID.String())
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, found)
+	assert.Equal(t, userID, got.UserID)
+	assert.Equal(t, RoleUser, got.Role)
+}
+
+func TestMiddlewareAnonymous(t *testing.T) {
+	var found bool
+	handler := Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, found = FromContext(r.Context())
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.False(t, found)
+}
+
+func TestMiddlewareInvalidUserID(t *testing.T) {
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderUserID, "not-a-uuid")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestRequireRole(t *testing.T) {
+	handler := Middleware(RequireRole(RoleModerator)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	tests := []struct {
+		name     string
+		role     string
+		withUser bool
+		expected int
+	}{
+		{name: "anonymous", expected: http.StatusUnauthorized},
+		{name: "user", withUser: true, expected: http.StatusForbidden},
+		{name: "moderator", role: RoleModerator, withUser: true, expected: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.withUser {
+				req.Header.Set(HeaderUserID, uuid.NewString())
+			}
+			if tt.role != "" {
+				req.Header.Set(HeaderUserRole, tt.role)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, tt.expected, rr.Code)
+		})
+	}
+}
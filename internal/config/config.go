@@ -1,15 +1,97 @@
 package config
 
 import (
+	"fmt"
+	"net/netip"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 )
 
+// Поддерживаемые хранилища данных.
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+// Окружения, в которых запускается приложение.
+const (
+	EnvProduction  = "production"
+	EnvDevelopment = "development"
+)
+
+// DefaultGRPCAddr - адрес gRPC-сервера по умолчанию.
+const DefaultGRPCAddr = ":9090"
+
+// DefaultIdempotencyTTL - срок хранения ключей Idempotency-Key по умолчанию.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// DefaultIdempotencySweepInterval - как часто по умолчанию удаляются истекшие ключи Idempotency-Key.
+const DefaultIdempotencySweepInterval = 10 * time.Minute
+
+// DefaultMaxBodyBytes - максимальный размер тела запроса по умолчанию (1 МиБ).
+const DefaultMaxBodyBytes = 1 << 20
+
+// DefaultMaxImportBytes - максимальный размер тела запроса на импорт по умолчанию (64 МиБ).
+const DefaultMaxImportBytes = 64 << 20
+
+// Ограничения частоты запросов по умолчанию, запросов в минуту на IP-адрес и на пользователя.
+const (
+	DefaultReadRateLimit  = 300
+	DefaultWriteRateLimit = 60
+)
+
+// Получатели событий из outbox (OUTBOX_SINKS).
+const (
+	// OutboxSinkBus - шина событий внутри процесса: SSE и WebSocket.
+	OutboxSinkBus = "bus"
+	// OutboxSinkWebhook - журнал доставок webhook.
+	OutboxSinkWebhook = "webhook"
+	// OutboxSinkLog - журнал приложения.
+	OutboxSinkLog = "log"
+	// OutboxSinkNotifications - уведомления пользователей.
+	OutboxSinkNotifications = "notifications"
+)
+
+// DefaultOutboxSinks - получатели событий из outbox по умолчанию.
+var DefaultOutboxSinks = []string{OutboxSinkBus, OutboxSinkWebhook, OutboxSinkNotifications}
+
+// DefaultOutboxRetention - срок хранения доставленных сообщений outbox по умолчанию.
+const DefaultOutboxRetention = 7 * 24 * time.Hour
+
 // Config хранит все конфигурации приложения.
 type Config struct {
-	DatabaseURL string
+	// Env - окружение: в EnvDevelopment включаются инструменты разработчика, например GraphiQL.
+	Env            string
+	DatabaseURL    string
+	Storage        string
+	IdempotencyTTL time.Duration
+	// IdempotencySweepInterval - как часто удаляются истекшие ключи Idempotency-Key.
+	IdempotencySweepInterval time.Duration
+	// ReadRateLimit и WriteRateLimit - ограничения чтения и записи в запросах в минуту, 0 отключает ограничение.
+	ReadRateLimit  int
+	WriteRateLimit int
+	// TrustedProxies - сети прокси, которым можно верить в X-Forwarded-For при определении IP-адреса клиента.
+	TrustedProxies []netip.Prefix
+	// MaxBodyBytes - максимальный размер тела запроса в байтах.
+	MaxBodyBytes int64
+	// MaxImportBytes - максимальный размер тела запроса на импорт в байтах.
+	MaxImportBytes int64
+	CORS           CORS
+	// GRPCAddr - адрес, на котором gRPC-сервер слушает вместе с HTTP-сервером.
+	GRPCAddr string
+	// OutboxSinks - получатели, которым Relay передает события из outbox (только для PostgreSQL).
+	OutboxSinks []string
+	// OutboxRetention - сколько хранятся доставленные сообщения outbox.
+	OutboxRetention time.Duration
+	// PublicURL - внешний адрес сервиса без завершающего "/" для абсолютных ссылок в лентах Atom и RSS.
+	// Пустой адрес берется из запроса.
+	PublicURL string
 }
 
 // Load считывает конфигурацию из .env файла или переменных окружения.
@@ -19,12 +101,143 @@ func Load(log *logrus.Logger) (*Config, error) {
 	}
 
 	config := &Config{
+		Env:         os.Getenv("APP_ENV"),
 		DatabaseURL: os.Getenv("DATABASE_URL"),
+		Storage:     os.Getenv("STORAGE"),
+		GRPCAddr:    os.Getenv("GRPC_ADDR"),
+	}
+	switch config.Env {
+	case "":
+		config.Env = EnvProduction
+	case EnvProduction, EnvDevelopment:
+	default:
+		return nil, fmt.Errorf("invalid APP_ENV %q: must be %s or %s", config.Env, EnvProduction, EnvDevelopment)
+	}
+	if config.Storage == "" {
+		config.Storage = StoragePostgres
+	}
+	if config.GRPCAddr == "" {
+		config.GRPCAddr = DefaultGRPCAddr
+	}
+
+	var err error
+	if config.IdempotencyTTL, err = durationEnv("IDEMPOTENCY_TTL", DefaultIdempotencyTTL); err != nil {
+		return nil, err
+	}
+	config.IdempotencySweepInterval, err = durationEnv("IDEMPOTENCY_SWEEP_INTERVAL", DefaultIdempotencySweepInterval)
+	if err != nil {
+		return nil, err
+	}
+	if config.ReadRateLimit, err = rateLimitEnv("RATE_LIMIT_READ", DefaultReadRateLimit); err != nil {
+		return nil, err
+	}
+	if config.WriteRateLimit, err = rateLimitEnv("RATE_LIMIT_WRITE", DefaultWriteRateLimit); err != nil {
+		return nil, err
+	}
+	if config.TrustedProxies, err = proxiesEnv("TRUSTED_PROXIES"); err != nil {
+		return nil, err
+	}
+
+	if config.MaxBodyBytes, err = sizeEnv("MAX_BODY_BYTES", DefaultMaxBodyBytes); err != nil {
+		return nil, err
+	}
+	if config.MaxImportBytes, err = sizeEnv("MAX_IMPORT_BYTES", DefaultMaxImportBytes); err != nil {
+		return nil, err
+	}
+
+	if config.CORS, err = loadCORS(); err != nil {
+		return nil, err
+	}
+
+	config.OutboxSinks = listEnv("OUTBOX_SINKS", DefaultOutboxSinks)
+	for _, sink := range config.OutboxSinks {
+		switch sink {
+		case OutboxSinkBus, OutboxSinkWebhook, OutboxSinkLog, OutboxSinkNotifications:
+		default:
+			return nil, fmt.Errorf("invalid OUTBOX_SINKS entry %q: must be %s, %s, %s or %s",
+				sink, OutboxSinkBus, OutboxSinkWebhook, OutboxSinkNotifications, OutboxSinkLog)
+		}
+	}
+	if config.OutboxRetention, err = durationEnv("OUTBOX_RETENTION", DefaultOutboxRetention); err != nil {
+		return nil, err
+	}
+
+	if config.PublicURL = strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/"); config.PublicURL != "" {
+		parsed, err := url.Parse(config.PublicURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			parsed.RawQuery != "" || parsed.Fragment != "" {
+			return nil, fmt.Errorf(
+				"invalid PUBLIC_URL %q: must be an absolute http or https URL such as https://qa.example.com",
+				config.PublicURL)
+		}
 	}
 
 	return config, nil
 }
 
+// rateLimitEnv читает ограничение частоты запросов из переменной окружения name.
+func rateLimitEnv(name string, def int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative number of requests per minute", name, value)
+	}
+	return limit, nil
+}
+
+// proxiesEnv читает список сетей (10.0.0.0/8) или отдельных адресов (10.0.0.1) через запятую
+// из переменной окружения name.
+func proxiesEnv(name string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range listEnv(name, nil) {
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(item)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid %s entry %q: must be an IP address or a CIDR such as 10.0.0.0/8",
+					name, item)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// durationEnv читает положительную длительность из переменной окружения name.
+func durationEnv(name string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive duration such as 24h", name, value)
+	}
+	return duration, nil
+}
+
+// sizeEnv читает размер в байтах из переменной окружения name.
+func sizeEnv(name string, def int64) (int64, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return def, nil
+	}
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive number of bytes", name, value)
+	}
+	return size, nil
+}
+
+// IsDevelopment сообщает, запущено ли приложение в окружении разработки.
+func (c *Config) IsDevelopment() bool {
+	return c.Env == EnvDevelopment
+}
+
 // GetDatabaseURL возвращает строку подключения к базе данных.
 func (c *Config) GetDatabaseURL() string {
 	return c.DatabaseURL
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Настройки CORS по умолчанию. Источники по умолчанию не разрешены, то есть CORS выключен.
+// X-User-ID и X-User-Role по умолчанию не разрешены: их выставляет доверенный шлюз, а не страница,
+// иначе любой разрешенный источник мог бы назначить себе роль модератора.
+var (
+	DefaultCORSAllowedMethods = []string{"GET", "POST", "PATCH", "DELETE"}
+	DefaultCORSAllowedHeaders = []string{
+		"Content-Type", "Idempotency-Key", "If-Match", "If-None-Match", "If-Modified-Since",
+	}
+	DefaultCORSExposedHeaders = []string{
+		"ETag", "Last-Modified", "Location", "Retry-After", "Idempotent-Replayed",
+		"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+		"Deprecation", "Sunset", "Link",
+	}
+)
+
+// DefaultCORSMaxAge - сколько браузер может кэшировать ответ на предварительный запрос.
+const DefaultCORSMaxAge = 10 * time.Minute
+
+// CORS хранит настройки Cross-Origin Resource Sharing для браузерных клиентов.
+type CORS struct {
+	// AllowedOrigins - разрешенные источники: точные (https://app.example.com),
+	// с поддоменами (https://*.example.com) или * для любого источника.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// loadCORS считывает настройки CORS из переменных окружения CORS_*.
+func loadCORS() (CORS, error) {
+	cors := CORS{
+		AllowedOrigins: listEnv("CORS_ALLOWED_ORIGINS", nil),
+		AllowedMethods: listEnv("CORS_ALLOWED_METHODS", DefaultCORSAllowedMethods),
+		AllowedHeaders: listEnv("CORS_ALLOWED_HEADERS", DefaultCORSAllowedHeaders),
+		ExposedHeaders: listEnv("CORS_EXPOSED_HEADERS", DefaultCORSExposedHeaders),
+		MaxAge:         DefaultCORSMaxAge,
+	}
+	for i, origin := range cors.AllowedOrigins {
+		cors.AllowedOrigins[i] = strings.ToLower(strings.TrimSuffix(origin, "/"))
+	}
+	for i, method := range cors.AllowedMethods {
+		cors.AllowedMethods[i] = strings.ToUpper(method)
+	}
+
+	if value := os.Getenv("CORS_ALLOW_CREDENTIALS"); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return CORS{}, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS %q: must be true or false", value)
+		}
+		cors.AllowCredentials = allow
+	}
+	// Браузеры не передают учетные данные источнику *, а отражение любого источника
+	// открыло бы API с учетными данными пользователя для любого сайта
+	if cors.AllowCredentials && slices.Contains(cors.AllowedOrigins, "*") {
+		return CORS{}, fmt.Errorf("CORS_ALLOW_CREDENTIALS cannot be used with CORS_ALLOWED_ORIGINS=*")
+	}
+
+	if value := os.Getenv("CORS_MAX_AGE"); value != "" {
+		maxAge, err := time.ParseDuration(value)
+		if err != nil || maxAge < 0 {
+			return CORS{}, fmt.Errorf("invalid CORS_MAX_AGE %q: must be a non-negative duration such as 10m", value)
+		}
+		cors.MaxAge = maxAge
+	}
+	return cors, nil
+}
+
+// listEnv читает список через запятую из переменной окружения name, пустые элементы пропускаются.
+func listEnv(name string, def []string) []string {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return slices.Clone(def)
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,148 @@
+// Package cors разрешает браузерным клиентам с других источников обращаться к API
+// (Cross-Origin Resource Sharing, https://fetch.spec.whatwg.org/#http-cors-protocol).
+package cors
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shenikar/question-service/internal/config"
+)
+
+// CORS - middleware, добавляющее к ответам заголовки Access-Control-* для разрешенных источников.
+type CORS struct {
+	origins          []string
+	methods          []string
+	headers          []string
+	anyHeader        bool
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+// New создает middleware из настроек cfg. Без разрешенных источников заголовки CORS не добавляются.
+func New(cfg config.CORS) *CORS {
+	c := &CORS{
+		origins:          cfg.AllowedOrigins,
+		methods:          cfg.AllowedMethods,
+		exposedHeaders:   strings.Join(cfg.ExposedHeaders, ", "),
+		allowCredentials: cfg.AllowCredentials,
+	}
+	for _, header := range cfg.AllowedHeaders {
+		if header == "*" {
+			c.anyHeader = true
+			continue
+		}
+		c.headers = append(c.headers, http.CanonicalHeaderKey(header))
+	}
+	if cfg.MaxAge > 0 {
+		c.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+	return c
+}
+
+// Handler оборачивает обработчик. Предварительные запросы (OPTIONS с Access-Control-Request-Method)
+// обрабатываются здесь же: 204 для разрешенных источника, метода и заголовков, иначе 403.
+// Для обычных запросов с неразрешенного источника заголовки CORS не добавляются, и браузер не отдаст
+// ответ странице.
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if origin == "" || len(c.origins) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Ответ зависит от Origin, поэтому кэши должны различать запросы с разными источниками
+		w.Header().Add("Vary", "Origin")
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			c.preflight(w, r, origin)
+			return
+		}
+		if c.OriginAllowed(origin) {
+			c.setOrigin(w, origin)
+			if c.exposedHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", c.exposedHeaders)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *CORS) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	method := r.Header.Get("Access-Control-Request-Method")
+	requested := requestedHeaders(r)
+	if !c.OriginAllowed(origin) || !slices.Contains(c.methods, method) || !c.headersAllowed(requested) {
+		http.Error(w, "CORS request is not allowed", http.StatusForbidden)
+		return
+	}
+
+	c.setOrigin(w, origin)
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(c.methods, ", "))
+	if len(requested) > 0 {
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+	}
+	if c.maxAge != "" {
+		w.Header().Set("Access-Control-Max-Age", c.maxAge)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setOrigin разрешает ответ источнику origin. Источник возвращается явно, а не как *,
+// потому что * несовместим с передачей учетных данных.
+func (c *CORS) setOrigin(w http.ResponseWriter, origin string) {
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if c.allowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// OriginAllowed проверяет источник по списку: * разрешает любой источник,
+// шаблон https://*.example.com - любой поддомен example.com (но не сам example.com).
+func (c *CORS) OriginAllowed(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range c.origins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		prefix, suffix, wildcard := strings.Cut(allowed, "*")
+		if wildcard && len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			subdomain := origin[len(prefix) : len(origin)-len(suffix)]
+			if !strings.ContainsAny(subdomain, "/:") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *CORS) headersAllowed(requested []string) bool {
+	if c.anyHeader {
+		return true
+	}
+	for _, header := range requested {
+		if !slices.Contains(c.headers, header) {
+			return false
+		}
+	}
+	return true
+}
+
+// requestedHeaders возвращает заголовки из Access-Control-Request-Headers в каноническом виде.
+func requestedHeaders(r *http.Request) []string {
+	var headers []string
+	for _, value := range r.Header.Values("Access-Control-Request-Headers") {
+		for _, header := range strings.Split(value, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, http.CanonicalHeaderKey(header))
+			}
+		}
+	}
+	return headers
+}
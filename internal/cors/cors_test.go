@@ -0,0 +1,147 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/config"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func newTestCORS() http.Handler {
+	return New(config.CORS{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.preview.example.com"},
+		AllowedMethods:   []string{"GET", "POST", "PATCH"},
+		AllowedHeaders:   []string{"Content-Type", "If-Match"},
+		ExposedHeaders:   []string{"ETag", "Location"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}).Handler(okHandler)
+}
+
+func serve(handler http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCORSSimpleRequest(t *testing.T) {
+	handler := newTestCORS()
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://APP.example.com", true},
+		{"https://pr-42.preview.example.com", true},
+		{"https://preview.example.com", false},
+		{"https://evil.com/.preview.example.com", false},
+		{"http://app.example.com", false},
+		{"https://app.example.com.evil.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/questions", nil)
+			req.Header.Set("Origin", tt.origin)
+			rr := serve(handler, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Header().Values("Vary"), "Origin")
+			if !tt.allowed {
+				assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+				return
+			}
+			assert.Equal(t, tt.origin, rr.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, "true", rr.Header().Get("Access-Control-Allow-Credentials"))
+			assert.Equal(t, "ETag, Location", rr.Header().Get("Access-Control-Expose-Headers"))
+		})
+	}
+}
+
+func TestCORSWithoutOrigin(t *testing.T) {
+	rr := serve(newTestCORS(), httptest.NewRequest(http.MethodGet, "/questions", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rr.Header().Get("Vary"))
+}
+
+func TestCORSPreflight(t *testing.T) {
+	tests := []struct {
+		name    string
+		origin  string
+		method  string
+		headers string
+		status  int
+	}{
+		{"allowed", "https://app.example.com", "PATCH", "content-type, if-match", http.StatusNoContent},
+		{"without headers", "https://pr-1.preview.example.com", "POST", "", http.StatusNoContent},
+		{"origin denied", "https://evil.com", "PATCH", "", http.StatusForbidden},
+		{"method denied", "https://app.example.com", "DELETE", "", http.StatusForbidden},
+		{"header denied", "https://app.example.com", "POST", "Content-Type, X-Debug", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/questions/1", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", tt.method)
+			if tt.headers != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.headers)
+			}
+			rr := serve(newTestCORS(), req)
+
+			assert.Equal(t, tt.status, rr.Code)
+			if tt.status != http.StatusNoContent {
+				assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+				return
+			}
+			assert.Equal(t, tt.origin, rr.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, "GET, POST, PATCH", rr.Header().Get("Access-Control-Allow-Methods"))
+			assert.Equal(t, "600", rr.Header().Get("Access-Control-Max-Age"))
+			if tt.headers != "" {
+				assert.Equal(t, "Content-Type, If-Match", rr.Header().Get("Access-Control-Allow-Headers"))
+			}
+		})
+	}
+}
+
+func TestCORSAnyOriginAndHeader(t *testing.T) {
+	handler := New(config.CORS{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET"},
+		AllowedHeaders: []string{"*"},
+	}).Handler(okHandler)
+
+	req := httptest.NewRequest(http.MethodOptions, "/questions", nil)
+	req.Header.Set("Origin", "https://anywhere.test")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	req.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	rr := serve(handler, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, "https://anywhere.test", rr.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Custom", rr.Header().Get("Access-Control-Allow-Headers"))
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Empty(t, rr.Header().Get("Access-Control-Max-Age"))
+}
+
+func TestCORSDisabled(t *testing.T) {
+	handler := New(config.CORS{AllowedMethods: []string{"GET"}}).Handler(okHandler)
+
+	req := httptest.NewRequest(http.MethodOptions, "/questions", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	rr := serve(handler, req)
+
+	// Без CORS предварительный запрос доходит до роутера как обычный OPTIONS
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+}
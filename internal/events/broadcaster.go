@@ -0,0 +1,245 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shenikar/question-service/internal/models"
+)
+
+// Темы Broadcaster.
+const (
+	// TopicQuestions - события всех вопросов и ответов.
+	TopicQuestions = "questions"
+	// topicQuestionPrefix - префикс темы событий одного вопроса и его ответов, например question:42.
+	topicQuestionPrefix = "question:"
+	// topicTagPrefix - префикс темы событий вопросов с тегом и их ответов, например tag:go.
+	topicTagPrefix = "tag:"
+)
+
+// MaxClientTopics - на сколько тем может подписаться один клиент Broadcaster.
+const MaxClientTopics = 100
+
+var (
+	// ErrUnknownTopic возвращается при подписке на тему, которой нет.
+	ErrUnknownTopic = errors.New("unknown topic")
+	// ErrTooManyTopics возвращается, если клиент уже подписан на MaxClientTopics тем.
+	ErrTooManyTopics = errors.New("too many topics")
+)
+
+// QuestionTopic возвращает тему событий вопроса id и его ответов.
+func QuestionTopic(id uint) string {
+	return topicQuestionPrefix + strconv.FormatUint(uint64(id), 10)
+}
+
+// TagTopic возвращает тему событий вопросов с тегом tag и их ответов.
+func TagTopic(tag string) string {
+	return topicTagPrefix + tag
+}
+
+// ParseTopic проверяет тему и возвращает ее каноническую запись.
+func ParseTopic(topic string) (string, error) {
+	if topic == TopicQuestions {
+		return topic, nil
+	}
+	if idStr, ok := strings.CutPrefix(topic, topicQuestionPrefix); ok {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil || id == 0 {
+			return "", fmt.Errorf("%w %q: invalid question ID", ErrUnknownTopic, topic)
+		}
+		return QuestionTopic(uint(id)), nil
+	}
+	if tag, ok := strings.CutPrefix(topic, topicTagPrefix); ok {
+		name, ok := models.NormalizeTag(tag)
+		if !ok {
+			return "", fmt.Errorf("%w %q: invalid tag", ErrUnknownTopic, topic)
+		}
+		return TagTopic(name), nil
+	}
+	return "", fmt.Errorf("%w %q: expected %s, %s<id> or %s<tag>",
+		ErrUnknownTopic, topic, TopicQuestions, topicQuestionPrefix, topicTagPrefix)
+}
+
+// Broadcaster рассылает события клиентам, подписанным на темы.
+// Как и Hub, публикация никогда не блокируется: клиент, у которого заполнился буфер, отключается.
+// В отличие от Hub, Broadcaster не хранит прошедшие события.
+type Broadcaster struct {
+	mu           sync.Mutex
+	clientBuffer int
+	clients      map[*Client]struct{}
+	topics       map[string]map[*Client]struct{}
+	closed       bool
+}
+
+// NewBroadcaster создает Broadcaster, который буферизует до clientBuffer событий для каждого клиента.
+func NewBroadcaster(clientBuffer int) *Broadcaster {
+	return &Broadcaster{
+		clientBuffer: clientBuffer,
+		clients:      make(map[*Client]struct{}),
+		topics:       make(map[string]map[*Client]struct{}),
+	}
+}
+
+// Publish рассылает событие клиентам, подписанным на TopicQuestions, на тему его вопроса
+// или на тему одного из тегов вопроса. Клиент, подписанный на несколько из этих тем, получает событие один раз.
+func (b *Broadcaster) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	topics := []string{TopicQuestions, QuestionTopic(event.QuestionID)}
+	for _, tag := range event.Tags {
+		topics = append(topics, TagTopic(tag))
+	}
+	sent := make(map[*Client]struct{})
+	for _, topic := range topics {
+		for client := range b.topics[topic] {
+			if _, ok := sent[client]; !ok {
+				sent[client] = struct{}{}
+				b.send(client, event)
+			}
+		}
+	}
+}
+
+// send передает событие клиенту или отключает его, если буфер заполнен. Вызывается под b.mu.
+func (b *Broadcaster) send(client *Client, event Event) {
+	select {
+	case client.events <- event:
+	default:
+		b.drop(client, ErrSlowConsumer)
+	}
+}
+
+// Connect регистрирует клиента без подписок.
+func (b *Broadcaster) Connect() (*Client, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	client := &Client{
+		events:      make(chan Event, b.clientBuffer),
+		topics:      make(map[string]struct{}),
+		broadcaster: b,
+	}
+	b.clients[client] = struct{}{}
+	return client, nil
+}
+
+// Close отключает всех клиентов и перестает принимать события и подключения.
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for client := range b.clients {
+		b.drop(client, ErrClosed)
+	}
+}
+
+// drop отключает клиента и снимает все его подписки. Вызывается под b.mu.
+func (b *Broadcaster) drop(client *Client, err error) {
+	if _, ok := b.clients[client]; !ok {
+		return
+	}
+	delete(b.clients, client)
+	for topic := range client.topics {
+		b.unsubscribe(client, topic)
+	}
+	client.err = err
+	close(client.events)
+}
+
+// unsubscribe снимает подписку клиента на тему. Вызывается под b.mu.
+func (b *Broadcaster) unsubscribe(client *Client, topic string) {
+	delete(client.topics, topic)
+	clients := b.topics[topic]
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(b.topics, topic)
+	}
+}
+
+// Client - подключение к Broadcaster с набором тем, который можно менять.
+type Client struct {
+	events      chan Event
+	topics      map[string]struct{}
+	broadcaster *Broadcaster
+	err         error
+}
+
+// Subscribe подписывает клиента на тему и возвращает ее каноническую запись.
+// Повторная подписка на ту же тему ничего не меняет.
+func (c *Client) Subscribe(topic string) (string, error) {
+	topic, err := ParseTopic(topic)
+	if err != nil {
+		return "", err
+	}
+
+	b := c.broadcaster
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.clients[c]; !ok {
+		return "", ErrClosed
+	}
+	if _, ok := c.topics[topic]; ok {
+		return topic, nil
+	}
+	if len(c.topics) >= MaxClientTopics {
+		return "", fmt.Errorf("%w: at most %d per connection", ErrTooManyTopics, MaxClientTopics)
+	}
+	c.topics[topic] = struct{}{}
+	if b.topics[topic] == nil {
+		b.topics[topic] = make(map[*Client]struct{})
+	}
+	b.topics[topic][c] = struct{}{}
+	return topic, nil
+}
+
+// Unsubscribe отписывает клиента от темы и возвращает ее каноническую запись.
+// Отписка от темы, на которую клиент не подписан, ничего не меняет.
+func (c *Client) Unsubscribe(topic string) (string, error) {
+	topic, err := ParseTopic(topic)
+	if err != nil {
+		return "", err
+	}
+
+	b := c.broadcaster
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := c.topics[topic]; ok {
+		b.unsubscribe(c, topic)
+	}
+	return topic, nil
+}
+
+// Events возвращает канал событий. Канал закрывается, когда клиента отключили; причину возвращает Err.
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+// Err возвращает причину отключения клиента: ErrSlowConsumer или ErrClosed.
+// Вызывается после закрытия канала Events.
+func (c *Client) Err() error {
+	return c.err
+}
+
+// Close отключает клиента.
+func (c *Client) Close() {
+	c.broadcaster.mu.Lock()
+	defer c.broadcaster.mu.Unlock()
+	c.broadcaster.drop(c, nil)
+}
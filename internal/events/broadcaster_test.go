@@ -0,0 +1,141 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func clientEvent(t *testing.T, client *Client) Event {
+	select {
+	case event, ok := <-client.Events():
+		assert.True(t, ok, "client disconnected")
+		return event
+	default:
+		t.Fatal("no event")
+		return Event{}
+	}
+}
+
+func TestParseTopic(t *testing.T) {
+	topic, err := ParseTopic("questions")
+	assert.NoError(t, err)
+	assert.Equal(t, TopicQuestions, topic)
+
+	topic, err = ParseTopic("question:007")
+	assert.NoError(t, err)
+	assert.Equal(t, "question:7", topic)
+
+	topic, err = ParseTopic("tag:Go")
+	assert.NoError(t, err)
+	assert.Equal(t, "tag:go", topic)
+
+	for _, invalid := range []string{"", "answers", "question:", "question:0", "question:-1", "tag:", "tag:a b"} {
+		_, err = ParseTopic(invalid)
+		assert.ErrorIs(t, err, ErrUnknownTopic, invalid)
+	}
+}
+
+func TestBroadcasterRoutesEventsByTopic(t *testing.T) {
+	b := NewBroadcaster(10)
+	all, err := b.Connect()
+	assert.NoError(t, err)
+	one, err := b.Connect()
+	assert.NoError(t, err)
+	_, err = all.Subscribe(TopicQuestions)
+	assert.NoError(t, err)
+	// Подписка на обе темы не удваивает события
+	_, err = all.Subscribe("question:1")
+	assert.NoError(t, err)
+	_, err = one.Subscribe("question:1")
+	assert.NoError(t, err)
+
+	b.Publish(Event{Type: TypeQuestionCreated, QuestionID: 2})
+	b.Publish(Event{Type: TypeAnswerCreated, QuestionID: 1, AnswerID: 3})
+
+	assert.Equal(t, uint(2), clientEvent(t, all).QuestionID)
+	event := clientEvent(t, all)
+	assert.Equal(t, uint(3), event.AnswerID)
+	assert.False(t, event.Time.IsZero())
+	assert.Empty(t, all.Events())
+	assert.Equal(t, uint(3), clientEvent(t, one).AnswerID)
+	assert.Empty(t, one.Events())
+
+	_, err = one.Unsubscribe("question:1")
+	assert.NoError(t, err)
+	b.Publish(Event{Type: TypeAnswerDeleted, QuestionID: 1, AnswerID: 3})
+	assert.Empty(t, one.Events())
+	assert.Equal(t, TypeAnswerDeleted, clientEvent(t, all).Type)
+}
+
+func TestBroadcasterRoutesEventsByTag(t *testing.T) {
+	b := NewBroadcaster(10)
+	golang, err := b.Connect()
+	assert.NoError(t, err)
+	// Вопрос с обоими тегами не удваивает события
+	_, err = golang.Subscribe("tag:go")
+	assert.NoError(t, err)
+	_, err = golang.Subscribe("tag:linux")
+	assert.NoError(t, err)
+	_, err = golang.Subscribe("question:1")
+	assert.NoError(t, err)
+
+	b.Publish(Event{Type: TypeQuestionCreated, QuestionID: 1, Tags: []string{"go", "linux"}})
+	b.Publish(Event{Type: TypeQuestionCreated, QuestionID: 2, Tags: []string{"postgresql"}})
+	b.Publish(Event{Type: TypeAnswerCreated, QuestionID: 3, AnswerID: 4, Tags: []string{"linux"}})
+
+	assert.Equal(t, uint(1), clientEvent(t, golang).QuestionID)
+	assert.Equal(t, uint(4), clientEvent(t, golang).AnswerID)
+	assert.Empty(t, golang.Events())
+}
+
+func TestBroadcasterDropsSlowConsumer(t *testing.T) {
+	b := NewBroadcaster(1)
+	slow, err := b.Connect()
+	assert.NoError(t, err)
+	_, err = slow.Subscribe(TopicQuestions)
+	assert.NoError(t, err)
+
+	b.Publish(Event{QuestionID: 1})
+	b.Publish(Event{QuestionID: 2})
+
+	assert.Equal(t, uint(1), clientEvent(t, slow).QuestionID)
+	_, ok := <-slow.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+	_, err = slow.Subscribe("question:1")
+	assert.ErrorIs(t, err, ErrClosed)
+	slow.Close()
+}
+
+func TestBroadcasterLimitsTopics(t *testing.T) {
+	client, err := NewBroadcaster(1).Connect()
+	assert.NoError(t, err)
+	for id := uint(1); id <= MaxClientTopics; id++ {
+		_, err = client.Subscribe(QuestionTopic(id))
+		assert.NoError(t, err)
+	}
+	_, err = client.Subscribe(TopicQuestions)
+	assert.ErrorIs(t, err, ErrTooManyTopics)
+	// Повторная подписка не добавляет темы
+	_, err = client.Subscribe(QuestionTopic(1))
+	assert.NoError(t, err)
+}
+
+func TestBroadcasterClose(t *testing.T) {
+	b := NewBroadcaster(10)
+	client, err := b.Connect()
+	assert.NoError(t, err)
+	_, err = client.Subscribe(TopicQuestions)
+	assert.NoError(t, err)
+
+	b.Close()
+	_, ok := <-client.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, client.Err(), ErrClosed)
+	client.Close()
+
+	_, err = b.Connect()
+	assert.ErrorIs(t, err, ErrClosed)
+	b.Publish(Event{QuestionID: 1})
+}
@@ -0,0 +1,64 @@
+// Package events доставляет события об изменениях данных подписчикам внутри процесса.
+// Сервисный слой публикует события в Hub и Broadcaster. На Hub подписываются потоки Server-Sent Events
+// конкретного вопроса, на Broadcaster - соединения WebSocket, выбирающие темы.
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/shenikar/question-service/internal/models"
+)
+
+// Типы событий.
+const (
+	TypeQuestionCreated = "question.created"
+	// TypeQuestionUpdated - изменены заголовок или тело вопроса.
+	TypeQuestionUpdated = "question.updated"
+	TypeQuestionDeleted = "question.deleted"
+	TypeQuestionClosed  = "question.closed"
+	// TypeQuestionMarkedDuplicate - вопрос закрыт как дубликат другого вопроса.
+	TypeQuestionMarkedDuplicate = "question.marked_duplicate"
+	TypeQuestionReopened        = "question.reopened"
+	TypeQuestionLocked          = "question.locked"
+	TypeQuestionArchived        = "question.archived"
+	TypeAnswerCreated           = "answer.created"
+	TypeAnswerUpdated           = "answer.updated"
+	TypeAnswerDeleted           = "answer.deleted"
+	TypeAnswerAccepted          = "answer.accepted"
+	TypeCommentCreated          = "comment.created"
+	TypeCommentDeleted          = "comment.deleted"
+)
+
+// Types - все типы событий.
+var Types = []string{
+	TypeQuestionCreated, TypeQuestionUpdated, TypeQuestionDeleted, TypeQuestionClosed, TypeQuestionMarkedDuplicate,
+	TypeQuestionReopened, TypeQuestionLocked, TypeQuestionArchived,
+	TypeAnswerCreated, TypeAnswerUpdated, TypeAnswerDeleted, TypeAnswerAccepted,
+	TypeCommentCreated, TypeCommentDeleted,
+}
+
+// Event - событие об изменении вопроса QuestionID, ответа на него или комментария к ним.
+type Event struct {
+	// ID назначается Hub при публикации и возрастает от события к событию.
+	ID uint64
+	// UUID - постоянный идентификатор события: назначается один раз при фиксации изменения
+	// и сохраняется в outbox, поэтому не меняется при повторной отправке события получателям.
+	UUID       uuid.UUID
+	Type       string
+	QuestionID uint
+	// AnswerID заполняется только для событий об ответах.
+	AnswerID uint
+	// CommentID заполняется только для событий о комментариях.
+	CommentID uint
+	// Question - вопрос после изменения для событий о вопросе, кроме TypeQuestionDeleted; иначе nil.
+	Question *models.Question
+	// Answer - ответ после изменения; nil для TypeAnswerDeleted и событий о вопросах и комментариях.
+	Answer *models.Answer
+	// Comment - созданный комментарий для TypeCommentCreated, иначе nil.
+	Comment *models.Comment
+	// Tags - теги вопроса QuestionID: по ним Broadcaster рассылает событие в темы tag:<name>.
+	Tags []string
+	Time time.Time
+}
@@ -0,0 +1,228 @@
+package events
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Размеры буферов Hub по умолчанию.
+const (
+	// DefaultReplaySize - сколько последних событий хранится для возобновления потоков.
+	DefaultReplaySize = 1000
+	// DefaultClientBuffer - сколько событий может ждать отправки одному подписчику.
+	DefaultClientBuffer = 64
+)
+
+var (
+	// ErrClosed возвращается при подписке на остановленный Hub и из Subscription.Err после остановки.
+	ErrClosed = errors.New("event hub is closed")
+	// ErrSlowConsumer возвращается из Subscription.Err, если подписчик не успевал забирать события
+	// и был отключен.
+	ErrSlowConsumer = errors.New("subscriber is too slow")
+)
+
+// Hub рассылает события подписчикам вопросов и хранит последние события для возобновления.
+// Публикация никогда не блокируется: подписчик, у которого заполнился буфер, отключается
+// и может переподключиться, указав ID последнего полученного события.
+type Hub struct {
+	mu           sync.Mutex
+	clientBuffer int
+	// firstID - ID, с которого начинается нумерация событий этого процесса.
+	firstID uint64
+	lastID  uint64
+	replay  ring
+	subs    map[uint]map[*Subscription]struct{}
+	closed  bool
+}
+
+// NewHub создает Hub, который хранит replaySize последних событий всех вопросов
+// и буферизует до clientBuffer событий для каждого подписчика.
+func NewHub(replaySize, clientBuffer int) *Hub {
+	// ID начинаются с времени запуска в микросекундах, поэтому ID событий предыдущего запуска процесса
+	// меньше всех ID этого, и возобновление с ними распознается как разрыв
+	firstID := uint64(time.Now().UnixMicro())
+	return &Hub{
+		clientBuffer: clientBuffer,
+		firstID:      firstID,
+		lastID:       firstID,
+		replay:       ring{events: make([]Event, 0, replaySize), size: replaySize},
+		subs:         make(map[uint]map[*Subscription]struct{}),
+	}
+}
+
+// Publish назначает событию ID и рассылает его подписчикам вопроса event.QuestionID.
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+
+	h.lastID++
+	event.ID = h.lastID
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	h.replay.push(event)
+
+	for sub := range h.subs[event.QuestionID] {
+		select {
+		case sub.events <- event:
+		default:
+			h.drop(sub, ErrSlowConsumer)
+		}
+	}
+}
+
+// Subscribe подписывается на события вопроса questionID.
+// Если resume, клиент возобновляет поток после события lastEventID: пропущенные им события вопроса
+// попадают в Subscription.Replay, а если они уже не хранятся, выставляется Subscription.Resync.
+func (h *Hub) Subscribe(questionID uint, lastEventID uint64, resume bool) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrClosed
+	}
+
+	sub := &Subscription{
+		LastID:     h.lastID,
+		events:     make(chan Event, h.clientBuffer),
+		hub:        h,
+		questionID: questionID,
+	}
+	if resume {
+		if h.covers(lastEventID) {
+			sub.Replay = h.replay.after(lastEventID, questionID)
+		} else {
+			sub.Resync = true
+		}
+	}
+
+	if h.subs[questionID] == nil {
+		h.subs[questionID] = make(map[*Subscription]struct{})
+	}
+	h.subs[questionID][sub] = struct{}{}
+	return sub, nil
+}
+
+// covers сообщает, хранятся ли все события после lastEventID.
+func (h *Hub) covers(lastEventID uint64) bool {
+	if lastEventID < h.firstID || lastEventID > h.lastID {
+		// Событие из другого запуска процесса
+		return false
+	}
+	if lastEventID == h.lastID {
+		return true
+	}
+	// Пока буфер не заполнен, в нем все события этого запуска
+	oldest, ok := h.replay.oldest()
+	return ok && (!h.replay.full() || lastEventID+1 >= oldest)
+}
+
+// Close отключает всех подписчиков и перестает принимать события и подписки.
+// Вызывается при остановке сервера, чтобы открытые потоки завершились.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for _, subs := range h.subs {
+		for sub := range subs {
+			h.drop(sub, ErrClosed)
+		}
+	}
+}
+
+// drop отключает подписчика. Вызывается под h.mu.
+func (h *Hub) drop(sub *Subscription, err error) {
+	subs := h.subs[sub.questionID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.questionID)
+	}
+	sub.err = err
+	close(sub.events)
+}
+
+// Subscription - подписка на события одного вопроса.
+type Subscription struct {
+	// LastID - ID последнего события на момент подписки; с него клиент может продолжить после Resync.
+	LastID uint64
+	// Replay - пропущенные клиентом события вопроса, которые нужно отправить перед новыми.
+	Replay []Event
+	// Resync сообщает, что часть пропущенных событий уже не хранится и клиенту нужно перечитать вопрос.
+	Resync bool
+
+	events     chan Event
+	hub        *Hub
+	questionID uint
+	err        error
+}
+
+// Events возвращает канал новых событий. Канал закрывается, когда подписчика отключили;
+// причину возвращает Err.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err возвращает причину отключения подписчика: ErrSlowConsumer или ErrClosed.
+// Вызывается после закрытия канала Events.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Close отменяет подписку.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s, nil)
+}
+
+// ring - кольцевой буфер последних событий в порядке публикации.
+type ring struct {
+	events []Event
+	size   int
+	start  int
+}
+
+func (r *ring) push(event Event) {
+	if r.size <= 0 {
+		return
+	}
+	if len(r.events) < r.size {
+		r.events = append(r.events, event)
+		return
+	}
+	r.events[r.start] = event
+	r.start = (r.start + 1) % r.size
+}
+
+func (r *ring) full() bool {
+	return r.size <= 0 || len(r.events) == r.size
+}
+
+// oldest возвращает ID самого старого хранимого события.
+func (r *ring) oldest() (uint64, bool) {
+	if len(r.events) == 0 {
+		return 0, false
+	}
+	return r.events[r.start].ID, true
+}
+
+// after возвращает хранимые события вопроса questionID с ID больше lastID.
+func (r *ring) after(lastID uint64, questionID uint) []Event {
+	var result []Event
+	for i := range r.events {
+		event := r.events[(r.start+i)%len(r.events)]
+		if event.ID > lastID && event.QuestionID == questionID {
+			result = append(result, event)
+		}
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receive(t *testing.T, sub *Subscription) Event {
+	select {
+	case event, ok := <-sub.Events():
+		assert.True(t, ok, "subscription closed")
+		return event
+	default:
+		t.Fatal("no event")
+		return Event{}
+	}
+}
+
+func TestHubDeliversEventsOfQuestion(t *testing.T) {
+	hub := NewHub(10, 10)
+	sub, err := hub.Subscribe(1, 0, false)
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	hub.Publish(Event{Type: TypeAnswerCreated, QuestionID: 2, AnswerID: 1})
+	hub.Publish(Event{Type: TypeAnswerCreated, QuestionID: 1, AnswerID: 2})
+
+	event := receive(t, sub)
+	assert.Equal(t, uint(2), event.AnswerID)
+	assert.Equal(t, sub.LastID+2, event.ID)
+	assert.False(t, event.Time.IsZero())
+	assert.Empty(t, sub.Events())
+}
+
+func TestHubReplay(t *testing.T) {
+	hub := NewHub(10, 10)
+	first, err := hub.Subscribe(1, 0, false)
+	assert.NoError(t, err)
+	hub.Publish(Event{Type: TypeAnswerCreated, QuestionID: 1, AnswerID: 1})
+	seen := receive(t, first)
+	first.Close()
+
+	hub.Publish(Event{Type: TypeAnswerCreated, QuestionID: 1, AnswerID: 2})
+	hub.Publish(Event{Type: TypeAnswerCreated, QuestionID: 2, AnswerID: 3})
+	hub.Publish(Event{Type: TypeAnswerDeleted, QuestionID: 1, AnswerID: 1})
+
+	sub, err := hub.Subscribe(1, seen.ID, true)
+	assert.NoError(t, err)
+	defer sub.Close()
+	assert.False(t, sub.Resync)
+	if assert.Len(t, sub.Replay, 2) {
+		assert.Equal(t, uint(2), sub.Replay[0].AnswerID)
+		assert.Equal(t, TypeAnswerDeleted, sub.Replay[1].Type)
+	}
+}
+
+func TestHubResyncWhenEventsAreGone(t *testing.T) {
+	hub := NewHub(2, 10)
+	hub.Publish(Event{QuestionID: 1, AnswerID: 1})
+	hub.Publish(Event{QuestionID: 1, AnswerID: 2})
+	hub.Publish(Event{QuestionID: 1, AnswerID: 3})
+
+	// Первое событие вытеснено из буфера
+	sub, err := hub.Subscribe(1, hub.firstID+1, true)
+	assert.NoError(t, err)
+	assert.False(t, sub.Resync)
+	assert.Len(t, sub.Replay, 2)
+
+	sub, err = hub.Subscribe(1, hub.firstID, true)
+	assert.NoError(t, err)
+	assert.True(t, sub.Resync)
+	assert.Empty(t, sub.Replay)
+	assert.Equal(t, hub.lastID, sub.LastID)
+
+	// ID из предыдущего запуска процесса
+	sub, err = hub.Subscribe(1, 42, true)
+	assert.NoError(t, err)
+	assert.True(t, sub.Resync)
+}
+
+func TestHubDropsSlowConsumer(t *testing.T) {
+	hub := NewHub(10, 1)
+	slow, err := hub.Subscribe(1, 0, false)
+	assert.NoError(t, err)
+	fast, err := hub.Subscribe(1, 0, false)
+	assert.NoError(t, err)
+
+	hub.Publish(Event{QuestionID: 1, AnswerID: 1})
+	receive(t, fast)
+	hub.Publish(Event{QuestionID: 1, AnswerID: 2})
+
+	// Медленный подписчик получает то, что успело попасть в буфер, и отключается
+	assert.Equal(t, uint(1), receive(t, slow).AnswerID)
+	_, ok := <-slow.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+	assert.Equal(t, uint(2), receive(t, fast).AnswerID)
+	slow.Close()
+}
+
+func TestHubClose(t *testing.T) {
+	hub := NewHub(10, 10)
+	sub, err := hub.Subscribe(1, 0, false)
+	assert.NoError(t, err)
+
+	hub.Close()
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, sub.Err(), ErrClosed)
+	sub.Close()
+
+	_, err = hub.Subscribe(1, 0, false)
+	assert.ErrorIs(t, err, ErrClosed)
+	hub.Publish(Event{QuestionID: 1})
+}
@@ -0,0 +1,234 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/shenikar/question-service/internal/models"
+)
+
+// CSVColumns - колонки CSV. Каждая строка описывает вопрос и один его ответ;
+// вопрос без ответов занимает одну строку с пустыми колонками ответа. Теги перечисляются через пробел.
+var CSVColumns = []string{
+	"question_id", "title", "body", "user_id", "status", "score", "created_at", "tags",
+	"answer_id", "answer", "answer_user_id", "answer_created_at",
+}
+
+// question - представление вопроса в выгрузке.
+type question struct {
+	ID               uint       `json:"id"`
+	UserID           *uuid.UUID `json:"user_id"`
+	Title            string     `json:"title"`
+	Body             string     `json:"body"`
+	Tags             []string   `json:"tags"`
+	Status           string     `json:"status"`
+	CloseReason      string     `json:"close_reason,omitempty"`
+	DuplicateOf      *uint      `json:"duplicate_of,omitempty"`
+	AcceptedAnswerID *uint      `json:"accepted_answer_id,omitempty"`
+	Score            int        `json:"score"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	Answers          []answer   `json:"answers"`
+}
+
+// answer - представление ответа в выгрузке.
+type answer struct {
+	ID        uint      `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toQuestion(q *models.Question) question {
+	resp := question{
+		ID:               q.ID,
+		UserID:           q.UserID,
+		Title:            q.Title,
+		Body:             q.Body,
+		Tags:             append([]string{}, q.Tags...),
+		Status:           q.Status,
+		CloseReason:      q.CloseReason,
+		DuplicateOf:      q.DuplicateOf,
+		AcceptedAnswerID: q.AcceptedAnswerID,
+		Score:            q.Score,
+		CreatedAt:        q.CreatedAt,
+		UpdatedAt:        q.UpdatedAt,
+		Answers:          make([]answer, 0, len(q.Answers)),
+	}
+	for _, a := range q.Answers {
+		resp.Answers = append(resp.Answers, answer{
+			ID:        a.ID,
+			UserID:    a.UserID,
+			Text:      a.Text,
+			CreatedAt: a.CreatedAt,
+			UpdatedAt: a.UpdatedAt,
+		})
+	}
+	return resp
+}
+
+// encoder пишет вопросы в выходной поток. Close дописывает окончание выгрузки
+// и сбрасывает буфер, но не закрывает сам поток.
+type encoder interface {
+	WriteQuestion(q *models.Question) error
+	Close() error
+}
+
+// newEncoder создает encoder формата format. Вывод буферизуется, и до первого вопроса
+// (или Close) в w ничего не пишется.
+func newEncoder(w io.Writer, format Format) (encoder, error) {
+	buf := bufio.NewWriter(w)
+	switch format {
+	case FormatNDJSON:
+		return &ndjsonEncoder{buf: buf, enc: json.NewEncoder(buf)}, nil
+	case FormatJSON:
+		return &jsonEncoder{buf: buf}, nil
+	case FormatCSV:
+		return &csvEncoder{buf: buf, w: csv.NewWriter(buf)}, nil
+	case FormatMarkdown:
+		return &markdownEncoder{buf: buf}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+type ndjsonEncoder struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) WriteQuestion(q *models.Question) error {
+	return e.enc.Encode(toQuestion(q))
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return e.buf.Flush()
+}
+
+// jsonEncoder пишет массив поэлементно: открывающая скобка - с первым вопросом,
+// закрывающая - в Close.
+type jsonEncoder struct {
+	buf     *bufio.Writer
+	started bool
+}
+
+func (e *jsonEncoder) WriteQuestion(q *models.Question) error {
+	sep := ",\n"
+	if !e.started {
+		sep = "[\n"
+		e.started = true
+	}
+	if _, err := e.buf.WriteString(sep); err != nil {
+		return err
+	}
+	// json.Encoder завершает значение переводом строки, который здесь не нужен
+	data, err := json.Marshal(toQuestion(q))
+	if err != nil {
+		return err
+	}
+	_, err = e.buf.Write(data)
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	end := "\n]\n"
+	if !e.started {
+		end = "[]\n"
+	}
+	if _, err := e.buf.WriteString(end); err != nil {
+		return err
+	}
+	return e.buf.Flush()
+}
+
+type csvEncoder struct {
+	buf     *bufio.Writer
+	w       *csv.Writer
+	started bool
+}
+
+func (e *csvEncoder) WriteQuestion(q *models.Question) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	userID := ""
+	if q.UserID != nil {
+		userID = q.UserID.String()
+	}
+	row := []string{
+		strconv.FormatUint(uint64(q.ID), 10), q.Title, q.Body, userID, q.Status,
+		strconv.Itoa(q.Score), q.CreatedAt.Format(time.RFC3339Nano), strings.Join(q.Tags, " "),
+	}
+	if len(q.Answers) == 0 {
+		return e.w.Write(append(row, "", "", "", ""))
+	}
+	for _, a := range q.Answers {
+		if err := e.w.Write(append(row[:len(row):len(row)],
+			strconv.FormatUint(uint64(a.ID), 10), a.Text, a.UserID.String(), a.CreatedAt.Format(time.RFC3339Nano),
+		)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	return e.w.Write(CSVColumns)
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+	return e.buf.Flush()
+}
+
+// markdownEncoder пишет по разделу на вопрос; тело вопроса и тексты ответов уже в markdown
+// и вставляются как есть.
+type markdownEncoder struct {
+	buf *bufio.Writer
+}
+
+func (e *markdownEncoder) WriteQuestion(q *models.Question) error {
+	author := "anonymous"
+	if q.UserID != nil {
+		author = q.UserID.String()
+	}
+	fmt.Fprintf(e.buf, "# %s\n\n", q.Title)
+	fmt.Fprintf(e.buf, "*Question %d · %s · %s · %s*\n\n", q.ID, q.Status, q.CreatedAt.Format(time.RFC3339), author)
+	if len(q.Tags) > 0 {
+		fmt.Fprintf(e.buf, "Tags: `%s`\n\n", strings.Join(q.Tags, "` `"))
+	}
+	if q.Body != "" {
+		fmt.Fprintf(e.buf, "%s\n\n", q.Body)
+	}
+	for _, a := range q.Answers {
+		fmt.Fprintf(e.buf, "## Answer %d\n\n", a.ID)
+		fmt.Fprintf(e.buf, "*%s · %s*\n\n", a.CreatedAt.Format(time.RFC3339), a.UserID)
+		fmt.Fprintf(e.buf, "%s\n\n", a.Text)
+	}
+	// Ошибка записи сохраняется в bufio.Writer и возвращается любой следующей записью
+	_, err := e.buf.WriteString("---\n\n")
+	return err
+}
+
+func (e *markdownEncoder) Close() error {
+	return e.buf.Flush()
+}
@@ -0,0 +1,115 @@
+// Package exporter выгружает вопросы вместе с ответами в NDJSON, JSON, CSV или markdown.
+// Вопросы читаются из хранилища пачками и сразу пишутся в выходной поток,
+// поэтому объем памяти не зависит от размера выгрузки.
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/repository"
+)
+
+// DefaultBatchSize - сколько вопросов читается из хранилища за один запрос по умолчанию.
+const DefaultBatchSize = 500
+
+// Format - формат выгрузки.
+type Format string
+
+// Поддерживаемые форматы.
+const (
+	// FormatNDJSON - по одному JSON-объекту вопроса в строке.
+	FormatNDJSON Format = "ndjson"
+	// FormatJSON - JSON-массив вопросов.
+	FormatJSON Format = "json"
+	// FormatCSV - CSV с заголовком, по строке на ответ (см. CSVColumns).
+	FormatCSV Format = "csv"
+	// FormatMarkdown - markdown-документ, по разделу на вопрос.
+	FormatMarkdown Format = "markdown"
+)
+
+// formatInfo - тип содержимого и расширение файла для каждого формата.
+var formatInfo = map[Format]struct{ contentType, extension string }{
+	FormatNDJSON:   {"application/x-ndjson", ".ndjson"},
+	FormatJSON:     {"application/json", ".json"},
+	FormatCSV:      {"text/csv; charset=utf-8", ".csv"},
+	FormatMarkdown: {"text/markdown; charset=utf-8", ".md"},
+}
+
+// ParseFormat возвращает формат по его названию.
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(name))
+	if _, ok := formatInfo[format]; !ok {
+		return "", fmt.Errorf("unsupported export format %q: must be one of ndjson, json, csv, markdown", name)
+	}
+	return format, nil
+}
+
+// ContentType возвращает тип содержимого выгрузки в формате f.
+func (f Format) ContentType() string {
+	return formatInfo[f].contentType
+}
+
+// Extension возвращает расширение файла выгрузки в формате f.
+func (f Format) Extension() string {
+	return formatInfo[f].extension
+}
+
+// Options - параметры выгрузки.
+type Options struct {
+	Format Format
+	// Spec - фильтры выгружаемых вопросов; сортировка не учитывается, вопросы выгружаются по возрастанию ID.
+	Spec query.QuestionSpec
+	// BatchSize - сколько вопросов читать за один запрос; 0 означает DefaultBatchSize.
+	BatchSize int
+}
+
+// Exporter выгружает вопросы из хранилища.
+type Exporter struct {
+	repo   repository.Repository
+	logger *logrus.Logger
+}
+
+// New создает новый экземпляр выгрузчика.
+func New(repo repository.Repository, logger *logrus.Logger) *Exporter {
+	return &Exporter{repo: repo, logger: logger}
+}
+
+// Export пишет в w вопросы, удовлетворяющие фильтрам, и возвращает их количество.
+// Пока не прочитана первая пачка, в w ничего не пишется; ошибка после этого оставляет
+// в w неполную выгрузку.
+func (e *Exporter) Export(w io.Writer, opts Options) (int, error) {
+	e.logger.Infof("Exporting questions as %s: %+v", opts.Format, opts.Spec)
+	enc, err := newEncoder(w, opts.Format)
+	if err != nil {
+		return 0, err
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	count := 0
+	err = e.repo.FindQuestionsInBatches(opts.Spec, batchSize, func(questions []models.Question) error {
+		for i := range questions {
+			if err := enc.WriteQuestion(&questions[i]); err != nil {
+				return fmt.Errorf("failed to write question %d: %w", questions[i].ID, err)
+			}
+		}
+		count += len(questions)
+		return nil
+	})
+	if err == nil {
+		err = enc.Close()
+	}
+	if err != nil {
+		return count, err
+	}
+	e.logger.Infof("Exported %d questions", count)
+	return count, nil
+}
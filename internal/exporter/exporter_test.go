@@ -0,0 +1,168 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/repository"
+)
+
+var (
+	day1 = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	day2 = time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	day3 = time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)
+)
+
+// failingRepository возвращает ошибку после первых ok пачек.
+type failingRepository struct {
+	repository.Repository
+	ok int
+}
+
+func (r *failingRepository) FindQuestionsInBatches(
+	spec query.QuestionSpec, batchSize int, fn func([]models.Question) error,
+) error {
+	remaining := r.ok
+	err := r.Repository.FindQuestionsInBatches(spec, batchSize, func(questions []models.Question) error {
+		if remaining == 0 {
+			return errors.New("connection reset")
+		}
+		remaining--
+		return fn(questions)
+	})
+	return err
+}
+
+func newRepository(t *testing.T) repository.Repository {
+	repo := repository.NewMemoryRepository(logrus.New())
+	author := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	assert.NoError(t, repo.CreateQuestions([]*models.Question{
+		{Title: "First question", Body: "First **body**", UserID: &author, Status: models.QuestionStatusOpen,
+			Tags: models.Tags{"go", "linux"}, CreatedAt: day1, Answers: []models.Answer{
+				{UserID: author, Text: "First answer", CreatedAt: day2},
+				{UserID: author, Text: "Second answer", CreatedAt: day3},
+			}},
+		{Title: "Second question", Status: models.QuestionStatusClosed, CreatedAt: day2},
+		{Title: "Third question", Status: models.QuestionStatusOpen, CreatedAt: day3},
+	}))
+	return repo
+}
+
+func export(t *testing.T, repo repository.Repository, opts Options) string {
+	var buf bytes.Buffer
+	_, err := New(repo, logrus.New()).Export(&buf, opts)
+	assert.NoError(t, err)
+	return buf.String()
+}
+
+func TestExportNDJSON(t *testing.T) {
+	out := export(t, newRepository(t), Options{Format: FormatNDJSON, BatchSize: 2})
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	assert.Len(t, lines, 3)
+	var first question
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, uint(1), first.ID)
+	assert.Equal(t, "First **body**", first.Body)
+	assert.Equal(t, []string{"go", "linux"}, first.Tags)
+	assert.True(t, first.CreatedAt.Equal(day1))
+	assert.Len(t, first.Answers, 2)
+	assert.Equal(t, "Second answer", first.Answers[1].Text)
+	assert.Contains(t, lines[2], `"title":"Third question"`)
+	assert.Contains(t, lines[2], `"tags":[]`)
+	assert.Contains(t, lines[2], `"answers":[]`)
+}
+
+func TestExportJSON(t *testing.T) {
+	var questions []question
+	assert.NoError(t, json.Unmarshal([]byte(export(t, newRepository(t), Options{Format: FormatJSON})), &questions))
+	assert.Len(t, questions, 3)
+	assert.Equal(t, "Second question", questions[1].Title)
+
+	assert.Equal(t, "[]\n", export(t, repository.NewMemoryRepository(logrus.New()), Options{Format: FormatJSON}))
+}
+
+func TestExportCSV(t *testing.T) {
+	rows, err := csv.NewReader(strings.NewReader(export(t, newRepository(t), Options{Format: FormatCSV}))).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 5)
+	assert.Equal(t, CSVColumns, rows[0])
+	// Вопрос повторяется в строке каждого ответа
+	assert.Equal(t, []string{"1", "First question", "First **body**", "11111111-1111-1111-1111-111111111111",
+		"open", "0", "2024-01-01T10:00:00Z", "go linux", "1", "First answer", "11111111-1111-1111-1111-111111111111",
+		"2024-01-02T10:00:00Z"}, rows[1])
+	assert.Equal(t, "Second answer", rows[2][9])
+	assert.Equal(t, []string{"2", "Second question", "", "", "closed", "0", "2024-01-02T10:00:00Z", "", "", "", "", ""},
+		rows[3])
+
+	assert.Equal(t, strings.Join(CSVColumns, ",")+"\n",
+		export(t, repository.NewMemoryRepository(logrus.New()), Options{Format: FormatCSV}))
+}
+
+func TestExportMarkdown(t *testing.T) {
+	out := export(t, newRepository(t), Options{Format: FormatMarkdown})
+	assert.True(t, strings.HasPrefix(out, "# First question\n\n"+
+		"*Question 1 · open · 2024-01-01T10:00:00Z · 11111111-1111-1111-1111-111111111111*\n\n"+
+		"Tags: `go` `linux`\n\n"+
+		"First **body**\n\n"+
+		"## Answer 1\n\n"))
+	assert.Contains(t, out, "# Second question\n\n*Question 2 · closed · 2024-01-02T10:00:00Z · anonymous*\n\n---\n\n")
+}
+
+func TestExportFilters(t *testing.T) {
+	after, before := day2, day3
+	out := export(t, newRepository(t), Options{Format: FormatNDJSON, Spec: query.QuestionSpec{
+		CreatedAfter:  &after,
+		CreatedBefore: &before,
+	}})
+	assert.Equal(t, 1, strings.Count(out, "\n"))
+	assert.Contains(t, out, `"title":"Second question"`)
+
+	out = export(t, newRepository(t), Options{Format: FormatNDJSON, Spec: query.QuestionSpec{
+		Statuses: []string{models.QuestionStatusOpen},
+	}})
+	assert.Equal(t, 2, strings.Count(out, "\n"))
+	assert.NotContains(t, out, "Second question")
+
+	out = export(t, newRepository(t), Options{Format: FormatNDJSON, Spec: query.QuestionSpec{
+		Tags: []string{"linux", "go"},
+	}})
+	assert.Equal(t, 1, strings.Count(out, "\n"))
+	assert.Contains(t, out, `"title":"First question"`)
+}
+
+func TestExportRepositoryError(t *testing.T) {
+	var buf bytes.Buffer
+	count, err := New(&failingRepository{Repository: newRepository(t)}, logrus.New()).
+		Export(&buf, Options{Format: FormatJSON, BatchSize: 1})
+	assert.EqualError(t, err, "connection reset")
+	assert.Zero(t, count)
+	assert.Empty(t, buf.String(), "nothing is written before the first batch")
+
+	count, err = New(&failingRepository{Repository: newRepository(t), ok: 2}, logrus.New()).
+		Export(&buf, Options{Format: FormatNDJSON, BatchSize: 1})
+	assert.Error(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("Markdown")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatMarkdown, format)
+	assert.Equal(t, ".md", format.Extension())
+	assert.Equal(t, "text/markdown; charset=utf-8", format.ContentType())
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
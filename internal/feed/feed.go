@@ -0,0 +1,193 @@
+// Package feed строит ленты Atom 1.0 (RFC 4287) и RSS 2.0 для программ чтения лент.
+// Текст элементов экранируется при кодировании XML, поэтому HTML из markdown передается как есть.
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Типы содержимого лент.
+const (
+	ContentTypeAtom = "application/atom+xml; charset=utf-8"
+	ContentTypeRSS  = "application/rss+xml; charset=utf-8"
+)
+
+// generator - название программы, создавшей ленту.
+const generator = "question-service"
+
+// Feed - лента, общая для Atom и RSS.
+type Feed struct {
+	// ID - постоянный идентификатор ленты (IRI), SelfURL - адрес самой ленты,
+	// Link - адрес ресурса, который лента описывает.
+	ID          string
+	Title       string
+	Description string
+	Link        string
+	SelfURL     string
+	// Updated - время последнего изменения ленты, обычно самое позднее из Updated записей.
+	Updated time.Time
+	Entries []Entry
+}
+
+// Entry - запись ленты.
+type Entry struct {
+	// ID - постоянный идентификатор записи, одинаковый во всех лентах, где она встречается.
+	ID     string
+	Title  string
+	Link   string
+	Author string
+	// HTML - содержимое записи в HTML.
+	HTML string
+	// Categories - категории записи, например теги вопроса.
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     atomPerson     `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Atom кодирует ленту в формате Atom 1.0.
+func (f Feed) Atom() ([]byte, error) {
+	doc := atomFeed{
+		ID:       f.ID,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  atomTime(f.Updated),
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: f.SelfURL},
+			{Rel: "alternate", Type: "application/json", Href: f.Link},
+		},
+		Generator: generator,
+	}
+	for _, e := range f.Entries {
+		entry := atomEntry{
+			ID:        e.ID,
+			Title:     e.Title,
+			Link:      atomLink{Rel: "alternate", Type: "application/json", Href: e.Link},
+			Published: atomTime(e.Published),
+			Updated:   atomTime(e.Updated),
+			Author:    atomPerson{Name: e.Author},
+			Content:   atomContent{Type: "html", Body: e.HTML},
+		}
+		for _, category := range e.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return encode(doc)
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS кодирует ленту в формате RSS 2.0. У RSS нет времени изменения записи и имени автора
+// без адреса электронной почты, поэтому они не передаются; идентификатор записи - guid.
+func (f Feed) RSS() ([]byte, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			LastBuildDate: rssTime(f.Updated),
+			Generator:     generator,
+			Self:          atomLink{Rel: "self", Type: "application/rss+xml", Href: f.SelfURL},
+		},
+	}
+	for _, e := range f.Entries {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			GUID:        rssGUID{IsPermaLink: e.ID == e.Link, Value: e.ID},
+			PubDate:     rssTime(e.Published),
+			Categories:  e.Categories,
+			Description: e.HTML,
+		})
+	}
+	return encode(doc)
+}
+
+func encode(doc any) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func rssTime(t time.Time) string {
+	return t.UTC().Format(time.RFC1123Z)
+}
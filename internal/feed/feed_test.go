@@ -0,0 +1,66 @@
+package feed
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFeed() Feed {
+	published := time.Date(2026, 10, 18, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	return Feed{
+		ID:          "tag:qa.example.com,2026:questions",
+		Title:       "Q&A <newest>",
+		Description: "Newest questions",
+		Link:        "https://qa.example.com/api/v1/questions",
+		SelfURL:     "https://qa.example.com/api/v1/feeds/questions.atom",
+		Updated:     published.Add(time.Hour),
+		Entries: []Entry{{
+			ID:         "https://qa.example.com/api/v1/questions/1",
+			Title:      "Tabs & spaces",
+			Link:       "https://qa.example.com/api/v1/questions/1",
+			Author:     "Anonymous",
+			HTML:       "<p>a &amp; b</p>",
+			Categories: []string{"go", "c++"},
+			Published:  published,
+			Updated:    published.Add(time.Hour),
+		}},
+	}
+}
+
+func TestAtom(t *testing.T) {
+	body, err := testFeed().Atom()
+	assert.NoError(t, err)
+	doc := string(body)
+	assert.True(t, strings.HasPrefix(doc, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, doc, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, doc, `<title>Q&amp;A &lt;newest&gt;</title>`)
+	assert.Contains(t, doc, `<link rel="self" type="application/atom+xml" `+
+		`href="https://qa.example.com/api/v1/feeds/questions.atom"></link>`)
+	// Время в UTC, HTML экранирован как текст
+	assert.Contains(t, doc, `<published>2026-10-18T09:00:00Z</published>`)
+	assert.Contains(t, doc, `<updated>2026-10-18T10:00:00Z</updated>`)
+	assert.Contains(t, doc, `<content type="html">&lt;p&gt;a &amp;amp; b&lt;/p&gt;</content>`)
+	assert.Contains(t, doc, `<category term="go"></category>`)
+	assert.Contains(t, doc, `<category term="c++"></category>`)
+}
+
+func TestRSS(t *testing.T) {
+	f := testFeed()
+	body, err := f.RSS()
+	assert.NoError(t, err)
+	doc := string(body)
+	assert.Contains(t, doc, `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, doc, `<lastBuildDate>Sun, 18 Oct 2026 10:00:00 +0000</lastBuildDate>`)
+	assert.Contains(t, doc, `<guid isPermaLink="true">https://qa.example.com/api/v1/questions/1</guid>`)
+	assert.Contains(t, doc, `<pubDate>Sun, 18 Oct 2026 09:00:00 +0000</pubDate>`)
+	assert.Contains(t, doc, `<category>go</category>`)
+
+	// Идентификатор, который не является адресом записи, не помечается как постоянная ссылка
+	f.Entries[0].ID = "tag:qa.example.com,2026:question/1"
+	body, err = f.RSS()
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `<guid isPermaLink="false">tag:qa.example.com,2026:question/1</guid>`)
+}
@@ -0,0 +1,52 @@
+package graphql
+
+import (
+	"errors"
+
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// Коды ошибок в поле extensions.code ответа GraphQL.
+const (
+	codeBadUserInput      = "BAD_USER_INPUT"
+	codeNotFound          = "NOT_FOUND"
+	codeDuplicateQuestion = "DUPLICATE_QUESTION"
+	codeQuestionNotOpen   = "QUESTION_NOT_OPEN"
+	codeVersionConflict   = "VERSION_CONFLICT"
+	codeInternal          = "INTERNAL"
+)
+
+// resolverError - ошибка резолвера с кодом, по которому клиент может ее обработать.
+type resolverError struct {
+	message    string
+	extensions map[string]any
+}
+
+func newError(code, message string) *resolverError {
+	return &resolverError{message: message, extensions: map[string]any{"code": code}}
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+// Extensions возвращает поле extensions ошибки в ответе GraphQL.
+func (e *resolverError) Extensions() map[string]any {
+	return e.extensions
+}
+
+// serviceError сопоставляет ошибке сервиса код, как REST API сопоставляет ей HTTP-статус.
+func serviceError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return newError(codeNotFound, err.Error())
+	case errors.Is(err, service.ErrVersionConflict):
+		return newError(codeVersionConflict, err.Error())
+	case errors.Is(err, service.ErrQuestionNotOpen):
+		return newError(codeQuestionNotOpen, err.Error())
+	case errors.Is(err, service.ErrInvalidTags):
+		return newError(codeBadUserInput, err.Error())
+	default:
+		return newError(codeInternal, err.Error())
+	}
+}
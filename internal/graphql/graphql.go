@@ -0,0 +1,119 @@
+// Package graphql предоставляет GraphQL API над вопросами и ответами поверх service.Service.
+// Вложенные поля (ответы вопросов, вопрос ответа) загружаются пачками через загрузчики запроса,
+// поэтому список вопросов вместе с ответами читается фиксированным числом запросов к хранилищу.
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/service"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+//go:embed graphiql.html
+var graphiQLPage []byte
+
+// maxDepth ограничивает вложенность запроса, чтобы цикл вопрос -> ответы -> вопрос нельзя было
+// развернуть в сколь угодно дорогой запрос.
+const maxDepth = 8
+
+// Handler обрабатывает запросы к GraphQL API.
+type Handler struct {
+	schema       *gql.Schema
+	service      service.Service
+	logger       *logrus.Logger
+	maxBodyBytes int64
+	graphiQL     bool
+}
+
+// NewHandler создает обработчик GraphQL. maxBodyBytes ограничивает размер тела запроса,
+// graphiQL включает страницу GraphiQL (только для разработки).
+func NewHandler(s service.Service, logger *logrus.Logger, maxBodyBytes int64, graphiQL bool) *Handler {
+	schema := gql.MustParseSchema(schemaSDL,
+		&resolver{service: s, logger: logger, validate: validator.New()},
+		gql.UseStringDescriptions(),
+		gql.MaxDepth(maxDepth),
+		// Резолверы всех элементов страницы должны выполняться одновременно, чтобы попасть в одну пачку
+		gql.MaxParallelism(maxPageSize),
+	)
+	return &Handler{schema: schema, service: s, logger: logger, maxBodyBytes: maxBodyBytes, graphiQL: graphiQL}
+}
+
+// Request - тело запроса GraphQL.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Query выполняет запрос или мутацию GraphQL.
+// Ошибки выполнения возвращаются в поле errors ответа со статусом 200, как принято в GraphQL.
+// @Summary Execute a GraphQL query
+// @Description Execute a GraphQL query or mutation over questions and answers. The schema is available
+// @Description through introspection. Errors are returned in the errors field with an extensions.code.
+// @Tags graphql
+// @Accept  json
+// @Produce  json
+// @Param request body Request true "GraphQL request"
+// @Success 200 {object} object "GraphQL response with data and errors"
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 413 {string} string "Request body too large"
+// @Failure 415 {string} string "Unsupported Content-Type"
+// @Router /graphql [post]
+func (h *Handler) Query(w http.ResponseWriter, r *http.Request) {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil ||
+		mediaType != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes)).Decode(&req); err != nil {
+		h.logger.Warnf("Failed to decode GraphQL request: %v", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Query == "" {
+		http.Error(w, "query is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.service))
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	if len(resp.Errors) > 0 {
+		h.logger.Infof("GraphQL operation %q finished with %d errors", req.OperationName, len(resp.Errors))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Failed to encode GraphQL response: %v", err)
+	}
+}
+
+// GraphiQL отдает страницу GraphiQL для интерактивных запросов.
+// Страница доступна только в окружении разработки, иначе возвращается 404.
+func (h *Handler) GraphiQL(w http.ResponseWriter, r *http.Request) {
+	if !h.graphiQL {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write(graphiQLPage); err != nil {
+		h.logger.Errorf("Failed to write GraphiQL page: %v", err)
+	}
+}
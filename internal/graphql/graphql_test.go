@@ -0,0 +1,435 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/repository"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// countingService запоминает пачки ID, с которыми вызывались пакетные методы сервиса.
+type countingService struct {
+	service.Service
+	mu              sync.Mutex
+	questionBatches [][]uint
+	answerBatches   [][]uint
+}
+
+func (s *countingService) GetQuestionsByIDs(ids []uint) (map[uint]models.Question, error) {
+	s.mu.Lock()
+	s.questionBatches = append(s.questionBatches, ids)
+	s.mu.Unlock()
+	return s.Service.GetQuestionsByIDs(ids)
+}
+
+func (s *countingService) GetAnswersByQuestionIDs(questionIDs []uint) (map[uint][]models.Answer, error) {
+	s.mu.Lock()
+	s.answerBatches = append(s.answerBatches, questionIDs)
+	s.mu.Unlock()
+	return s.Service.GetAnswersByQuestionIDs(questionIDs)
+}
+
+// newTestService создает сервис с тремя вопросами; у первого и третьего есть ответы.
+func newTestService(t *testing.T) *countingService {
+	logger := logrus.New()
+	s := service.NewService(repository.NewMemoryRepository(logger), logger, nil)
+	for _, title := range []string{"How to install Go?", "Why is PostgreSQL slow?", "What is a goroutine?"} {
+		_, err := s.CreateQuestion(&models.Question{Title: title, Body: "Details about *" + title + "*"}, false)
+		assert.NoError(t, err)
+	}
+	for _, answer := range []struct {
+		questionID uint
+		text       string
+	}{{1, "Use the official installer"}, {3, "A lightweight thread"}, {1, "Use your package manager"}} {
+		assert.NoError(t, s.CreateAnswer(answer.questionID, &models.Answer{Text: answer.text}))
+	}
+	return &countingService{Service: s}
+}
+
+type gqlError struct {
+	Message    string         `json:"message"`
+	Extensions map[string]any `json:"extensions"`
+}
+
+// execute выполняет запрос через HTTP-обработчик и разбирает data в dst.
+func execute(
+	t *testing.T, h *Handler, ctx context.Context, query string, vars map[string]any, dst any,
+) []gqlError {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	assert.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)).WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	h.Query(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []gqlError      `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	if dst != nil && len(resp.Data) > 0 {
+		assert.NoError(t, json.Unmarshal(resp.Data, dst))
+	}
+	return resp.Errors
+}
+
+type pageInfoData struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor"`
+}
+
+type answerData struct {
+	ID       string `json:"id"`
+	Text     string `json:"text"`
+	Question struct {
+		Title string `json:"title"`
+	} `json:"question"`
+}
+
+type questionData struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	BodyHTML    string   `json:"bodyHtml"`
+	Tags        []string `json:"tags"`
+	AuthorID    *string  `json:"authorId"`
+	Status      string   `json:"status"`
+	AnswerCount int      `json:"answerCount"`
+	Answers     struct {
+		TotalCount int `json:"totalCount"`
+		Edges      []struct {
+			Node answerData `json:"node"`
+		} `json:"edges"`
+	} `json:"answers"`
+}
+
+type questionsData struct {
+	Questions struct {
+		TotalCount int          `json:"totalCount"`
+		PageInfo   pageInfoData `json:"pageInfo"`
+		Edges      []struct {
+			Cursor string       `json:"cursor"`
+			Node   questionData `json:"node"`
+		} `json:"edges"`
+	} `json:"questions"`
+}
+
+const questionsQuery = `query($first: Int, $after: String) {
+  questions(first: $first, after: $after, sort: OLDEST) {
+    totalCount
+    pageInfo { hasNextPage endCursor }
+    edges {
+      cursor
+      node {
+        id title bodyHtml status answerCount
+        answers { totalCount edges { node { id text question { title } } } }
+      }
+    }
+  }
+}`
+
+func TestQuestionsBatchesNestedAnswers(t *testing.T) {
+	s := newTestService(t)
+	h := NewHandler(s, logrus.New(), 1<<20, false)
+
+	var data questionsData
+	errs := execute(t, h, context.Background(), questionsQuery, map[string]any{"first": 10}, &data)
+	assert.Empty(t, errs)
+
+	questions := data.Questions
+	assert.Equal(t, 3, questions.TotalCount)
+	assert.False(t, questions.PageInfo.HasNextPage)
+	assert.Len(t, questions.Edges, 3)
+	first := questions.Edges[0].Node
+	assert.Equal(t, "How to install Go?", first.Title)
+	assert.Equal(t, "<p>Details about <em>How to install Go?</em></p>\n", first.BodyHTML)
+	assert.Equal(t, "OPEN", first.Status)
+	assert.Equal(t, 2, first.AnswerCount)
+	assert.Equal(t, 2, first.Answers.TotalCount)
+	assert.Equal(t, "Use the official installer", first.Answers.Edges[0].Node.Text)
+	assert.Equal(t, "How to install Go?", first.Answers.Edges[1].Node.Question.Title)
+	assert.Empty(t, questions.Edges[1].Node.Answers.Edges)
+
+	// Ответы всех вопросов загружены одной пачкой, а вопросы ответов взяты из уже прочитанного списка
+	assert.Len(t, s.answerBatches, 1)
+	assert.ElementsMatch(t, []uint{1, 2, 3}, s.answerBatches[0])
+	assert.Empty(t, s.questionBatches)
+}
+
+func TestQuestionsPagination(t *testing.T) {
+	h := NewHandler(newTestService(t), logrus.New(), 1<<20, false)
+
+	var page questionsData
+	assert.Empty(t, execute(t, h, context.Background(), questionsQuery, map[string]any{"first": 2}, &page))
+	assert.Equal(t, 3, page.Questions.TotalCount)
+	assert.True(t, page.Questions.PageInfo.HasNextPage)
+	assert.Len(t, page.Questions.Edges, 2)
+	assert.Equal(t, page.Questions.Edges[1].Cursor, *page.Questions.PageInfo.EndCursor)
+
+	var next questionsData
+	assert.Empty(t, execute(t, h, context.Background(), questionsQuery,
+		map[string]any{"first": 2, "after": *page.Questions.PageInfo.EndCursor}, &next))
+	assert.False(t, next.Questions.PageInfo.HasNextPage)
+	assert.Len(t, next.Questions.Edges, 1)
+	assert.Equal(t, "What is a goroutine?", next.Questions.Edges[0].Node.Title)
+
+	errs := execute(t, h, context.Background(), questionsQuery, map[string]any{"first": 2, "after": "bogus"}, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, codeBadUserInput, errs[0].Extensions["code"])
+
+	errs = execute(t, h, context.Background(), questionsQuery, map[string]any{"first": maxPageSize + 1}, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "first must be between 0 and 100", errs[0].Message)
+}
+
+func TestQuestionsFilter(t *testing.T) {
+	h := NewHandler(newTestService(t), logrus.New(), 1<<20, false)
+
+	var data questionsData
+	errs := execute(t, h, context.Background(), `{
+	  questions(filter: {hasAnswers: true, text: "go"}, sort: ANSWERS) { edges { node { title } } }
+	}`, nil, &data)
+	assert.Empty(t, errs)
+	assert.Len(t, data.Questions.Edges, 2)
+	assert.Equal(t, "How to install Go?", data.Questions.Edges[0].Node.Title)
+
+	errs = execute(t, h, context.Background(), `{
+	  questions(filter: {authorId: "nobody"}) { totalCount }
+	}`, nil, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, codeBadUserInput, errs[0].Extensions["code"])
+}
+
+func TestQuestionAndAnswerByID(t *testing.T) {
+	s := newTestService(t)
+	h := NewHandler(s, logrus.New(), 1<<20, false)
+
+	var data struct {
+		Question *questionData `json:"question"`
+		Missing  *questionData `json:"missing"`
+		Answer   *answerData   `json:"answer"`
+		NoAnswer *answerData   `json:"noAnswer"`
+	}
+	errs := execute(t, h, context.Background(), `{
+	  question(id: "1") { id title answers(first: 1) { totalCount edges { node { text } } } }
+	  missing: question(id: "42") { id }
+	  answer(id: "2") { id text question { title } }
+	  noAnswer: answer(id: "42") { id }
+	}`, nil, &data)
+	assert.Empty(t, errs)
+	assert.Equal(t, "1", data.Question.ID)
+	assert.Equal(t, 2, data.Question.Answers.TotalCount)
+	assert.Len(t, data.Question.Answers.Edges, 1)
+	assert.Nil(t, data.Missing)
+	assert.Equal(t, "What is a goroutine?", data.Answer.Question.Title)
+	assert.Nil(t, data.NoAnswer)
+
+	// Вопросы из корневого поля и из ответа загружены одной пачкой
+	assert.Len(t, s.questionBatches, 1)
+	assert.ElementsMatch(t, []uint{1, 42, 3}, s.questionBatches[0])
+}
+
+func TestDuplicateOf(t *testing.T) {
+	s := newTestService(t)
+	assert.NoError(t, s.MarkDuplicate(2, 1, uuid.New()))
+	h := NewHandler(s, logrus.New(), 1<<20, false)
+
+	var data struct {
+		Question struct {
+			Status      string `json:"status"`
+			CloseReason string `json:"closeReason"`
+			DuplicateOf struct {
+				Title string `json:"title"`
+			} `json:"duplicateOf"`
+		} `json:"question"`
+	}
+	assert.Empty(t, execute(t, h, context.Background(),
+		`{ question(id: "2") { status closeReason duplicateOf { title } } }`, nil, &data))
+	assert.Equal(t, "CLOSED", data.Question.Status)
+	assert.Equal(t, models.CloseReasonDuplicate, data.Question.CloseReason)
+	assert.Equal(t, "How to install Go?", data.Question.DuplicateOf.Title)
+}
+
+func TestQuestionMutations(t *testing.T) {
+	h := NewHandler(newTestService(t), logrus.New(), 1<<20, false)
+	author := uuid.New()
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{UserID: author, Role: auth.RoleUser})
+
+	var created struct {
+		CreateQuestion struct {
+			Question           questionData `json:"question"`
+			PossibleDuplicates []struct {
+				Question struct {
+					ID string `json:"id"`
+				} `json:"question"`
+			} `json:"possibleDuplicates"`
+		} `json:"createQuestion"`
+	}
+	errs := execute(t, h, ctx, `mutation($input: CreateQuestionInput!) {
+	  createQuestion(input: $input) {
+	    question { id title authorId status tags }
+	    possibleDuplicates { question { id } }
+	  }
+	}`, map[string]any{"input": map[string]any{
+		"title": "How do I install Go?", "body": "On Linux", "tags": []string{"Go", "linux"},
+	}}, &created)
+	assert.Empty(t, errs)
+	question := created.CreateQuestion.Question
+	assert.Equal(t, "4", question.ID)
+	assert.Equal(t, author.String(), *question.AuthorID)
+	assert.Equal(t, "OPEN", question.Status)
+	assert.Equal(t, []string{"go", "linux"}, question.Tags)
+	assert.Len(t, created.CreateQuestion.PossibleDuplicates, 1)
+	assert.Equal(t, "1", created.CreateQuestion.PossibleDuplicates[0].Question.ID)
+
+	// В строгом режиме похожий вопрос не создается
+	errs = execute(t, h, ctx, `mutation {
+	  createQuestion(input: {title: "How to install Go", strict: true}) { question { id } }
+	}`, nil, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, codeDuplicateQuestion, errs[0].Extensions["code"])
+	assert.Equal(t, []any{"1", "4"}, errs[0].Extensions["possibleDuplicates"])
+
+	errs = execute(t, h, ctx, `mutation { createQuestion(input: {title: "Go"}) { question { id } } }`, nil, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, codeBadUserInput, errs[0].Extensions["code"])
+	assert.Contains(t, errs[0].Message, "'Title' failed on the 'min' tag")
+
+	errs = execute(t, h, ctx, `mutation {
+	  createQuestion(input: {title: "Tagged question", tags: ["no spaces"]}) { question { id } }
+	}`, nil, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, codeBadUserInput, errs[0].Extensions["code"])
+
+	var tagged questionsData
+	assert.Empty(t, execute(t, h, ctx, `{
+	  questions(filter: {tags: ["LINUX"]}) { edges { node { title } } }
+	}`, nil, &tagged))
+	if assert.Len(t, tagged.Questions.Edges, 1) {
+		assert.Equal(t, "How do I install Go?", tagged.Questions.Edges[0].Node.Title)
+	}
+
+	var deleted struct {
+		DeleteQuestion struct {
+			DeletedID string `json:"deletedId"`
+		} `json:"deleteQuestion"`
+	}
+	errs = execute(t, h, ctx, `mutation { deleteQuestion(id: "4", version: 2) { deletedId } }`, nil, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, codeVersionConflict, errs[0].Extensions["code"])
+	assert.Empty(t, execute(t, h, ctx, `mutation { deleteQuestion(id: "4", version: 1) { deletedId } }`, nil, &deleted))
+	assert.Equal(t, "4", deleted.DeleteQuestion.DeletedID)
+}
+
+func TestAnswerMutations(t *testing.T) {
+	s := newTestService(t)
+	_, err := s.CloseQuestion(2, uuid.New(), "off-topic")
+	assert.NoError(t, err)
+	h := NewHandler(s, logrus.New(), 1<<20, false)
+	author := uuid.New()
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{UserID: author, Role: auth.RoleUser})
+
+	var created struct {
+		CreateAnswer struct {
+			ID       string `json:"id"`
+			AuthorID string `json:"authorId"`
+			Question struct {
+				AnswerCount int `json:"answerCount"`
+			} `json:"question"`
+		} `json:"createAnswer"`
+	}
+	errs := execute(t, h, ctx, `mutation {
+	  createAnswer(input: {questionId: "3", text: "It is scheduled by the Go runtime"}) {
+	    id authorId question { answerCount }
+	  }
+	}`, nil, &created)
+	assert.Empty(t, errs)
+	assert.Equal(t, "4", created.CreateAnswer.ID)
+	assert.Equal(t, author.String(), created.CreateAnswer.AuthorID)
+	assert.Equal(t, 2, created.CreateAnswer.Question.AnswerCount)
+
+	errs = execute(t, h, context.Background(), `mutation {
+	  createAnswer(input: {questionId: "2", text: "Too late"}) { id }
+	}`, nil, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, codeQuestionNotOpen, errs[0].Extensions["code"])
+
+	errs = execute(t, h, context.Background(), `mutation {
+	  createAnswer(input: {questionId: "3", text: "ok"}) { id }
+	}`, nil, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, codeBadUserInput, errs[0].Extensions["code"])
+
+	var deleted struct {
+		DeleteAnswer struct {
+			DeletedID string `json:"deletedId"`
+		} `json:"deleteAnswer"`
+	}
+	assert.Empty(t, execute(t, h, context.Background(),
+		`mutation { deleteAnswer(id: "4") { deletedId } }`, nil, &deleted))
+	assert.Equal(t, "4", deleted.DeleteAnswer.DeletedID)
+
+	errs = execute(t, h, context.Background(), `mutation { deleteAnswer(id: "x") { deletedId } }`, nil, nil)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, `invalid ID "x"`, errs[0].Message)
+}
+
+func TestQueryRequestErrors(t *testing.T) {
+	h := NewHandler(newTestService(t), logrus.New(), 64, false)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{"wrong content type", "text/plain", `{"query": "{ questions { totalCount } }"}`,
+			http.StatusUnsupportedMediaType},
+		{"invalid JSON", "application/json", `{"query": `, http.StatusBadRequest},
+		{"missing query", "application/json", `{"variables": {}}`, http.StatusBadRequest},
+		{"body too large", "application/json", `{"query": "` + strings.Repeat(" ", 64) + `"}`,
+			http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rr := httptest.NewRecorder()
+			h.Query(rr, req)
+			assert.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+
+	// Ошибки разбора запроса GraphQL возвращаются в теле ответа
+	errs := execute(t, NewHandler(newTestService(t), logrus.New(), 1<<20, false), context.Background(),
+		`{ questions { unknownField } }`, nil, nil)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Message, `Cannot query field "unknownField"`)
+}
+
+func TestGraphiQL(t *testing.T) {
+	rr := httptest.NewRecorder()
+	NewHandler(newTestService(t), logrus.New(), 1<<20, false).
+		GraphiQL(rr, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	NewHandler(newTestService(t), logrus.New(), 1<<20, true).
+		GraphiQL(rr, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), "GraphiQL.createFetcher")
+}
@@ -0,0 +1,108 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/dataloader"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// loaderWait - сколько загрузчик ждет другие ключи, прежде чем выполнить пачку.
+// Резолверы полей списка выполняются параллельно и успевают попасть в одну пачку.
+const loaderWait = 2 * time.Millisecond
+
+// idKey - ключ загрузчика: ID вопроса.
+type idKey uint
+
+func (k idKey) String() string   { return strconv.FormatUint(uint64(k), 10) }
+func (k idKey) Raw() interface{} { return uint(k) }
+
+// loaders собирает обращения резолверов к вопросам и ответам в пачки, чтобы вложенные поля
+// списка (ответы каждого вопроса, вопрос каждого ответа) загружались одним запросом, а не N.
+// Загрузчики создаются на каждый запрос GraphQL и кешируют результаты только в его пределах.
+type loaders struct {
+	questions *dataloader.Loader
+	answers   *dataloader.Loader
+}
+
+func newLoaders(s service.Service) *loaders {
+	return &loaders{
+		questions: dataloader.NewBatchedLoader(func(_ context.Context, keys dataloader.Keys) []*dataloader.Result {
+			ids := keyIDs(keys)
+			questions, err := s.GetQuestionsByIDs(ids)
+			return batchResults(ids, err, func(id uint) interface{} {
+				if question, ok := questions[id]; ok {
+					return &question
+				}
+				return (*models.Question)(nil)
+			})
+		}, dataloader.WithWait(loaderWait)),
+		answers: dataloader.NewBatchedLoader(func(_ context.Context, keys dataloader.Keys) []*dataloader.Result {
+			ids := keyIDs(keys)
+			answers, err := s.GetAnswersByQuestionIDs(ids)
+			return batchResults(ids, err, func(id uint) interface{} {
+				return answers[id]
+			})
+		}, dataloader.WithWait(loaderWait)),
+	}
+}
+
+// loadQuestion загружает вопрос по ID. Для несуществующего вопроса возвращается nil без ошибки.
+func (l *loaders) loadQuestion(ctx context.Context, id uint) (*models.Question, error) {
+	value, err := l.questions.Load(ctx, idKey(id))()
+	if err != nil {
+		return nil, err
+	}
+	return value.(*models.Question), nil
+}
+
+// primeQuestion кеширует уже прочитанный вопрос, чтобы ссылки на него не загружали его повторно.
+func (l *loaders) primeQuestion(ctx context.Context, question *models.Question) {
+	l.questions.Prime(ctx, idKey(question.ID), question)
+}
+
+// loadAnswers загружает ответы на вопрос в порядке создания.
+func (l *loaders) loadAnswers(ctx context.Context, questionID uint) ([]models.Answer, error) {
+	value, err := l.answers.Load(ctx, idKey(questionID))()
+	if err != nil {
+		return nil, err
+	}
+	return value.([]models.Answer), nil
+}
+
+func keyIDs(keys dataloader.Keys) []uint {
+	ids := make([]uint, len(keys))
+	for i, key := range keys {
+		ids[i] = key.Raw().(uint)
+	}
+	return ids
+}
+
+// batchResults строит результаты пачки в порядке ключей. Ошибка хранилища возвращается для каждого ключа.
+func batchResults(ids []uint, err error, value func(id uint) interface{}) []*dataloader.Result {
+	results := make([]*dataloader.Result, len(ids))
+	for i, id := range ids {
+		if err != nil {
+			results[i] = &dataloader.Result{Error: err}
+		} else {
+			results[i] = &dataloader.Result{Data: value(id)}
+		}
+	}
+	return results
+}
+
+type loadersKey struct{}
+
+// withLoaders возвращает копию контекста с загрузчиками запроса.
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFrom возвращает загрузчики запроса из контекста.
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,278 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// resolver - корневой резолвер запросов и мутаций. Все данные читаются и изменяются через service.Service,
+// поэтому GraphQL подчиняется тем же бизнес-правилам, что и REST API.
+type resolver struct {
+	service  service.Service
+	logger   *logrus.Logger
+	validate *validator.Validate
+}
+
+// Question возвращает вопрос по ID или null, если его нет.
+func (r *resolver) Question(ctx context.Context, args struct{ ID gql.ID }) (*questionResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	question, err := loadersFrom(ctx).loadQuestion(ctx, id)
+	if err != nil || question == nil {
+		return nil, err
+	}
+	return &questionResolver{q: question}, nil
+}
+
+// Answer возвращает ответ по ID или null, если его нет.
+func (r *resolver) Answer(args struct{ ID gql.ID }) (*answerResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Как и REST API, любая ошибка чтения означает, что ответа нет
+	answer, err := r.service.GetAnswer(id, query.Projection{})
+	if err != nil {
+		r.logger.Warnf("Failed to get answer with ID %d: %v", id, err)
+		return nil, nil
+	}
+	return &answerResolver{a: answer}, nil
+}
+
+// questionFilter - входной тип QuestionFilter.
+type questionFilter struct {
+	Statuses      *[]string
+	CreatedAfter  *gql.Time
+	CreatedBefore *gql.Time
+	AuthorID      *gql.ID
+	HasAnswers    *bool
+	MinAnswers    *int32
+	MaxAnswers    *int32
+	Text          *string
+	Tags          *[]string
+}
+
+// Questions возвращает страницу списка вопросов. Вопросы выбираются так же, как в GET /questions:
+// весь отфильтрованный список, из которого берется страница.
+func (r *resolver) Questions(ctx context.Context, args struct {
+	First  int32
+	After  *string
+	Filter *questionFilter
+	Sort   string
+}) (*connection[*questionResolver], error) {
+	spec, err := questionSpec(args.Filter, args.Sort)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := r.service.GetAllQuestions(spec, query.Projection{})
+	if err != nil {
+		r.logger.Errorf("Failed to get all questions: %v", err)
+		return nil, serviceError(err)
+	}
+
+	l := loadersFrom(ctx)
+	page := pageArgs{First: args.First, After: args.After}
+	return newConnection(questions, page, func(q *models.Question) *questionResolver {
+		l.primeQuestion(ctx, q)
+		return &questionResolver{q: q}
+	})
+}
+
+// questionSpec строит спецификацию списка вопросов из аргументов запроса.
+func questionSpec(filter *questionFilter, sort string) (query.QuestionSpec, error) {
+	spec := query.QuestionSpec{Sort: strings.ToLower(sort)}
+	if filter == nil {
+		return spec, nil
+	}
+	if filter.Statuses != nil {
+		for _, status := range *filter.Statuses {
+			spec.Statuses = append(spec.Statuses, strings.ToLower(status))
+		}
+	}
+	if filter.CreatedAfter != nil {
+		spec.CreatedAfter = &filter.CreatedAfter.Time
+	}
+	if filter.CreatedBefore != nil {
+		spec.CreatedBefore = &filter.CreatedBefore.Time
+	}
+	if filter.AuthorID != nil {
+		authorID, err := uuid.Parse(string(*filter.AuthorID))
+		if err != nil {
+			return spec, newError(codeBadUserInput, fmt.Sprintf("invalid authorId %q", *filter.AuthorID))
+		}
+		spec.AuthorID = &authorID
+	}
+	spec.HasAnswers = filter.HasAnswers
+	if filter.MinAnswers != nil {
+		minAnswers := int64(*filter.MinAnswers)
+		spec.MinAnswers = &minAnswers
+	}
+	if filter.MaxAnswers != nil {
+		maxAnswers := int64(*filter.MaxAnswers)
+		spec.MaxAnswers = &maxAnswers
+	}
+	if filter.Text != nil {
+		spec.Text = *filter.Text
+	}
+	if filter.Tags != nil {
+		for _, tag := range *filter.Tags {
+			name, ok := models.NormalizeTag(tag)
+			if !ok {
+				return spec, newError(codeBadUserInput, fmt.Sprintf("invalid tag %q", tag))
+			}
+			spec.Tags = append(spec.Tags, name)
+		}
+	}
+	return spec, nil
+}
+
+// createQuestionPayload резолвит тип CreateQuestionPayload.
+type createQuestionPayload struct {
+	question   *models.Question
+	duplicates []models.SimilarQuestion
+}
+
+func (p *createQuestionPayload) Question() *questionResolver { return &questionResolver{q: p.question} }
+
+func (p *createQuestionPayload) PossibleDuplicates() []*similarQuestionResolver {
+	return similarQuestions(p.duplicates)
+}
+
+// CreateQuestion создает вопрос от имени пользователя запроса; анонимный вопрос создается без автора.
+func (r *resolver) CreateQuestion(ctx context.Context, args struct {
+	Input struct {
+		Title  string
+		Body   *string
+		Tags   *[]string
+		Strict bool
+	}
+}) (*createQuestionPayload, error) {
+	question := &models.Question{Title: args.Input.Title}
+	if args.Input.Body != nil {
+		question.Body = *args.Input.Body
+	}
+	if args.Input.Tags != nil {
+		question.Tags = *args.Input.Tags
+	}
+	if err := r.validate.Struct(question); err != nil {
+		return nil, newError(codeBadUserInput, err.Error())
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		question.UserID = &identity.UserID
+	}
+
+	duplicates, err := r.service.CreateQuestion(question, args.Input.Strict)
+	if errors.Is(err, service.ErrDuplicateQuestion) {
+		r.logger.Warnf("Question rejected as possible duplicate: %v", err)
+		ids := make([]gql.ID, len(duplicates))
+		for i := range duplicates {
+			ids[i] = toID(duplicates[i].ID)
+		}
+		dupErr := newError(codeDuplicateQuestion, err.Error())
+		dupErr.extensions["possibleDuplicates"] = ids
+		return nil, dupErr
+	}
+	if err != nil {
+		r.logger.Errorf("Failed to create question: %v", err)
+		return nil, serviceError(err)
+	}
+	r.logger.Infof("Question created successfully with ID: %d", question.ID)
+	return &createQuestionPayload{question: question, duplicates: duplicates}, nil
+}
+
+// deletePayload резолвит тип DeletePayload.
+type deletePayload struct {
+	id uint
+}
+
+func (p *deletePayload) DeletedID() gql.ID { return toID(p.id) }
+
+// deleteArgs - аргументы удаления: с version запись удаляется, только если она все еще в этой версии.
+type deleteArgs struct {
+	ID      gql.ID
+	Version *int32
+}
+
+func (a deleteArgs) ifMatch() []int {
+	if a.Version == nil {
+		return nil
+	}
+	return []int{int(*a.Version)}
+}
+
+// DeleteQuestion удаляет вопрос вместе с ответами.
+func (r *resolver) DeleteQuestion(args deleteArgs) (*deletePayload, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.service.DeleteQuestion(id, args.ifMatch()); err != nil {
+		r.logger.Errorf("Failed to delete question with ID %d: %v", id, err)
+		return nil, serviceError(err)
+	}
+	r.logger.Infof("Question with ID %d deleted successfully", id)
+	return &deletePayload{id: id}, nil
+}
+
+// CreateAnswer создает ответ на открытый вопрос от имени пользователя запроса; анонимный ответ
+// получает новый ID пользователя.
+func (r *resolver) CreateAnswer(ctx context.Context, args struct {
+	Input struct {
+		QuestionID gql.ID
+		Text       string
+	}
+}) (*answerResolver, error) {
+	questionID, err := parseID(args.Input.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	answer := &models.Answer{Text: args.Input.Text}
+	if err := r.validate.Struct(answer); err != nil {
+		return nil, newError(codeBadUserInput, err.Error())
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		answer.UserID = identity.UserID
+	}
+	if err := r.service.CreateAnswer(questionID, answer); err != nil {
+		r.logger.Errorf("Failed to create answer for question ID %d: %v", questionID, err)
+		return nil, serviceError(err)
+	}
+	r.logger.Infof("Answer created successfully for question ID %d", questionID)
+	return &answerResolver{a: answer}, nil
+}
+
+// DeleteAnswer удаляет ответ.
+func (r *resolver) DeleteAnswer(args deleteArgs) (*deletePayload, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.service.DeleteAnswer(id, args.ifMatch()); err != nil {
+		r.logger.Errorf("Failed to delete answer with ID %d: %v", id, err)
+		return nil, serviceError(err)
+	}
+	r.logger.Infof("Answer with ID %d deleted successfully", id)
+	return &deletePayload{id: id}, nil
+}
+
+func similarQuestions(duplicates []models.SimilarQuestion) []*similarQuestionResolver {
+	resolvers := make([]*similarQuestionResolver, len(duplicates))
+	for i := range duplicates {
+		resolvers[i] = &similarQuestionResolver{s: &duplicates[i]}
+	}
+	return resolvers
+}
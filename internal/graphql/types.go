@@ -0,0 +1,233 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	gql "github.com/graph-gophers/graphql-go"
+
+	"github.com/shenikar/question-service/internal/markdown"
+	"github.com/shenikar/question-service/internal/models"
+)
+
+// Размер страницы списков: по умолчанию задается схемой, больше maxPageSize запросить нельзя.
+const maxPageSize = 100
+
+// questionResolver резолвит тип Question.
+type questionResolver struct {
+	q *models.Question
+}
+
+func (r *questionResolver) ID() gql.ID          { return toID(r.q.ID) }
+func (r *questionResolver) AuthorID() *gql.ID   { return uuidID(r.q.UserID) }
+func (r *questionResolver) Title() string       { return r.q.Title }
+func (r *questionResolver) Body() string        { return r.q.Body }
+func (r *questionResolver) BodyHTML() string    { return markdown.Render(r.q.Body) }
+func (r *questionResolver) Tags() []string      { return append([]string{}, r.q.Tags...) }
+func (r *questionResolver) Status() string      { return strings.ToUpper(r.q.Status) }
+func (r *questionResolver) ClosedBy() *gql.ID   { return uuidID(r.q.ClosedBy) }
+func (r *questionResolver) Score() int32        { return int32(r.q.Score) }
+func (r *questionResolver) Version() int32      { return int32(r.q.Version) }
+func (r *questionResolver) AnswerCount() int32  { return int32(r.q.AnswerCount) }
+func (r *questionResolver) CommentCount() int32 { return int32(r.q.CommentCount) }
+func (r *questionResolver) CreatedAt() gql.Time { return gql.Time{Time: r.q.CreatedAt} }
+func (r *questionResolver) UpdatedAt() gql.Time { return gql.Time{Time: r.q.UpdatedAt} }
+func (r *questionResolver) LastActivityAt() gql.Time {
+	return gql.Time{Time: r.q.LastActivityAt}
+}
+
+func (r *questionResolver) CloseReason() *string {
+	if r.q.CloseReason == "" {
+		return nil
+	}
+	return &r.q.CloseReason
+}
+
+func (r *questionResolver) ClosedAt() *gql.Time {
+	if r.q.ClosedAt == nil {
+		return nil
+	}
+	return &gql.Time{Time: *r.q.ClosedAt}
+}
+
+// DuplicateOf загружает исходный вопрос через загрузчик запроса.
+func (r *questionResolver) DuplicateOf(ctx context.Context) (*questionResolver, error) {
+	if r.q.DuplicateOf == nil {
+		return nil, nil
+	}
+	question, err := loadersFrom(ctx).loadQuestion(ctx, *r.q.DuplicateOf)
+	if err != nil || question == nil {
+		return nil, err
+	}
+	return &questionResolver{q: question}, nil
+}
+
+// AcceptedAnswer ищет принятый ответ среди ответов вопроса, загруженных вместе с ответами других вопросов.
+func (r *questionResolver) AcceptedAnswer(ctx context.Context) (*answerResolver, error) {
+	if r.q.AcceptedAnswerID == nil {
+		return nil, nil
+	}
+	answers, err := loadersFrom(ctx).loadAnswers(ctx, r.q.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range answers {
+		if answers[i].ID == *r.q.AcceptedAnswerID {
+			return &answerResolver{a: &answers[i]}, nil
+		}
+	}
+	return nil, nil
+}
+
+// Answers возвращает страницу ответов. Ответы всех вопросов страницы загружаются одним запросом.
+func (r *questionResolver) Answers(ctx context.Context, args pageArgs) (*connection[*answerResolver], error) {
+	answers, err := loadersFrom(ctx).loadAnswers(ctx, r.q.ID)
+	if err != nil {
+		return nil, err
+	}
+	return newConnection(answers, args, func(a *models.Answer) *answerResolver {
+		return &answerResolver{a: a}
+	})
+}
+
+// answerResolver резолвит тип Answer.
+type answerResolver struct {
+	a *models.Answer
+}
+
+func (r *answerResolver) ID() gql.ID          { return toID(r.a.ID) }
+func (r *answerResolver) AuthorID() gql.ID    { return gql.ID(r.a.UserID.String()) }
+func (r *answerResolver) Text() string        { return r.a.Text }
+func (r *answerResolver) TextHTML() string    { return markdown.Render(r.a.Text) }
+func (r *answerResolver) Version() int32      { return int32(r.a.Version) }
+func (r *answerResolver) CommentCount() int32 { return int32(r.a.CommentCount) }
+func (r *answerResolver) CreatedAt() gql.Time { return gql.Time{Time: r.a.CreatedAt} }
+func (r *answerResolver) UpdatedAt() gql.Time { return gql.Time{Time: r.a.UpdatedAt} }
+
+// Question загружает вопрос ответа через загрузчик запроса.
+func (r *answerResolver) Question(ctx context.Context) (*questionResolver, error) {
+	question, err := loadersFrom(ctx).loadQuestion(ctx, r.a.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	if question == nil {
+		return nil, newError(codeNotFound, fmt.Sprintf("question with ID %d not found", r.a.QuestionID))
+	}
+	return &questionResolver{q: question}, nil
+}
+
+// similarQuestionResolver резолвит тип SimilarQuestion.
+type similarQuestionResolver struct {
+	s *models.SimilarQuestion
+}
+
+func (r *similarQuestionResolver) Question() *questionResolver {
+	return &questionResolver{q: &r.s.Question}
+}
+func (r *similarQuestionResolver) Similarity() float64 { return r.s.Similarity }
+
+// pageArgs - аргументы страницы списка: first элементов после курсора after.
+type pageArgs struct {
+	First int32
+	After *string
+}
+
+// connection - страница списка в формате Relay Cursor Connections.
+// Курсор элемента - его позиция в полном списке.
+type connection[N any] struct {
+	edges   []*edge[N]
+	hasNext bool
+	total   int
+}
+
+type edge[N any] struct {
+	cursor string
+	node   N
+}
+
+// newConnection выбирает из items страницу, описанную args, и оборачивает ее элементы функцией node.
+func newConnection[T, N any](items []T, args pageArgs, node func(*T) N) (*connection[N], error) {
+	if args.First < 0 || args.First > maxPageSize {
+		return nil, newError(codeBadUserInput, fmt.Sprintf("first must be between 0 and %d", maxPageSize))
+	}
+	start := 0
+	if args.After != nil {
+		offset, err := decodeCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		start = min(offset+1, len(items))
+	}
+	end := min(start+int(args.First), len(items))
+
+	conn := &connection[N]{hasNext: end < len(items), total: len(items)}
+	for i := start; i < end; i++ {
+		conn.edges = append(conn.edges, &edge[N]{cursor: encodeCursor(i), node: node(&items[i])})
+	}
+	return conn, nil
+}
+
+func (c *connection[N]) Edges() []*edge[N] { return c.edges }
+func (c *connection[N]) TotalCount() int32 { return int32(c.total) }
+func (e *edge[N]) Cursor() string          { return e.cursor }
+func (e *edge[N]) Node() N                 { return e.node }
+
+func (c *connection[N]) PageInfo() *pageInfo {
+	info := &pageInfo{hasNextPage: c.hasNext}
+	if len(c.edges) > 0 {
+		info.endCursor = &c.edges[len(c.edges)-1].cursor
+	}
+	return info
+}
+
+// pageInfo резолвит тип PageInfo.
+type pageInfo struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (p *pageInfo) HasNextPage() bool  { return p.hasNextPage }
+func (p *pageInfo) EndCursor() *string { return p.endCursor }
+
+// cursorPrefix отличает курсоры этого API от произвольных строк.
+const cursorPrefix = "offset:"
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil && strings.HasPrefix(string(raw), cursorPrefix) {
+		offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix))
+		if err == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+	return 0, newError(codeBadUserInput, fmt.Sprintf("invalid cursor %q", cursor))
+}
+
+func toID(id uint) gql.ID {
+	return gql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+// parseID разбирает ID вопроса или ответа из аргумента запроса.
+func parseID(id gql.ID) (uint, error) {
+	parsed, err := strconv.ParseUint(string(id), 10, 0)
+	if err != nil || parsed == 0 {
+		return 0, newError(codeBadUserInput, fmt.Sprintf("invalid ID %q", id))
+	}
+	return uint(parsed), nil
+}
+
+func uuidID(id *uuid.UUID) *gql.ID {
+	if id == nil {
+		return nil
+	}
+	value := gql.ID(id.String())
+	return &value
+}
@@ -0,0 +1,57 @@
+package grpcapi
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// errorDomain - домен причин ошибок в errdetails.ErrorInfo.
+const errorDomain = "question.v1"
+
+// serviceError сопоставляет ошибке сервиса код gRPC, как REST API сопоставляет ей HTTP-статус.
+func serviceError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, service.ErrQuestionNotOpen):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrInvalidTags):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// duplicateError - ошибка строгого режима CreateQuestion. ID похожих вопросов передаются
+// в деталях ошибки (errdetails.ErrorInfo, метаданные possible_duplicates через запятую).
+func duplicateError(err error, duplicates []models.SimilarQuestion) error {
+	ids := make([]string, len(duplicates))
+	for i := range duplicates {
+		ids[i] = strconv.FormatUint(uint64(duplicates[i].ID), 10)
+	}
+	st, detailsErr := status.New(codes.AlreadyExists, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason:   "DUPLICATE_QUESTION",
+		Domain:   errorDomain,
+		Metadata: map[string]string{"possible_duplicates": strings.Join(ids, ",")},
+	})
+	if detailsErr != nil {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return st.Err()
+}
+
+// invalidArgument - ошибка некорректного запроса.
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
@@ -0,0 +1,35 @@
+// Package grpcapi предоставляет gRPC API над вопросами и ответами поверх service.Service.
+// Описание API - api/question/v1/question.proto. Кроме QuestionService сервер отвечает на проверки
+// grpc.health.v1.Health и поддерживает server reflection, чтобы с ним можно было работать через grpcurl.
+package grpcapi
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	questionv1 "github.com/shenikar/question-service/api/question/v1"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// NewServer создает gRPC-сервер с QuestionService, сервисом проверки здоровья и reflection.
+// Сервер использует тот же экземпляр service.Service, что и HTTP-обработчики.
+func NewServer(s service.Service, logger *logrus.Logger) *grpc.Server {
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(authInterceptor, loggingInterceptor(logger)))
+	questionv1.RegisterQuestionServiceServer(srv, &questionServer{
+		service:  s,
+		logger:   logger,
+		validate: validator.New(),
+	})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(questionv1.QuestionService_ServiceDesc.ServiceName,
+		healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthServer)
+
+	reflection.Register(srv)
+	return srv
+}
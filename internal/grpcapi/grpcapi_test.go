@@ -0,0 +1,287 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	questionv1 "github.com/shenikar/question-service/api/question/v1"
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/repository"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// newTestConn запускает gRPC-сервер поверх сервиса в памяти на bufconn и возвращает подключение к нему.
+func newTestConn(t *testing.T) (*grpc.ClientConn, service.Service) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	s := service.NewService(repository.NewMemoryRepository(logger), logger, nil)
+
+	listener := bufconn.Listen(1 << 20)
+	srv := NewServer(s, logger)
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, s
+}
+
+// asUser возвращает контекст с пользователем в метаданных запроса.
+func asUser(userID uuid.UUID, role string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(),
+		metadataUserID, userID.String(), metadataUserRole, role)
+}
+
+func TestCreateAndGetQuestion(t *testing.T) {
+	conn, _ := newTestConn(t)
+	client := questionv1.NewQuestionServiceClient(conn)
+	userID := uuid.New()
+
+	created, err := client.CreateQuestion(asUser(userID, auth.RoleUser),
+		&questionv1.CreateQuestionRequest{Title: "How to install Go?", Body: "On *Linux*"})
+	assert.NoError(t, err)
+	assert.Equal(t, userID.String(), created.GetQuestion().GetAuthorId())
+	assert.Equal(t, questionv1.QuestionStatus_QUESTION_STATUS_OPEN, created.GetQuestion().GetStatus())
+	assert.Equal(t, "<p>On <em>Linux</em></p>\n", created.GetQuestion().GetBodyHtml())
+	assert.Empty(t, created.GetPossibleDuplicates())
+
+	questionID := created.GetQuestion().GetId()
+	answerer := uuid.New()
+	answer, err := client.CreateAnswer(asUser(answerer, auth.RoleUser),
+		&questionv1.CreateAnswerRequest{QuestionId: questionID, Text: "Use the official installer"})
+	assert.NoError(t, err)
+	assert.Equal(t, questionID, answer.GetQuestionId())
+	assert.Equal(t, answerer.String(), answer.GetAuthorId())
+
+	// Анонимный ответ получает новый ID пользователя
+	anonymous, err := client.CreateAnswer(context.Background(),
+		&questionv1.CreateAnswerRequest{QuestionId: questionID, Text: "Download the archive"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, answerer.String(), anonymous.GetAuthorId())
+	assert.NotEqual(t, uuid.Nil.String(), anonymous.GetAuthorId())
+
+	question, err := client.GetQuestion(context.Background(), &questionv1.GetQuestionRequest{Id: questionID})
+	assert.NoError(t, err)
+	assert.Equal(t, "How to install Go?", question.GetTitle())
+	assert.Equal(t, int64(2), question.GetAnswerCount())
+	if assert.Len(t, question.GetAnswers(), 2) {
+		assert.Equal(t, answer.GetId(), question.GetAnswers()[0].GetId())
+	}
+
+	got, err := client.GetAnswer(context.Background(), &questionv1.GetAnswerRequest{Id: answer.GetId()})
+	assert.NoError(t, err)
+	assert.Equal(t, "Use the official installer", got.GetText())
+}
+
+func TestCreateQuestionValidation(t *testing.T) {
+	conn, _ := newTestConn(t)
+	client := questionv1.NewQuestionServiceClient(conn)
+
+	_, err := client.CreateQuestion(context.Background(), &questionv1.CreateQuestionRequest{Title: "Go"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.CreateQuestion(context.Background(),
+		&questionv1.CreateQuestionRequest{Title: "How to install Go?", Tags: []string{"no spaces"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCreateQuestionStrictDuplicate(t *testing.T) {
+	conn, _ := newTestConn(t)
+	client := questionv1.NewQuestionServiceClient(conn)
+
+	original, err := client.CreateQuestion(context.Background(),
+		&questionv1.CreateQuestionRequest{Title: "How to install Go on Linux?"})
+	assert.NoError(t, err)
+
+	_, err = client.CreateQuestion(context.Background(),
+		&questionv1.CreateQuestionRequest{Title: "How to install Go on Linux", Strict: true})
+	st := status.Convert(err)
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.True(t, ok)
+		assert.Equal(t, "DUPLICATE_QUESTION", info.GetReason())
+		assert.Equal(t, "1", info.GetMetadata()["possible_duplicates"])
+	}
+	assert.Equal(t, uint64(1), original.GetQuestion().GetId())
+}
+
+func TestListQuestions(t *testing.T) {
+	conn, s := newTestConn(t)
+	client := questionv1.NewQuestionServiceClient(conn)
+	for _, req := range []*questionv1.CreateQuestionRequest{
+		{Title: "How to install Go?", Tags: []string{"go", "linux"}},
+		{Title: "Why is PostgreSQL slow?", Tags: []string{"postgresql"}},
+		{Title: "What is a goroutine?", Tags: []string{"go", "concurrency"}},
+	} {
+		_, err := client.CreateQuestion(context.Background(), req)
+		assert.NoError(t, err)
+	}
+	_, err := s.CloseQuestion(2, uuid.New(), "off-topic")
+	assert.NoError(t, err)
+
+	resp, err := client.ListQuestions(context.Background(), &questionv1.ListQuestionsRequest{
+		Statuses: []questionv1.QuestionStatus{questionv1.QuestionStatus_QUESTION_STATUS_OPEN},
+		Sort:     questionv1.QuestionSort_QUESTION_SORT_OLDEST,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, resp.GetQuestions(), 2) {
+		assert.Equal(t, uint64(1), resp.GetQuestions()[0].GetId())
+		assert.Equal(t, uint64(3), resp.GetQuestions()[1].GetId())
+	}
+
+	resp, err = client.ListQuestions(context.Background(), &questionv1.ListQuestionsRequest{Text: "postgresql"})
+	assert.NoError(t, err)
+	if assert.Len(t, resp.GetQuestions(), 1) {
+		assert.Equal(t, questionv1.QuestionStatus_QUESTION_STATUS_CLOSED, resp.GetQuestions()[0].GetStatus())
+		assert.Equal(t, "off-topic", resp.GetQuestions()[0].GetCloseReason())
+	}
+
+	resp, err = client.ListQuestions(context.Background(),
+		&questionv1.ListQuestionsRequest{Tags: []string{"Go", "concurrency"}})
+	assert.NoError(t, err)
+	if assert.Len(t, resp.GetQuestions(), 1) {
+		assert.Equal(t, []string{"go", "concurrency"}, resp.GetQuestions()[0].GetTags())
+	}
+}
+
+func TestListQuestionsInvalidArguments(t *testing.T) {
+	conn, _ := newTestConn(t)
+	client := questionv1.NewQuestionServiceClient(conn)
+
+	for name, req := range map[string]*questionv1.ListQuestionsRequest{
+		"answer range": {MinAnswers: proto.Int64(3), MaxAnswers: proto.Int64(1)},
+		"author":       {AuthorId: "not-a-uuid"},
+		"status":       {Statuses: []questionv1.QuestionStatus{questionv1.QuestionStatus_QUESTION_STATUS_UNSPECIFIED}},
+		"sort":         {Sort: questionv1.QuestionSort(42)},
+		"tags":         {Tags: []string{"no spaces"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.ListQuestions(context.Background(), req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestUpdateQuestionRequiresModerator(t *testing.T) {
+	conn, _ := newTestConn(t)
+	client := questionv1.NewQuestionServiceClient(conn)
+	_, err := client.CreateQuestion(context.Background(),
+		&questionv1.CreateQuestionRequest{Title: "How to install Go?"})
+	assert.NoError(t, err)
+	req := &questionv1.UpdateQuestionRequest{Id: 1, Title: proto.String("How to install Go 1.24?")}
+
+	_, err = client.UpdateQuestion(context.Background(), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.UpdateQuestion(asUser(uuid.New(), auth.RoleUser), req)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	updated, err := client.UpdateQuestion(asUser(uuid.New(), auth.RoleModerator), req)
+	assert.NoError(t, err)
+	assert.Equal(t, "How to install Go 1.24?", updated.GetTitle())
+	assert.Equal(t, int64(2), updated.GetVersion())
+}
+
+func TestVersionConflict(t *testing.T) {
+	conn, _ := newTestConn(t)
+	client := questionv1.NewQuestionServiceClient(conn)
+	created, err := client.CreateQuestion(context.Background(),
+		&questionv1.CreateQuestionRequest{Title: "How to install Go?"})
+	assert.NoError(t, err)
+	answer, err := client.CreateAnswer(context.Background(),
+		&questionv1.CreateAnswerRequest{QuestionId: created.GetQuestion().GetId(), Text: "Use the installer"})
+	assert.NoError(t, err)
+
+	_, err = client.UpdateAnswer(asUser(uuid.New(), auth.RoleModerator),
+		&questionv1.UpdateAnswerRequest{Id: answer.GetId(), Text: "Use the installer", Version: proto.Int64(7)})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	_, err = client.DeleteQuestion(context.Background(),
+		&questionv1.DeleteQuestionRequest{Id: created.GetQuestion().GetId(), Version: proto.Int64(7)})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	_, err = client.DeleteAnswer(context.Background(),
+		&questionv1.DeleteAnswerRequest{Id: answer.GetId(), Version: proto.Int64(answer.GetVersion())})
+	assert.NoError(t, err)
+	_, err = client.DeleteQuestion(context.Background(),
+		&questionv1.DeleteQuestionRequest{Id: created.GetQuestion().GetId(), Version: proto.Int64(1)})
+	assert.NoError(t, err)
+
+	_, err = client.GetQuestion(context.Background(), &questionv1.GetQuestionRequest{Id: created.GetQuestion().GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCreateAnswerErrors(t *testing.T) {
+	conn, s := newTestConn(t)
+	client := questionv1.NewQuestionServiceClient(conn)
+	_, err := s.CreateQuestion(&models.Question{Title: "How to install Go?"}, false)
+	assert.NoError(t, err)
+	_, err = s.LockQuestion(1, uuid.New())
+	assert.NoError(t, err)
+
+	_, err = client.CreateAnswer(context.Background(),
+		&questionv1.CreateAnswerRequest{QuestionId: 1, Text: "Use the official installer"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.CreateAnswer(context.Background(),
+		&questionv1.CreateAnswerRequest{QuestionId: 42, Text: "Use the official installer"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.CreateAnswer(context.Background(), &questionv1.CreateAnswerRequest{QuestionId: 0, Text: "Text"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestInvalidUserMetadata(t *testing.T) {
+	conn, _ := newTestConn(t)
+	client := questionv1.NewQuestionServiceClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), metadataUserID, "not-a-uuid")
+	_, err := client.CreateQuestion(ctx, &questionv1.CreateQuestionRequest{Title: "How to install Go?"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestHealthAndReflection(t *testing.T) {
+	conn, _ := newTestConn(t)
+
+	health, err := healthpb.NewHealthClient(conn).Check(context.Background(),
+		&healthpb.HealthCheckRequest{Service: questionv1.QuestionService_ServiceDesc.ServiceName})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.GetStatus())
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	assert.NoError(t, err)
+	var services []string
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		services = append(services, svc.GetName())
+	}
+	assert.Contains(t, services, questionv1.QuestionService_ServiceDesc.ServiceName)
+	assert.Contains(t, services, healthpb.Health_ServiceDesc.ServiceName)
+}
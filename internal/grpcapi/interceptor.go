@@ -0,0 +1,74 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/shenikar/question-service/internal/auth"
+)
+
+// Ключи метаданных с пользователем запроса. Ключи метаданных gRPC всегда в нижнем регистре,
+// поэтому это те же заголовки X-User-ID и X-User-Role, что выставляет шлюз для HTTP.
+var (
+	metadataUserID   = strings.ToLower(auth.HeaderUserID)
+	metadataUserRole = strings.ToLower(auth.HeaderUserRole)
+)
+
+// authInterceptor сохраняет пользователя из метаданных запроса в контексте, как auth.Middleware для HTTP.
+// Запросы без x-user-id считаются анонимными, некорректный x-user-id отклоняется с UNAUTHENTICATED.
+func authInterceptor(
+	ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	rawID := firstValue(md, metadataUserID)
+	if rawID == "" {
+		return handler(ctx, req)
+	}
+
+	userID, err := uuid.Parse(rawID)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid %s metadata", metadataUserID)
+	}
+	role := firstValue(md, metadataUserRole)
+	if role == "" {
+		role = auth.RoleUser
+	}
+	return handler(auth.WithIdentity(ctx, auth.Identity{UserID: userID, Role: role}), req)
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// requireRole проверяет, что запрос выполняет аутентифицированный пользователь с указанной ролью.
+func requireRole(ctx context.Context, role string) error {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	if identity.Role != role {
+		return status.Error(codes.PermissionDenied, "forbidden")
+	}
+	return nil
+}
+
+// loggingInterceptor пишет в лог метод, код ответа и длительность каждого вызова.
+func loggingInterceptor(logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logger.Infof("gRPC %s finished with %s in %v", info.FullMethod, status.Code(err), time.Since(start))
+		return resp, err
+	}
+}
@@ -0,0 +1,175 @@
+package grpcapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	questionv1 "github.com/shenikar/question-service/api/question/v1"
+	"github.com/shenikar/question-service/internal/markdown"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+)
+
+// Префиксы значений перечислений в protobuf; после префикса идет значение модели в верхнем регистре.
+const (
+	statusPrefix = "QUESTION_STATUS_"
+	sortPrefix   = "QUESTION_SORT_"
+)
+
+// toQuestion преобразует модель вопроса в сообщение protobuf.
+func toQuestion(q *models.Question) *questionv1.Question {
+	// Только что созданный вопрос еще не прочитан из хранилища, и вычисляемые поля пусты
+	lastActivityAt := q.LastActivityAt
+	if lastActivityAt.IsZero() {
+		lastActivityAt = q.CreatedAt
+	}
+
+	msg := &questionv1.Question{
+		Id:               uint64(q.ID),
+		AuthorId:         uuidString(q.UserID),
+		Title:            q.Title,
+		Body:             q.Body,
+		BodyHtml:         markdown.Render(q.Body),
+		Status:           toStatus(q.Status),
+		CloseReason:      q.CloseReason,
+		ClosedBy:         uuidString(q.ClosedBy),
+		ClosedAt:         timestamp(q.ClosedAt),
+		DuplicateOf:      optionalID(q.DuplicateOf),
+		AcceptedAnswerId: optionalID(q.AcceptedAnswerID),
+		Score:            int64(q.Score),
+		Version:          int64(q.Version),
+		AnswerCount:      q.AnswerCount,
+		CommentCount:     q.CommentCount,
+		CreatedAt:        timestamppb.New(q.CreatedAt),
+		UpdatedAt:        timestamppb.New(q.UpdatedAt),
+		LastActivityAt:   timestamppb.New(lastActivityAt),
+		Tags:             q.Tags,
+	}
+	for i := range q.Answers {
+		msg.Answers = append(msg.Answers, toAnswer(&q.Answers[i]))
+	}
+	return msg
+}
+
+// toAnswer преобразует модель ответа в сообщение protobuf.
+func toAnswer(a *models.Answer) *questionv1.Answer {
+	return &questionv1.Answer{
+		Id:           uint64(a.ID),
+		QuestionId:   uint64(a.QuestionID),
+		AuthorId:     a.UserID.String(),
+		Text:         a.Text,
+		TextHtml:     markdown.Render(a.Text),
+		Version:      int64(a.Version),
+		CommentCount: a.CommentCount,
+		CreatedAt:    timestamppb.New(a.CreatedAt),
+		UpdatedAt:    timestamppb.New(a.UpdatedAt),
+	}
+}
+
+func toSimilarQuestions(duplicates []models.SimilarQuestion) []*questionv1.SimilarQuestion {
+	msgs := make([]*questionv1.SimilarQuestion, len(duplicates))
+	for i := range duplicates {
+		msgs[i] = &questionv1.SimilarQuestion{
+			Question:   toQuestion(&duplicates[i].Question),
+			Similarity: duplicates[i].Similarity,
+		}
+	}
+	return msgs
+}
+
+func toStatus(status string) questionv1.QuestionStatus {
+	return questionv1.QuestionStatus(questionv1.QuestionStatus_value[statusPrefix+strings.ToUpper(status)])
+}
+
+func uuidString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func optionalID(id *uint) *uint64 {
+	if id == nil {
+		return nil
+	}
+	value := uint64(*id)
+	return &value
+}
+
+// questionSpec строит спецификацию списка вопросов из запроса ListQuestions
+// и проверяет ее так же, как query.ParseQuestionSpec проверяет параметры GET /questions.
+func questionSpec(req *questionv1.ListQuestionsRequest) (query.QuestionSpec, error) {
+	spec := query.QuestionSpec{
+		HasAnswers: req.HasAnswers,
+		MinAnswers: req.MinAnswers,
+		MaxAnswers: req.MaxAnswers,
+		Text:       strings.TrimSpace(req.GetText()),
+		Sort:       query.SortNewest,
+	}
+
+	if sort := req.GetSort(); sort != questionv1.QuestionSort_QUESTION_SORT_UNSPECIFIED {
+		if _, ok := questionv1.QuestionSort_name[int32(sort)]; !ok {
+			return spec, fmt.Errorf("sort: unknown value %d", sort)
+		}
+		spec.Sort = strings.ToLower(strings.TrimPrefix(sort.String(), sortPrefix))
+	}
+	for _, status := range req.GetStatuses() {
+		if _, ok := questionv1.QuestionStatus_name[int32(status)]; !ok ||
+			status == questionv1.QuestionStatus_QUESTION_STATUS_UNSPECIFIED {
+			return spec, fmt.Errorf("statuses: invalid value %d", status)
+		}
+		spec.Statuses = append(spec.Statuses, strings.ToLower(strings.TrimPrefix(status.String(), statusPrefix)))
+	}
+	if req.CreatedAfter != nil {
+		createdAfter := req.GetCreatedAfter().AsTime()
+		spec.CreatedAfter = &createdAfter
+	}
+	if req.CreatedBefore != nil {
+		createdBefore := req.GetCreatedBefore().AsTime()
+		spec.CreatedBefore = &createdBefore
+	}
+	if req.GetAuthorId() != "" {
+		authorID, err := uuid.Parse(req.GetAuthorId())
+		if err != nil {
+			return spec, fmt.Errorf("author_id: invalid UUID %q", req.GetAuthorId())
+		}
+		spec.AuthorID = &authorID
+	}
+	for _, tag := range req.GetTags() {
+		name, ok := models.NormalizeTag(tag)
+		if !ok {
+			return spec, fmt.Errorf("tags: invalid tag %q", tag)
+		}
+		spec.Tags = append(spec.Tags, name)
+	}
+
+	switch {
+	case spec.MinAnswers != nil && *spec.MinAnswers < 0, spec.MaxAnswers != nil && *spec.MaxAnswers < 0:
+		return spec, errors.New("min_answers and max_answers must not be negative")
+	case spec.MinAnswers != nil && spec.MaxAnswers != nil && *spec.MinAnswers > *spec.MaxAnswers:
+		return spec, errors.New("max_answers: must not be less than min_answers")
+	case spec.CreatedAfter != nil && spec.CreatedBefore != nil && !spec.CreatedAfter.Before(*spec.CreatedBefore):
+		return spec, errors.New("created_before: must be later than created_after")
+	}
+	return spec, nil
+}
+
+// ifMatch преобразует необязательную версию запроса в условие для сервиса; nil означает отсутствие условия.
+func ifMatch(version *int64) []int {
+	if version == nil {
+		return nil
+	}
+	return []int{int(*version)}
+}
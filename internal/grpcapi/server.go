@@ -0,0 +1,226 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	questionv1 "github.com/shenikar/question-service/api/question/v1"
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// questionServer - реализация QuestionService. Все данные читаются и изменяются через service.Service,
+// поэтому gRPC API подчиняется тем же бизнес-правилам, что и REST API.
+type questionServer struct {
+	questionv1.UnimplementedQuestionServiceServer
+	service  service.Service
+	logger   *logrus.Logger
+	validate *validator.Validate
+}
+
+// CreateQuestion создает вопрос от имени пользователя запроса; анонимный вопрос создается без автора.
+func (s *questionServer) CreateQuestion(
+	ctx context.Context, req *questionv1.CreateQuestionRequest,
+) (*questionv1.CreateQuestionResponse, error) {
+	question := &models.Question{Title: req.GetTitle(), Body: req.GetBody(), Tags: req.GetTags()}
+	if err := s.validate.Struct(question); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		question.UserID = &identity.UserID
+	}
+
+	duplicates, err := s.service.CreateQuestion(question, req.GetStrict())
+	if errors.Is(err, service.ErrDuplicateQuestion) {
+		s.logger.Warnf("Question rejected as possible duplicate: %v", err)
+		return nil, duplicateError(err, duplicates)
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to create question: %v", err)
+		return nil, serviceError(err)
+	}
+	s.logger.Infof("Question created successfully with ID: %d", question.ID)
+	return &questionv1.CreateQuestionResponse{
+		Question:           toQuestion(question),
+		PossibleDuplicates: toSimilarQuestions(duplicates),
+	}, nil
+}
+
+// GetQuestion возвращает вопрос вместе с ответами.
+func (s *questionServer) GetQuestion(
+	_ context.Context, req *questionv1.GetQuestionRequest,
+) (*questionv1.Question, error) {
+	id, err := toID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	question, err := s.service.GetQuestion(id, query.Projection{Include: []string{query.IncludeAnswers}})
+	if err != nil {
+		// Как и REST API, любая ошибка чтения означает, что вопроса нет
+		s.logger.Errorf("Failed to get question with ID %d: %v", id, err)
+		return nil, status.Errorf(codes.NotFound, "question with ID %d not found", id)
+	}
+	return toQuestion(question), nil
+}
+
+// ListQuestions возвращает вопросы, удовлетворяющие фильтрам, без ответов.
+func (s *questionServer) ListQuestions(
+	_ context.Context, req *questionv1.ListQuestionsRequest,
+) (*questionv1.ListQuestionsResponse, error) {
+	spec, err := questionSpec(req)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	questions, err := s.service.GetAllQuestions(spec, query.Projection{})
+	if err != nil {
+		s.logger.Errorf("Failed to get all questions: %v", err)
+		return nil, serviceError(err)
+	}
+
+	resp := &questionv1.ListQuestionsResponse{Questions: make([]*questionv1.Question, len(questions))}
+	for i := range questions {
+		resp.Questions[i] = toQuestion(&questions[i])
+	}
+	return resp, nil
+}
+
+// UpdateQuestion изменяет заголовок и (или) тело вопроса. Доступно только модераторам.
+func (s *questionServer) UpdateQuestion(
+	ctx context.Context, req *questionv1.UpdateQuestionRequest,
+) (*questionv1.Question, error) {
+	if err := requireRole(ctx, auth.RoleModerator); err != nil {
+		return nil, err
+	}
+	id, err := toID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if req.Title == nil && req.Body == nil {
+		return nil, status.Error(codes.InvalidArgument, "nothing to update")
+	}
+	fields := struct {
+		Title *string `validate:"omitnil,min=3,max=250"`
+		Body  *string `validate:"omitnil,max=30000"`
+	}{Title: req.Title, Body: req.Body}
+	if err := s.validate.Struct(&fields); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	question, err := s.service.UpdateQuestion(id, ifMatch(req.Version), req.Title, req.Body)
+	if err != nil {
+		s.logger.Errorf("Failed to update question with ID %d: %v", id, err)
+		return nil, serviceError(err)
+	}
+	s.logger.Infof("Question with ID %d updated to version %d", id, question.Version)
+	return toQuestion(question), nil
+}
+
+// DeleteQuestion удаляет вопрос вместе с ответами.
+func (s *questionServer) DeleteQuestion(
+	_ context.Context, req *questionv1.DeleteQuestionRequest,
+) (*questionv1.DeleteQuestionResponse, error) {
+	id, err := toID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.DeleteQuestion(id, ifMatch(req.Version)); err != nil {
+		s.logger.Errorf("Failed to delete question with ID %d: %v", id, err)
+		return nil, serviceError(err)
+	}
+	s.logger.Infof("Question with ID %d deleted successfully", id)
+	return &questionv1.DeleteQuestionResponse{}, nil
+}
+
+// CreateAnswer создает ответ на открытый вопрос от имени пользователя запроса; анонимный ответ
+// получает новый ID пользователя.
+func (s *questionServer) CreateAnswer(
+	ctx context.Context, req *questionv1.CreateAnswerRequest,
+) (*questionv1.Answer, error) {
+	questionID, err := toID(req.GetQuestionId())
+	if err != nil {
+		return nil, err
+	}
+	answer := &models.Answer{Text: req.GetText()}
+	if err := s.validate.Struct(answer); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		answer.UserID = identity.UserID
+	}
+	if err := s.service.CreateAnswer(questionID, answer); err != nil {
+		s.logger.Errorf("Failed to create answer for question ID %d: %v", questionID, err)
+		return nil, serviceError(err)
+	}
+	s.logger.Infof("Answer created successfully for question ID %d", questionID)
+	return toAnswer(answer), nil
+}
+
+// GetAnswer возвращает ответ.
+func (s *questionServer) GetAnswer(_ context.Context, req *questionv1.GetAnswerRequest) (*questionv1.Answer, error) {
+	id, err := toID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	answer, err := s.service.GetAnswer(id, query.Projection{})
+	if err != nil {
+		// Как и REST API, любая ошибка чтения означает, что ответа нет
+		s.logger.Errorf("Failed to get answer with ID %d: %v", id, err)
+		return nil, status.Errorf(codes.NotFound, "answer with ID %d not found", id)
+	}
+	return toAnswer(answer), nil
+}
+
+// UpdateAnswer изменяет текст ответа. Доступно только модераторам.
+func (s *questionServer) UpdateAnswer(
+	ctx context.Context, req *questionv1.UpdateAnswerRequest,
+) (*questionv1.Answer, error) {
+	if err := requireRole(ctx, auth.RoleModerator); err != nil {
+		return nil, err
+	}
+	id, err := toID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate.Var(req.GetText(), "required,min=3,max=10000"); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	answer, err := s.service.UpdateAnswer(id, ifMatch(req.Version), req.GetText())
+	if err != nil {
+		s.logger.Errorf("Failed to update answer with ID %d: %v", id, err)
+		return nil, serviceError(err)
+	}
+	s.logger.Infof("Answer with ID %d updated to version %d", id, answer.Version)
+	return toAnswer(answer), nil
+}
+
+// DeleteAnswer удаляет ответ.
+func (s *questionServer) DeleteAnswer(
+	_ context.Context, req *questionv1.DeleteAnswerRequest,
+) (*questionv1.DeleteAnswerResponse, error) {
+	id, err := toID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.DeleteAnswer(id, ifMatch(req.Version)); err != nil {
+		s.logger.Errorf("Failed to delete answer with ID %d: %v", id, err)
+		return nil, serviceError(err)
+	}
+	s.logger.Infof("Answer with ID %d deleted successfully", id)
+	return &questionv1.DeleteAnswerResponse{}, nil
+}
+
+// toID проверяет ID из запроса: ID начинаются с 1 и помещаются в uint.
+func toID(id uint64) (uint, error) {
+	if id == 0 || uint64(uint(id)) != id {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid ID %d", id)
+	}
+	return uint(id), nil
+}
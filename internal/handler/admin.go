@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/exporter"
+	"github.com/shenikar/question-service/internal/importer"
+	"github.com/shenikar/question-service/internal/query"
+)
+
+// AdminHandler обрабатывает административные HTTP-запросы.
+type AdminHandler struct {
+	importer       *importer.Importer
+	exporter       *exporter.Exporter
+	logger         *logrus.Logger
+	maxImportBytes int64
+}
+
+// NewAdminHandler создает новый экземпляр обработчика административных запросов.
+// maxImportBytes ограничивает размер тела запроса на импорт.
+func NewAdminHandler(
+	imp *importer.Importer, exp *exporter.Exporter, logger *logrus.Logger, maxImportBytes int64,
+) *AdminHandler {
+	return &AdminHandler{importer: imp, exporter: exp, logger: logger, maxImportBytes: maxImportBytes}
+}
+
+// importContentTypes - форматы импорта, соответствующие Content-Type тела запроса.
+var importContentTypes = map[string]importer.Format{
+	"application/x-ndjson": importer.FormatNDJSON,
+	"application/json":     importer.FormatJSON,
+	"text/csv":             importer.FormatCSV,
+}
+
+// Import импортирует вопросы с ответами из тела запроса.
+// @Summary Import questions
+// @Description Import questions with their answers from NDJSON (one question per line), a JSON array of
+// @Description questions or CSV. Every record is validated with the same rules as questions and answers created
+// @Description through the API; questions whose title already exists are skipped. Records are saved in batches,
+// @Description each batch in its own transaction, and the report lists the outcome of every record.
+// @Description Requires the moderator role.
+// @Tags admin
+// @Accept json,application/x-ndjson,text/csv
+// @Produce json
+// @Param data body string true "Questions to import"
+// @Param format query string false "Input format, by default derived from Content-Type" Enums(ndjson, json, csv)
+// @Param dry_run query bool false "Only validate the records, do not save them"
+// @Success 200 {object} ImportReportResponse
+// @Failure 400 {object} ImportReportResponse "The input could not be read to the end"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 413 {object} ImportReportResponse "Request body too large"
+// @Failure 415 {string} string "Unsupported Content-Type"
+// @Router /admin/import [post]
+func (h *AdminHandler) Import(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Received request to import questions")
+	opts := importer.Options{}
+	if name := r.URL.Query().Get("format"); name != "" {
+		format, err := importer.ParseFormat(name)
+		if err != nil {
+			h.logger.Warnf("Invalid import format: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		opts.Format = format
+	} else {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format, ok := importContentTypes[mediaType]
+		if !ok {
+			h.logger.Warnf("Unsupported import Content-Type: %q", r.Header.Get("Content-Type"))
+			http.Error(w, "Content-Type must be application/x-ndjson, application/json or text/csv",
+				http.StatusUnsupportedMediaType)
+			return
+		}
+		opts.Format = format
+	}
+	if dryRun := r.URL.Query().Get("dry_run"); dryRun != "" {
+		var err error
+		if opts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			h.logger.Warnf("Invalid dry_run parameter: %s, error: %v", dryRun, err)
+			http.Error(w, "Invalid dry_run parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	status := http.StatusOK
+	report, err := h.importer.Import(http.MaxBytesReader(w, r.Body, h.maxImportBytes), opts)
+	if err != nil {
+		h.logger.Warnf("Import stopped: %v", err)
+		status = http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(toImportReportResponse(report)); err != nil {
+		h.logger.Errorf("Failed to encode response for Import: %v", err)
+	}
+}
+
+// Export выгружает вопросы с ответами потоком, читая их из хранилища пачками.
+// @Summary Export questions
+// @Description Stream questions with their answers as NDJSON, a JSON array, CSV (one row per answer) or markdown.
+// @Description Questions are read from the storage in batches and written as they arrive, ordered by ID.
+// @Description Accepts the same filters as the question list; sort is ignored. Requires the moderator role.
+// @Tags admin
+// @Produce json,application/x-ndjson,text/csv,text/markdown,application/gzip
+// @Param format query string false "Output format" Enums(ndjson, json, csv, markdown) default(ndjson)
+// @Param gzip query bool false "Compress the output with gzip"
+// @Param status query []string false "Question statuses" collectionFormat(csv)
+// @Param created_after query string false "Created at or after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param created_before query string false "Created before this time (RFC 3339 or YYYY-MM-DD)"
+// @Param author query string false "Author user ID (UUID)"
+// @Param q query string false "Case-insensitive text contained in the title or body"
+// @Param tag query []string false "Tags the question must all have, comma-separated or repeated" collectionFormat(csv)
+// @Success 200 {string} string "Exported questions"
+// @Failure 400 {object} InvalidParamsResponse
+// @Failure 403 {string} string "Forbidden"
+// @Router /admin/export [get]
+func (h *AdminHandler) Export(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Received request to export questions")
+	opts := exporter.Options{Format: exporter.FormatNDJSON}
+	if name := r.URL.Query().Get("format"); name != "" {
+		format, err := exporter.ParseFormat(name)
+		if err != nil {
+			h.logger.Warnf("Invalid export format: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		opts.Format = format
+	}
+	compress := false
+	if value := r.URL.Query().Get("gzip"); value != "" {
+		var err error
+		if compress, err = strconv.ParseBool(value); err != nil {
+			h.logger.Warnf("Invalid gzip parameter: %s, error: %v", value, err)
+			http.Error(w, "Invalid gzip parameter", http.StatusBadRequest)
+			return
+		}
+	}
+	spec, err := query.ParseQuestionSpec(r.URL.Query())
+	if err != nil {
+		h.logger.Warnf("Invalid export parameters: %v", err)
+		writeQueryError(w, h.logger, err)
+		return
+	}
+	opts.Spec = spec
+
+	filename := "questions" + opts.Format.Extension()
+	contentType := opts.Format.ContentType()
+	if compress {
+		filename += ".gz"
+		contentType = "application/gzip"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	out := &trackingWriter{w: w}
+	var gz *gzip.Writer
+	dst := io.Writer(out)
+	if compress {
+		gz = gzip.NewWriter(out)
+		dst = gz
+	}
+	count, err := h.exporter.Export(dst, opts)
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		h.logger.Errorf("Export failed after %d questions: %v", count, err)
+		if out.written {
+			// Статус уже отправлен: обрываем соединение, чтобы клиент не принял неполную выгрузку за целую
+			panic(http.ErrAbortHandler)
+		}
+		w.Header().Del("Content-Disposition")
+		http.Error(w, "Failed to export questions", http.StatusInternalServerError)
+	}
+}
+
+// trackingWriter запоминает, было ли что-то записано в ответ.
+type trackingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.w.Write(p)
+}
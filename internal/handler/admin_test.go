@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/exporter"
+	"github.com/shenikar/question-service/internal/importer"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/repository"
+)
+
+func importRequest(h *AdminHandler, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rr := httptest.NewRecorder()
+	h.Import(rr, req)
+	return rr
+}
+
+func decodeReport(t *testing.T, rr *httptest.ResponseRecorder) ImportReportResponse {
+	var report ImportReportResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	return report
+}
+
+func TestImportFormats(t *testing.T) {
+	repo := repository.NewMemoryRepository(logrus.New())
+	h := NewAdminHandler(importer.New(repo, logrus.New()), nil, logrus.New(), testMaxBodyBytes)
+
+	rr := importRequest(h, "/admin/import", "application/x-ndjson", `{"title":"NDJSON question"}`+"\n")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	report := decodeReport(t, rr)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, []ImportResultResponse{{Record: 1, Line: 1, Title: "NDJSON question", Status: "created",
+		QuestionID: 1}}, report.Results)
+
+	rr = importRequest(h, "/admin/import", "application/json; charset=utf-8", `[{"title":"JSON question"}]`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, decodeReport(t, rr).Created)
+
+	rr = importRequest(h, "/admin/import", "text/csv", "title,answer\nCSV question,CSV answer\n")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, decodeReport(t, rr).Results[0].Answers)
+
+	// Параметр format важнее Content-Type
+	rr = importRequest(h, "/admin/import?format=csv", "text/plain", "title\nPlain question\n")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 1, decodeReport(t, rr).Created)
+
+	questions, err := repo.GetAllQuestions(query.QuestionSpec{}, query.Projection{})
+	assert.NoError(t, err)
+	assert.Len(t, questions, 4)
+}
+
+func TestImportDryRun(t *testing.T) {
+	repo := repository.NewMemoryRepository(logrus.New())
+	h := NewAdminHandler(importer.New(repo, logrus.New()), nil, logrus.New(), testMaxBodyBytes)
+
+	rr := importRequest(h, "/admin/import?dry_run=true", "application/x-ndjson", `{"title":"Question"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	report := decodeReport(t, rr)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Created)
+
+	questions, err := repo.GetAllQuestions(query.QuestionSpec{}, query.Projection{})
+	assert.NoError(t, err)
+	assert.Empty(t, questions)
+}
+
+func TestImportInvalidRequest(t *testing.T) {
+	h := NewAdminHandler(importer.New(repository.NewMemoryRepository(logrus.New()), logrus.New()), nil,
+		logrus.New(), 64)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, importRequest(h, "/admin/import", "text/plain", "").Code)
+	assert.Equal(t, http.StatusBadRequest,
+		importRequest(h, "/admin/import?format=xml", "application/json", "[]").Code)
+	assert.Equal(t, http.StatusBadRequest,
+		importRequest(h, "/admin/import?dry_run=maybe", "application/json", "[]").Code)
+
+	// Входные данные не удалось дочитать: отчет описывает, что успели импортировать
+	rr := importRequest(h, "/admin/import", "application/json", `[{"title":"Question"}, {`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	report := decodeReport(t, rr)
+	assert.Equal(t, 1, report.Created)
+	assert.Contains(t, report.Error, "malformed JSON")
+
+	rr = importRequest(h, "/admin/import", "application/x-ndjson", strings.Repeat(`{"title":"Question"}`+"\n", 10))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Contains(t, decodeReport(t, rr).Error, "request body too large")
+}
+
+// brokenRepository не может прочитать вопросы для выгрузки.
+type brokenRepository struct {
+	repository.Repository
+}
+
+func (brokenRepository) FindQuestionsInBatches(query.QuestionSpec, int, func([]models.Question) error) error {
+	return errors.New("connection refused")
+}
+
+func exportRequest(h *AdminHandler, target string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	h.Export(rr, httptest.NewRequest(http.MethodGet, target, nil))
+	return rr
+}
+
+func newExportHandler(t *testing.T, repo repository.Repository) *AdminHandler {
+	assert.NoError(t, repo.CreateQuestion(&models.Question{Title: "Open question", Status: models.QuestionStatusOpen}))
+	assert.NoError(t, repo.CreateQuestion(&models.Question{Title: "Closed question",
+		Status: models.QuestionStatusClosed}))
+	return NewAdminHandler(nil, exporter.New(repo, logrus.New()), logrus.New(), testMaxBodyBytes)
+}
+
+func TestExport(t *testing.T) {
+	h := newExportHandler(t, repository.NewMemoryRepository(logrus.New()))
+
+	rr := exportRequest(h, "/admin/export")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=questions.ndjson`, rr.Header().Get("Content-Disposition"))
+	assert.Equal(t, 2, strings.Count(rr.Body.String(), "\n"))
+
+	rr = exportRequest(h, "/admin/export?format=csv&status=closed")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rr.Body.String(),
+		strings.Join(exporter.CSVColumns, ",")+"\n2,Closed question,,,closed,0,"), rr.Body.String())
+
+	rr = exportRequest(h, "/admin/export?format=json&gzip=true")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/gzip", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=questions.json.gz`, rr.Header().Get("Content-Disposition"))
+	gz, err := gzip.NewReader(rr.Body)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+	var questions []map[string]any
+	assert.NoError(t, json.Unmarshal(data, &questions))
+	assert.Len(t, questions, 2)
+}
+
+func TestExportInvalidRequest(t *testing.T) {
+	h := newExportHandler(t, repository.NewMemoryRepository(logrus.New()))
+
+	assert.Equal(t, http.StatusBadRequest, exportRequest(h, "/admin/export?format=xml").Code)
+	assert.Equal(t, http.StatusBadRequest, exportRequest(h, "/admin/export?gzip=maybe").Code)
+
+	rr := exportRequest(h, "/admin/export?created_after=yesterday")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "created_after")
+
+	rr = exportRequest(h, "/admin/export?tag=hello+world")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "tag")
+
+	// Ошибка до начала выгрузки: клиент получает 500, а не пустой файл
+	rr = exportRequest(newExportHandler(t, brokenRepository{repository.NewMemoryRepository(logrus.New())}),
+		"/admin/export")
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Empty(t, rr.Header().Get("Content-Disposition"))
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// CreateQuestionComment создает комментарий к вопросу.
+// @Summary Create a comment for a question
+// @Description Create a short clarifying comment for a specific question. The author is the user from
+// @Description X-User-ID; anonymous comments get a new user ID.
+// @Tags comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Question ID"
+// @Param comment body CreateCommentRequest true "Comment to create"
+// @Success 201 {object} CommentResponse
+// @Router /questions/{id}/comments [post]
+func (h *Handler) CreateQuestionComment(w http.ResponseWriter, r *http.Request) {
+	h.createComment(w, r, models.CommentParentQuestion)
+}
+
+// CreateAnswerComment создает комментарий к ответу.
+// @Summary Create a comment for an answer
+// @Description Create a short clarifying comment for a specific answer. The author is the user from
+// @Description X-User-ID; anonymous comments get a new user ID.
+// @Tags comments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Answer ID"
+// @Param comment body CreateCommentRequest true "Comment to create"
+// @Success 201 {object} CommentResponse
+// @Router /answers/{id}/comments [post]
+func (h *Handler) CreateAnswerComment(w http.ResponseWriter, r *http.Request) {
+	h.createComment(w, r, models.CommentParentAnswer)
+}
+
+// GetQuestionComments получает комментарии к вопросу.
+// @Summary Get comments for a question
+// @Description Get all comments for a specific question
+// @Tags comments
+// @Produce  json
+// @Param id path int true "Question ID"
+// @Success 200 {array} CommentResponse
+// @Router /questions/{id}/comments [get]
+func (h *Handler) GetQuestionComments(w http.ResponseWriter, r *http.Request) {
+	h.getComments(w, r, models.CommentParentQuestion)
+}
+
+// GetAnswerComments получает комментарии к ответу.
+// @Summary Get comments for an answer
+// @Description Get all comments for a specific answer
+// @Tags comments
+// @Produce  json
+// @Param id path int true "Answer ID"
+// @Success 200 {array} CommentResponse
+// @Router /answers/{id}/comments [get]
+func (h *Handler) GetAnswerComments(w http.ResponseWriter, r *http.Request) {
+	h.getComments(w, r, models.CommentParentAnswer)
+}
+
+// DeleteComment удаляет комментарий по ID. Удалить комментарий может его автор или модератор.
+// @Summary Delete a comment by ID
+// @Description Delete a comment by its ID. Only the author of the comment or a moderator can delete it.
+// @Tags comments
+// @Param id path int true "Comment ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Invalid comment ID"
+// @Failure 401 {string} string "Authentication required"
+// @Failure 403 {string} string "Only the comment author or a moderator can delete it"
+// @Failure 404 {string} string "Comment not found"
+// @Router /comments/{id} [delete]
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.logger.Infof("Received request to delete comment with ID: %s", idStr)
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid comment ID for deletion: %s, error: %v", idStr, err)
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+
+	identity, ok := auth.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	moderator := identity.Role == auth.RoleModerator
+	if err := h.service.DeleteComment(uint(id), identity.UserID, moderator); err != nil {
+		h.logger.Errorf("Failed to delete comment with ID %d: %v", id, err)
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrNotCommentAuthor):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Infof("Comment with ID %d deleted successfully", id)
+}
+
+// createComment создает комментарий к родителю указанного типа.
+func (h *Handler) createComment(w http.ResponseWriter, r *http.Request, parentType string) {
+	idStr := chi.URLParam(r, "id")
+	h.logger.Infof("Received request to create comment for %s ID: %s", parentType, idStr)
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid %s ID for comment creation: %s, error: %v", parentType, idStr, err)
+		http.Error(w, "Invalid "+parentType+" ID", http.StatusBadRequest)
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		h.logger.Warnf("Failed to decode comment request body: %v", err)
+		writeBodyError(w, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		h.logger.Warnf("Validation failed for comment: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comment := toCommentModel(&req)
+	if identity, ok := auth.FromContext(r.Context()); ok {
+		comment.UserID = identity.UserID
+	}
+	if err := h.service.CreateComment(parentType, uint(id), comment); err != nil {
+		h.logger.Errorf("Failed to create comment for %s ID %d: %v", parentType, id, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(toCommentResponse(comment)); err != nil {
+		h.logger.Errorf("Failed to encode response for CreateComment: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	h.logger.Infof("Comment created successfully for %s ID %d", parentType, id)
+}
+
+// getComments возвращает комментарии к родителю указанного типа.
+func (h *Handler) getComments(w http.ResponseWriter, r *http.Request, parentType string) {
+	idStr := chi.URLParam(r, "id")
+	h.logger.Infof("Received request to get comments for %s ID: %s", parentType, idStr)
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid %s ID for comments: %s, error: %v", parentType, idStr, err)
+		http.Error(w, "Invalid "+parentType+" ID", http.StatusBadRequest)
+		return
+	}
+
+	comments, err := h.service.GetComments(parentType, uint(id))
+	if err != nil {
+		h.logger.Errorf("Failed to get comments for %s ID %d: %v", parentType, id, err)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(toCommentResponses(comments)); err != nil {
+		h.logger.Errorf("Failed to encode response for GetComments: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	h.logger.Infof("Comments for %s ID %d retrieved successfully", parentType, id)
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+func TestCreateQuestionCommentHandler(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	commentJSON, _ := json.Marshal(&CreateCommentRequest{Text: "Test Comment"})
+
+	mockService.On("CreateComment", models.CommentParentQuestion, uint(1), mock.AnythingOfType("*models.Comment")).
+		Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/questions/1/comments", bytes.NewBuffer(commentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Post("/questions/{id}/comments", handler.CreateQuestionComment)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateAnswerCommentHandler(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	commentJSON, _ := json.Marshal(&CreateCommentRequest{Text: "Test Comment"})
+
+	mockService.On("CreateComment", models.CommentParentAnswer, uint(2), mock.AnythingOfType("*models.Comment")).
+		Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/answers/2/comments", bytes.NewBuffer(commentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Post("/answers/{id}/comments", handler.CreateAnswerComment)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateCommentHandlerSetsAuthor(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	author := uuid.New()
+	mockService.On("CreateComment", models.CommentParentQuestion, uint(1), mock.MatchedBy(func(c *models.Comment) bool {
+		return c.UserID == author
+	})).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/questions/1/comments", bytes.NewBufferString(`{"text": "Comment"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{UserID: author, Role: auth.RoleUser}))
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Post("/questions/{id}/comments", handler.CreateQuestionComment)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateCommentHandlerTooLong(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	// Комментарий длиннее 200 символов, хотя для ответа такая длина допустима
+	commentJSON, _ := json.Marshal(&CreateCommentRequest{Text: strings.Repeat("a", 201)})
+
+	req := httptest.NewRequest(http.MethodPost, "/questions/1/comments", bytes.NewBuffer(commentJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Post("/questions/{id}/comments", handler.CreateQuestionComment)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetAnswerCommentsHandler(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	expectedComments := []models.Comment{
+		{ID: 1, ParentType: models.CommentParentAnswer, ParentID: 2, Text: "C1"},
+		{ID: 2, ParentType: models.CommentParentAnswer, ParentID: 2, Text: "C2"},
+	}
+
+	mockService.On("GetComments", models.CommentParentAnswer, uint(2)).Return(expectedComments, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/answers/2/comments", nil)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Get("/answers/{id}/comments", handler.GetAnswerComments)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var responseComments []CommentResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&responseComments))
+	assert.Len(t, responseComments, 2)
+	assert.Equal(t, models.CommentParentAnswer, responseComments[0].ParentType)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetQuestionCommentsHandlerNotFound(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	mockService.On("GetComments", models.CommentParentQuestion, uint(999)).Return(nil, errors.New("not found"))
+
+	req := httptest.NewRequest(http.MethodGet, "/questions/999/comments", nil)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Get("/questions/{id}/comments", handler.GetQuestionComments)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteCommentHandler(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	author := uuid.New()
+	mockService.On("DeleteComment", uint(1), author, false).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/comments/1", nil)
+	req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{UserID: author, Role: auth.RoleUser}))
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Delete("/comments/{id}", handler.DeleteComment)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteCommentHandlerModerator(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	moderator := uuid.New()
+	mockService.On("DeleteComment", uint(1), moderator, true).Return(nil)
+
+	req := asModerator(httptest.NewRequest(http.MethodDelete, "/comments/1", nil), moderator)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Delete("/comments/{id}", handler.DeleteComment)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteCommentHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"not found", fmt.Errorf("comment with ID 1: %w", service.ErrNotFound), http.StatusNotFound},
+		{"not author", service.ErrNotCommentAuthor, http.StatusForbidden},
+		{"storage error", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+			user := uuid.New()
+			mockService.On("DeleteComment", uint(1), user, false).Return(tt.err)
+
+			req := httptest.NewRequest(http.MethodDelete, "/comments/1", nil)
+			req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{UserID: user, Role: auth.RoleUser}))
+			rr := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Delete("/comments/{id}", handler.DeleteComment)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteCommentHandlerUnauthenticated(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	req := httptest.NewRequest(http.MethodDelete, "/comments/1", nil)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Delete("/comments/{id}", handler.DeleteComment)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "DeleteComment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteCommentHandlerInvalidID(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	req := httptest.NewRequest(http.MethodDelete, "/comments/abc", nil) // Некорректный ID
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Delete("/comments/{id}", handler.DeleteComment)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "DeleteComment", mock.Anything, mock.Anything, mock.Anything)
+}
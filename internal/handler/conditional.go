@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shenikar/question-service/internal/query"
+)
+
+// entityTag строит сильный ETag представления ресурса вида "<версия>-<хеш>".
+// Версия ресурса нужна для If-Match. Хеш учитывает время последнего изменения, которое в БД
+// обновляют триггеры при любом изменении ресурса и вложенных в него данных, и проекцию,
+// так как от нее зависит тело ответа.
+func entityTag(resource string, id uint, version int, updatedAt time.Time, proj query.Projection) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s/%d@%d?fields=%s&include=%s",
+		resource, id, updatedAt.UnixNano(), strings.Join(proj.Fields, ","), strings.Join(proj.Include, ",")))
+	return fmt.Sprintf(`"%d-%s"`, version, hex.EncodeToString(sum[:16]))
+}
+
+// ifMatchVersions разбирает заголовок If-Match в список версий ресурса.
+// nil означает, что условия нет: заголовок не передан или равен *.
+// Слабые и чужие ETag не соответствуют ни одной версии, поэтому с ними условие всегда ложно.
+func ifMatchVersions(r *http.Request) []int {
+	values := r.Header.Values("If-Match")
+	if len(values) == 0 {
+		return nil
+	}
+	versions := []int{}
+	for _, tag := range strings.Split(strings.Join(values, ","), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil
+		}
+		if version, ok := tagVersion(tag); ok {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// tagVersion извлекает версию ресурса из сильного ETag, построенного entityTag.
+func tagVersion(tag string) (int, bool) {
+	tag, ok := strings.CutPrefix(tag, `"`)
+	if !ok {
+		return 0, false
+	}
+	prefix, _, _ := strings.Cut(strings.TrimSuffix(tag, `"`), "-")
+	version, err := strconv.Atoi(prefix)
+	return version, err == nil && version > 0
+}
+
+// writeVersionConflict отвечает на конфликт версий: 412, если клиент передал If-Match,
+// и 409, если ресурс изменили конкурентно без условия со стороны клиента.
+func (h *Handler) writeVersionConflict(w http.ResponseWriter, r *http.Request, err error) {
+	if len(r.Header.Values("If-Match")) > 0 {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusConflict)
+}
+
+// notModified выставляет заголовки ETag и Last-Modified и проверяет условия GET-запроса (RFC 9110, раздел 13).
+// If-Modified-Since учитывается, только если нет If-None-Match. Если у клиента актуальное
+// представление, отвечает 304 Not Modified без тела и возвращает true.
+func notModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+
+	if ifNoneMatch := r.Header.Values("If-None-Match"); len(ifNoneMatch) > 0 {
+		if !etagMatches(strings.Join(ifNoneMatch, ","), etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		if err != nil || lastModified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches сравнивает ETag со списком из If-None-Match. Для GET сравнение слабое:
+// префикс W/ не учитывается.
+func etagMatches(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+)
+
+// getQuestion выполняет GET /questions/{id} с указанными заголовками.
+func getQuestion(handler *Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Get("/questions/{id}", handler.GetQuestion)
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestGetQuestionHandlerConditional(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	updatedAt := time.Date(2026, 10, 18, 12, 30, 15, 500, time.UTC)
+	question := &models.Question{ID: 1, Title: "Question 1", Version: 3, UpdatedAt: updatedAt}
+	mockService.On("GetQuestion", uint(1), defaultQuestionProjection).Return(question, nil)
+	mockService.On("GetQuestion", uint(1), query.Projection{Fields: []string{"title"}}).Return(question, nil)
+
+	rr := getQuestion(handler, "/questions/1", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	assert.Regexp(t, `^"3-[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "Sun, 18 Oct 2026 12:30:15 GMT", rr.Header().Get("Last-Modified"))
+
+	rr = getQuestion(handler, "/questions/1", http.Header{"If-None-Match": {`"other", W/` + etag}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	assert.Equal(t, etag, rr.Header().Get("ETag"))
+
+	// Другая проекция - другое представление и другой ETag
+	rr = getQuestion(handler, "/questions/1?fields=title&include=", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+
+	rr = getQuestion(handler, "/questions/1", http.Header{"If-Modified-Since": {"Sun, 18 Oct 2026 12:30:15 GMT"}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	rr = getQuestion(handler, "/questions/1", http.Header{"If-Modified-Since": {"Sun, 18 Oct 2026 12:30:14 GMT"}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// If-None-Match важнее If-Modified-Since
+	rr = getQuestion(handler, "/questions/1", http.Header{
+		"If-None-Match":     {`"stale"`},
+		"If-Modified-Since": {"Sun, 18 Oct 2026 12:30:15 GMT"},
+	})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestEntityTag(t *testing.T) {
+	proj := query.Projection{Include: []string{query.IncludeAnswers}}
+	updatedAt := time.Now()
+
+	tag := entityTag("questions", 1, 2, updatedAt, proj)
+	assert.Equal(t, tag, entityTag("questions", 1, 2, updatedAt, proj))
+	assert.NotEqual(t, tag, entityTag("questions", 1, 2, updatedAt.Add(time.Microsecond), proj))
+	assert.NotEqual(t, tag, entityTag("answers", 1, 2, updatedAt, proj))
+	assert.NotEqual(t, tag, entityTag("questions", 1, 3, updatedAt, proj))
+
+	version, ok := tagVersion(tag)
+	assert.True(t, ok)
+	assert.Equal(t, 2, version)
+}
+
+func TestIfMatchVersions(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		want   []int
+	}{
+		{"absent", nil, nil},
+		{"any", []string{`"2-abc", *`}, nil},
+		{"single", []string{`"2-abc"`}, []int{2}},
+		{"list", []string{`"2-abc", "5-def"`, `"7-0"`}, []int{2, 5, 7}},
+		{"weak and foreign tags", []string{`W/"2-abc", "stale", 3`}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/questions/1", nil)
+			req.Header["If-Match"] = tt.header
+			assert.Equal(t, tt.want, ifMatchVersions(req))
+		})
+	}
+}
+
+func TestGetAnswerHandlerNotModified(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	answer := &models.Answer{ID: 1, Text: "Answer", UpdatedAt: time.Now()}
+	mockService.On("GetAnswer", uint(1), query.Projection{}).Return(answer, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/answers/1", nil)
+	req.Header.Set("If-None-Match", "*")
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Get("/answers/{id}", handler.GetAnswer)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// bodyError - ошибка разбора тела запроса с HTTP-статусом ответа.
+type bodyError struct {
+	status int
+	msg    string
+}
+
+func (e *bodyError) Error() string {
+	return e.msg
+}
+
+// decodeJSON разбирает JSON-тело запроса в dst с ограничением размера h.maxBodyBytes.
+func (h *Handler) decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	return decodeJSONBody(w, r, dst, h.maxBodyBytes)
+}
+
+// decodeJSONBody строго разбирает JSON-тело запроса в dst. Тело должно иметь Content-Type application/json,
+// не превышать maxBodyBytes, содержать ровно одно JSON-значение и только известные поля.
+// Возвращает *bodyError, который записывает writeBodyError.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst any, maxBodyBytes int64) error {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil ||
+		mediaType != "application/json" {
+		return &bodyError{
+			status: http.StatusUnsupportedMediaType,
+			msg:    "Content-Type must be application/json",
+		}
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return describeDecodeError(err)
+	}
+	// После значения допустимы только пробельные символы
+	offset := dec.InputOffset()
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return describeDecodeError(err)
+		}
+		return badRequest("request body must contain a single JSON value, found more data at byte offset %d", offset)
+	}
+	return nil
+}
+
+// describeDecodeError переводит ошибку json.Decoder в понятное клиенту сообщение.
+func describeDecodeError(err error) *bodyError {
+	var (
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		maxBytesErr *http.MaxBytesError
+	)
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return &bodyError{
+			status: http.StatusRequestEntityTooLarge,
+			msg:    fmt.Sprintf("request body must not be larger than %d bytes", maxBytesErr.Limit),
+		}
+	case errors.As(err, &syntaxErr):
+		return badRequest("request body contains malformed JSON at byte offset %d: %v", syntaxErr.Offset, err)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return badRequest("request body contains malformed JSON: unexpected end of input")
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return badRequest("field %q must be of type %s (at byte offset %d)",
+				typeErr.Field, typeErr.Type, typeErr.Offset)
+		}
+		return badRequest("request body must be a JSON %s (at byte offset %d)", typeErr.Type, typeErr.Offset)
+	case errors.Is(err, io.EOF):
+		return badRequest("request body must not be empty")
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json не экспортирует тип этой ошибки
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return badRequest("request body contains unknown field %s", field)
+	default:
+		return badRequest("invalid request body: %v", err)
+	}
+}
+
+func badRequest(format string, args ...any) *bodyError {
+	return &bodyError{status: http.StatusBadRequest, msg: fmt.Sprintf(format, args...)}
+}
+
+// writeBodyError отвечает на ошибку decodeJSON.
+func writeBodyError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	var bodyErr *bodyError
+	if errors.As(err, &bodyErr) {
+		status = bodyErr.status
+	}
+	http.Error(w, err.Error(), status)
+}
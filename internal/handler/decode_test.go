@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		message     string
+	}{
+		{"valid", "application/json", `{"text":"Hello"}`, 0, ""},
+		{"charset", "application/json; charset=utf-8", ` {"text":"Hello"} ` + "\n", 0, ""},
+		{"missing content type", "", `{"text":"Hello"}`, http.StatusUnsupportedMediaType, "application/json"},
+		{"form", "application/x-www-form-urlencoded", `text=Hello`, http.StatusUnsupportedMediaType,
+			"application/json"},
+		{"empty", "application/json", ``, http.StatusBadRequest, "must not be empty"},
+		{"syntax", "application/json", `{"text": "Hello",}`, http.StatusBadRequest, "at byte offset 18"},
+		{"truncated", "application/json", `{"text": "Hel`, http.StatusBadRequest, "unexpected end of input"},
+		{"wrong type", "application/json", `{"text": 42}`, http.StatusBadRequest,
+			`field "text" must be of type string`},
+		{"not an object", "application/json", `["Hello"]`, http.StatusBadRequest, "must be a JSON"},
+		{"unknown field", "application/json", `{"text":"Hello","extra":1}`, http.StatusBadRequest,
+			`unknown field "extra"`},
+		{"multiple values", "application/json", `{"text":"Hello"}{"text":"Bye"}`, http.StatusBadRequest,
+			"single JSON value, found more data at byte offset 16"},
+		{"trailing garbage", "application/json", `{"text":"Hello"} x`, http.StatusBadRequest, "single JSON value"},
+		{"too large", "application/json", `{"text":"` + strings.Repeat("a", 64) + `"}`,
+			http.StatusRequestEntityTooLarge, "larger than 32 bytes"},
+	}
+
+	handler := NewHandler(new(MockService), logrus.New(), 32)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/questions/1/comments", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rr := httptest.NewRecorder()
+
+			var dst CreateCommentRequest
+			err := handler.decodeJSON(rr, req, &dst)
+			if tt.status == 0 {
+				assert.NoError(t, err)
+				assert.Equal(t, "Hello", dst.Text)
+				return
+			}
+
+			writeBodyError(rr, err)
+			assert.Equal(t, tt.status, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.message)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateQuestionRequest - тело запроса на создание вопроса.
+type CreateQuestionRequest struct {
+	Title string   `json:"title" validate:"required,min=3,max=250"`
+	Body  string   `json:"body" validate:"max=30000"`
+	Tags  []string `json:"tags" validate:"max=5"`
+}
+
+// QuestionResponse - представление вопроса в ответах API.
+// BodyHTML содержит санитизированный HTML, полученный из markdown в Body.
+// Comments и Author заполняются, только если клиент запросил их в параметре include.
+type QuestionResponse struct {
+	ID               uint              `json:"id"`
+	UserID           *uuid.UUID        `json:"user_id"`
+	Title            string            `json:"title"`
+	Body             string            `json:"body"`
+	BodyHTML         string            `json:"body_html"`
+	Tags             []string          `json:"tags"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	Version          int               `json:"version"`
+	LastActivityAt   time.Time         `json:"last_activity_at"`
+	Score            int               `json:"score"`
+	Status           string            `json:"status"`
+	CloseReason      string            `json:"close_reason,omitempty"`
+	ClosedBy         *uuid.UUID        `json:"closed_by,omitempty"`
+	ClosedAt         *time.Time        `json:"closed_at,omitempty"`
+	DuplicateOf      *uint             `json:"duplicate_of"`
+	AcceptedAnswerID *uint             `json:"accepted_answer_id"`
+	AnswerCount      int64             `json:"answer_count"`
+	CommentCount     int64             `json:"comment_count"`
+	Answers          []AnswerResponse  `json:"answers"`
+	Comments         []CommentResponse `json:"comments,omitempty"`
+	Author           *AuthorResponse   `json:"author,omitempty"`
+}
+
+// InvalidParamsResponse - ответ на запрос с некорректными параметрами строки запроса.
+type InvalidParamsResponse struct {
+	Error  string                 `json:"error"`
+	Params []InvalidParamResponse `json:"params"`
+}
+
+// InvalidParamResponse описывает ошибку в одном параметре строки запроса.
+type InvalidParamResponse struct {
+	Param   string `json:"param"`
+	Message string `json:"message"`
+}
+
+// UpdateQuestionRequest - тело запроса на изменение вопроса. Непереданные поля не изменяются.
+type UpdateQuestionRequest struct {
+	Title *string `json:"title" validate:"omitnil,min=3,max=250"`
+	Body  *string `json:"body" validate:"omitnil,max=30000"`
+}
+
+// CloseQuestionRequest - тело запроса на закрытие вопроса.
+type CloseQuestionRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=250"`
+}
+
+// CreateQuestionResponse - созданный вопрос вместе с похожими существующими вопросами.
+type CreateQuestionResponse struct {
+	QuestionResponse
+	PossibleDuplicates []SimilarQuestionResponse `json:"possible_duplicates"`
+}
+
+// SimilarQuestionResponse - краткое представление похожего вопроса.
+type SimilarQuestionResponse struct {
+	ID         uint      `json:"id"`
+	Title      string    `json:"title"`
+	Similarity float64   `json:"similarity"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// DuplicateQuestionResponse - ответ на отклоненный в строгом режиме вопрос.
+type DuplicateQuestionResponse struct {
+	Error              string                    `json:"error"`
+	PossibleDuplicates []SimilarQuestionResponse `json:"possible_duplicates"`
+}
+
+// CreateAnswerRequest - тело запроса на создание ответа.
+type CreateAnswerRequest struct {
+	Text string `json:"text" validate:"required,min=3,max=10000"`
+}
+
+// UpdateAnswerRequest - тело запроса на изменение ответа.
+type UpdateAnswerRequest struct {
+	Text string `json:"text" validate:"required,min=3,max=10000"`
+}
+
+// AnswerResponse - представление ответа в ответах API.
+// TextHTML содержит санитизированный HTML, полученный из markdown в Text.
+// Comments и Author заполняются, только если клиент запросил их в параметре include.
+type AnswerResponse struct {
+	ID           uint              `json:"id"`
+	QuestionID   uint              `json:"question_id"`
+	UserID       uuid.UUID         `json:"user_id"`
+	Text         string            `json:"text"`
+	TextHTML     string            `json:"text_html"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	Version      int               `json:"version"`
+	CommentCount int64             `json:"comment_count"`
+	Comments     []CommentResponse `json:"comments,omitempty"`
+	Author       *AuthorResponse   `json:"author,omitempty"`
+}
+
+// DeletedAnswerResponse - данные события answer.deleted в потоке событий вопроса.
+type DeletedAnswerResponse struct {
+	ID         uint `json:"id"`
+	QuestionID uint `json:"question_id"`
+}
+
+// DeletedCommentResponse - данные события comment.deleted; QuestionID - вопрос, к которому относился
+// комментарий.
+type DeletedCommentResponse struct {
+	ID         uint `json:"id"`
+	QuestionID uint `json:"question_id"`
+}
+
+// DeletedQuestionResponse - данные события question.deleted.
+type DeletedQuestionResponse struct {
+	ID uint `json:"id"`
+}
+
+// WebSocketCommand - сообщение клиента в соединении /ws: подписка (subscribe) или отписка (unsubscribe)
+// от темы.
+type WebSocketCommand struct {
+	Action string `json:"action"`
+	Topic  string `json:"topic"`
+}
+
+// WebSocketMessage - сообщение сервера в соединении /ws. Для событий Type - тип события, Data - его данные
+// в том же виде, что и в потоке событий вопроса. На команды клиента сервер отвечает сообщениями
+// subscribed, unsubscribed или error.
+type WebSocketMessage struct {
+	Type       string    `json:"type"`
+	Topic      string    `json:"topic,omitempty"`
+	QuestionID uint      `json:"question_id,omitempty"`
+	Data       any       `json:"data,omitempty"`
+	Time       time.Time `json:"time,omitzero"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// CreateCommentRequest - тело запроса на создание комментария.
+type CreateCommentRequest struct {
+	Text string `json:"text" validate:"required,min=3,max=200"`
+}
+
+// CommentResponse - представление комментария в ответах API.
+type CommentResponse struct {
+	ID         uint            `json:"id"`
+	ParentType string          `json:"parent_type"`
+	ParentID   uint            `json:"parent_id"`
+	UserID     uuid.UUID       `json:"user_id"`
+	Text       string          `json:"text"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Author     *AuthorResponse `json:"author,omitempty"`
+}
+
+// AuthorResponse - автор вопроса, ответа или комментария.
+// Профилей пользователей в сервисе нет, поэтому автор описывается только идентификатором.
+type AuthorResponse struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// ImportReportResponse - отчет об импорте вопросов.
+// Error заполнен, если входные данные не удалось дочитать: записи после этого места не импортированы.
+type ImportReportResponse struct {
+	DryRun  bool                   `json:"dry_run"`
+	Created int                    `json:"created"`
+	Skipped int                    `json:"skipped"`
+	Failed  int                    `json:"failed"`
+	Error   string                 `json:"error,omitempty"`
+	Results []ImportResultResponse `json:"results"`
+}
+
+// ImportResultResponse - итог импорта одной записи: created, skipped или failed.
+// Record - порядковый номер записи, Line - строка, с которой она начинается (для NDJSON и CSV).
+type ImportResultResponse struct {
+	Record     int    `json:"record"`
+	Line       int    `json:"line,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Status     string `json:"status"`
+	QuestionID uint   `json:"question_id,omitempty"`
+	Answers    int    `json:"answers,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// CreateWebhookRequest - тело запроса на создание подписки на события.
+// Events - типы событий или "*" для любых событий. По умолчанию подписка активна.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,http_url,max=2000"`
+	Events      []string `json:"events" validate:"required,min=1"`
+	Description string   `json:"description" validate:"max=250"`
+	Active      *bool    `json:"active"`
+}
+
+// UpdateWebhookRequest - тело запроса на изменение подписки. Отсутствующие поля не меняются.
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url" validate:"omitnil,http_url,max=2000"`
+	Events      []string `json:"events" validate:"omitnil,min=1"`
+	Description *string  `json:"description" validate:"omitnil,max=250"`
+	Active      *bool    `json:"active"`
+}
+
+// WebhookResponse - подписка на события. Secret - ключ подписи доставок; он возвращается только
+// при создании подписки.
+type WebhookResponse struct {
+	ID          uint      `json:"id"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse - доставка события подписке. Payload - тело запроса к получателю;
+// оно возвращается только при запросе одной доставки.
+type WebhookDeliveryResponse struct {
+	ID             uint            `json:"id"`
+	SubscriptionID uint            `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	ReplayOf       *uint           `json:"replay_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Payload        json.RawMessage `json:"payload,omitempty" swaggertype:"object"`
+}
+
+// NotificationResponse - уведомление пользователя. Type - new_answer, answer_accepted или mention.
+// AnswerID заполнен для уведомлений об ответах, ActorID - если известен пользователь, вызвавший событие,
+// ReadAt - для прочитанных уведомлений.
+type NotificationResponse struct {
+	ID         uint       `json:"id"`
+	Type       string     `json:"type"`
+	QuestionID uint       `json:"question_id"`
+	AnswerID   *uint      `json:"answer_id,omitempty"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+}
+
+// NotificationListResponse - страница уведомлений пользователя и число всех его непрочитанных уведомлений.
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	UnreadCount   int64                  `json:"unread_count"`
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// DefaultHeartbeatInterval - как часто в поток событий пишется комментарий, чтобы прокси
+// не закрывали простаивающее соединение, а клиент замечал разрыв.
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// eventResync - событие, после которого клиенту нужно перечитать вопрос:
+// пропущенные им события уже не хранятся.
+const eventResync = "resync"
+
+// EventsHandler отдает события вопросов потоком Server-Sent Events.
+type EventsHandler struct {
+	service   service.Service
+	hub       *events.Hub
+	logger    *logrus.Logger
+	heartbeat time.Duration
+}
+
+// NewEventsHandler создает обработчик потоков событий. heartbeat - интервал между комментариями,
+// которые поддерживают соединение.
+func NewEventsHandler(
+	s service.Service, hub *events.Hub, logger *logrus.Logger, heartbeat time.Duration,
+) *EventsHandler {
+	return &EventsHandler{service: s, hub: hub, logger: logger, heartbeat: heartbeat}
+}
+
+// QuestionEvents отдает поток событий о вопросе, ответах на него и комментариях к ним.
+// @Summary Stream events of a question
+// @Description Server-Sent Events stream of changes to a question, its answers and comments. Events
+// @Description answer.created, answer.updated and answer.accepted carry the answer (AnswerResponse),
+// @Description answer.deleted carries DeletedAnswerResponse. comment.created carries the comment (CommentResponse),
+// @Description comment.deleted carries DeletedCommentResponse; comments on an answer go to the stream of its
+// @Description question. question.updated, question.closed, question.reopened, question.locked,
+// @Description question.archived and question.marked_duplicate carry the question (QuestionResponse). When the question itself is
+// @Description deleted, question.deleted with DeletedQuestionResponse is sent.
+// @Description Every event has an id; after a reconnect send the last one in Last-Event-ID to receive the
+// @Description missed events. If they are no longer kept, a resync event is sent first and the question should
+// @Description be read again. A comment is sent every 15 seconds to keep the connection alive. Clients that do
+// @Description not keep up with the events are disconnected and should reconnect with Last-Event-ID.
+// @Tags answers
+// @Produce text/event-stream
+// @Param id path int true "Question ID"
+// @Param Last-Event-ID header string false "ID of the last received event"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {string} string "Invalid question ID"
+// @Failure 404 {string} string "Question not found"
+// @Failure 500 {string} string "Failed to get question"
+// @Failure 503 {string} string "Server is shutting down"
+// @Router /questions/{id}/events [get]
+func (h *EventsHandler) QuestionEvents(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid question ID for event stream: %s, error: %v", idStr, err)
+		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.service.GetQuestion(uint(id), query.Projection{Fields: []string{"id"}}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, service.ErrNotFound) {
+			h.logger.Warnf("Event stream requested for missing question ID %d: %v", id, err)
+			http.Error(w, "Question not found", http.StatusNotFound)
+			return
+		}
+		h.logger.Errorf("Failed to get question with ID %d: %v", id, err)
+		http.Error(w, "Failed to get question", http.StatusInternalServerError)
+		return
+	}
+
+	// Last-Event-ID, который сервер не выдавал, тоже означает разрыв: клиент получит resync
+	lastEventID, resume := uint64(0), false
+	if raw := r.Header.Get("Last-Event-ID"); raw != "" {
+		lastEventID, _ = strconv.ParseUint(raw, 10, 64)
+		resume = true
+	}
+	sub, err := h.hub.Subscribe(uint(id), lastEventID, resume)
+	if err != nil {
+		http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Отключает буферизацию ответа в nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	stream := &eventStream{w: w, rc: http.NewResponseController(w)}
+
+	h.logger.Infof("Event stream opened for question ID %d", id)
+	if err := h.writeBacklog(stream, sub); err != nil {
+		h.logger.Infof("Event stream for question ID %d closed: %v", id, err)
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				h.logger.Infof("Event stream for question ID %d closed: %v", id, sub.Err())
+				return
+			}
+			err = stream.event(event.ID, event.Type, EventData(event))
+		case <-ticker.C:
+			err = stream.comment("heartbeat")
+		case <-r.Context().Done():
+			h.logger.Infof("Event stream for question ID %d closed by client", id)
+			return
+		}
+		if err != nil {
+			h.logger.Infof("Event stream for question ID %d closed: %v", id, err)
+			return
+		}
+	}
+}
+
+// writeBacklog отправляет начало потока: resync или пропущенные клиентом события.
+func (h *EventsHandler) writeBacklog(stream *eventStream, sub *events.Subscription) error {
+	if sub.Resync {
+		if err := stream.event(sub.LastID, eventResync, struct{}{}); err != nil {
+			return err
+		}
+	}
+	for _, event := range sub.Replay {
+		if err := stream.event(event.ID, event.Type, EventData(event)); err != nil {
+			return err
+		}
+	}
+	// Комментарий отправляет заголовки сразу, даже если событий пока нет
+	return stream.comment("connected")
+}
+
+// EventData возвращает данные события в том виде, в каком их отдает REST API.
+func EventData(event events.Event) any {
+	switch {
+	case event.Question != nil:
+		return toQuestionResponse(event.Question)
+	case event.Type == events.TypeQuestionDeleted:
+		return DeletedQuestionResponse{ID: event.QuestionID}
+	case event.Comment != nil:
+		return toCommentResponse(event.Comment)
+	case event.Type == events.TypeCommentDeleted:
+		return DeletedCommentResponse{ID: event.CommentID, QuestionID: event.QuestionID}
+	case event.Answer != nil:
+		return toAnswerResponse(event.Answer)
+	default:
+		return DeletedAnswerResponse{ID: event.AnswerID, QuestionID: event.QuestionID}
+	}
+}
+
+// eventStream пишет события в формате text/event-stream и сразу отправляет их клиенту.
+type eventStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func (s *eventStream) event(id uint64, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if _, err := fmt.Fprintf(s.w, "id: %d\nevent: %s\ndata: %s\n\n", id, eventType, payload); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+func (s *eventStream) comment(text string) error {
+	if _, err := fmt.Fprintf(s.w, ": %s\n\n", text); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+func (s *eventStream) flush() error {
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/repository"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// sseMessage - сообщение потока событий: событие или комментарий.
+type sseMessage struct {
+	id, event, data, comment string
+}
+
+// sseClient читает поток событий по сообщениям.
+type sseClient struct {
+	resp   *http.Response
+	reader *bufio.Reader
+}
+
+func (c *sseClient) next(t *testing.T) sseMessage {
+	var msg sseMessage
+	for {
+		line, err := c.reader.ReadString('\n')
+		if !assert.NoError(t, err) {
+			return msg
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return msg
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			msg.id = value
+		case "event":
+			msg.event = value
+		case "data":
+			msg.data = value
+		case "":
+			msg.comment = value
+		}
+	}
+}
+
+// nextEvent пропускает комментарии и возвращает следующее событие.
+func (c *sseClient) nextEvent(t *testing.T) sseMessage {
+	for {
+		if msg := c.next(t); msg.event != "" || msg.comment == "" {
+			return msg
+		}
+	}
+}
+
+type eventsFixture struct {
+	server  *httptest.Server
+	service service.Service
+	hub     *events.Hub
+}
+
+func newEventsFixture(t *testing.T, replaySize, clientBuffer int, heartbeat time.Duration) *eventsFixture {
+	logger := logrus.New()
+	hub := events.NewHub(replaySize, clientBuffer)
+	s := service.NewService(repository.NewMemoryRepository(logger), logger, hub)
+	_, err := s.CreateQuestion(&models.Question{Title: "How to install Go?"}, false)
+	assert.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Get("/questions/{id}/events", NewEventsHandler(s, hub, logger, heartbeat).QuestionEvents)
+	server := httptest.NewServer(r)
+	t.Cleanup(func() {
+		hub.Close()
+		server.Close()
+	})
+	return &eventsFixture{server: server, service: s, hub: hub}
+}
+
+func (f *eventsFixture) connect(t *testing.T, lastEventID string) *sseClient {
+	req, err := http.NewRequest(http.MethodGet, f.server.URL+"/questions/1/events", nil)
+	assert.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	return &sseClient{resp: resp, reader: bufio.NewReader(resp.Body)}
+}
+
+func TestQuestionEventsStream(t *testing.T) {
+	f := newEventsFixture(t, 100, 10, time.Hour)
+	client := f.connect(t, "")
+	assert.Equal(t, "connected", client.next(t).comment)
+
+	answer := &models.Answer{Text: "Use the official installer"}
+	assert.NoError(t, f.service.CreateAnswer(1, answer))
+	created := client.nextEvent(t)
+	assert.Equal(t, events.TypeAnswerCreated, created.event)
+	var resp AnswerResponse
+	assert.NoError(t, json.Unmarshal([]byte(created.data), &resp))
+	assert.Equal(t, answer.ID, resp.ID)
+	assert.Equal(t, "<p>Use the official installer</p>\n", resp.TextHTML)
+
+	_, err := f.service.UpdateAnswer(answer.ID, nil, "Use your package manager")
+	assert.NoError(t, err)
+	assert.Equal(t, events.TypeAnswerUpdated, client.nextEvent(t).event)
+
+	assert.NoError(t, f.service.DeleteAnswer(answer.ID, nil))
+	deleted := client.nextEvent(t)
+	assert.Equal(t, events.TypeAnswerDeleted, deleted.event)
+	assert.JSONEq(t, `{"id": 1, "question_id": 1}`, deleted.data)
+
+	createdID, _ := strconv.ParseUint(created.id, 10, 64)
+	deletedID, _ := strconv.ParseUint(deleted.id, 10, 64)
+	assert.Equal(t, createdID+2, deletedID)
+}
+
+func TestQuestionEventsCommentsAndStatus(t *testing.T) {
+	f := newEventsFixture(t, 100, 10, time.Hour)
+	client := f.connect(t, "")
+	assert.Equal(t, "connected", client.next(t).comment)
+
+	answer := &models.Answer{Text: "Use the official installer"}
+	assert.NoError(t, f.service.CreateAnswer(1, answer))
+	assert.Equal(t, events.TypeAnswerCreated, client.nextEvent(t).event)
+
+	// Комментарий к ответу попадает в поток вопроса этого ответа
+	comment := &models.Comment{Text: "Thanks"}
+	assert.NoError(t, f.service.CreateComment(models.CommentParentAnswer, answer.ID, comment))
+	created := client.nextEvent(t)
+	assert.Equal(t, events.TypeCommentCreated, created.event)
+	var resp CommentResponse
+	assert.NoError(t, json.Unmarshal([]byte(created.data), &resp))
+	assert.Equal(t, comment.ID, resp.ID)
+	assert.Equal(t, models.CommentParentAnswer, resp.ParentType)
+
+	assert.NoError(t, f.service.DeleteComment(comment.ID, comment.UserID, false))
+	deleted := client.nextEvent(t)
+	assert.Equal(t, events.TypeCommentDeleted, deleted.event)
+	assert.JSONEq(t, `{"id": 1, "question_id": 1}`, deleted.data)
+
+	_, err := f.service.CloseQuestion(1, uuid.New(), "Off-topic")
+	assert.NoError(t, err)
+	closed := client.nextEvent(t)
+	assert.Equal(t, events.TypeQuestionClosed, closed.event)
+	var question QuestionResponse
+	assert.NoError(t, json.Unmarshal([]byte(closed.data), &question))
+	assert.Equal(t, models.QuestionStatusClosed, question.Status)
+}
+
+func TestQuestionEventsResume(t *testing.T) {
+	f := newEventsFixture(t, 100, 10, time.Hour)
+	first := f.connect(t, "")
+	assert.NoError(t, f.service.CreateAnswer(1, &models.Answer{Text: "First answer"}))
+	seen := first.nextEvent(t)
+	_ = first.resp.Body.Close()
+
+	assert.NoError(t, f.service.CreateAnswer(1, &models.Answer{Text: "Second answer"}))
+	assert.NoError(t, f.service.CreateAnswer(1, &models.Answer{Text: "Third answer"}))
+
+	client := f.connect(t, seen.id)
+	for _, text := range []string{"Second answer", "Third answer"} {
+		msg := client.next(t)
+		assert.Equal(t, events.TypeAnswerCreated, msg.event)
+		assert.Contains(t, msg.data, text)
+	}
+	assert.Equal(t, "connected", client.next(t).comment)
+}
+
+func TestQuestionEventsResync(t *testing.T) {
+	f := newEventsFixture(t, 1, 10, time.Hour)
+	for _, text := range []string{"First answer", "Second answer"} {
+		assert.NoError(t, f.service.CreateAnswer(1, &models.Answer{Text: text}))
+	}
+
+	// ID из предыдущего запуска сервера: пропущенные события неизвестны
+	client := f.connect(t, "42")
+	msg := client.next(t)
+	assert.Equal(t, "resync", msg.event)
+	assert.NotEmpty(t, msg.id)
+	assert.Equal(t, "connected", client.next(t).comment)
+}
+
+func TestQuestionEventsHeartbeat(t *testing.T) {
+	f := newEventsFixture(t, 100, 10, 10*time.Millisecond)
+	client := f.connect(t, "")
+	assert.Equal(t, "connected", client.next(t).comment)
+	assert.Equal(t, "heartbeat", client.next(t).comment)
+}
+
+func TestQuestionEventsClosedOnShutdown(t *testing.T) {
+	f := newEventsFixture(t, 100, 10, time.Hour)
+	client := f.connect(t, "")
+	assert.Equal(t, "connected", client.next(t).comment)
+
+	f.hub.Close()
+	_, err := client.reader.ReadString('\n')
+	assert.Error(t, err)
+
+	resp, err := http.Get(f.server.URL + "/questions/1/events")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestQuestionEventsQuestionNotFound(t *testing.T) {
+	f := newEventsFixture(t, 100, 10, time.Hour)
+
+	resp, err := http.Get(f.server.URL + "/questions/42/events")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestQuestionEventsStorageError(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("GetQuestion", uint(1), mock.Anything).Return(nil, errors.New("connection refused"))
+	hub := events.NewHub(100, 10)
+	t.Cleanup(hub.Close)
+	r := chi.NewRouter()
+	r.Get("/questions/{id}/events", NewEventsHandler(mockService, hub, logrus.New(), time.Hour).QuestionEvents)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/questions/1/events", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/feed"
+	"github.com/shenikar/question-service/internal/markdown"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// FeedSize - сколько последних вопросов или ответов попадает в ленту.
+const FeedSize = 50
+
+// anonymousAuthor - имя автора вопроса, заданного анонимно.
+const anonymousAuthor = "Anonymous"
+
+// FeedHandler отдает ленты Atom и RSS новых вопросов и ответов на вопрос.
+type FeedHandler struct {
+	service   service.Service
+	publicURL string
+	apiPrefix string
+	logger    *logrus.Logger
+}
+
+// NewFeedHandler создает обработчик лент. publicURL - внешний адрес сервиса для абсолютных ссылок
+// и идентификаторов записей; если он пуст, адрес берется из запроса. apiPrefix - префикс версии API,
+// по которому ссылки ведут на вопросы и ответы.
+func NewFeedHandler(s service.Service, publicURL, apiPrefix string, logger *logrus.Logger) *FeedHandler {
+	return &FeedHandler{service: s, publicURL: publicURL, apiPrefix: apiPrefix, logger: logger}
+}
+
+// QuestionsAtom отдает ленту Atom новых вопросов.
+// @Summary Atom feed of the newest questions
+// @Description Atom 1.0 feed of the 50 newest questions, filtered by the same parameters as the question list.
+// @Description Question tags are listed as entry categories. Supports If-None-Match and If-Modified-Since.
+// @Tags feeds
+// @Produce application/atom+xml
+// @Param status query []string false "Statuses to include, comma-separated or repeated" collectionFormat(csv)
+// @Param author query string false "Author user ID" format(uuid)
+// @Param has_answers query bool false "Only questions with (true) or without (false) answers"
+// @Param q query string false "Case-insensitive text contained in the title or body"
+// @Param tag query []string false "Tags the question must all have, comma-separated or repeated" collectionFormat(csv)
+// @Success 200 {string} string "Atom feed"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} InvalidParamsResponse
+// @Router /feeds/questions.atom [get]
+func (h *FeedHandler) QuestionsAtom(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Received request for the questions Atom feed")
+	if f, ok := h.questionsFeed(w, r, "questions.atom"); ok {
+		h.writeFeed(w, r, feed.ContentTypeAtom, f.Atom, f.Updated)
+	}
+}
+
+// QuestionsRSS отдает ленту RSS новых вопросов.
+// @Summary RSS feed of the newest questions
+// @Description RSS 2.0 feed of the 50 newest questions, filtered by the same parameters as the question list.
+// @Description Question tags are listed as entry categories. Supports If-None-Match and If-Modified-Since.
+// @Tags feeds
+// @Produce application/rss+xml
+// @Param status query []string false "Statuses to include, comma-separated or repeated" collectionFormat(csv)
+// @Param author query string false "Author user ID" format(uuid)
+// @Param has_answers query bool false "Only questions with (true) or without (false) answers"
+// @Param q query string false "Case-insensitive text contained in the title or body"
+// @Param tag query []string false "Tags the question must all have, comma-separated or repeated" collectionFormat(csv)
+// @Success 200 {string} string "RSS feed"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} InvalidParamsResponse
+// @Router /feeds/questions.rss [get]
+func (h *FeedHandler) QuestionsRSS(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Received request for the questions RSS feed")
+	if f, ok := h.questionsFeed(w, r, "questions.rss"); ok {
+		h.writeFeed(w, r, feed.ContentTypeRSS, f.RSS, f.Updated)
+	}
+}
+
+// questionsFeed строит ленту новых вопросов, name - имя файла ленты в /feeds. Если параметры некорректны
+// или вопросы не удалось прочитать, отвечает ошибкой и возвращает false.
+func (h *FeedHandler) questionsFeed(w http.ResponseWriter, r *http.Request, name string) (feed.Feed, bool) {
+	values := r.URL.Query()
+	spec, err := query.ParseQuestionSpec(values)
+	if values.Has(query.ParamSort) {
+		err = query.Join(err, &query.Error{Params: []query.ParamError{
+			{Param: query.ParamSort, Message: "feeds are always sorted by creation time"},
+		}})
+	}
+	if err != nil {
+		h.logger.Warnf("Invalid questions feed parameters: %v", err)
+		writeQueryError(w, h.logger, err)
+		return feed.Feed{}, false
+	}
+
+	spec.Sort, spec.Limit = query.SortNewest, FeedSize
+	questions, err := h.service.GetAllQuestions(spec, query.Projection{})
+	if err != nil {
+		h.logger.Errorf("Failed to get questions for the feed: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return feed.Feed{}, false
+	}
+
+	base := h.baseURL(r)
+	f := feed.Feed{
+		Title:       "Newest questions",
+		Description: fmt.Sprintf("The %d newest questions", FeedSize),
+		Link:        withQuery(base+"/questions", r.URL.RawQuery),
+		SelfURL:     withQuery(base+"/feeds/"+name, r.URL.RawQuery),
+	}
+	f.ID = f.SelfURL
+	for _, q := range questions {
+		link := fmt.Sprintf("%s/questions/%d", base, q.ID)
+		f.Entries = append(f.Entries, feed.Entry{
+			ID:         link,
+			Title:      q.Title,
+			Link:       link,
+			Author:     authorName(q.UserID),
+			HTML:       markdown.Render(q.Body),
+			Categories: q.Tags,
+			Published:  q.CreatedAt,
+			Updated:    q.UpdatedAt,
+		})
+		if q.UpdatedAt.After(f.Updated) {
+			f.Updated = q.UpdatedAt
+		}
+	}
+	if f.Updated.IsZero() {
+		// В пустой ленте нет времени изменения, а Atom требует его; начало эпохи не меняется между запросами
+		f.Updated = time.Unix(0, 0)
+	}
+	return f, true
+}
+
+// QuestionAtom отдает ленту Atom ответов на вопрос.
+// @Summary Atom feed of the answers to a question
+// @Description Atom 1.0 feed of the 50 newest answers to a question. Supports If-None-Match and
+// @Description If-Modified-Since.
+// @Tags feeds
+// @Produce application/atom+xml
+// @Param id path int true "Question ID"
+// @Success 200 {string} string "Atom feed"
+// @Success 304 "Not Modified"
+// @Failure 400 {string} string "Invalid question ID"
+// @Failure 404 {string} string "Question not found"
+// @Failure 500 {string} string "Failed to get question"
+// @Router /questions/{id}/feed.atom [get]
+func (h *FeedHandler) QuestionAtom(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.logger.Infof("Received request for the Atom feed of question %s", idStr)
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid question ID: %s, error: %v", idStr, err)
+		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+		return
+	}
+
+	question, err := h.service.GetQuestion(uint(id), query.Projection{Include: []string{query.IncludeAnswers}})
+	if err != nil {
+		h.logger.Errorf("Failed to get question with ID %d: %v", id, err)
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Question not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get question", http.StatusInternalServerError)
+		return
+	}
+
+	answers := slices.Clone(question.Answers)
+	slices.SortFunc(answers, func(a, b models.Answer) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	answers = answers[:min(len(answers), FeedSize)]
+
+	base := h.baseURL(r)
+	link := fmt.Sprintf("%s/questions/%d", base, question.ID)
+	// Время изменения вопроса учитывает изменения его ответов
+	f := feed.Feed{
+		ID:      link + "/feed.atom",
+		Title:   "Answers: " + question.Title,
+		Link:    link,
+		SelfURL: link + "/feed.atom",
+		Updated: question.UpdatedAt,
+	}
+	for _, a := range answers {
+		answerLink := fmt.Sprintf("%s/answers/%d", base, a.ID)
+		f.Entries = append(f.Entries, feed.Entry{
+			ID:        answerLink,
+			Title:     fmt.Sprintf("Answer #%d: %s", a.ID, question.Title),
+			Link:      answerLink,
+			Author:    authorName(&a.UserID),
+			HTML:      markdown.Render(a.Text),
+			Published: a.CreatedAt,
+			Updated:   a.UpdatedAt,
+		})
+	}
+	h.writeFeed(w, r, feed.ContentTypeAtom, f.Atom, f.Updated)
+}
+
+// writeFeed кодирует ленту и отдает ее, если у клиента нет актуальной копии. ETag - хеш тела ленты,
+// поэтому он меняется при любом изменении записей, в том числе при удалении вопроса.
+func (h *FeedHandler) writeFeed(
+	w http.ResponseWriter, r *http.Request, contentType string, encode func() ([]byte, error), updated time.Time,
+) {
+	body, err := encode()
+	if err != nil {
+		h.logger.Errorf("Failed to encode feed: %v", err)
+		http.Error(w, "Failed to encode feed", http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(body)
+	if notModified(w, r, `"`+hex.EncodeToString(sum[:16])+`"`, updated) {
+		h.logger.Info("Feed not modified")
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if _, err := w.Write(body); err != nil {
+		h.logger.Errorf("Failed to write feed: %v", err)
+	}
+}
+
+// baseURL возвращает адрес, от которого строятся ссылки на ресурсы API, например https://qa.example.com/api/v1.
+func (h *FeedHandler) baseURL(r *http.Request) string {
+	if h.publicURL != "" {
+		return h.publicURL + h.apiPrefix
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + h.apiPrefix
+}
+
+// withQuery добавляет к адресу строку запроса, если она не пуста.
+func withQuery(link, rawQuery string) string {
+	if rawQuery == "" {
+		return link
+	}
+	return link + "?" + rawQuery
+}
+
+// authorName возвращает имя автора записи ленты: ID пользователя или anonymousAuthor.
+func authorName(userID *uuid.UUID) string {
+	if userID == nil {
+		return anonymousAuthor
+	}
+	return userID.String()
+}
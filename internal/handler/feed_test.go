@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/repository"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// testAtomFeed - поля ленты Atom, которые проверяют тесты.
+type testAtomFeed struct {
+	ID      string `xml:"id"`
+	Title   string `xml:"title"`
+	Updated string `xml:"updated"`
+	Entries []struct {
+		ID         string `xml:"id"`
+		Title      string `xml:"title"`
+		Author     string `xml:"author>name"`
+		Content    string `xml:"content"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+// testRSS - поля ленты RSS, которые проверяют тесты.
+type testRSS struct {
+	Items []struct {
+		Title string `xml:"title"`
+		GUID  string `xml:"guid"`
+	} `xml:"channel>item"`
+}
+
+func newFeedRouter(t *testing.T, publicURL string) (http.Handler, service.Service) {
+	logger := logrus.New()
+	s := service.NewService(repository.NewMemoryRepository(logger), logger, nil)
+	_, err := s.CreateQuestion(&models.Question{
+		Title: "How to install Go?", Body: "Is `brew` enough?", Tags: models.Tags{"go", "macos"},
+	}, false)
+	assert.NoError(t, err)
+	_, err = s.CreateQuestion(
+		&models.Question{Title: "Tabs & spaces <again>", Body: "<script>alert(1)</script>"}, false)
+	assert.NoError(t, err)
+
+	h := NewFeedHandler(s, publicURL, "/api/v1", logger)
+	r := chi.NewRouter()
+	r.Get("/feeds/questions.atom", h.QuestionsAtom)
+	r.Get("/feeds/questions.rss", h.QuestionsRSS)
+	r.Get("/questions/{id}/feed.atom", h.QuestionAtom)
+	return r, s
+}
+
+func getFeed(router http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestQuestionsAtomFeed(t *testing.T) {
+	router, _ := newFeedRouter(t, "")
+
+	rr := getFeed(router, "/feeds/questions.atom?status=open", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.NotEmpty(t, rr.Header().Get("ETag"))
+	assert.NotEmpty(t, rr.Header().Get("Last-Modified"))
+
+	var f testAtomFeed
+	assert.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &f))
+	assert.Equal(t, "http://example.com/api/v1/feeds/questions.atom?status=open", f.ID)
+	if assert.Len(t, f.Entries, 2) {
+		// Новые вопросы первыми; текст экранирован при кодировании и восстанавливается при разборе
+		assert.Equal(t, "http://example.com/api/v1/questions/2", f.Entries[0].ID)
+		assert.Equal(t, "Tabs & spaces <again>", f.Entries[0].Title)
+		assert.Equal(t, "Anonymous", f.Entries[0].Author)
+		assert.NotContains(t, f.Entries[0].Content, "<script>")
+		assert.Equal(t, "<p>Is <code>brew</code> enough?</p>\n", f.Entries[1].Content)
+	}
+	assert.NotContains(t, rr.Body.String(), "Tabs & spaces")
+}
+
+func TestQuestionsFeedByTag(t *testing.T) {
+	router, _ := newFeedRouter(t, "")
+
+	rr := getFeed(router, "/feeds/questions.atom?tag=Go", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var f testAtomFeed
+	assert.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &f))
+	if assert.Len(t, f.Entries, 1) {
+		assert.Equal(t, "How to install Go?", f.Entries[0].Title)
+		if assert.Len(t, f.Entries[0].Categories, 2) {
+			assert.Equal(t, "go", f.Entries[0].Categories[0].Term)
+			assert.Equal(t, "macos", f.Entries[0].Categories[1].Term)
+		}
+	}
+
+	rr = getFeed(router, "/feeds/questions.rss?tag=go,linux", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var empty testRSS
+	assert.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &empty))
+	assert.Empty(t, empty.Items)
+}
+
+func TestQuestionsFeedConditionalGet(t *testing.T) {
+	router, s := newFeedRouter(t, "")
+
+	rr := getFeed(router, "/feeds/questions.rss", nil)
+	etag, lastModified := rr.Header().Get("ETag"), rr.Header().Get("Last-Modified")
+
+	rr = getFeed(router, "/feeds/questions.rss", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	rr = getFeed(router, "/feeds/questions.rss", http.Header{"If-Modified-Since": {lastModified}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+
+	// Удаление вопроса меняет ленту, даже если время последнего изменения не выросло
+	assert.NoError(t, s.DeleteQuestion(2, nil))
+	rr = getFeed(router, "/feeds/questions.rss", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEqual(t, etag, rr.Header().Get("ETag"))
+}
+
+func TestQuestionsRSSFeed(t *testing.T) {
+	router, _ := newFeedRouter(t, "https://qa.example.com")
+
+	rr := getFeed(router, "/feeds/questions.rss", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", rr.Header().Get("Content-Type"))
+
+	var f testRSS
+	assert.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &f))
+	if assert.Len(t, f.Items, 2) {
+		assert.Equal(t, "https://qa.example.com/api/v1/questions/2", f.Items[0].GUID)
+		assert.Equal(t, "How to install Go?", f.Items[1].Title)
+	}
+}
+
+func TestQuestionsFeedRejectsInvalidTagAndSort(t *testing.T) {
+	router, _ := newFeedRouter(t, "")
+
+	rr := getFeed(router, "/feeds/questions.atom?tag=hello+world&sort=votes", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var resp InvalidParamsResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Params, 2) {
+		assert.Equal(t, "tag", resp.Params[0].Param)
+		assert.Equal(t, "sort", resp.Params[1].Param)
+	}
+}
+
+func TestQuestionAtomFeed(t *testing.T) {
+	router, s := newFeedRouter(t, "")
+	first := &models.Answer{Text: "Use the official installer"}
+	assert.NoError(t, s.CreateAnswer(1, first))
+	assert.NoError(t, s.CreateAnswer(1, &models.Answer{Text: "Use **brew**"}))
+
+	rr := getFeed(router, "/questions/1/feed.atom", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var f testAtomFeed
+	assert.NoError(t, xml.Unmarshal(rr.Body.Bytes(), &f))
+	assert.Equal(t, "http://example.com/api/v1/questions/1/feed.atom", f.ID)
+	assert.Equal(t, "Answers: How to install Go?", f.Title)
+	if assert.Len(t, f.Entries, 2) {
+		assert.Equal(t, "http://example.com/api/v1/answers/2", f.Entries[0].ID)
+		assert.Equal(t, "<p>Use <strong>brew</strong></p>\n", f.Entries[0].Content)
+		assert.Equal(t, first.UserID.String(), f.Entries[1].Author)
+	}
+
+	rr = getFeed(router, "/questions/1/feed.atom", http.Header{"If-None-Match": {rr.Header().Get("ETag")}})
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+}
+
+func TestQuestionAtomFeedErrors(t *testing.T) {
+	router, _ := newFeedRouter(t, "")
+
+	assert.Equal(t, http.StatusBadRequest, getFeed(router, "/questions/abc/feed.atom", nil).Code)
+	assert.Equal(t, http.StatusNotFound, getFeed(router, "/questions/42/feed.atom", nil).Code)
+}
+
+func TestQuestionAtomFeedStorageError(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("GetQuestion", uint(1), mock.Anything).Return(nil, errors.New("connection refused"))
+	h := NewFeedHandler(mockService, "", "/api/v1", logrus.New())
+	r := chi.NewRouter()
+	r.Get("/questions/{id}/feed.atom", h.QuestionAtom)
+
+	assert.Equal(t, http.StatusInternalServerError, getFeed(r, "/questions/1/feed.atom", nil).Code)
+	mockService.AssertExpectations(t)
+}
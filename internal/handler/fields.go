@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/shenikar/question-service/internal/query"
+)
+
+// project оставляет в JSON-представлении ресурса только запрошенные поля и встроенные данные.
+// Запрошенные, но пустые встроенные данные все равно попадают в результат:
+// списки как [], автор как null.
+func project(v any, proj query.Projection, res query.Resource) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+
+	for key := range obj {
+		if slices.Contains(res.Includes, key) {
+			if !proj.Includes(key) {
+				delete(obj, key)
+			}
+			continue
+		}
+		if !proj.HasField(key) {
+			delete(obj, key)
+		}
+	}
+	for _, include := range proj.Include {
+		if _, ok := obj[include]; ok {
+			continue
+		}
+		if include == query.IncludeAuthor {
+			obj[include] = json.RawMessage("null")
+		} else {
+			obj[include] = json.RawMessage("[]")
+		}
+	}
+	return obj, nil
+}
+
+// projectQuestions применяет project к каждому вопросу списка.
+func projectQuestions(questions []QuestionResponse, proj query.Projection) ([]map[string]json.RawMessage, error) {
+	resp := make([]map[string]json.RawMessage, 0, len(questions))
+	for i := range questions {
+		if proj.Includes(query.IncludeAuthor) {
+			withQuestionAuthors(&questions[i])
+		}
+		obj, err := project(&questions[i], proj, query.QuestionResource)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, obj)
+	}
+	return resp, nil
+}
+
+// writeQuestion отвечает представлением вопроса с запрошенными полями и встроенными данными.
+func (h *Handler) writeQuestion(w http.ResponseWriter, question QuestionResponse, proj query.Projection) {
+	if proj.Includes(query.IncludeAuthor) {
+		withQuestionAuthors(&question)
+	}
+	h.writeProjected(w, &question, proj, query.QuestionResource)
+}
+
+// writeAnswer отвечает представлением ответа с запрошенными полями и встроенными данными.
+func (h *Handler) writeAnswer(w http.ResponseWriter, answer AnswerResponse, proj query.Projection) {
+	if proj.Includes(query.IncludeAuthor) {
+		withAnswerAuthors(&answer)
+	}
+	h.writeProjected(w, &answer, proj, query.AnswerResource)
+}
+
+func (h *Handler) writeProjected(w http.ResponseWriter, v any, proj query.Projection, res query.Resource) {
+	obj, err := project(v, proj, res)
+	if err != nil {
+		h.logger.Errorf("Failed to apply projection %+v: %v", proj, err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		h.logger.Errorf("Failed to encode response: %v", err)
+	}
+}
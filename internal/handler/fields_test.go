@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+)
+
+func TestGetAllQuestionsHandlerSparseFields(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	proj := query.Projection{Fields: []string{"id", "title"}}
+	mockService.On("GetAllQuestions", query.QuestionSpec{Sort: query.SortNewest}, proj).
+		Return([]models.Question{{ID: 1, Title: "Question 1", Body: "Body"}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/questions?fields=id,title&include=", nil)
+	rr := httptest.NewRecorder()
+
+	handler.GetQuestions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []map[string]any
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, []map[string]any{{"id": float64(1), "title": "Question 1"}}, resp)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetQuestionHandlerIncludes(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	author, commenter := uuid.New(), uuid.New()
+	proj := query.Projection{Include: []string{query.IncludeComments, query.IncludeAuthor}}
+	mockService.On("GetQuestion", uint(1), proj).Return(&models.Question{
+		ID:       1,
+		UserID:   &author,
+		Title:    "Question 1",
+		Comments: []models.Comment{{ID: 7, UserID: commenter, Text: "Which OS?"}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/questions/1?include=comments,author", nil)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Get("/questions/{id}", handler.GetQuestion)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.NotContains(t, resp, "answers")
+	assert.Contains(t, resp, "body")
+
+	var question QuestionResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &question))
+	assert.Equal(t, author, question.Author.ID)
+	assert.Len(t, question.Comments, 1)
+	assert.Equal(t, commenter, question.Comments[0].Author.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAnswerHandlerIncludesEmptyComments(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	proj := query.Projection{Fields: []string{"text"}, Include: []string{query.IncludeComments}}
+	mockService.On("GetAnswer", uint(1), proj).Return(&models.Answer{ID: 1, Text: "Answer"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/answers/1?fields=text&include=comments", nil)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Get("/answers/{id}", handler.GetAnswer)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"text":"Answer","comments":[]}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetQuestionHandlerInvalidProjection(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	req := httptest.NewRequest(http.MethodGet, "/questions/1?fields=id,password&include=votes", nil)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Get("/questions/{id}", handler.GetQuestion)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	var resp InvalidParamsResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Len(t, resp.Params, 2)
+	assert.Equal(t, query.ParamFields, resp.Params[0].Param)
+	assert.Equal(t, query.ParamInclude, resp.Params[1].Param)
+	mockService.AssertNotCalled(t, "GetQuestion", mock.Anything, mock.Anything)
+}
+
+// Поля ресурсов должны совпадать с полями JSON в DTO, иначе проекция молча их потеряет.
+func TestResourceFieldsMatchResponses(t *testing.T) {
+	for _, tt := range []struct {
+		res  query.Resource
+		resp any
+	}{
+		{query.QuestionResource, QuestionResponse{}},
+		{query.AnswerResource, AnswerResponse{}},
+	} {
+		keys := jsonKeys(reflect.TypeOf(tt.resp))
+		for _, field := range append(tt.res.Fields, tt.res.Includes...) {
+			assert.Contains(t, keys, field, "%T", tt.resp)
+		}
+	}
+}
+
+func jsonKeys(typ reflect.Type) []string {
+	keys := make([]string, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		keys = append(keys, name)
+	}
+	return keys
+}
@@ -2,61 +2,107 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"path"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 
-	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/query"
 	"github.com/shenikar/question-service/internal/service"
 )
 
 // Handler обрабатывает HTTP-запросы.
 type Handler struct {
-	service service.Service
-	logger  *logrus.Logger
+	service      service.Service
+	logger       *logrus.Logger
+	maxBodyBytes int64
 }
 
 // NewHandler создает новый экземпляр обработчика.
-func NewHandler(s service.Service, logger *logrus.Logger) *Handler {
-	return &Handler{service: s, logger: logger}
+// maxBodyBytes ограничивает размер JSON-тела запросов.
+func NewHandler(s service.Service, logger *logrus.Logger, maxBodyBytes int64) *Handler {
+	return &Handler{service: s, logger: logger, maxBodyBytes: maxBodyBytes}
 }
 
 // CreateQuestion создает новый вопрос.
 // @Summary Create a new question
-// @Description Create a new question with the input payload
+// @Description Create a new question with the input payload and return similar existing questions
 // @Tags questions
 // @Accept  json
 // @Produce  json
-// @Param question body models.Question true "Question to create"
-// @Success 201 {object} models.Question
+// @Param question body CreateQuestionRequest true "Question to create"
+// @Param strict query bool false "Reject the question if similar questions exist"
+// @Param Idempotency-Key header string false "Unique key that makes retries of this request safe"
+// @Success 201 {object} CreateQuestionResponse
+// @Failure 400 {string} string "Invalid request body or tags"
+// @Failure 409 {object} DuplicateQuestionResponse
+// @Failure 422 {string} string "Idempotency-Key was already used with a different request"
 // @Router /questions [post]
 func (h *Handler) CreateQuestion(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("Received request to create question")
-	var question models.Question
-	if err := json.NewDecoder(r.Body).Decode(&question); err != nil {
+	strict := false
+	if strictStr := r.URL.Query().Get("strict"); strictStr != "" {
+		var err error
+		if strict, err = strconv.ParseBool(strictStr); err != nil {
+			h.logger.Warnf("Invalid strict parameter: %s, error: %v", strictStr, err)
+			http.Error(w, "Invalid strict parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var req CreateQuestionRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
 		h.logger.Warnf("Failed to decode request body: %v", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBodyError(w, err)
 		return
 	}
 
 	validate := validator.New()
-	if err := validate.Struct(&question); err != nil {
+	if err := validate.Struct(&req); err != nil {
 		h.logger.Warnf("Validation failed for question: %v", err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.CreateQuestion(&question); err != nil {
+	question := toQuestionModel(&req)
+	if identity, ok := auth.FromContext(r.Context()); ok {
+		question.UserID = &identity.UserID
+	}
+	duplicates, err := h.service.CreateQuestion(question, strict)
+	if errors.Is(err, service.ErrDuplicateQuestion) {
+		h.logger.Warnf("Question rejected as possible duplicate: %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		if err := json.NewEncoder(w).Encode(DuplicateQuestionResponse{
+			Error:              err.Error(),
+			PossibleDuplicates: toSimilarQuestionResponses(duplicates),
+		}); err != nil {
+			h.logger.Errorf("Failed to encode duplicate response for CreateQuestion: %v", err)
+		}
+		return
+	}
+	if errors.Is(err, service.ErrInvalidTags) {
+		h.logger.Warnf("Invalid question tags: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
 		h.logger.Errorf("Failed to create question: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(question); err != nil {
+	if err := json.NewEncoder(w).Encode(CreateQuestionResponse{
+		QuestionResponse:   toQuestionResponse(question),
+		PossibleDuplicates: toSimilarQuestionResponses(duplicates),
+	}); err != nil {
 		h.logger.Errorf("Failed to encode response for CreateQuestion: %v", err)
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		return
@@ -65,12 +111,28 @@ func (h *Handler) CreateQuestion(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetQuestion получает вопрос по ID.
+// Вопрос, закрытый как дубликат, перенаправляет читателя на исходный вопрос.
+// Поддерживаются условные запросы: если представление не изменилось, возвращается 304.
 // @Summary Get a question by ID
-// @Description Get a question by its ID
+// @Description Get a question by its ID. Questions closed as duplicates redirect to the original question
+// @Description unless redirect=false is passed. Supports conditional requests with If-None-Match and
+// @Description If-Modified-Since; the ETag changes whenever the question, its answers or comments change.
 // @Tags questions
 // @Produce  json
 // @Param id path int true "Question ID"
-// @Success 200 {object} models.Question
+// @Param redirect query bool false "Redirect from a duplicate to the original question" default(true)
+// @Param fields query []string false "Question fields to return, comma-separated" collectionFormat(csv)
+// @Param include query []string false "Related data to embed: answers (default), comments, author" collectionFormat(csv)
+// @Param If-None-Match header string false "ETag of a previously received representation"
+// @Param If-Modified-Since header string false "Last-Modified of a previously received representation"
+// @Success 200 {object} QuestionResponse
+// @Header 200 {string} ETag "Strong entity tag of the representation"
+// @Header 200 {string} Last-Modified "Time of the last change"
+// @Success 301 "Moved Permanently"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} InvalidParamsResponse
+// @Failure 404 {string} string "Question not found"
+// @Failure 500 {string} string "Failed to get question"
 // @Router /questions/{id} [get]
 func (h *Handler) GetQuestion(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -82,39 +144,90 @@ func (h *Handler) GetQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	question, err := h.service.GetQuestion(uint(id))
+	proj, err := query.ParseProjection(r.URL.Query(), query.QuestionResource)
+	if err != nil {
+		h.logger.Warnf("Invalid question projection: %v", err)
+		writeQueryError(w, h.logger, err)
+		return
+	}
+
+	question, err := h.service.GetQuestion(uint(id), proj)
 	if err != nil {
 		h.logger.Errorf("Failed to get question with ID %d: %v", id, err)
-		http.Error(w, "Question not found", http.StatusNotFound)
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Question not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get question", http.StatusInternalServerError)
 		return
 	}
 
-	if err := json.NewEncoder(w).Encode(question); err != nil {
-		h.logger.Errorf("Failed to encode response for GetQuestion: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	if question.DuplicateOf != nil && r.URL.Query().Get("redirect") != "false" {
+		// Параметры запроса (fields, include) сохраняются, чтобы оригинал вернулся в том же виде
+		target := path.Join(path.Dir(r.URL.Path), strconv.FormatUint(uint64(*question.DuplicateOf), 10))
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		h.logger.Infof("Question with ID %d is a duplicate, redirecting to %s", id, target)
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+		return
+	}
+
+	etag := entityTag("questions", question.ID, question.Version, question.UpdatedAt, proj)
+	if notModified(w, r, etag, question.UpdatedAt) {
+		h.logger.Infof("Question with ID %d not modified", id)
 		return
 	}
+
+	h.writeQuestion(w, toQuestionResponse(question), proj)
 	h.logger.Infof("Question with ID %d retrieved successfully", id)
 }
 
-// GetQuestions получает все вопросы.
+// GetQuestions получает список вопросов с фильтрами и сортировкой.
 // @Summary Get all questions
-// @Description Get a list of all questions
+// @Description Get a list of questions filtered and sorted by query parameters
 // @Tags questions
 // @Produce  json
-// @Success 200 {array} models.Question
+// @Param status query []string false "Statuses to include, comma-separated or repeated" collectionFormat(csv)
+// @Param created_after query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_before query string false "Created before (RFC 3339 or YYYY-MM-DD)"
+// @Param author query string false "Author user ID" format(uuid)
+// @Param has_answers query bool false "Only questions with (true) or without (false) answers"
+// @Param min_answers query int false "Minimum number of answers" minimum(0)
+// @Param max_answers query int false "Maximum number of answers" minimum(0)
+// @Param q query string false "Case-insensitive text contained in the title or body"
+// @Param tag query []string false "Tags the question must all have, comma-separated or repeated" collectionFormat(csv)
+// @Param sort query string false "Sort order" Enums(newest, oldest, answers, activity, votes) default(newest)
+// @Param fields query []string false "Question fields to return, comma-separated" collectionFormat(csv)
+// @Param include query []string false "Related data to embed: answers (default), comments, author" collectionFormat(csv)
+// @Success 200 {array} QuestionResponse
+// @Failure 400 {object} InvalidParamsResponse
 // @Router /questions [get]
 func (h *Handler) GetQuestions(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("Received request to get all questions")
-	questions, err := h.service.GetAllQuestions()
+	spec, specErr := query.ParseQuestionSpec(r.URL.Query())
+	proj, projErr := query.ParseProjection(r.URL.Query(), query.QuestionResource)
+	if err := query.Join(specErr, projErr); err != nil {
+		h.logger.Warnf("Invalid question list parameters: %v", err)
+		writeQueryError(w, h.logger, err)
+		return
+	}
+
+	questions, err := h.service.GetAllQuestions(spec, proj)
 	if err != nil {
 		h.logger.Errorf("Failed to get all questions: %v", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	resp, err := projectQuestions(toQuestionResponses(questions), proj)
+	if err != nil {
+		h.logger.Errorf("Failed to apply projection %+v: %v", proj, err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(questions); err != nil {
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		h.logger.Errorf("Failed to encode response for GetQuestions: %v", err)
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		return
@@ -122,12 +235,33 @@ func (h *Handler) GetQuestions(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("All questions retrieved successfully")
 }
 
-// DeleteQuestion удаляет вопрос по ID.
+// writeQueryError отвечает 400 со списком некорректных параметров строки запроса.
+func writeQueryError(w http.ResponseWriter, logger *logrus.Logger, err error) {
+	resp := InvalidParamsResponse{Error: "invalid query parameters", Params: []InvalidParamResponse{}}
+	var queryErr *query.Error
+	if errors.As(err, &queryErr) {
+		resp.Params = toInvalidParamResponses(queryErr.Params)
+	} else {
+		resp.Error = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Errorf("Failed to encode invalid parameters response: %v", err)
+	}
+}
+
+// DeleteQuestion удаляет вопрос по ID. С заголовком If-Match вопрос удаляется, только если не изменился.
 // @Summary Delete a question by ID
-// @Description Delete a question by its ID
+// @Description Delete a question by its ID. With If-Match the question is deleted only if its version
+// @Description still matches the given ETag.
 // @Tags questions
 // @Param id path int true "Question ID"
+// @Param If-Match header string false "ETag of the question version to delete"
 // @Success 204 "No Content"
+// @Failure 404 {string} string "Question not found"
+// @Failure 412 {string} string "Question has been modified"
 // @Router /questions/{id} [delete]
 func (h *Handler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -139,9 +273,9 @@ func (h *Handler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeleteQuestion(uint(id)); err != nil {
+	if err := h.service.DeleteQuestion(uint(id), ifMatchVersions(r)); err != nil {
 		h.logger.Errorf("Failed to delete question with ID %d: %v", id, err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.writeModifyError(w, r, err)
 		return
 	}
 
@@ -149,15 +283,72 @@ func (h *Handler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
 	h.logger.Infof("Question with ID %d deleted successfully", id)
 }
 
+// MarkDuplicate закрывает вопрос как дубликат другого вопроса.
+// @Summary Close a question as a duplicate
+// @Description Close a question as a duplicate of another question. Requires the moderator role.
+// @Tags questions
+// @Param id path int true "Question ID"
+// @Param otherID path int true "Original question ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Invalid duplicate target"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Question not found"
+// @Failure 409 {string} string "Question is locked"
+// @Router /questions/{id}/duplicate-of/{otherID} [post]
+func (h *Handler) MarkDuplicate(w http.ResponseWriter, r *http.Request) {
+	idStr, otherIDStr := chi.URLParam(r, "id"), chi.URLParam(r, "otherID")
+	h.logger.Infof("Received request to mark question %s as duplicate of %s", idStr, otherIDStr)
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid question ID: %s, error: %v", idStr, err)
+		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+		return
+	}
+	otherID, err := strconv.ParseUint(otherIDStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid original question ID: %s, error: %v", otherIDStr, err)
+		http.Error(w, "Invalid original question ID", http.StatusBadRequest)
+		return
+	}
+
+	identity, ok := auth.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.MarkDuplicate(uint(id), uint(otherID), identity.UserID); err != nil {
+		h.logger.Errorf("Failed to mark question %d as duplicate of %d: %v", id, otherID, err)
+		switch {
+		case errors.Is(err, service.ErrInvalidDuplicate):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidStatusTransition), errors.Is(err, service.ErrVersionConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Infof("Question with ID %d marked as duplicate of %d", id, otherID)
+}
+
 // CreateAnswer создает ответ на вопрос.
 // @Summary Create an answer for a question
-// @Description Create an answer for a specific question
+// @Description Create an answer for a specific question. The author is the user from X-User-ID; anonymous
+// @Description answers get a new user ID.
 // @Tags answers
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Question ID"
-// @Param answer body models.Answer true "Answer to create"
-// @Success 201 {object} models.Answer
+// @Param answer body CreateAnswerRequest true "Answer to create"
+// @Param Idempotency-Key header string false "Unique key that makes retries of this request safe"
+// @Success 201 {object} AnswerResponse
+// @Failure 409 {string} string "Question is not open"
+// @Failure 422 {string} string "Idempotency-Key was already used with a different request"
 // @Router /questions/{id}/answers [post]
 func (h *Handler) CreateAnswer(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -169,28 +360,36 @@ func (h *Handler) CreateAnswer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var answer models.Answer
-	if err := json.NewDecoder(r.Body).Decode(&answer); err != nil {
+	var req CreateAnswerRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
 		h.logger.Warnf("Failed to decode answer request body: %v", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBodyError(w, err)
 		return
 	}
 
 	validate := validator.New()
-	if err := validate.Struct(&answer); err != nil {
+	if err := validate.Struct(&req); err != nil {
 		h.logger.Warnf("Validation failed for answer: %v", err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.CreateAnswer(uint(id), &answer); err != nil {
+	answer := toAnswerModel(&req)
+	if identity, ok := auth.FromContext(r.Context()); ok {
+		answer.UserID = identity.UserID
+	}
+	if err := h.service.CreateAnswer(uint(id), answer); err != nil {
 		h.logger.Errorf("Failed to create answer for question ID %d: %v", id, err)
+		if errors.Is(err, service.ErrQuestionNotOpen) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(answer); err != nil {
+	if err := json.NewEncoder(w).Encode(toAnswerResponse(answer)); err != nil {
 		h.logger.Errorf("Failed to encode response for CreateAnswer: %v", err)
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 		return
@@ -198,13 +397,23 @@ func (h *Handler) CreateAnswer(w http.ResponseWriter, r *http.Request) {
 	h.logger.Infof("Answer created successfully for question ID %d", id)
 }
 
-// GetAnswer получает ответ по ID.
+// GetAnswer получает ответ по ID. Поддерживаются условные запросы, как и для вопроса.
 // @Summary Get an answer by ID
-// @Description Get an answer by its ID
+// @Description Get an answer by its ID. Supports conditional requests with If-None-Match and If-Modified-Since.
 // @Tags answers
 // @Produce  json
 // @Param id path int true "Answer ID"
-// @Success 200 {object} models.Answer
+// @Param fields query []string false "Answer fields to return, comma-separated" collectionFormat(csv)
+// @Param include query []string false "Related data to embed: comments, author" collectionFormat(csv)
+// @Param If-None-Match header string false "ETag of a previously received representation"
+// @Param If-Modified-Since header string false "Last-Modified of a previously received representation"
+// @Success 200 {object} AnswerResponse
+// @Header 200 {string} ETag "Strong entity tag of the representation"
+// @Header 200 {string} Last-Modified "Time of the last change"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} InvalidParamsResponse
+// @Failure 404 {string} string "Answer not found"
+// @Failure 500 {string} string "Failed to get answer"
 // @Router /answers/{id} [get]
 func (h *Handler) GetAnswer(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -216,27 +425,44 @@ func (h *Handler) GetAnswer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	answer, err := h.service.GetAnswer(uint(id))
+	proj, err := query.ParseProjection(r.URL.Query(), query.AnswerResource)
+	if err != nil {
+		h.logger.Warnf("Invalid answer projection: %v", err)
+		writeQueryError(w, h.logger, err)
+		return
+	}
+
+	answer, err := h.service.GetAnswer(uint(id), proj)
 	if err != nil {
 		h.logger.Errorf("Failed to get answer with ID %d: %v", id, err)
-		http.Error(w, "Answer not found", http.StatusNotFound)
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Answer not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get answer", http.StatusInternalServerError)
 		return
 	}
 
-	if err := json.NewEncoder(w).Encode(answer); err != nil {
-		h.logger.Errorf("Failed to encode response for GetAnswer: %v", err)
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	etag := entityTag("answers", answer.ID, answer.Version, answer.UpdatedAt, proj)
+	if notModified(w, r, etag, answer.UpdatedAt) {
+		h.logger.Infof("Answer with ID %d not modified", id)
 		return
 	}
+
+	h.writeAnswer(w, toAnswerResponse(answer), proj)
 	h.logger.Infof("Answer with ID %d retrieved successfully", id)
 }
 
-// DeleteAnswer удаляет ответ по ID.
+// DeleteAnswer удаляет ответ по ID. С заголовком If-Match ответ удаляется, только если не изменился.
 // @Summary Delete an answer by ID
-// @Description Delete an answer by its ID
+// @Description Delete an answer by its ID. With If-Match the answer is deleted only if its version
+// @Description still matches the given ETag.
 // @Tags answers
 // @Param id path int true "Answer ID"
+// @Param If-Match header string false "ETag of the answer version to delete"
 // @Success 204 "No Content"
+// @Failure 404 {string} string "Answer not found"
+// @Failure 412 {string} string "Answer has been modified"
 // @Router /answers/{id} [delete]
 func (h *Handler) DeleteAnswer(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -248,12 +474,63 @@ func (h *Handler) DeleteAnswer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeleteAnswer(uint(id)); err != nil {
+	if err := h.service.DeleteAnswer(uint(id), ifMatchVersions(r)); err != nil {
 		h.logger.Errorf("Failed to delete answer with ID %d: %v", id, err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.writeModifyError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 	h.logger.Infof("Answer with ID %d deleted successfully", id)
 }
+
+// AcceptAnswer отмечает ответ принятым автором вопроса.
+// @Summary Accept an answer
+// @Description Mark an answer as accepted and return its question. Only the author of the question can accept
+// @Description an answer, and only while the question is open; a previously accepted answer is replaced.
+// @Description The author of the answer is notified.
+// @Tags answers
+// @Produce  json
+// @Param id path int true "Answer ID"
+// @Success 200 {object} QuestionResponse
+// @Failure 400 {string} string "Invalid answer ID"
+// @Failure 401 {string} string "Authentication required"
+// @Failure 403 {string} string "Only the question author can accept an answer"
+// @Failure 404 {string} string "Answer not found"
+// @Failure 409 {string} string "Question is not open or has been modified"
+// @Router /answers/{id}/accept [post]
+func (h *Handler) AcceptAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.logger.Infof("Received request to accept answer with ID: %s", idStr)
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid answer ID to accept: %s, error: %v", idStr, err)
+		http.Error(w, "Invalid answer ID", http.StatusBadRequest)
+		return
+	}
+
+	identity, ok := auth.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	question, err := h.service.AcceptAnswer(uint(id), identity.UserID)
+	if err != nil {
+		h.logger.Errorf("Failed to accept answer with ID %d: %v", id, err)
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrNotQuestionAuthor):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, service.ErrQuestionNotOpen), errors.Is(err, service.ErrVersionConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeQuestion(w, toQuestionResponse(question), query.Projection{})
+	h.logger.Infof("Answer with ID %d accepted for question %d", id, question.ID)
+}
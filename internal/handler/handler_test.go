@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 
+	"github.com/shenikar/question-service/internal/auth"
 	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/service"
 )
 
 // MockService - мок для интерфейса service.Service
@@ -21,29 +27,45 @@ type MockService struct {
 	mock.Mock
 }
 
-func (m *MockService) CreateQuestion(question *models.Question) error {
-	args := m.Called(question)
-	return args.Error(0)
+func (m *MockService) CreateQuestion(question *models.Question, strict bool) ([]models.SimilarQuestion, error) {
+	args := m.Called(question, strict)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SimilarQuestion), args.Error(1)
 }
 
-func (m *MockService) GetQuestion(id uint) (*models.Question, error) {
-	args := m.Called(id)
+func (m *MockService) GetQuestion(id uint, proj query.Projection) (*models.Question, error) {
+	args := m.Called(id, proj)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Question), args.Error(1)
 }
 
-func (m *MockService) GetAllQuestions() ([]models.Question, error) {
-	args := m.Called()
+func (m *MockService) GetAllQuestions(spec query.QuestionSpec, proj query.Projection) ([]models.Question, error) {
+	args := m.Called(spec, proj)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Question), args.Error(1)
 }
 
-func (m *MockService) DeleteQuestion(id uint) error {
-	args := m.Called(id)
+func (m *MockService) GetQuestionsByIDs(ids []uint) (map[uint]models.Question, error) {
+	args := m.Called(ids)
+	return args.Get(0).(map[uint]models.Question), args.Error(1)
+}
+
+func (m *MockService) UpdateQuestion(id uint, ifMatch []int, title, body *string) (*models.Question, error) {
+	args := m.Called(id, ifMatch, title, body)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockService) DeleteQuestion(id uint, ifMatch []int) error {
+	args := m.Called(id, ifMatch)
 	return args.Error(0)
 }
 
@@ -52,32 +74,122 @@ func (m *MockService) CreateAnswer(questionID uint, answer *models.Answer) error
 	return args.Error(0)
 }
 
-func (m *MockService) GetAnswer(id uint) (*models.Answer, error) {
-	args := m.Called(id)
+func (m *MockService) GetAnswer(id uint, proj query.Projection) (*models.Answer, error) {
+	args := m.Called(id, proj)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Answer), args.Error(1)
 }
 
-func (m *MockService) DeleteAnswer(id uint) error {
-	args := m.Called(id)
+func (m *MockService) GetAnswersByQuestionIDs(questionIDs []uint) (map[uint][]models.Answer, error) {
+	args := m.Called(questionIDs)
+	return args.Get(0).(map[uint][]models.Answer), args.Error(1)
+}
+
+func (m *MockService) UpdateAnswer(id uint, ifMatch []int, text string) (*models.Answer, error) {
+	args := m.Called(id, ifMatch, text)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Answer), args.Error(1)
+}
+
+func (m *MockService) DeleteAnswer(id uint, ifMatch []int) error {
+	args := m.Called(id, ifMatch)
+	return args.Error(0)
+}
+
+func (m *MockService) AcceptAnswer(id uint, userID uuid.UUID) (*models.Question, error) {
+	args := m.Called(id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockService) CreateComment(parentType string, parentID uint, comment *models.Comment) error {
+	args := m.Called(parentType, parentID, comment)
 	return args.Error(0)
 }
 
+func (m *MockService) GetComments(parentType string, parentID uint) ([]models.Comment, error) {
+	args := m.Called(parentType, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Comment), args.Error(1)
+}
+
+func (m *MockService) DeleteComment(id uint, userID uuid.UUID, moderator bool) error {
+	args := m.Called(id, userID, moderator)
+	return args.Error(0)
+}
+
+func (m *MockService) MarkDuplicate(id, duplicateOf uint, moderator uuid.UUID) error {
+	args := m.Called(id, duplicateOf, moderator)
+	return args.Error(0)
+}
+
+func (m *MockService) CloseQuestion(id uint, moderator uuid.UUID, reason string) (*models.Question, error) {
+	args := m.Called(id, moderator, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockService) ReopenQuestion(id uint) (*models.Question, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockService) LockQuestion(id uint, moderator uuid.UUID) (*models.Question, error) {
+	args := m.Called(id, moderator)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+func (m *MockService) ArchiveQuestion(id uint, moderator uuid.UUID) (*models.Question, error) {
+	args := m.Called(id, moderator)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Question), args.Error(1)
+}
+
+// defaultQuestionProjection - проекция вопроса для запроса без параметров fields и include.
+var defaultQuestionProjection = query.Projection{Include: []string{query.IncludeAnswers}}
+
+// testMaxBodyBytes - ограничение размера тела запроса в тестах обработчиков.
+const testMaxBodyBytes = 1 << 20
+
+// asModerator добавляет в контекст запроса модератора с указанным ID.
+func asModerator(req *http.Request, moderator uuid.UUID) *http.Request {
+	return req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{
+		UserID: moderator,
+		Role:   auth.RoleModerator,
+	}))
+}
+
 func TestCreateQuestionHandler(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	question := &models.Question{Text: "Test Question"}
+	question := &CreateQuestionRequest{Title: "Test Question"}
 	questionJSON, _ := json.Marshal(question)
 
 	req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBuffer(questionJSON))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
 
-	mockService.On("CreateQuestion", mock.AnythingOfType("*models.Question")).Return(nil)
+	mockService.On("CreateQuestion", mock.AnythingOfType("*models.Question"), false).Return(nil, nil)
 
 	handler.CreateQuestion(rr, req)
 
@@ -85,19 +197,45 @@ func TestCreateQuestionHandler(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateQuestionHandlerSetsAuthor(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	author := uuid.New()
+	questionJSON, _ := json.Marshal(&CreateQuestionRequest{Title: "Test Question"})
+	req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBuffer(questionJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{UserID: author, Role: auth.RoleUser}))
+	rr := httptest.NewRecorder()
+
+	mockService.On("CreateQuestion", mock.MatchedBy(func(q *models.Question) bool {
+		return q.UserID != nil && *q.UserID == author
+	}), false).Return(nil, nil)
+
+	handler.CreateQuestion(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var resp CreateQuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, &author, resp.UserID)
+	mockService.AssertExpectations(t)
+}
+
 func TestCreateQuestionHandlerServiceError(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	question := &models.Question{Text: "Test Question"}
+	question := &CreateQuestionRequest{Title: "Test Question"}
 	questionJSON, _ := json.Marshal(question)
 
 	req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBuffer(questionJSON))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
 
-	mockService.On("CreateQuestion", mock.AnythingOfType("*models.Question")).Return(errors.New("service error"))
+	mockService.On("CreateQuestion", mock.AnythingOfType("*models.Question"), false).
+		Return(nil, errors.New("service error"))
 
 	handler.CreateQuestion(rr, req)
 
@@ -105,12 +243,123 @@ func TestCreateQuestionHandlerServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateQuestionHandlerTags(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	questionJSON, _ := json.Marshal(&CreateQuestionRequest{Title: "Test Question", Tags: []string{"Go", "linux"}})
+	req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBuffer(questionJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	mockService.On("CreateQuestion", mock.MatchedBy(func(q *models.Question) bool {
+		return assert.ObjectsAreEqual(models.Tags{"Go", "linux"}, q.Tags)
+	}), false).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Question).Tags = models.Tags{"go", "linux"}
+	}).Return(nil, nil)
+
+	handler.CreateQuestion(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var resp CreateQuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, []string{"go", "linux"}, resp.Tags)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateQuestionHandlerInvalidTags(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	questionJSON, _ := json.Marshal(&CreateQuestionRequest{Title: "Test Question", Tags: []string{"hello world"}})
+	req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBuffer(questionJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	mockService.On("CreateQuestion", mock.AnythingOfType("*models.Question"), false).
+		Return(nil, fmt.Errorf("%w: invalid tag %q", service.ErrInvalidTags, "hello world"))
+
+	handler.CreateQuestion(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateQuestionHandlerPossibleDuplicates(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	questionJSON, _ := json.Marshal(&CreateQuestionRequest{Title: "How to install Go?"})
+	similar := []models.SimilarQuestion{
+		{Question: models.Question{ID: 7, Title: "How do I install Go?"}, Similarity: 0.8},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBuffer(questionJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	mockService.On("CreateQuestion", mock.AnythingOfType("*models.Question"), false).Return(similar, nil)
+
+	handler.CreateQuestion(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var response CreateQuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	assert.Equal(t, "How to install Go?", response.Title)
+	assert.Len(t, response.PossibleDuplicates, 1)
+	assert.Equal(t, uint(7), response.PossibleDuplicates[0].ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateQuestionHandlerStrictConflict(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	questionJSON, _ := json.Marshal(&CreateQuestionRequest{Title: "How to install Go?"})
+	similar := []models.SimilarQuestion{
+		{Question: models.Question{ID: 7, Title: "How do I install Go?"}, Similarity: 0.8},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/questions?strict=true", bytes.NewBuffer(questionJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	mockService.On("CreateQuestion", mock.AnythingOfType("*models.Question"), true).
+		Return(similar, service.ErrDuplicateQuestion)
+
+	handler.CreateQuestion(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var response DuplicateQuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	assert.Len(t, response.PossibleDuplicates, 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateQuestionHandlerInvalidStrict(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	questionJSON, _ := json.Marshal(&CreateQuestionRequest{Title: "How to install Go?"})
+	req := httptest.NewRequest(http.MethodPost, "/questions?strict=maybe", bytes.NewBuffer(questionJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	handler.CreateQuestion(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "CreateQuestion", mock.Anything, mock.Anything)
+}
+
 func TestCreateQuestionHandlerInvalidInput(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	questionJSON := []byte(`{"text": ""}`) // Пустой текст
+	questionJSON := []byte(`{"title": ""}`) // Пустой заголовок
 	req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBuffer(questionJSON))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
@@ -118,17 +367,173 @@ func TestCreateQuestionHandlerInvalidInput(t *testing.T) {
 	handler.CreateQuestion(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockService.AssertNotCalled(t, "CreateQuestion", mock.Anything)
+	mockService.AssertNotCalled(t, "CreateQuestion", mock.Anything, mock.Anything)
+}
+
+func TestCreateQuestionHandlerUnknownField(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	questionJSON := []byte(`{"title": "Test Question", "id": 42}`) // Клиент не может задать ID
+	req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBuffer(questionJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	handler.CreateQuestion(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNotCalled(t, "CreateQuestion", mock.Anything, mock.Anything)
+}
+
+func TestGetQuestionHandlerSnakeCaseResponse(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	expectedQuestion := &models.Question{
+		ID:    1,
+		Title: "Test Question",
+		Answers: []models.Answer{
+			{ID: 2, QuestionID: 1, Text: "Test Answer"},
+		},
+	}
+
+	mockService.On("GetQuestion", uint(1), defaultQuestionProjection).Return(expectedQuestion, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/questions/1", nil)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Get("/questions/{id}", handler.GetQuestion)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body map[string]any
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+	assert.Contains(t, body, "created_at")
+	assert.NotContains(t, body, "CreatedAt")
+	answers, ok := body["answers"].([]any)
+	assert.True(t, ok)
+	assert.Len(t, answers, 1)
+	assert.Contains(t, answers[0], "question_id")
+	mockService.AssertExpectations(t)
+}
+
+func TestGetQuestionHandlerRendersMarkdown(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	expectedQuestion := &models.Question{
+		ID:    1,
+		Title: "Test Question",
+		Body:  "Use `go mod tidy` <script>alert(1)</script>",
+		Answers: []models.Answer{
+			{ID: 2, QuestionID: 1, Text: "**Bold** answer"},
+		},
+	}
+
+	mockService.On("GetQuestion", uint(1), defaultQuestionProjection).Return(expectedQuestion, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/questions/1", nil)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Get("/questions/{id}", handler.GetQuestion)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var responseQuestion QuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&responseQuestion))
+	assert.Equal(t, expectedQuestion.Body, responseQuestion.Body)
+	assert.Contains(t, responseQuestion.BodyHTML, "<code>go mod tidy</code>")
+	assert.NotContains(t, responseQuestion.BodyHTML, "<script>")
+	assert.Contains(t, responseQuestion.Answers[0].TextHTML, "<strong>Bold</strong>")
+	mockService.AssertExpectations(t)
+}
+
+func TestGetQuestionHandlerRedirectsDuplicate(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	original := uint(1)
+	mockService.On("GetQuestion", uint(2), defaultQuestionProjection).
+		Return(&models.Question{ID: 2, DuplicateOf: &original}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/questions/{id}", handler.GetQuestion)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/questions/2", nil))
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "/questions/1", rr.Header().Get("Location"))
+
+	// Параметры запроса переносятся в адрес оригинала
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/questions/2?include=answers", nil))
+	assert.Equal(t, http.StatusMovedPermanently, rr.Code)
+	assert.Equal(t, "/questions/1?include=answers", rr.Header().Get("Location"))
+
+	// С redirect=false возвращается сам закрытый вопрос
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/questions/2?redirect=false", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var responseQuestion QuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&responseQuestion))
+	assert.Equal(t, &original, responseQuestion.DuplicateOf)
+	mockService.AssertExpectations(t)
+}
+
+func TestMarkDuplicateHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		serviceErr error
+		expected   int
+	}{
+		{name: "success", url: "/questions/2/duplicate-of/1", expected: http.StatusNoContent},
+		{
+			name: "invalid target", url: "/questions/1/duplicate-of/1",
+			serviceErr: service.ErrInvalidDuplicate, expected: http.StatusBadRequest,
+		},
+		{
+			name: "not found", url: "/questions/2/duplicate-of/999",
+			serviceErr: fmt.Errorf("question with ID 999: %w", service.ErrNotFound), expected: http.StatusNotFound,
+		},
+		{
+			name: "locked", url: "/questions/2/duplicate-of/1",
+			serviceErr: service.ErrInvalidStatusTransition, expected: http.StatusConflict,
+		},
+		{name: "invalid ID", url: "/questions/2/duplicate-of/abc", expected: http.StatusBadRequest},
+	}
+
+	moderator := uuid.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+			mockService.On("MarkDuplicate", mock.Anything, mock.Anything, moderator).Return(tt.serviceErr)
+
+			r := chi.NewRouter()
+			r.Post("/questions/{id}/duplicate-of/{otherID}", handler.MarkDuplicate)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, asModerator(httptest.NewRequest(http.MethodPost, tt.url, nil), moderator))
+
+			assert.Equal(t, tt.expected, rr.Code)
+		})
+	}
 }
 
 func TestGetQuestionHandler(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	expectedQuestion := &models.Question{ID: 1, Text: "Test Question"}
+	expectedQuestion := &models.Question{ID: 1, Title: "Test Question"}
 
-	mockService.On("GetQuestion", uint(1)).Return(expectedQuestion, nil)
+	mockService.On("GetQuestion", uint(1), defaultQuestionProjection).Return(expectedQuestion, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/questions/1", nil)
 	rr := httptest.NewRecorder()
@@ -139,7 +544,7 @@ func TestGetQuestionHandler(t *testing.T) {
 	r.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
-	var responseQuestion models.Question
+	var responseQuestion QuestionResponse
 	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&responseQuestion))
 	assert.Equal(t, expectedQuestion.ID, responseQuestion.ID)
 	mockService.AssertExpectations(t)
@@ -148,14 +553,15 @@ func TestGetQuestionHandler(t *testing.T) {
 func TestGetAllQuestionsHandler(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
 	expectedQuestions := []models.Question{
-		{ID: 1, Text: "Question 1"},
-		{ID: 2, Text: "Question 2"},
+		{ID: 1, Title: "Question 1"},
+		{ID: 2, Title: "Question 2"},
 	}
 
-	mockService.On("GetAllQuestions").Return(expectedQuestions, nil)
+	mockService.On("GetAllQuestions", query.QuestionSpec{Sort: query.SortNewest}, defaultQuestionProjection).
+		Return(expectedQuestions, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/questions", nil)
 	rr := httptest.NewRecorder()
@@ -163,17 +569,85 @@ func TestGetAllQuestionsHandler(t *testing.T) {
 	handler.GetQuestions(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
-	var responseQuestions []models.Question
+	var responseQuestions []QuestionResponse
 	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&responseQuestions))
 	assert.Len(t, responseQuestions, 2)
-	assert.Equal(t, expectedQuestions[0].Text, responseQuestions[0].Text)
+	assert.Equal(t, expectedQuestions[0].Title, responseQuestions[0].Title)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAllQuestionsHandlerStatusFilter(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	spec := query.QuestionSpec{
+		Statuses: []string{models.QuestionStatusClosed, models.QuestionStatusLocked},
+		Sort:     query.SortNewest,
+	}
+	mockService.On("GetAllQuestions", spec, defaultQuestionProjection).Return([]models.Question{}, nil)
+
+	// Статусы можно передать через запятую и повторением параметра
+	req := httptest.NewRequest(http.MethodGet, "/questions?status=closed&status=locked", nil)
+	rr := httptest.NewRecorder()
+	handler.GetQuestions(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/questions?status=closed,locked", nil)
+	rr = httptest.NewRecorder()
+	handler.GetQuestions(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertNumberOfCalls(t, "GetAllQuestions", 2)
+}
+
+func TestGetAllQuestionsHandlerInvalidStatus(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	req := httptest.NewRequest(http.MethodGet, "/questions?status=open,deleted&min_answers=x&sort=random", nil)
+	rr := httptest.NewRecorder()
+
+	handler.GetQuestions(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	var resp InvalidParamsResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, "invalid query parameters", resp.Error)
+	assert.Len(t, resp.Params, 3)
+	assert.Equal(t, "status", resp.Params[0].Param)
+	assert.Contains(t, resp.Params[0].Message, `"deleted"`)
+	mockService.AssertNotCalled(t, "GetAllQuestions", mock.Anything, mock.Anything)
+}
+
+func TestGetAllQuestionsHandlerFilterAndSort(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	mockService.On("GetAllQuestions", mock.MatchedBy(func(spec query.QuestionSpec) bool {
+		return spec.Sort == query.SortVotes && spec.Text == "goroutine" && *spec.MinAnswers == 1
+	}), defaultQuestionProjection).
+		Return([]models.Question{{ID: 1, Title: "Goroutine leak", AnswerCount: 2, Score: 7}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/questions?sort=votes&q=goroutine&min_answers=1", nil)
+	rr := httptest.NewRecorder()
+
+	handler.GetQuestions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []QuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, int64(2), resp[0].AnswerCount)
+	assert.Equal(t, 7, resp[0].Score)
 	mockService.AssertExpectations(t)
 }
 
 func TestGetQuestionHandlerInvalidID(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
 	req := httptest.NewRequest(http.MethodGet, "/questions/abc", nil) // Некорректный ID
 	rr := httptest.NewRecorder()
@@ -183,15 +657,16 @@ func TestGetQuestionHandlerInvalidID(t *testing.T) {
 	r.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockService.AssertNotCalled(t, "GetQuestion", mock.Anything)
+	mockService.AssertNotCalled(t, "GetQuestion", mock.Anything, mock.Anything)
 }
 
 func TestGetAllQuestionsHandlerError(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	mockService.On("GetAllQuestions").Return(nil, errors.New("database error"))
+	mockService.On("GetAllQuestions", query.QuestionSpec{Sort: query.SortNewest}, defaultQuestionProjection).
+		Return(nil, errors.New("database error"))
 
 	req := httptest.NewRequest(http.MethodGet, "/questions", nil)
 	rr := httptest.NewRecorder()
@@ -205,9 +680,9 @@ func TestGetAllQuestionsHandlerError(t *testing.T) {
 func TestDeleteQuestionHandler(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	mockService.On("DeleteQuestion", uint(1)).Return(nil)
+	mockService.On("DeleteQuestion", uint(1), []int(nil)).Return(nil)
 
 	req := httptest.NewRequest(http.MethodDelete, "/questions/1", nil)
 	rr := httptest.NewRecorder()
@@ -223,9 +698,10 @@ func TestDeleteQuestionHandler(t *testing.T) {
 func TestGetAllQuestionsHandlerEmpty(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	mockService.On("GetAllQuestions").Return([]models.Question{}, nil)
+	mockService.On("GetAllQuestions", query.QuestionSpec{Sort: query.SortNewest}, defaultQuestionProjection).
+		Return([]models.Question{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/questions", nil)
 	rr := httptest.NewRecorder()
@@ -233,7 +709,7 @@ func TestGetAllQuestionsHandlerEmpty(t *testing.T) {
 	handler.GetQuestions(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
-	var responseQuestions []models.Question
+	var responseQuestions []QuestionResponse
 	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&responseQuestions))
 	assert.Len(t, responseQuestions, 0)
 	mockService.AssertExpectations(t)
@@ -242,9 +718,9 @@ func TestGetAllQuestionsHandlerEmpty(t *testing.T) {
 func TestDeleteQuestionHandlerNotFound(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	mockService.On("DeleteQuestion", uint(999)).Return(errors.New("not found"))
+	mockService.On("DeleteQuestion", uint(999), []int(nil)).Return(errors.New("not found"))
 
 	req := httptest.NewRequest(http.MethodDelete, "/questions/999", nil)
 	rr := httptest.NewRecorder()
@@ -260,10 +736,10 @@ func TestDeleteQuestionHandlerNotFound(t *testing.T) {
 func TestCreateAnswerHandler(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
 	questionID := uint(1)
-	answer := &models.Answer{Text: "Test Answer"}
+	answer := &CreateAnswerRequest{Text: "Test Answer"}
 	answerJSON, _ := json.Marshal(answer)
 
 	req := httptest.NewRequest(http.MethodPost, "/questions/1/answers", bytes.NewBuffer(answerJSON))
@@ -280,10 +756,90 @@ func TestCreateAnswerHandler(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateAnswerHandlerSetsAuthor(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	author := uuid.New()
+	mockService.On("CreateAnswer", uint(1), mock.MatchedBy(func(a *models.Answer) bool {
+		return a.UserID == author
+	})).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/questions/1/answers", bytes.NewBufferString(`{"text": "Answer"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{UserID: author, Role: auth.RoleUser}))
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Post("/questions/{id}/answers", handler.CreateAnswer)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAcceptAnswerHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	author, accepted := uuid.New(), uint(5)
+	mockService.On("AcceptAnswer", uint(5), author).
+		Return(&models.Question{ID: 1, UserID: &author, AcceptedAnswerID: &accepted}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/answers/5/accept", nil)
+	req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{UserID: author, Role: auth.RoleUser}))
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Post("/answers/{id}/accept", handler.AcceptAnswer)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp QuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, &accepted, resp.AcceptedAnswerID)
+	mockService.AssertExpectations(t)
+}
+
+func TestAcceptAnswerHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		anonymous  bool
+		expected   int
+	}{
+		{"anonymous", nil, true, http.StatusUnauthorized},
+		{"not found", fmt.Errorf("answer with ID 5: %w", service.ErrNotFound), false, http.StatusNotFound},
+		{"not the author", service.ErrNotQuestionAuthor, false, http.StatusForbidden},
+		{"conflict", service.ErrVersionConflict, false, http.StatusConflict},
+		{"question not open", fmt.Errorf("question with ID 1 is closed: %w", service.ErrQuestionNotOpen), false,
+			http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+			mockService.On("AcceptAnswer", uint(5), mock.Anything).Return(nil, tt.serviceErr)
+
+			req := httptest.NewRequest(http.MethodPost, "/answers/5/accept", nil)
+			if !tt.anonymous {
+				req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{UserID: uuid.New()}))
+			}
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Post("/answers/{id}/accept", handler.AcceptAnswer)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expected, rr.Code)
+		})
+	}
+}
+
 func TestDeleteQuestionHandlerInvalidID(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
 	req := httptest.NewRequest(http.MethodDelete, "/questions/abc", nil) // Некорректный ID
 	rr := httptest.NewRecorder()
@@ -293,13 +849,13 @@ func TestDeleteQuestionHandlerInvalidID(t *testing.T) {
 	r.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockService.AssertNotCalled(t, "DeleteQuestion", mock.Anything)
+	mockService.AssertNotCalled(t, "DeleteQuestion", mock.Anything, mock.Anything)
 }
 
 func TestCreateAnswerHandlerInvalidInput(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
 	answerJSON := []byte(`{"text": ""}`) // Пустой текст
 	req := httptest.NewRequest(http.MethodPost, "/questions/1/answers", bytes.NewBuffer(answerJSON))
@@ -317,10 +873,10 @@ func TestCreateAnswerHandlerInvalidInput(t *testing.T) {
 func TestCreateAnswerHandlerServiceError(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
 	questionID := uint(1)
-	answer := &models.Answer{Text: "Test Answer"}
+	answer := &CreateAnswerRequest{Text: "Test Answer"}
 	answerJSON, _ := json.Marshal(answer)
 
 	mockService.On("CreateAnswer", questionID, mock.AnythingOfType("*models.Answer")).
@@ -338,14 +894,36 @@ func TestCreateAnswerHandlerServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateAnswerHandlerQuestionNotOpen(t *testing.T) {
+	mockService := new(MockService)
+	logger := logrus.New()
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
+
+	answerJSON, _ := json.Marshal(&CreateAnswerRequest{Text: "Test Answer"})
+
+	mockService.On("CreateAnswer", uint(1), mock.AnythingOfType("*models.Answer")).
+		Return(fmt.Errorf("question with ID 1 is closed: %w", service.ErrQuestionNotOpen))
+
+	req := httptest.NewRequest(http.MethodPost, "/questions/1/answers", bytes.NewBuffer(answerJSON))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Post("/questions/{id}/answers", handler.CreateAnswer)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestGetAnswerHandler(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
 	expectedAnswer := &models.Answer{ID: 1, QuestionID: 1, Text: "Test Answer"}
 
-	mockService.On("GetAnswer", uint(1)).Return(expectedAnswer, nil)
+	mockService.On("GetAnswer", uint(1), query.Projection{}).Return(expectedAnswer, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/answers/1", nil)
 	rr := httptest.NewRecorder()
@@ -355,7 +933,7 @@ func TestGetAnswerHandler(t *testing.T) {
 	r.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
-	var responseAnswer models.Answer
+	var responseAnswer AnswerResponse
 	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&responseAnswer))
 	assert.Equal(t, expectedAnswer.ID, responseAnswer.ID)
 	mockService.AssertExpectations(t)
@@ -364,9 +942,9 @@ func TestGetAnswerHandler(t *testing.T) {
 func TestCreateAnswerHandlerInvalidQuestionID(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	answer := &models.Answer{Text: "Test Answer"}
+	answer := &CreateAnswerRequest{Text: "Test Answer"}
 	answerJSON, _ := json.Marshal(answer)
 
 	req := httptest.NewRequest(http.MethodPost, "/questions/abc/answers", // Некорректный ID вопроса
@@ -385,45 +963,53 @@ func TestCreateAnswerHandlerInvalidQuestionID(t *testing.T) {
 func TestGetAnswerHandlerNotFound(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	mockService.On("GetAnswer", uint(999)).Return(nil, errors.New("not found"))
-
-	req := httptest.NewRequest(http.MethodGet, "/answers/999", nil)
-	rr := httptest.NewRecorder()
+	mockService.On("GetAnswer", uint(999), query.Projection{}).Return(nil, gorm.ErrRecordNotFound)
+	mockService.On("GetAnswer", uint(1), query.Projection{}).Return(nil, errors.New("database error"))
 
 	r := chi.NewRouter()
 	r.Get("/answers/{id}", handler.GetAnswer)
-	r.ServeHTTP(rr, req)
 
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/answers/999", nil))
 	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Прочие ошибки сервиса - не отсутствие ответа
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/answers/1", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	mockService.AssertExpectations(t)
 }
 
 func TestGetQuestionHandlerNotFound(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	mockService.On("GetQuestion", uint(999)).Return(nil, errors.New("not found"))
-
-	req := httptest.NewRequest(http.MethodGet, "/questions/999", nil)
-	rr := httptest.NewRecorder()
+	mockService.On("GetQuestion", uint(999), defaultQuestionProjection).Return(nil, gorm.ErrRecordNotFound)
+	mockService.On("GetQuestion", uint(1), defaultQuestionProjection).Return(nil, errors.New("database error"))
 
 	r := chi.NewRouter()
 	r.Get("/questions/{id}", handler.GetQuestion)
-	r.ServeHTTP(rr, req)
 
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/questions/999", nil))
 	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Прочие ошибки сервиса - не отсутствие вопроса
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/questions/1", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	mockService.AssertExpectations(t)
 }
 
 func TestDeleteAnswerHandler(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	mockService.On("DeleteAnswer", uint(1)).Return(nil)
+	mockService.On("DeleteAnswer", uint(1), []int(nil)).Return(nil)
 
 	req := httptest.NewRequest(http.MethodDelete, "/answers/1", nil)
 	rr := httptest.NewRecorder()
@@ -439,7 +1025,7 @@ func TestDeleteAnswerHandler(t *testing.T) {
 func TestGetAnswerHandlerInvalidID(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
 	req := httptest.NewRequest(http.MethodGet, "/answers/abc", nil) // Некорректный ID
 	rr := httptest.NewRecorder()
@@ -449,15 +1035,15 @@ func TestGetAnswerHandlerInvalidID(t *testing.T) {
 	r.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockService.AssertNotCalled(t, "GetAnswer", mock.Anything)
+	mockService.AssertNotCalled(t, "GetAnswer", mock.Anything, mock.Anything)
 }
 
 func TestDeleteAnswerHandlerNotFound(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
-	mockService.On("DeleteAnswer", uint(999)).Return(errors.New("not found"))
+	mockService.On("DeleteAnswer", uint(999), []int(nil)).Return(errors.New("not found"))
 
 	req := httptest.NewRequest(http.MethodDelete, "/answers/999", nil)
 	rr := httptest.NewRecorder()
@@ -473,7 +1059,7 @@ func TestDeleteAnswerHandlerNotFound(t *testing.T) {
 func TestDeleteAnswerHandlerInvalidID(t *testing.T) {
 	mockService := new(MockService)
 	logger := logrus.New()
-	handler := NewHandler(mockService, logger)
+	handler := NewHandler(mockService, logger, testMaxBodyBytes)
 
 	req := httptest.NewRequest(http.MethodDelete, "/answers/abc", nil) // Некорректный ID
 	rr := httptest.NewRecorder()
@@ -483,5 +1069,5 @@ func TestDeleteAnswerHandlerInvalidID(t *testing.T) {
 	r.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
-	mockService.AssertNotCalled(t, "DeleteAnswer", mock.Anything)
+	mockService.AssertNotCalled(t, "DeleteAnswer", mock.Anything, mock.Anything)
 }
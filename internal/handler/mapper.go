@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/shenikar/question-service/internal/importer"
+	"github.com/shenikar/question-service/internal/markdown"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/notification"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/webhook"
+)
+
+// toQuestionModel преобразует запрос на создание вопроса в модель.
+func toQuestionModel(req *CreateQuestionRequest) *models.Question {
+	return &models.Question{Title: req.Title, Body: req.Body, Tags: req.Tags}
+}
+
+// toQuestionResponse преобразует модель вопроса в DTO ответа.
+func toQuestionResponse(q *models.Question) QuestionResponse {
+	answers := make([]AnswerResponse, 0, len(q.Answers))
+	for i := range q.Answers {
+		answers = append(answers, toAnswerResponse(&q.Answers[i]))
+	}
+
+	// Только что созданный вопрос еще не прочитан из хранилища, и вычисляемые поля пусты
+	lastActivityAt := q.LastActivityAt
+	if lastActivityAt.IsZero() {
+		lastActivityAt = q.CreatedAt
+	}
+
+	resp := QuestionResponse{
+		ID:               q.ID,
+		UserID:           q.UserID,
+		Title:            q.Title,
+		Body:             q.Body,
+		BodyHTML:         markdown.Render(q.Body),
+		Tags:             append([]string{}, q.Tags...),
+		CreatedAt:        q.CreatedAt,
+		UpdatedAt:        q.UpdatedAt,
+		Version:          q.Version,
+		LastActivityAt:   lastActivityAt,
+		Score:            q.Score,
+		Status:           q.Status,
+		CloseReason:      q.CloseReason,
+		ClosedBy:         q.ClosedBy,
+		ClosedAt:         q.ClosedAt,
+		DuplicateOf:      q.DuplicateOf,
+		AcceptedAnswerID: q.AcceptedAnswerID,
+		AnswerCount:      q.AnswerCount,
+		CommentCount:     q.CommentCount,
+		Answers:          answers,
+	}
+	if q.Comments != nil {
+		resp.Comments = toCommentResponses(q.Comments)
+	}
+	return resp
+}
+
+// withQuestionAuthors заполняет авторов вопроса, его ответов и комментариев.
+func withQuestionAuthors(resp *QuestionResponse) {
+	resp.Author = toAuthorResponse(resp.UserID)
+	for i := range resp.Answers {
+		withAnswerAuthors(&resp.Answers[i])
+	}
+	withCommentAuthors(resp.Comments)
+}
+
+// withAnswerAuthors заполняет авторов ответа и его комментариев.
+func withAnswerAuthors(resp *AnswerResponse) {
+	resp.Author = toAuthorResponse(&resp.UserID)
+	withCommentAuthors(resp.Comments)
+}
+
+// withCommentAuthors заполняет авторов комментариев.
+func withCommentAuthors(comments []CommentResponse) {
+	for i := range comments {
+		comments[i].Author = toAuthorResponse(&comments[i].UserID)
+	}
+}
+
+// toAuthorResponse преобразует ID пользователя в DTO автора. Для анонимного автора возвращается nil.
+func toAuthorResponse(userID *uuid.UUID) *AuthorResponse {
+	if userID == nil {
+		return nil
+	}
+	return &AuthorResponse{ID: *userID}
+}
+
+// toSimilarQuestionResponses преобразует список похожих вопросов в DTO ответа.
+func toSimilarQuestionResponses(similar []models.SimilarQuestion) []SimilarQuestionResponse {
+	resp := make([]SimilarQuestionResponse, 0, len(similar))
+	for i := range similar {
+		resp = append(resp, SimilarQuestionResponse{
+			ID:         similar[i].ID,
+			Title:      similar[i].Title,
+			Similarity: similar[i].Similarity,
+			CreatedAt:  similar[i].CreatedAt,
+		})
+	}
+	return resp
+}
+
+// toQuestionResponses преобразует список моделей вопросов в DTO ответа.
+func toQuestionResponses(questions []models.Question) []QuestionResponse {
+	resp := make([]QuestionResponse, 0, len(questions))
+	for i := range questions {
+		resp = append(resp, toQuestionResponse(&questions[i]))
+	}
+	return resp
+}
+
+// toInvalidParamResponses преобразует ошибки разбора параметров запроса в DTO ответа.
+func toInvalidParamResponses(params []query.ParamError) []InvalidParamResponse {
+	resp := make([]InvalidParamResponse, 0, len(params))
+	for _, p := range params {
+		resp = append(resp, InvalidParamResponse{Param: p.Param, Message: p.Message})
+	}
+	return resp
+}
+
+// toAnswerModel преобразует запрос на создание ответа в модель.
+func toAnswerModel(req *CreateAnswerRequest) *models.Answer {
+	return &models.Answer{Text: req.Text}
+}
+
+// toAnswerResponse преобразует модель ответа в DTO ответа.
+func toAnswerResponse(a *models.Answer) AnswerResponse {
+	resp := AnswerResponse{
+		ID:           a.ID,
+		QuestionID:   a.QuestionID,
+		UserID:       a.UserID,
+		Text:         a.Text,
+		TextHTML:     markdown.Render(a.Text),
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+		Version:      a.Version,
+		CommentCount: a.CommentCount,
+	}
+	if a.Comments != nil {
+		resp.Comments = toCommentResponses(a.Comments)
+	}
+	return resp
+}
+
+// toCommentModel преобразует запрос на создание комментария в модель.
+func toCommentModel(req *CreateCommentRequest) *models.Comment {
+	return &models.Comment{Text: req.Text}
+}
+
+// toCommentResponse преобразует модель комментария в DTO ответа.
+func toCommentResponse(c *models.Comment) CommentResponse {
+	return CommentResponse{
+		ID:         c.ID,
+		ParentType: c.ParentType,
+		ParentID:   c.ParentID,
+		UserID:     c.UserID,
+		Text:       c.Text,
+		CreatedAt:  c.CreatedAt,
+	}
+}
+
+// toCommentResponses преобразует список моделей комментариев в DTO ответа.
+func toCommentResponses(comments []models.Comment) []CommentResponse {
+	resp := make([]CommentResponse, 0, len(comments))
+	for i := range comments {
+		resp = append(resp, toCommentResponse(&comments[i]))
+	}
+	return resp
+}
+
+// toImportReportResponse преобразует отчет об импорте в DTO ответа.
+func toImportReportResponse(report *importer.Report) ImportReportResponse {
+	resp := ImportReportResponse{
+		DryRun:  report.DryRun,
+		Created: report.Created,
+		Skipped: report.Skipped,
+		Failed:  report.Failed,
+		Error:   report.Error,
+		Results: make([]ImportResultResponse, 0, len(report.Results)),
+	}
+	for _, result := range report.Results {
+		resp.Results = append(resp.Results, ImportResultResponse{
+			Record:     result.Record,
+			Line:       result.Line,
+			Title:      result.Title,
+			Status:     string(result.Status),
+			QuestionID: result.QuestionID,
+			Answers:    result.Answers,
+			Reason:     result.Reason,
+		})
+	}
+	return resp
+}
+
+// toWebhookResponse преобразует подписку в DTO ответа без ключа подписи.
+func toWebhookResponse(sub *webhook.Subscription) WebhookResponse {
+	return WebhookResponse{
+		ID:          sub.ID,
+		URL:         sub.URL,
+		Events:      sub.Events,
+		Description: sub.Description,
+		Active:      sub.Active,
+		CreatedAt:   sub.CreatedAt,
+		UpdatedAt:   sub.UpdatedAt,
+	}
+}
+
+// toWebhookDeliveryResponse преобразует доставку в DTO ответа. withPayload добавляет тело запроса.
+func toWebhookDeliveryResponse(d *webhook.Delivery, withPayload bool) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastAttemptAt:  d.LastAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		ReplayOf:       d.ReplayOf,
+		CreatedAt:      d.CreatedAt,
+	}
+	if withPayload {
+		resp.Payload = d.Payload
+	}
+	return resp
+}
+
+// toNotificationResponse преобразует уведомление в DTO ответа.
+func toNotificationResponse(n *notification.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:         n.ID,
+		Type:       n.Type,
+		QuestionID: n.QuestionID,
+		AnswerID:   n.AnswerID,
+		ActorID:    n.ActorID,
+		CreatedAt:  n.CreatedAt,
+		ReadAt:     n.ReadAt,
+	}
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/notification"
+)
+
+// Размер страницы уведомлений.
+const (
+	defaultNotificationsLimit = 50
+	maxNotificationsLimit     = 100
+)
+
+// NotificationHandler отдает пользователю его уведомления и отмечает их прочитанными.
+type NotificationHandler struct {
+	store  notification.Store
+	logger *logrus.Logger
+}
+
+// NewNotificationHandler создает обработчик уведомлений.
+func NewNotificationHandler(store notification.Store, logger *logrus.Logger) *NotificationHandler {
+	return &NotificationHandler{store: store, logger: logger}
+}
+
+// ListNotifications возвращает последние уведомления пользователя.
+// @Summary List my notifications
+// @Description Notifications of the current user, newest first: new_answer when someone answers their question,
+// @Description answer_accepted when their answer is accepted and mention when they are mentioned as @<user ID>
+// @Description in a question or an answer. Notifications are created in the background shortly after the event.
+// @Description unread_count is the number of all unread notifications.
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Maximum number of notifications" default(50) maximum(100)
+// @Success 200 {object} NotificationListResponse
+// @Failure 400 {string} string "Invalid request"
+// @Failure 401 {string} string "Authentication required"
+// @Router /me/notifications [get]
+func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	identity, ok := h.identity(w, r)
+	if !ok {
+		return
+	}
+	h.logger.Infof("Received request for notifications of user %s", identity.UserID)
+
+	unreadOnly := false
+	if unreadStr := r.URL.Query().Get("unread"); unreadStr != "" {
+		var err error
+		if unreadOnly, err = strconv.ParseBool(unreadStr); err != nil {
+			h.logger.Warnf("Invalid unread parameter: %s, error: %v", unreadStr, err)
+			http.Error(w, "Invalid unread parameter", http.StatusBadRequest)
+			return
+		}
+	}
+	limit := defaultNotificationsLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 || limit > maxNotificationsLimit {
+			h.logger.Warnf("Invalid limit parameter: %s", limitStr)
+			http.Error(w, "limit must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+	}
+
+	notifications, err := h.store.List(identity.UserID, unreadOnly, limit)
+	if err != nil {
+		h.writeStoreError(w, "Failed to list notifications", err)
+		return
+	}
+	unread, err := h.store.CountUnread(identity.UserID)
+	if err != nil {
+		h.writeStoreError(w, "Failed to count unread notifications", err)
+		return
+	}
+	resp := NotificationListResponse{
+		Notifications: make([]NotificationResponse, 0, len(notifications)),
+		UnreadCount:   unread,
+	}
+	for i := range notifications {
+		resp.Notifications = append(resp.Notifications, toNotificationResponse(&notifications[i]))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("Failed to encode notifications response: %v", err)
+	}
+}
+
+// MarkNotificationRead отмечает уведомление прочитанным.
+// @Summary Mark a notification as read
+// @Description Mark a notification of the current user as read. Marking a read notification again keeps
+// @Description the original read time.
+// @Tags notifications
+// @Param id path int true "Notification ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Invalid notification ID"
+// @Failure 401 {string} string "Authentication required"
+// @Failure 404 {string} string "Notification not found"
+// @Router /me/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	identity, ok := h.identity(w, r)
+	if !ok {
+		return
+	}
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid notification ID: %s, error: %v", idStr, err)
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.store.MarkRead(identity.UserID, uint(id), time.Now()); err != nil {
+		h.writeStoreError(w, "Failed to mark notification as read", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Infof("Notification %d of user %s marked as read", id, identity.UserID)
+}
+
+// MarkAllNotificationsRead отмечает все уведомления пользователя прочитанными.
+// @Summary Mark all my notifications as read
+// @Description Mark all unread notifications of the current user as read.
+// @Tags notifications
+// @Success 204 "No Content"
+// @Failure 401 {string} string "Authentication required"
+// @Router /me/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	identity, ok := h.identity(w, r)
+	if !ok {
+		return
+	}
+	count, err := h.store.MarkAllRead(identity.UserID, time.Now())
+	if err != nil {
+		h.writeStoreError(w, "Failed to mark notifications as read", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Infof("%d notifications of user %s marked as read", count, identity.UserID)
+}
+
+// identity возвращает пользователя запроса. Уведомления есть только у аутентифицированных пользователей,
+// поэтому анонимному клиенту отвечает 401.
+func (h *NotificationHandler) identity(w http.ResponseWriter, r *http.Request) (auth.Identity, bool) {
+	identity, ok := auth.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+	}
+	return identity, ok
+}
+
+// writeStoreError отвечает на ошибку хранилища: 404 для notification.ErrNotFound, иначе 500.
+func (h *NotificationHandler) writeStoreError(w http.ResponseWriter, msg string, err error) {
+	if errors.Is(err, notification.ErrNotFound) {
+		h.logger.Warnf("%s: %v", msg, err)
+		http.Error(w, "Notification not found", http.StatusNotFound)
+		return
+	}
+	h.logger.Errorf("%s: %v", msg, err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
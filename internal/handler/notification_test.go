@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/notification"
+)
+
+func newNotificationRouter(store notification.Store) http.Handler {
+	h := NewNotificationHandler(store, logrus.New())
+	r := chi.NewRouter()
+	r.Get("/me/notifications", h.ListNotifications)
+	r.Post("/me/notifications/{id}/read", h.MarkNotificationRead)
+	r.Post("/me/notifications/read-all", h.MarkAllNotificationsRead)
+	return r
+}
+
+// notificationRequest выполняет запрос от имени userID; uuid.Nil означает анонимный запрос.
+func notificationRequest(r http.Handler, method, target string, userID uuid.UUID) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if userID != uuid.Nil {
+		req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{UserID: userID, Role: auth.RoleUser}))
+	}
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func decodeNotifications(t *testing.T, rr *httptest.ResponseRecorder) NotificationListResponse {
+	var resp NotificationListResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestNotificationsInbox(t *testing.T) {
+	store := notification.NewMemoryStore()
+	r := newNotificationRouter(store)
+	user, other, answerID := uuid.New(), uuid.New(), uint(5)
+	assert.NoError(t, store.Create([]notification.Notification{
+		{UserID: user, Type: notification.TypeNewAnswer, QuestionID: 1, AnswerID: &answerID, ActorID: &other,
+			Key: "answer:5"},
+		{UserID: user, Type: notification.TypeMention, QuestionID: 2, Key: "mention:question:2"},
+		{UserID: other, Type: notification.TypeAnswerAccepted, QuestionID: 1, Key: "accepted:5"},
+	}))
+
+	rr := notificationRequest(r, http.MethodGet, "/me/notifications", user)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	resp := decodeNotifications(t, rr)
+	assert.Equal(t, int64(2), resp.UnreadCount)
+	if assert.Len(t, resp.Notifications, 2) {
+		assert.Equal(t, notification.TypeMention, resp.Notifications[0].Type)
+		assert.Equal(t, notification.TypeNewAnswer, resp.Notifications[1].Type)
+		assert.Equal(t, &answerID, resp.Notifications[1].AnswerID)
+		assert.Equal(t, &other, resp.Notifications[1].ActorID)
+	}
+
+	// Чужое уведомление не найдется
+	assert.Equal(t, http.StatusNotFound, notificationRequest(r, http.MethodPost, "/me/notifications/3/read", user).Code)
+	rr = notificationRequest(r, http.MethodPost, "/me/notifications/1/read", user)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	resp = decodeNotifications(t, notificationRequest(r, http.MethodGet, "/me/notifications?unread=true", user))
+	assert.Equal(t, int64(1), resp.UnreadCount)
+	if assert.Len(t, resp.Notifications, 1) {
+		assert.Equal(t, uint(2), resp.Notifications[0].ID)
+		assert.Nil(t, resp.Notifications[0].ReadAt)
+	}
+	resp = decodeNotifications(t, notificationRequest(r, http.MethodGet, "/me/notifications?limit=1", user))
+	assert.Len(t, resp.Notifications, 1)
+
+	rr = notificationRequest(r, http.MethodPost, "/me/notifications/read-all", user)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	resp = decodeNotifications(t, notificationRequest(r, http.MethodGet, "/me/notifications?unread=1", user))
+	assert.Zero(t, resp.UnreadCount)
+	assert.NotNil(t, resp.Notifications)
+	assert.Empty(t, resp.Notifications)
+
+	// Уведомления другого пользователя не изменились
+	resp = decodeNotifications(t, notificationRequest(r, http.MethodGet, "/me/notifications", other))
+	assert.Equal(t, int64(1), resp.UnreadCount)
+}
+
+func TestNotificationsErrors(t *testing.T) {
+	r := newNotificationRouter(notification.NewMemoryStore())
+	user := uuid.New()
+
+	tests := []struct {
+		method, target string
+		userID         uuid.UUID
+		expected       int
+	}{
+		{http.MethodGet, "/me/notifications", uuid.Nil, http.StatusUnauthorized},
+		{http.MethodPost, "/me/notifications/1/read", uuid.Nil, http.StatusUnauthorized},
+		{http.MethodPost, "/me/notifications/read-all", uuid.Nil, http.StatusUnauthorized},
+		{http.MethodGet, "/me/notifications?unread=maybe", user, http.StatusBadRequest},
+		{http.MethodGet, "/me/notifications?limit=0", user, http.StatusBadRequest},
+		{http.MethodGet, "/me/notifications?limit=101", user, http.StatusBadRequest},
+		{http.MethodPost, "/me/notifications/abc/read", user, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			assert.Equal(t, tt.expected, notificationRequest(r, tt.method, tt.target, tt.userID).Code)
+		})
+	}
+}
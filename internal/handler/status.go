@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// CloseQuestion закрывает вопрос с указанием причины.
+// @Summary Close a question
+// @Description Close an open question with a reason. Closed questions do not accept answers.
+// @Description Requires the moderator role.
+// @Tags questions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Question ID"
+// @Param request body CloseQuestionRequest true "Close reason"
+// @Success 200 {object} QuestionResponse
+// @Failure 400 {string} string "Invalid request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Question not found"
+// @Failure 409 {string} string "Invalid status transition"
+// @Router /questions/{id}/close [post]
+func (h *Handler) CloseQuestion(w http.ResponseWriter, r *http.Request) {
+	var req CloseQuestionRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		h.logger.Warnf("Failed to decode close request body: %v", err)
+		writeBodyError(w, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		h.logger.Warnf("Validation failed for close request: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.changeStatus(w, r, models.QuestionStatusClosed, func(id uint, identity auth.Identity) (*models.Question, error) {
+		return h.service.CloseQuestion(id, identity.UserID, req.Reason)
+	})
+}
+
+// ReopenQuestion снова открывает закрытый или заблокированный вопрос.
+// @Summary Reopen a question
+// @Description Reopen a closed or locked question. Requires the moderator role.
+// @Tags questions
+// @Produce  json
+// @Param id path int true "Question ID"
+// @Success 200 {object} QuestionResponse
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Question not found"
+// @Failure 409 {string} string "Invalid status transition"
+// @Router /questions/{id}/reopen [post]
+func (h *Handler) ReopenQuestion(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, models.QuestionStatusOpen, func(id uint, _ auth.Identity) (*models.Question, error) {
+		return h.service.ReopenQuestion(id)
+	})
+}
+
+// LockQuestion блокирует вопрос.
+// @Summary Lock a question
+// @Description Lock an open or closed question. Locked questions do not accept answers and cannot be closed.
+// @Description Requires the moderator role.
+// @Tags questions
+// @Produce  json
+// @Param id path int true "Question ID"
+// @Success 200 {object} QuestionResponse
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Question not found"
+// @Failure 409 {string} string "Invalid status transition"
+// @Router /questions/{id}/lock [post]
+func (h *Handler) LockQuestion(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, models.QuestionStatusLocked, func(id uint, identity auth.Identity) (*models.Question, error) {
+		return h.service.LockQuestion(id, identity.UserID)
+	})
+}
+
+// ArchiveQuestion переводит вопрос в архив.
+// @Summary Archive a question
+// @Description Archive an open, closed or locked question. Archived questions are read-only: they do not accept
+// @Description answers and their status cannot change any more. Requires the moderator role.
+// @Tags questions
+// @Produce  json
+// @Param id path int true "Question ID"
+// @Success 200 {object} QuestionResponse
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Question not found"
+// @Failure 409 {string} string "Invalid status transition"
+// @Router /questions/{id}/archive [post]
+func (h *Handler) ArchiveQuestion(w http.ResponseWriter, r *http.Request) {
+	archive := func(id uint, identity auth.Identity) (*models.Question, error) {
+		return h.service.ArchiveQuestion(id, identity.UserID)
+	}
+	h.changeStatus(w, r, models.QuestionStatusArchived, archive)
+}
+
+// changeStatus - общая часть обработчиков смены статуса вопроса.
+func (h *Handler) changeStatus(
+	w http.ResponseWriter, r *http.Request, status string,
+	change func(id uint, identity auth.Identity) (*models.Question, error),
+) {
+	idStr := chi.URLParam(r, "id")
+	h.logger.Infof("Received request to change status of question %s to %s", idStr, status)
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid question ID: %s, error: %v", idStr, err)
+		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+		return
+	}
+
+	identity, ok := auth.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	question, err := change(uint(id), identity)
+	if err != nil {
+		h.logger.Errorf("Failed to change status of question %d to %s: %v", id, status, err)
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidStatusTransition), errors.Is(err, service.ErrVersionConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Ответы на вопрос при смене статуса не подгружаются, поэтому и не встраиваются
+	h.writeQuestion(w, toQuestionResponse(question), query.Projection{})
+	h.logger.Infof("Question with ID %d is now %s", id, status)
+}
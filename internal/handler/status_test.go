@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+func TestCloseQuestionHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	moderator, closedAt := uuid.New(), time.Now()
+	mockService.On("CloseQuestion", uint(1), moderator, "Off-topic").Return(&models.Question{
+		ID:          1,
+		Status:      models.QuestionStatusClosed,
+		CloseReason: "Off-topic",
+		ClosedBy:    &moderator,
+		ClosedAt:    &closedAt,
+	}, nil)
+
+	body, _ := json.Marshal(CloseQuestionRequest{Reason: "Off-topic"})
+	req := asModerator(httptest.NewRequest(http.MethodPost, "/questions/1/close", bytes.NewBuffer(body)), moderator)
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Post("/questions/{id}/close", handler.CloseQuestion)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp QuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, models.QuestionStatusClosed, resp.Status)
+	assert.Equal(t, "Off-topic", resp.CloseReason)
+	assert.Equal(t, &moderator, resp.ClosedBy)
+	mockService.AssertExpectations(t)
+}
+
+func TestCloseQuestionHandlerInvalidBody(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	for _, body := range []string{`{}`, `{"reason": "ok"}`, `{"reason": "Off-topic", "status": "open"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/questions/1/close", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = asModerator(req, uuid.New())
+		rr := httptest.NewRecorder()
+
+		r := chi.NewRouter()
+		r.Post("/questions/{id}/close", handler.CloseQuestion)
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+	mockService.AssertNotCalled(t, "CloseQuestion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChangeStatusHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		expected   int
+	}{
+		{"not found", fmt.Errorf("question with ID 1: %w", service.ErrNotFound), http.StatusNotFound},
+		{"invalid transition", service.ErrInvalidStatusTransition, http.StatusConflict},
+		{"internal", fmt.Errorf("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+			mockService.On("LockQuestion", uint(1), mock.Anything).Return(nil, tt.serviceErr)
+
+			r := chi.NewRouter()
+			r.Post("/questions/{id}/lock", handler.LockQuestion)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, asModerator(httptest.NewRequest(http.MethodPost, "/questions/1/lock", nil), uuid.New()))
+
+			assert.Equal(t, tt.expected, rr.Code)
+		})
+	}
+}
+
+func TestReopenQuestionHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	mockService.On("ReopenQuestion", uint(1)).Return(&models.Question{ID: 1, Status: models.QuestionStatusOpen}, nil)
+
+	r := chi.NewRouter()
+	r.Post("/questions/{id}/reopen", handler.ReopenQuestion)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, asModerator(httptest.NewRequest(http.MethodPost, "/questions/1/reopen", nil), uuid.New()))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp QuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, models.QuestionStatusOpen, resp.Status)
+	assert.Nil(t, resp.ClosedBy)
+	mockService.AssertExpectations(t)
+}
+
+func TestArchiveQuestionHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	moderator := uuid.New()
+	mockService.On("ArchiveQuestion", uint(1), moderator).
+		Return(&models.Question{ID: 1, Status: models.QuestionStatusArchived, ClosedBy: &moderator}, nil)
+
+	r := chi.NewRouter()
+	r.Post("/questions/{id}/archive", handler.ArchiveQuestion)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, asModerator(httptest.NewRequest(http.MethodPost, "/questions/1/archive", nil), moderator))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp QuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, models.QuestionStatusArchived, resp.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestChangeStatusHandlerUnauthenticated(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	r := chi.NewRouter()
+	r.Post("/questions/{id}/reopen", handler.ReopenQuestion)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/questions/1/reopen", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "ReopenQuestion", mock.Anything)
+}
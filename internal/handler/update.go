@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+// UpdateQuestion изменяет заголовок и (или) тело вопроса.
+// С заголовком If-Match изменение применяется, только если вопрос не изменился с момента чтения.
+// @Summary Update a question
+// @Description Update the title and/or body of a question. Omitted fields are left unchanged.
+// @Description With If-Match the update is applied only if the question version still matches the ETag.
+// @Description Requires the moderator role.
+// @Tags questions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Question ID"
+// @Param If-Match header string false "ETag of the question version being edited"
+// @Param request body UpdateQuestionRequest true "Fields to update"
+// @Success 200 {object} QuestionResponse
+// @Header 200 {string} ETag "Entity tag of the updated question"
+// @Failure 400 {string} string "Invalid request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Question not found"
+// @Failure 409 {string} string "Question was modified concurrently"
+// @Failure 412 {string} string "Question has been modified"
+// @Router /questions/{id} [patch]
+func (h *Handler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.logger.Infof("Received request to update question with ID: %s", idStr)
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid question ID: %s, error: %v", idStr, err)
+		http.Error(w, "Invalid question ID", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateQuestionRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		h.logger.Warnf("Failed to decode update request body: %v", err)
+		writeBodyError(w, err)
+		return
+	}
+	if req.Title == nil && req.Body == nil {
+		http.Error(w, "Nothing to update", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		h.logger.Warnf("Validation failed for update request: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	question, err := h.service.UpdateQuestion(uint(id), ifMatchVersions(r), req.Title, req.Body)
+	if err != nil {
+		h.logger.Errorf("Failed to update question with ID %d: %v", id, err)
+		h.writeModifyError(w, r, err)
+		return
+	}
+
+	etag := entityTag("questions", question.ID, question.Version, question.UpdatedAt, query.Projection{})
+	w.Header().Set("ETag", etag)
+	h.writeQuestion(w, toQuestionResponse(question), query.Projection{})
+	h.logger.Infof("Question with ID %d updated to version %d", id, question.Version)
+}
+
+// UpdateAnswer изменяет текст ответа.
+// С заголовком If-Match изменение применяется, только если ответ не изменился с момента чтения.
+// @Summary Update an answer
+// @Description Update the text of an answer. With If-Match the update is applied only if the answer
+// @Description version still matches the ETag. Requires the moderator role.
+// @Tags answers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Answer ID"
+// @Param If-Match header string false "ETag of the answer version being edited"
+// @Param request body UpdateAnswerRequest true "New answer text"
+// @Success 200 {object} AnswerResponse
+// @Header 200 {string} ETag "Entity tag of the updated answer"
+// @Failure 400 {string} string "Invalid request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Answer not found"
+// @Failure 409 {string} string "Answer was modified concurrently"
+// @Failure 412 {string} string "Answer has been modified"
+// @Router /answers/{id} [patch]
+func (h *Handler) UpdateAnswer(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	h.logger.Infof("Received request to update answer with ID: %s", idStr)
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid answer ID: %s, error: %v", idStr, err)
+		http.Error(w, "Invalid answer ID", http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateAnswerRequest
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		h.logger.Warnf("Failed to decode update request body: %v", err)
+		writeBodyError(w, err)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(&req); err != nil {
+		h.logger.Warnf("Validation failed for update request: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	answer, err := h.service.UpdateAnswer(uint(id), ifMatchVersions(r), req.Text)
+	if err != nil {
+		h.logger.Errorf("Failed to update answer with ID %d: %v", id, err)
+		h.writeModifyError(w, r, err)
+		return
+	}
+
+	etag := entityTag("answers", answer.ID, answer.Version, answer.UpdatedAt, query.Projection{})
+	w.Header().Set("ETag", etag)
+	h.writeAnswer(w, toAnswerResponse(answer), query.Projection{})
+	h.logger.Infof("Answer with ID %d updated to version %d", id, answer.Version)
+}
+
+// writeModifyError отвечает на ошибку изменения или удаления вопроса или ответа.
+func (h *Handler) writeModifyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrVersionConflict):
+		h.writeVersionConflict(w, r, err)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
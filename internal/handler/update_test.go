@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+func TestUpdateQuestionHandler(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	updated := &models.Question{ID: 1, Title: "New title", Body: "Body", Version: 3, UpdatedAt: time.Now()}
+	mockService.On("UpdateQuestion", uint(1), []int{2}, mock.MatchedBy(func(title *string) bool {
+		return title != nil && *title == "New title"
+	}), (*string)(nil)).Return(updated, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/questions/1", bytes.NewBufferString(`{"title":"New title"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2-0123456789abcdef"`)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Patch("/questions/{id}", handler.UpdateQuestion)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Regexp(t, `^"3-[0-9a-f]{32}"$`, rr.Header().Get("ETag"))
+
+	var response QuestionResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	assert.Equal(t, "New title", response.Title)
+	assert.Equal(t, 3, response.Version)
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateQuestionHandlerInvalidBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty", `{}`},
+		{"short title", `{"title":"Hi"}`},
+		{"unknown field", `{"text":"Hello"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+			req := httptest.NewRequest(http.MethodPatch, "/questions/1", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Patch("/questions/{id}", handler.UpdateQuestion)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			mockService.AssertNotCalled(t, "UpdateQuestion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateAnswerHandlerErrors(t *testing.T) {
+	conflict := fmt.Errorf("answer with ID 1 is at version 3: %w", service.ErrVersionConflict)
+	tests := []struct {
+		name       string
+		ifMatch    string
+		serviceErr error
+		expected   int
+	}{
+		{"stale If-Match", `"2-0123456789abcdef"`, conflict, http.StatusPreconditionFailed},
+		{"concurrent update", "", conflict, http.StatusConflict},
+		{"not found", "", fmt.Errorf("answer with ID 1: %w", service.ErrNotFound), http.StatusNotFound},
+		{"internal", "", fmt.Errorf("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+			mockService.On("UpdateAnswer", uint(1), mock.Anything, "New answer text").Return(nil, tt.serviceErr)
+
+			body := bytes.NewBufferString(`{"text":"New answer text"}`)
+			req := httptest.NewRequest(http.MethodPatch, "/answers/1", body)
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rr := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Patch("/answers/{id}", handler.UpdateAnswer)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expected, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteQuestionHandlerPreconditionFailed(t *testing.T) {
+	mockService := new(MockService)
+	handler := NewHandler(mockService, logrus.New(), testMaxBodyBytes)
+
+	mockService.On("DeleteQuestion", uint(1), []int{2}).
+		Return(fmt.Errorf("question with ID 1 is at version 3: %w", service.ErrVersionConflict))
+
+	req := httptest.NewRequest(http.MethodDelete, "/questions/1", nil)
+	req.Header.Set("If-Match", `"2-0123456789abcdef"`)
+	rr := httptest.NewRecorder()
+
+	r := chi.NewRouter()
+	r.Delete("/questions/{id}", handler.DeleteQuestion)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+	mockService.AssertExpectations(t)
+}
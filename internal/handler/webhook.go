@@ -0,0 +1,383 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/webhook"
+)
+
+// Размер страницы журнала доставок.
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 100
+)
+
+// WebhookHandler управляет подписками на события и журналом их доставок.
+type WebhookHandler struct {
+	store        webhook.Store
+	logger       *logrus.Logger
+	maxBodyBytes int64
+}
+
+// NewWebhookHandler создает обработчик подписок. maxBodyBytes ограничивает размер тела запроса.
+func NewWebhookHandler(store webhook.Store, logger *logrus.Logger, maxBodyBytes int64) *WebhookHandler {
+	return &WebhookHandler{store: store, logger: logger, maxBodyBytes: maxBodyBytes}
+}
+
+// CreateWebhook создает подписку на события.
+// @Summary Create a webhook
+// @Description Subscribe a URL to service events. Every matching event is sent as a POST request with
+// @Description a JSON body {id, type, created_at, data}, where data is the same as in the event stream.
+// @Description Requests are signed: X-Webhook-Signature is "sha256=" followed by the hex HMAC-SHA256 of
+// @Description "<X-Webhook-Timestamp>.<body>" with the secret, which is returned only in this response.
+// @Description A delivery succeeds on a 2xx response; otherwise it is retried with exponential backoff and marked
+// @Description dead after the last attempt. Events is a list of event types or "*" for all events.
+// @Description Requires the moderator role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param webhook body CreateWebhookRequest true "Webhook to create"
+// @Success 201 {object} WebhookResponse
+// @Failure 400 {string} string "Invalid request"
+// @Failure 403 {string} string "Forbidden"
+// @Router /admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("Received request to create webhook")
+	var req CreateWebhookRequest
+	if err := decodeJSONBody(w, r, &req, h.maxBodyBytes); err != nil {
+		h.logger.Warnf("Failed to decode webhook request body: %v", err)
+		writeBodyError(w, err)
+		return
+	}
+	if err := validateWebhookRequest(&req, req.Events); err != nil {
+		h.logger.Warnf("Validation failed for webhook: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		h.logger.Errorf("Failed to create webhook: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sub := &webhook.Subscription{
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      slices.Compact(slices.Sorted(slices.Values(req.Events))),
+		Description: req.Description,
+		Active:      req.Active == nil || *req.Active,
+	}
+	if err := h.store.CreateSubscription(sub); err != nil {
+		h.logger.Errorf("Failed to create webhook: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := toWebhookResponse(sub)
+	resp.Secret = sub.Secret
+	h.writeJSON(w, http.StatusCreated, resp)
+	h.logger.Infof("Webhook created successfully with ID: %d", sub.ID)
+}
+
+// ListWebhooks возвращает все подписки.
+// @Summary List webhooks
+// @Description Get all webhooks ordered by ID. Secrets are not returned. Requires the moderator role.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} WebhookResponse
+// @Failure 403 {string} string "Forbidden"
+// @Router /admin/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, _ *http.Request) {
+	subs, err := h.store.ListSubscriptions()
+	if err != nil {
+		h.logger.Errorf("Failed to list webhooks: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp := make([]WebhookResponse, 0, len(subs))
+	for i := range subs {
+		resp = append(resp, toWebhookResponse(&subs[i]))
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// GetWebhook возвращает подписку.
+// @Summary Get a webhook
+// @Description Get a webhook by ID. The secret is not returned. Requires the moderator role.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} WebhookResponse
+// @Failure 400 {string} string "Invalid webhook ID"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Webhook not found"
+// @Router /admin/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.subscription(w, r)
+	if !ok {
+		return
+	}
+	h.writeJSON(w, http.StatusOK, toWebhookResponse(sub))
+}
+
+// UpdateWebhook изменяет подписку.
+// @Summary Update a webhook
+// @Description Update the URL, events, description or active flag of a webhook. Omitted fields are left
+// @Description unchanged. Pending deliveries of an inactive webhook are marked dead instead of being sent.
+// @Description Requires the moderator role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param webhook body UpdateWebhookRequest true "Fields to update"
+// @Success 200 {object} WebhookResponse
+// @Failure 400 {string} string "Invalid request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Webhook not found"
+// @Router /admin/webhooks/{id} [patch]
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.subscription(w, r)
+	if !ok {
+		return
+	}
+	var req UpdateWebhookRequest
+	if err := decodeJSONBody(w, r, &req, h.maxBodyBytes); err != nil {
+		h.logger.Warnf("Failed to decode webhook update request body: %v", err)
+		writeBodyError(w, err)
+		return
+	}
+	if req.URL == nil && req.Events == nil && req.Description == nil && req.Active == nil {
+		http.Error(w, "Nothing to update", http.StatusBadRequest)
+		return
+	}
+	if err := validateWebhookRequest(&req, req.Events); err != nil {
+		h.logger.Warnf("Validation failed for webhook update: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.URL != nil {
+		sub.URL = *req.URL
+	}
+	if req.Events != nil {
+		sub.Events = slices.Compact(slices.Sorted(slices.Values(req.Events)))
+	}
+	if req.Description != nil {
+		sub.Description = *req.Description
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	if err := h.store.UpdateSubscription(sub); err != nil {
+		h.writeStoreError(w, "Failed to update webhook", "Webhook not found", err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, toWebhookResponse(sub))
+	h.logger.Infof("Webhook with ID %d updated", sub.ID)
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом ее доставок.
+// @Summary Delete a webhook
+// @Description Delete a webhook together with its delivery log. Requires the moderator role.
+// @Tags admin
+// @Param id path int true "Webhook ID"
+// @Success 204 "No Content"
+// @Failure 400 {string} string "Invalid webhook ID"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Webhook not found"
+// @Router /admin/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.pathID(w, r, "id", "webhook")
+	if !ok {
+		return
+	}
+	if err := h.store.DeleteSubscription(id); err != nil {
+		h.writeStoreError(w, "Failed to delete webhook", "Webhook not found", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.Infof("Webhook with ID %d deleted", id)
+}
+
+// ListDeliveries возвращает последние доставки подписки.
+// @Summary List webhook deliveries
+// @Description Get the latest deliveries of a webhook, newest first, without payloads.
+// @Description Requires the moderator role.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param status query string false "Delivery status" Enums(pending, succeeded, dead)
+// @Param limit query int false "Maximum number of deliveries" default(50) maximum(100)
+// @Success 200 {array} WebhookDeliveryResponse
+// @Failure 400 {string} string "Invalid request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Webhook not found"
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && !slices.Contains(webhook.Statuses, status) {
+		http.Error(w, "Invalid status parameter", http.StatusBadRequest)
+		return
+	}
+	limit := defaultDeliveriesLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 || limit > maxDeliveriesLimit {
+			h.logger.Warnf("Invalid limit parameter: %s", limitStr)
+			http.Error(w, "limit must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+	}
+	sub, ok := h.subscription(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.store.ListDeliveries(sub.ID, status, limit)
+	if err != nil {
+		h.logger.Errorf("Failed to list deliveries of webhook %d: %v", sub.ID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		resp = append(resp, toWebhookDeliveryResponse(&deliveries[i], false))
+	}
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// GetDelivery возвращает доставку вместе с телом запроса.
+// @Summary Get a webhook delivery
+// @Description Get a delivery of a webhook with its payload and the outcome of the last attempt.
+// @Description Requires the moderator role.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param deliveryID path int true "Delivery ID"
+// @Success 200 {object} WebhookDeliveryResponse
+// @Failure 400 {string} string "Invalid request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Delivery not found"
+// @Router /admin/webhooks/{id}/deliveries/{deliveryID} [get]
+func (h *WebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, ok := h.delivery(w, r)
+	if !ok {
+		return
+	}
+	h.writeJSON(w, http.StatusOK, toWebhookDeliveryResponse(delivery, true))
+}
+
+// ReplayDelivery отправляет событие доставки повторно.
+// @Summary Replay a webhook delivery
+// @Description Send the event of a delivery again, for example after the receiver has been fixed. A new pending
+// @Description delivery with the same payload and event ID is created; replay_of points to the original one.
+// @Description Requires the moderator role.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param deliveryID path int true "Delivery ID"
+// @Success 202 {object} WebhookDeliveryResponse
+// @Failure 400 {string} string "Invalid request"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Delivery not found"
+// @Router /admin/webhooks/{id}/deliveries/{deliveryID}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, ok := h.delivery(w, r)
+	if !ok {
+		return
+	}
+	replay := []webhook.Delivery{*delivery.Replay(time.Now())}
+	if err := h.store.CreateDeliveries(replay); err != nil {
+		h.writeStoreError(w, "Failed to replay webhook delivery", "Delivery not found", err)
+		return
+	}
+	h.writeJSON(w, http.StatusAccepted, toWebhookDeliveryResponse(&replay[0], false))
+	h.logger.Infof("Webhook delivery %d replayed as %d", delivery.ID, replay[0].ID)
+}
+
+// validateWebhookRequest проверяет тело запроса и список событий, если он передан.
+func validateWebhookRequest(req any, eventTypes []string) error {
+	if err := validator.New().Struct(req); err != nil {
+		return err
+	}
+	if eventTypes != nil {
+		return webhook.ValidateEvents(eventTypes)
+	}
+	return nil
+}
+
+// pathID разбирает идентификатор из параметра пути name. При ошибке отвечает клиенту 400.
+func (h *WebhookHandler) pathID(w http.ResponseWriter, r *http.Request, name, what string) (uint, bool) {
+	idStr := chi.URLParam(r, name)
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		h.logger.Warnf("Invalid %s ID: %s, error: %v", what, idStr, err)
+		http.Error(w, "Invalid "+what+" ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// subscription читает подписку из пути запроса. При ошибке отвечает клиенту.
+func (h *WebhookHandler) subscription(w http.ResponseWriter, r *http.Request) (*webhook.Subscription, bool) {
+	id, ok := h.pathID(w, r, "id", "webhook")
+	if !ok {
+		return nil, false
+	}
+	sub, err := h.store.GetSubscription(id)
+	if err != nil {
+		h.writeStoreError(w, "Failed to get webhook", "Webhook not found", err)
+		return nil, false
+	}
+	return sub, true
+}
+
+// delivery читает доставку подписки из пути запроса. При ошибке отвечает клиенту.
+func (h *WebhookHandler) delivery(w http.ResponseWriter, r *http.Request) (*webhook.Delivery, bool) {
+	subID, ok := h.pathID(w, r, "id", "webhook")
+	if !ok {
+		return nil, false
+	}
+	id, ok := h.pathID(w, r, "deliveryID", "delivery")
+	if !ok {
+		return nil, false
+	}
+	delivery, err := h.store.GetDelivery(id)
+	if err == nil && delivery.SubscriptionID != subID {
+		err = webhook.ErrNotFound
+	}
+	if err != nil {
+		h.writeStoreError(w, "Failed to get webhook delivery", "Delivery not found", err)
+		return nil, false
+	}
+	return delivery, true
+}
+
+// writeStoreError отвечает на ошибку хранилища: 404 с notFound для webhook.ErrNotFound, иначе 500.
+func (h *WebhookHandler) writeStoreError(w http.ResponseWriter, msg, notFound string, err error) {
+	if errors.Is(err, webhook.ErrNotFound) {
+		h.logger.Warnf("%s: %v", msg, err)
+		http.Error(w, notFound, http.StatusNotFound)
+		return
+	}
+	h.logger.Errorf("%s: %v", msg, err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func (h *WebhookHandler) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.logger.Errorf("Failed to encode webhook response: %v", err)
+	}
+}
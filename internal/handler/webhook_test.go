@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/webhook"
+)
+
+func newWebhookRouter(store webhook.Store) http.Handler {
+	h := NewWebhookHandler(store, logrus.New(), testMaxBodyBytes)
+	r := chi.NewRouter()
+	r.Post("/admin/webhooks", h.CreateWebhook)
+	r.Get("/admin/webhooks", h.ListWebhooks)
+	r.Get("/admin/webhooks/{id}", h.GetWebhook)
+	r.Patch("/admin/webhooks/{id}", h.UpdateWebhook)
+	r.Delete("/admin/webhooks/{id}", h.DeleteWebhook)
+	r.Get("/admin/webhooks/{id}/deliveries", h.ListDeliveries)
+	r.Get("/admin/webhooks/{id}/deliveries/{deliveryID}", h.GetDelivery)
+	r.Post("/admin/webhooks/{id}/deliveries/{deliveryID}/replay", h.ReplayDelivery)
+	return r
+}
+
+func webhookRequest(r http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestWebhookCRUD(t *testing.T) {
+	store := webhook.NewMemoryStore()
+	r := newWebhookRouter(store)
+
+	rr := webhookRequest(r, http.MethodPost, "/admin/webhooks",
+		`{"url":"https://hooks.example.com/qa","events":["answer.created","question.created","answer.created"]}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var created WebhookResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	assert.Equal(t, uint(1), created.ID)
+	assert.Equal(t, []string{"answer.created", "question.created"}, created.Events)
+	assert.True(t, created.Active)
+	assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+
+	// Ключ подписи возвращается только при создании
+	rr = webhookRequest(r, http.MethodGet, "/admin/webhooks/1", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "secret")
+	rr = webhookRequest(r, http.MethodGet, "/admin/webhooks", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), created.Secret)
+
+	rr = webhookRequest(r, http.MethodPatch, "/admin/webhooks/1", `{"events":["*"],"active":false}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var updated WebhookResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+	assert.Equal(t, []string{"*"}, updated.Events)
+	assert.False(t, updated.Active)
+	assert.Equal(t, "https://hooks.example.com/qa", updated.URL)
+	sub, err := store.GetSubscription(1)
+	assert.NoError(t, err)
+	assert.Equal(t, created.Secret, sub.Secret)
+
+	rr = webhookRequest(r, http.MethodDelete, "/admin/webhooks/1", "")
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	rr = webhookRequest(r, http.MethodGet, "/admin/webhooks/1", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	rr = webhookRequest(r, http.MethodDelete, "/admin/webhooks/1", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestWebhookValidation(t *testing.T) {
+	r := newWebhookRouter(webhook.NewMemoryStore())
+
+	tests := map[string]string{
+		"missing url":   `{"events":["*"]}`,
+		"invalid url":   `{"url":"ftp://hooks.example.com","events":["*"]}`,
+		"no events":     `{"url":"https://hooks.example.com","events":[]}`,
+		"unknown event": `{"url":"https://hooks.example.com","events":["answer.voted"]}`,
+		"unknown field": `{"url":"https://hooks.example.com","events":["*"],"secret":"mine"}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			rr := webhookRequest(r, http.MethodPost, "/admin/webhooks", body)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+
+	rr := webhookRequest(r, http.MethodPost, "/admin/webhooks", `{"url":"http://localhost:9000/hook","events":["*"]}`)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	rr = webhookRequest(r, http.MethodPatch, "/admin/webhooks/1", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = webhookRequest(r, http.MethodPatch, "/admin/webhooks/1", `{"events":["answer.voted"]}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = webhookRequest(r, http.MethodPatch, "/admin/webhooks/2", `{"active":true}`)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	rr = webhookRequest(r, http.MethodGet, "/admin/webhooks/abc", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	store := webhook.NewMemoryStore()
+	r := newWebhookRouter(store)
+	for _, url := range []string{"https://a.example.com", "https://b.example.com"} {
+		sub := &webhook.Subscription{URL: url, Events: []string{"*"}, Active: true}
+		assert.NoError(t, store.CreateSubscription(sub))
+	}
+	eventID := uuid.New()
+	deliveries := []webhook.Delivery{
+		{SubscriptionID: 1, EventID: eventID, EventType: "question.created",
+			Payload: []byte(`{"type":"question.created"}`), Status: webhook.StatusDead, Attempts: 8,
+			ResponseStatus: 500, LastError: "unexpected response status 500"},
+		{SubscriptionID: 1, EventID: uuid.New(), EventType: "answer.created", Payload: []byte(`{}`),
+			Status: webhook.StatusSucceeded, Attempts: 1, ResponseStatus: 200},
+		{SubscriptionID: 2, EventID: eventID, EventType: "question.created", Payload: []byte(`{}`),
+			Status: webhook.StatusPending, NextAttemptAt: time.Now()},
+	}
+	assert.NoError(t, store.CreateDeliveries(deliveries))
+
+	rr := webhookRequest(r, http.MethodGet, "/admin/webhooks/1/deliveries", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var list []WebhookDeliveryResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, uint(2), list[0].ID)
+		assert.Equal(t, uint(1), list[1].ID)
+		assert.Nil(t, list[1].Payload)
+	}
+
+	rr = webhookRequest(r, http.MethodGet, "/admin/webhooks/1/deliveries?status=dead&limit=1", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	if assert.Len(t, list, 1) {
+		assert.Equal(t, "unexpected response status 500", list[0].LastError)
+	}
+	rr = webhookRequest(r, http.MethodGet, "/admin/webhooks/1/deliveries?status=failed", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = webhookRequest(r, http.MethodGet, "/admin/webhooks/1/deliveries?limit=101", "")
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = webhookRequest(r, http.MethodGet, "/admin/webhooks/3/deliveries", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = webhookRequest(r, http.MethodGet, "/admin/webhooks/1/deliveries/1", "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var delivery WebhookDeliveryResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &delivery))
+	assert.Equal(t, eventID, delivery.EventID)
+	assert.JSONEq(t, `{"type":"question.created"}`, string(delivery.Payload))
+	// Доставка другой подписки не найдена
+	rr = webhookRequest(r, http.MethodGet, "/admin/webhooks/1/deliveries/3", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = webhookRequest(r, http.MethodPost, "/admin/webhooks/1/deliveries/1/replay", "")
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	var replay WebhookDeliveryResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &replay))
+	assert.Equal(t, uint(4), replay.ID)
+	assert.Equal(t, webhook.StatusPending, replay.Status)
+	assert.Equal(t, eventID, replay.EventID)
+	if assert.NotNil(t, replay.ReplayOf) {
+		assert.Equal(t, uint(1), *replay.ReplayOf)
+	}
+	stored, err := store.GetDelivery(4)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"type":"question.created"}`), stored.Payload)
+	rr = webhookRequest(r, http.MethodPost, "/admin/webhooks/2/deliveries/1/replay", "")
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
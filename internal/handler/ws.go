@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/events"
+)
+
+// DefaultPingInterval - как часто сервер отправляет клиенту WebSocket ping. Клиент, от которого
+// за два интервала не пришло ни pong, ни другого сообщения, отключается.
+const DefaultPingInterval = 30 * time.Second
+
+// Параметры соединений WebSocket.
+const (
+	// wsWriteTimeout - сколько ждать отправки одного сообщения клиенту.
+	wsWriteTimeout = 10 * time.Second
+	// wsMaxCommandBytes - максимальный размер сообщения клиента.
+	wsMaxCommandBytes = 1024
+)
+
+// Команды клиента и ответы на них в соединении /ws.
+const (
+	wsActionSubscribe   = "subscribe"
+	wsActionUnsubscribe = "unsubscribe"
+	wsTypeSubscribed    = "subscribed"
+	wsTypeUnsubscribed  = "unsubscribed"
+	wsTypeError         = "error"
+)
+
+// WebSocketHandler отдает события вопросов и ответов по WebSocket клиентам, подписанным на темы.
+type WebSocketHandler struct {
+	broadcaster  *events.Broadcaster
+	upgrader     websocket.Upgrader
+	logger       *logrus.Logger
+	pingInterval time.Duration
+}
+
+// NewWebSocketHandler создает обработчик соединений WebSocket.
+// Соединения принимаются от страниц того же хоста и от источников, для которых originAllowed
+// возвращает true; клиенты не из браузера заголовок Origin не передают и принимаются всегда.
+func NewWebSocketHandler(
+	b *events.Broadcaster, originAllowed func(origin string) bool, logger *logrus.Logger, pingInterval time.Duration,
+) *WebSocketHandler {
+	return &WebSocketHandler{
+		broadcaster: b,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				return origin == "" || sameHost(origin, r.Host) || originAllowed(origin)
+			},
+		},
+		logger:       logger,
+		pingInterval: pingInterval,
+	}
+}
+
+// sameHost сообщает, указывает ли источник на тот же хост, что и запрос.
+func sameHost(origin, host string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, host)
+}
+
+// Connect устанавливает соединение WebSocket и отправляет клиенту события тем, на которые он подписан.
+// Протокол описан в README: OpenAPI не описывает сообщения WebSocket, поэтому маршрута нет в Swagger.
+func (h *WebSocketHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	client, err := h.broadcaster.Connect()
+	if err != nil {
+		http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer client.Close()
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade уже ответил клиенту ошибкой
+		h.logger.Warnf("WebSocket upgrade from %s failed: %v", r.RemoteAddr, err)
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	h.logger.Infof("WebSocket client connected from %s", r.RemoteAddr)
+
+	// Писать в соединение может только одна горутина, поэтому ответы на команды передаются ей
+	replies := make(chan WebSocketMessage)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() { readErr <- h.readCommands(conn, client, replies, done) }()
+
+	if err := h.writeMessages(conn, client, replies, readErr); err != nil {
+		h.logger.Infof("WebSocket connection from %s closed: %v", r.RemoteAddr, err)
+	}
+}
+
+// readCommands читает команды клиента, пока соединение не разорвется, и передает ответы на них в replies.
+func (h *WebSocketHandler) readCommands(
+	conn *websocket.Conn, client *events.Client, replies chan<- WebSocketMessage, done <-chan struct{},
+) error {
+	conn.SetReadLimit(wsMaxCommandBytes)
+	pongWait := 2 * h.pingInterval
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		var reply WebSocketMessage
+		var cmd WebSocketCommand
+		if err := json.Unmarshal(data, &cmd); err != nil {
+			reply = WebSocketMessage{Type: wsTypeError, Error: "invalid command: " + err.Error()}
+		} else {
+			reply = handleWebSocketCommand(client, cmd)
+		}
+		select {
+		case replies <- reply:
+		case <-done:
+			return nil
+		}
+	}
+}
+
+// handleWebSocketCommand выполняет команду клиента и возвращает ответ на нее.
+func handleWebSocketCommand(client *events.Client, cmd WebSocketCommand) WebSocketMessage {
+	var topic, replyType string
+	var err error
+	switch cmd.Action {
+	case wsActionSubscribe:
+		topic, err = client.Subscribe(cmd.Topic)
+		replyType = wsTypeSubscribed
+	case wsActionUnsubscribe:
+		topic, err = client.Unsubscribe(cmd.Topic)
+		replyType = wsTypeUnsubscribed
+	default:
+		err = fmt.Errorf("unknown action %q: expected %s or %s", cmd.Action, wsActionSubscribe, wsActionUnsubscribe)
+	}
+	if err != nil {
+		return WebSocketMessage{Type: wsTypeError, Topic: cmd.Topic, Error: err.Error()}
+	}
+	return WebSocketMessage{Type: replyType, Topic: topic}
+}
+
+// writeMessages отправляет клиенту события, ответы на команды и ping, пока соединение не разорвется
+// или клиент не будет отключен.
+func (h *WebSocketHandler) writeMessages(
+	conn *websocket.Conn, client *events.Client, replies <-chan WebSocketMessage, readErr <-chan error,
+) error {
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case event, ok := <-client.Events():
+			if !ok {
+				return closeWebSocket(conn, client.Err())
+			}
+			err = writeWebSocket(conn, WebSocketMessage{
+				Type: event.Type, QuestionID: event.QuestionID, Data: EventData(event), Time: event.Time,
+			})
+		case reply := <-replies:
+			err = writeWebSocket(conn, reply)
+		case <-ticker.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+		case err = <-readErr:
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeWebSocket(conn *websocket.Conn, msg WebSocketMessage) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return conn.WriteJSON(msg)
+}
+
+// closeWebSocket сообщает клиенту, почему сервер его отключает, и возвращает эту причину.
+func closeWebSocket(conn *websocket.Conn, reason error) error {
+	code, text := websocket.CloseGoingAway, "server is shutting down"
+	if errors.Is(reason, events.ErrSlowConsumer) {
+		// Клиент может переподключиться и подписаться заново
+		code, text = websocket.CloseTryAgainLater, "client is too slow"
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text),
+		time.Now().Add(wsWriteTimeout))
+	return reason
+}
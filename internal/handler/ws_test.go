@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/repository"
+	"github.com/shenikar/question-service/internal/service"
+)
+
+type wsFixture struct {
+	server      *httptest.Server
+	service     service.Service
+	broadcaster *events.Broadcaster
+}
+
+func newWSFixture(t *testing.T, pingInterval time.Duration) *wsFixture {
+	logger := logrus.New()
+	broadcaster := events.NewBroadcaster(10)
+	s := service.NewService(repository.NewMemoryRepository(logger), logger, broadcaster)
+	originAllowed := func(origin string) bool { return origin == "https://app.example.com" }
+
+	server := httptest.NewServer(http.HandlerFunc(
+		NewWebSocketHandler(broadcaster, originAllowed, logger, pingInterval).Connect))
+	t.Cleanup(func() {
+		broadcaster.Close()
+		server.Close()
+	})
+	return &wsFixture{server: server, service: s, broadcaster: broadcaster}
+}
+
+func (f *wsFixture) dial(origin string) (*websocket.Conn, *http.Response, error) {
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(f.server.URL, "http"), header)
+}
+
+func (f *wsFixture) connect(t *testing.T) *websocket.Conn {
+	conn, _, err := f.dial("")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func wsCommand(t *testing.T, conn *websocket.Conn, action, topic string) WebSocketMessage {
+	assert.NoError(t, conn.WriteJSON(WebSocketCommand{Action: action, Topic: topic}))
+	return wsReceive(t, conn)
+}
+
+func wsReceive(t *testing.T, conn *websocket.Conn) WebSocketMessage {
+	var msg WebSocketMessage
+	assert.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestWebSocketEvents(t *testing.T) {
+	f := newWSFixture(t, time.Hour)
+	all, one := f.connect(t), f.connect(t)
+	subscribed := wsCommand(t, all, "subscribe", "questions")
+	assert.Equal(t, WebSocketMessage{Type: "subscribed", Topic: "questions"}, subscribed)
+	subscribed = wsCommand(t, one, "subscribe", "question:02")
+	assert.Equal(t, WebSocketMessage{Type: "subscribed", Topic: "question:2"}, subscribed)
+
+	_, err := f.service.CreateQuestion(&models.Question{Title: "How to install Go?"}, false)
+	assert.NoError(t, err)
+	_, err = f.service.CreateQuestion(&models.Question{Title: "How to write tests?"}, false)
+	assert.NoError(t, err)
+	assert.NoError(t, f.service.CreateAnswer(2, &models.Answer{Text: "Use the testing package"}))
+	assert.NoError(t, f.service.DeleteQuestion(2, nil))
+
+	first := wsReceive(t, all)
+	assert.Equal(t, events.TypeQuestionCreated, first.Type)
+	assert.Equal(t, uint(1), first.QuestionID)
+	assert.False(t, first.Time.IsZero())
+	for _, conn := range []*websocket.Conn{all, one} {
+		question := wsReceive(t, conn)
+		assert.Equal(t, events.TypeQuestionCreated, question.Type)
+		if data, ok := question.Data.(map[string]any); assert.True(t, ok) {
+			assert.Equal(t, "How to write tests?", data["title"])
+		}
+
+		answer := wsReceive(t, conn)
+		assert.Equal(t, events.TypeAnswerCreated, answer.Type)
+		assert.Equal(t, uint(2), answer.QuestionID)
+		if data, ok := answer.Data.(map[string]any); assert.True(t, ok) {
+			assert.Equal(t, "Use the testing package", data["text"])
+		}
+
+		deleted := wsReceive(t, conn)
+		assert.Equal(t, events.TypeQuestionDeleted, deleted.Type)
+		assert.Equal(t, map[string]any{"id": float64(2)}, deleted.Data)
+	}
+}
+
+func TestWebSocketTagTopics(t *testing.T) {
+	f := newWSFixture(t, time.Hour)
+	conn := f.connect(t)
+	assert.Equal(t, WebSocketMessage{Type: "subscribed", Topic: "tag:go"}, wsCommand(t, conn, "subscribe", "tag:Go"))
+
+	_, err := f.service.CreateQuestion(
+		&models.Question{Title: "Why is PostgreSQL slow?", Tags: models.Tags{"sql"}}, false)
+	assert.NoError(t, err)
+	_, err = f.service.CreateQuestion(&models.Question{Title: "How to install Go?", Tags: models.Tags{"go"}}, false)
+	assert.NoError(t, err)
+	assert.NoError(t, f.service.CreateAnswer(1, &models.Answer{Text: "Add an index"}))
+	assert.NoError(t, f.service.CreateAnswer(2, &models.Answer{Text: "Use the official installer"}))
+
+	question := wsReceive(t, conn)
+	assert.Equal(t, events.TypeQuestionCreated, question.Type)
+	assert.Equal(t, uint(2), question.QuestionID)
+	answer := wsReceive(t, conn)
+	assert.Equal(t, events.TypeAnswerCreated, answer.Type)
+	assert.Equal(t, uint(2), answer.QuestionID)
+}
+
+func TestWebSocketCommandErrors(t *testing.T) {
+	f := newWSFixture(t, time.Hour)
+	conn := f.connect(t)
+
+	msg := wsCommand(t, conn, "subscribe", "tag:hello world")
+	assert.Equal(t, "error", msg.Type)
+	assert.Equal(t, "tag:hello world", msg.Topic)
+	assert.Contains(t, msg.Error, "invalid tag")
+
+	msg = wsCommand(t, conn, "publish", "questions")
+	assert.Equal(t, "error", msg.Type)
+	assert.Contains(t, msg.Error, "unknown action")
+
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("{")))
+	msg = wsReceive(t, conn)
+	assert.Equal(t, "error", msg.Type)
+	assert.Contains(t, msg.Error, "invalid command")
+
+	// После ошибок соединение продолжает работать
+	assert.Equal(t, "unsubscribed", wsCommand(t, conn, "unsubscribe", "questions").Type)
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+	f := newWSFixture(t, time.Hour)
+
+	conn, _, err := f.dial("https://app.example.com")
+	if assert.NoError(t, err) {
+		_ = conn.Close()
+	}
+	conn, _, err = f.dial(f.server.URL)
+	if assert.NoError(t, err) {
+		_ = conn.Close()
+	}
+
+	_, resp, err := f.dial("https://evil.example.com")
+	assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	}
+}
+
+// wsTestPingInterval - интервал ping в тестах keepalive. Сервер ждет pong два интервала, поэтому интервал
+// берется с запасом на задержки планировщика на загруженной машине.
+const wsTestPingInterval = 200 * time.Millisecond
+
+func TestWebSocketPing(t *testing.T) {
+	f := newWSFixture(t, wsTestPingInterval)
+	conn := f.connect(t)
+
+	pings := make(chan struct{}, 10)
+	conn.SetPingHandler(func(data string) error {
+		select {
+		case pings <- struct{}{}:
+		default:
+		}
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	// Управляющие сообщения обрабатываются во время чтения
+	messages := make(chan WebSocketMessage)
+	go func() {
+		defer close(messages)
+		for {
+			var msg WebSocketMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			messages <- msg
+		}
+	}()
+
+	// Клиент отвечает на ping, поэтому сервер не отключает его и после нескольких интервалов:
+	// между первым и третьим ping проходит больше времени, чем сервер ждет pong
+	for range 3 {
+		select {
+		case <-pings:
+		case <-time.After(5 * time.Second):
+			t.Fatal("no ping")
+		}
+	}
+	assert.NoError(t, conn.WriteJSON(WebSocketCommand{Action: "subscribe", Topic: "questions"}))
+	assert.Equal(t, "subscribed", (<-messages).Type)
+}
+
+func TestWebSocketDropsUnresponsiveClient(t *testing.T) {
+	f := newWSFixture(t, wsTestPingInterval)
+	conn := f.connect(t)
+
+	// Клиент читает сообщения, но не отвечает на ping: сервер закрывает соединение
+	// раньше, чем истечет срок чтения клиента
+	conn.SetPingHandler(func(string) error { return nil })
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			assert.False(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+			var netErr net.Error
+			assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "client read timed out: %v", err)
+			return
+		}
+	}
+}
+
+func TestWebSocketClosedOnShutdown(t *testing.T) {
+	f := newWSFixture(t, time.Hour)
+	conn := f.connect(t)
+	assert.Equal(t, "subscribed", wsCommand(t, conn, "subscribe", "questions").Type)
+
+	f.broadcaster.Close()
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+
+	_, resp, err := f.dial("")
+	assert.Error(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	}
+}
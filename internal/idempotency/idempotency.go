@@ -0,0 +1,283 @@
+// Package idempotency делает повторы POST-запросов безопасными.
+// Клиент передает заголовок Idempotency-Key; первый запрос с ключом выполняется,
+// а его ответ сохраняется и возвращается на повторы с тем же ключом без повторного выполнения.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/auth"
+)
+
+// Заголовки идемпотентных запросов.
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+)
+
+// MaxKeyLength - максимальная длина Idempotency-Key.
+const MaxKeyLength = 255
+
+// replayedHeaders - заголовки ответа, которые сохраняются и возвращаются при повторе.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// Record - сохраненный запрос с ключом идемпотентности и ответ на него.
+// Пока запрос выполняется, StatusCode равен нулю.
+type Record struct {
+	Key         string      `gorm:"primaryKey"`
+	Fingerprint string      `gorm:"not null"`
+	StatusCode  int         `gorm:"not null;default:0"`
+	Header      http.Header `gorm:"serializer:json"`
+	Body        []byte
+	CreatedAt   time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null"`
+}
+
+// TableName задает имя таблицы для Record.
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
+
+// Completed сообщает, сохранен ли уже ответ на запрос.
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Store хранит ключи идемпотентности.
+type Store interface {
+	// Begin резервирует ключ record.Key. Если ключ уже занят и не истек, запись не изменяется,
+	// а возвращается сохраненная ранее запись; при успешном резервировании возвращается nil.
+	Begin(record *Record) (*Record, error)
+	// Complete сохраняет ответ на запрос с зарезервированным ключом.
+	Complete(record *Record) error
+	// Release освобождает ключ, чтобы запрос можно было повторить.
+	Release(key string) error
+	// DeleteExpired удаляет истекшие ключи и возвращает их количество.
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// Middleware сохраняет ответы на запросы с Idempotency-Key и возвращает их на повторы.
+type Middleware struct {
+	store        Store
+	ttl          time.Duration
+	maxBodyBytes int64
+	logger       *logrus.Logger
+	now          func() time.Time
+	clientIP     func(r *http.Request) string
+}
+
+// NewMiddleware создает middleware, которое хранит ответы в store в течение ttl.
+// Тело запроса читается целиком для сравнения с повторами, поэтому его размер ограничен maxBodyBytes.
+func NewMiddleware(store Store, ttl time.Duration, maxBodyBytes int64, logger *logrus.Logger) *Middleware {
+	return &Middleware{
+		store: store, ttl: ttl, maxBodyBytes: maxBodyBytes, logger: logger, now: time.Now, clientIP: remoteHost,
+	}
+}
+
+// WithClientIP задает, как определяется IP-адрес клиента, которым ограничиваются ключи анонимных запросов.
+// По умолчанию это адрес соединения; за прокси нужен адрес из X-Forwarded-For (см. ratelimit.Limiter.ClientIP).
+func (m *Middleware) WithClientIP(clientIP func(r *http.Request) string) *Middleware {
+	m.clientIP = clientIP
+	return m
+}
+
+// Handler оборачивает обработчик. Запросы без Idempotency-Key выполняются как обычно.
+// Повтор с тем же ключом получает сохраненный ответ с заголовком Idempotent-Replayed,
+// повтор с другим телом или на другой адрес - 422, повтор до завершения первого запроса - 409.
+// Ответы 5xx не сохраняются: после них запрос с тем же ключом выполняется заново.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderKey)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > MaxKeyLength {
+			http.Error(w, fmt.Sprintf("%s must be at most %d characters", HeaderKey, MaxKeyLength),
+				http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, m.maxBodyBytes))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("request body must not be larger than %d bytes", maxBytesErr.Limit),
+				http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := m.now()
+		record := &Record{
+			Key:         m.scopedKey(r, key),
+			Fingerprint: fingerprint(r, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(m.ttl),
+		}
+		existing, err := m.store.Begin(record)
+		if err != nil {
+			m.logger.Errorf("Failed to reserve idempotency key %q: %v", key, err)
+			http.Error(w, "Failed to process "+HeaderKey, http.StatusInternalServerError)
+			return
+		}
+		if existing != nil {
+			m.replay(w, existing, record)
+			return
+		}
+
+		// Если обработчик паникует, ключ освобождается, иначе повторы получали бы 409 до истечения ключа
+		defer func() {
+			if p := recover(); p != nil {
+				m.release(record.Key)
+				panic(p)
+			}
+		}()
+		rec := &recorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		m.finish(rec, record)
+	})
+}
+
+// replay отвечает на повтор запроса с уже использованным ключом.
+func (m *Middleware) replay(w http.ResponseWriter, existing, record *Record) {
+	switch {
+	case existing.Fingerprint != record.Fingerprint:
+		m.logger.Warnf("Idempotency key %q reused with a different request", existing.Key)
+		http.Error(w, HeaderKey+" was already used with a different request", http.StatusUnprocessableEntity)
+	case !existing.Completed():
+		http.Error(w, "A request with this "+HeaderKey+" is still in progress", http.StatusConflict)
+	default:
+		m.logger.Infof("Replaying stored response for idempotency key %q", existing.Key)
+		for name, values := range existing.Header {
+			w.Header()[name] = values
+		}
+		w.Header().Set(HeaderReplayed, "true")
+		w.WriteHeader(existing.StatusCode)
+		if _, err := w.Write(existing.Body); err != nil {
+			m.logger.Errorf("Failed to write replayed response: %v", err)
+		}
+	}
+}
+
+// finish сохраняет ответ или освобождает ключ, если запрос завершился ошибкой сервера.
+func (m *Middleware) finish(rec *recorder, record *Record) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	if rec.status >= http.StatusInternalServerError {
+		m.release(record.Key)
+		return
+	}
+
+	record.StatusCode = rec.status
+	record.Header = http.Header{}
+	for _, name := range replayedHeaders {
+		if values := rec.Header().Values(name); len(values) > 0 {
+			record.Header[name] = values
+		}
+	}
+	record.Body = rec.body.Bytes()
+	if err := m.store.Complete(record); err != nil {
+		m.logger.Errorf("Failed to store response for idempotency key %q: %v", record.Key, err)
+	}
+}
+
+func (m *Middleware) release(key string) {
+	if err := m.store.Release(key); err != nil {
+		m.logger.Errorf("Failed to release idempotency key %q: %v", key, err)
+	}
+}
+
+// scopedKey ограничивает ключ пользователем, а ключ анонимного запроса - IP-адресом клиента,
+// чтобы ключи разных клиентов не пересекались и один клиент не получал сохраненный ответ другого.
+func (m *Middleware) scopedKey(r *http.Request, key string) string {
+	if identity, ok := auth.FromContext(r.Context()); ok {
+		return identity.UserID.String() + ":" + key
+	}
+	return "ip:" + m.clientIP(r) + ":" + key
+}
+
+// remoteHost возвращает IP-адрес соединения.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// fingerprint - хеш метода, адреса и тела запроса. Адрес берется относительно версии API,
+// в которую смонтирован маршрут: повтор через устаревший адрес без префикса версии
+// остается тем же запросом.
+func fingerprint(r *http.Request, body []byte) string {
+	path := r.URL.EscapedPath()
+	// Внутри смонтированного подроутера chi хранит путь без префикса, под которым он смонтирован
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		path = rctx.RoutePath
+	}
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, path)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder пропускает ответ клиенту и запоминает его статус и тело.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// PurgeExpired удаляет истекшие ключи из store каждые interval до отмены ctx.
+// Блокирует вызывающую горутину.
+func PurgeExpired(ctx context.Context, store Store, interval time.Duration, logger *logrus.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			deleted, err := store.DeleteExpired(now)
+			if err != nil {
+				logger.Errorf("Failed to delete expired idempotency keys: %v", err)
+				continue
+			}
+			logger.Debugf("Deleted %d expired idempotency keys", deleted)
+		}
+	}
+}
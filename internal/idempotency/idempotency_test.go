@@ -0,0 +1,248 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/auth"
+)
+
+// countingHandler отвечает 201 с номером вызова и считает вызовы.
+type countingHandler struct {
+	calls  int
+	status int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Count", "ignored")
+	status := h.status
+	if status == 0 {
+		status = http.StatusCreated
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(`{"id":` + strings.Repeat("1", h.calls) + `}`))
+}
+
+func post(handler http.Handler, target, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewBufferString(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestMiddlewareReplaysResponse(t *testing.T) {
+	next := &countingHandler{}
+	handler := NewMiddleware(NewMemoryStore(), time.Hour, 1<<20, logrus.New()).Handler(next)
+
+	first := post(handler, "/questions", "key-1", `{"title":"Question"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(HeaderReplayed))
+
+	replay := post(handler, "/questions", "key-1", `{"title":"Question"}`)
+	assert.Equal(t, http.StatusCreated, replay.Code)
+	assert.Equal(t, "true", replay.Header().Get(HeaderReplayed))
+	assert.Equal(t, "application/json", replay.Header().Get("Content-Type"))
+	assert.Empty(t, replay.Header().Get("X-Request-Count"))
+	assert.Equal(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, 1, next.calls)
+
+	// Другой ключ - новый запрос
+	assert.Equal(t, `{"id":11}`, post(handler, "/questions", "key-2", `{"title":"Question"}`).Body.String())
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestMiddlewareWithoutKey(t *testing.T) {
+	next := &countingHandler{}
+	handler := NewMiddleware(NewMemoryStore(), time.Hour, 1<<20, logrus.New()).Handler(next)
+
+	post(handler, "/questions", "", `{}`)
+	post(handler, "/questions", "", `{}`)
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestMiddlewareRejectsDifferentPayload(t *testing.T) {
+	next := &countingHandler{}
+	handler := NewMiddleware(NewMemoryStore(), time.Hour, 1<<20, logrus.New()).Handler(next)
+
+	post(handler, "/questions", "key", `{"title":"Question"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, post(handler, "/questions", "key", `{"title":"Other"}`).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity,
+		post(handler, "/questions/1/answers", "key", `{"title":"Question"}`).Code)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestMiddlewareInProgress(t *testing.T) {
+	store := NewMemoryStore()
+	next := &countingHandler{}
+	handler := NewMiddleware(store, time.Hour, 1<<20, logrus.New()).Handler(next)
+
+	// Первый запрос с ключом зарезервировал его, но еще не завершился
+	req := httptest.NewRequest(http.MethodPost, "/questions", nil)
+	now := time.Now()
+	_, err := store.Begin(&Record{
+		Key:         "ip:192.0.2.1:key",
+		Fingerprint: fingerprint(req, []byte(`{}`)),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusConflict, post(handler, "/questions", "key", `{}`).Code)
+	assert.Equal(t, 0, next.calls)
+}
+
+func TestMiddlewareReleasesKeyOnServerError(t *testing.T) {
+	next := &countingHandler{status: http.StatusInternalServerError}
+	handler := NewMiddleware(NewMemoryStore(), time.Hour, 1<<20, logrus.New()).Handler(next)
+
+	assert.Equal(t, http.StatusInternalServerError, post(handler, "/questions", "key", `{}`).Code)
+	next.status = 0
+	assert.Equal(t, http.StatusCreated, post(handler, "/questions", "key", `{}`).Code)
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestMiddlewareExpiredKey(t *testing.T) {
+	next := &countingHandler{}
+	middleware := NewMiddleware(NewMemoryStore(), time.Minute, 1<<20, logrus.New())
+	now := time.Now()
+	middleware.now = func() time.Time { return now }
+	handler := middleware.Handler(next)
+
+	post(handler, "/questions", "key", `{}`)
+	now = now.Add(time.Minute)
+	assert.Empty(t, post(handler, "/questions", "key", `{}`).Header().Get(HeaderReplayed))
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestMiddlewareScopesKeysByUser(t *testing.T) {
+	next := &countingHandler{}
+	handler := NewMiddleware(NewMemoryStore(), time.Hour, 1<<20, logrus.New()).Handler(next)
+
+	for _, userID := range []uuid.UUID{uuid.New(), uuid.New()} {
+		req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBufferString(`{}`))
+		req.Header.Set(HeaderKey, "key")
+		req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{UserID: userID, Role: auth.RoleUser}))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Empty(t, rr.Header().Get(HeaderReplayed))
+	}
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestMiddlewareScopesAnonymousKeysByIP(t *testing.T) {
+	next := &countingHandler{}
+	handler := NewMiddleware(NewMemoryStore(), time.Hour, 1<<20, logrus.New()).Handler(next)
+
+	postFrom := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBufferString(`{}`))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(HeaderKey, "key")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Empty(t, postFrom("192.0.2.1:1234").Header().Get(HeaderReplayed))
+	// Другой клиент с тем же ключом не получает чужой ответ
+	assert.Empty(t, postFrom("192.0.2.2:1234").Header().Get(HeaderReplayed))
+	// Тот же клиент с другого порта - повтор
+	assert.Equal(t, "true", postFrom("192.0.2.1:5678").Header().Get(HeaderReplayed))
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestMiddlewareWithClientIP(t *testing.T) {
+	next := &countingHandler{}
+	handler := NewMiddleware(NewMemoryStore(), time.Hour, 1<<20, logrus.New()).
+		WithClientIP(func(r *http.Request) string { return r.Header.Get("X-Forwarded-For") }).
+		Handler(next)
+
+	for _, client := range []string{"203.0.113.1", "203.0.113.2"} {
+		req := httptest.NewRequest(http.MethodPost, "/questions", bytes.NewBufferString(`{}`))
+		req.Header.Set(HeaderKey, "key")
+		req.Header.Set("X-Forwarded-For", client)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Empty(t, rr.Header().Get(HeaderReplayed))
+	}
+	assert.Equal(t, 2, next.calls)
+}
+
+func TestMiddlewareIgnoresVersionPrefix(t *testing.T) {
+	next := &countingHandler{}
+	idem := NewMiddleware(NewMemoryStore(), time.Hour, 1<<20, logrus.New())
+	routes := func(r chi.Router) {
+		r.With(idem.Handler).Post("/questions", next.ServeHTTP)
+		r.With(idem.Handler).Post("/questions/{id}/answers", next.ServeHTTP)
+	}
+	r := chi.NewRouter()
+	r.Route("/api/v1", routes)
+	r.Group(routes)
+
+	assert.Equal(t, http.StatusCreated, post(r, "/api/v1/questions", "key", `{}`).Code)
+	// Повтор через адрес без префикса версии - тот же запрос
+	assert.Equal(t, "true", post(r, "/questions", "key", `{}`).Header().Get(HeaderReplayed))
+	assert.Equal(t, http.StatusUnprocessableEntity, post(r, "/api/v1/questions/1/answers", "key", `{}`).Code)
+	assert.Equal(t, 1, next.calls)
+}
+
+// countingStore считает удаления истекших ключей.
+type countingStore struct {
+	Store
+	purges atomic.Int32
+}
+
+func (s *countingStore) DeleteExpired(now time.Time) (int64, error) {
+	s.purges.Add(1)
+	return s.Store.DeleteExpired(now)
+}
+
+func TestPurgeExpiredStopsOnCancel(t *testing.T) {
+	store := &countingStore{Store: NewMemoryStore()}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		PurgeExpired(ctx, store, time.Millisecond, logrus.New())
+	}()
+
+	assert.Eventually(t, func() bool { return store.purges.Load() > 0 }, time.Second, time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("PurgeExpired did not stop after cancel")
+	}
+}
+
+func TestMiddlewareKeyTooLong(t *testing.T) {
+	next := &countingHandler{}
+	handler := NewMiddleware(NewMemoryStore(), time.Hour, 1<<20, logrus.New()).Handler(next)
+
+	rr := post(handler, "/questions", strings.Repeat("k", MaxKeyLength+1), `{}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, 0, next.calls)
+}
+
+func TestMiddlewareBodyTooLarge(t *testing.T) {
+	next := &countingHandler{}
+	handler := NewMiddleware(NewMemoryStore(), time.Hour, 8, logrus.New()).Handler(next)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(handler, "/questions", "key", `{"title":"Question"}`).Code)
+	assert.Equal(t, 0, next.calls)
+}
@@ -0,0 +1,58 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryStore - хранилище ключей в памяти процесса для тестов и режима STORAGE=memory.
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewMemoryStore создает хранилище ключей в памяти.
+func NewMemoryStore() Store {
+	return &memoryStore{records: make(map[string]Record)}
+}
+
+func (s *memoryStore) Begin(record *Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[record.Key]; ok && existing.ExpiresAt.After(record.CreatedAt) {
+		return &existing, nil
+	}
+	s.records[record.Key] = *record
+	return nil, nil
+}
+
+func (s *memoryStore) Complete(record *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[record.Key] = *record
+	return nil
+}
+
+func (s *memoryStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+func (s *memoryStore) DeleteExpired(now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for key, record := range s.records {
+		if !record.ExpiresAt.After(now) {
+			delete(s.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,53 @@
+package idempotency
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dbStore хранит ключи в таблице idempotency_keys.
+type dbStore struct {
+	db *gorm.DB
+}
+
+// NewStore создает хранилище ключей в PostgreSQL.
+func NewStore(db *gorm.DB) Store {
+	return &dbStore{db: db}
+}
+
+// Begin вставляет ключ, если его нет. Конкурентные запросы с одним ключом
+// разрешает уникальный индекс: вставка второго ничего не делает, и он получает запись первого.
+func (s *dbStore) Begin(record *Record) (*Record, error) {
+	var existing *Record
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("key = ? AND expires_at <= ?", record.Key, record.CreatedAt).
+			Delete(&Record{}).Error; err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil || result.RowsAffected == 1 {
+			return result.Error
+		}
+		existing = &Record{}
+		return tx.Where("key = ?", record.Key).Take(existing).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (s *dbStore) Complete(record *Record) error {
+	return s.db.Model(record).Select("status_code", "header", "body").Updates(record).Error
+}
+
+func (s *dbStore) Release(key string) error {
+	return s.db.Where("key = ?", key).Delete(&Record{}).Error
+}
+
+func (s *dbStore) DeleteExpired(now time.Time) (int64, error) {
+	result := s.db.Where("expires_at <= ?", now).Delete(&Record{})
+	return result.RowsAffected, result.Error
+}
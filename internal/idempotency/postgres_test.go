@@ -0,0 +1,89 @@
+package idempotency
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqldb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: sqldb,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	return gormDB, mock
+}
+
+func TestStoreBegin(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	now := time.Now()
+	record := &Record{Key: "anonymous:key", Fingerprint: "abc", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "idempotency_keys" WHERE key = \$1 AND expires_at <= \$2`).
+		WithArgs("anonymous:key", now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "idempotency_keys" .* ON CONFLICT DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	existing, err := store.Begin(record)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreBeginExistingKey(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	now := time.Now()
+	record := &Record{Key: "anonymous:key", Fingerprint: "abc", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "idempotency_keys"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "idempotency_keys" .* ON CONFLICT DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT \* FROM "idempotency_keys" WHERE key = \$1 LIMIT \$2`).
+		WithArgs("anonymous:key", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "fingerprint", "status_code", "header", "body"}).
+			AddRow("anonymous:key", "abc", http.StatusCreated, `{"Content-Type":["application/json"]}`, []byte(`{}`)))
+	mock.ExpectCommit()
+
+	existing, err := store.Begin(record)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, existing.StatusCode)
+	assert.Equal(t, "application/json", existing.Header.Get("Content-Type"))
+	assert.Equal(t, []byte(`{}`), existing.Body)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreComplete(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "idempotency_keys" SET "status_code"=\$1,"header"=\$2,"body"=\$3 WHERE "key" = \$4`).
+		WithArgs(http.StatusCreated, `{"Location":["/questions/1"]}`, []byte(`{}`), "anonymous:key").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := store.Complete(&Record{
+		Key:        "anonymous:key",
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Location": {"/questions/1"}},
+		Body:       []byte(`{}`),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,268 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Format - формат входных данных импорта.
+type Format string
+
+// Поддерживаемые форматы.
+const (
+	// FormatNDJSON - по одному JSON-объекту вопроса в строке.
+	FormatNDJSON Format = "ndjson"
+	// FormatJSON - JSON-массив вопросов.
+	FormatJSON Format = "json"
+	// FormatCSV - CSV с заголовком, по строке на ответ (см. CSVColumns).
+	FormatCSV Format = "csv"
+)
+
+// ParseFormat возвращает формат по его названию.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatNDJSON, FormatJSON, FormatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported import format %q: must be one of ndjson, json, csv", name)
+	}
+}
+
+// Question - импортируемый вопрос вместе с ответами.
+// Незаданные автор и время создания заполняются так же, как при создании через API.
+type Question struct {
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	UserID    *uuid.UUID `json:"user_id"`
+	CreatedAt *time.Time `json:"created_at"`
+	Answers   []Answer   `json:"answers"`
+}
+
+// Answer - импортируемый ответ.
+type Answer struct {
+	Text      string     `json:"text"`
+	UserID    *uuid.UUID `json:"user_id"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+// CSVColumns - допустимые колонки CSV. Обязательна только title.
+// Каждая строка описывает вопрос и, если заполнена колонка answer, один его ответ;
+// идущие подряд строки с одинаковым заголовком относятся к одному вопросу,
+// а его тело, автор и время создания берутся из первой из них.
+var CSVColumns = []string{"title", "body", "user_id", "created_at", "answer", "answer_user_id", "answer_created_at"}
+
+// record - запись входных данных: разобранный вопрос или ошибка его разбора.
+type record struct {
+	// line - номер строки, с которой начинается запись; для JSON-массива не заполняется.
+	line     int
+	question Question
+	err      error
+}
+
+// readRecords читает записи из r и передает их fn по порядку.
+// Ошибки отдельных записей передаются в record.err, а возвращается только ошибка,
+// после которой входные данные невозможно читать дальше.
+func readRecords(r io.Reader, format Format, fn func(record)) error {
+	switch format {
+	case FormatNDJSON:
+		return readNDJSON(r, fn)
+	case FormatJSON:
+		return readJSON(r, fn)
+	case FormatCSV:
+		return readCSV(r, fn)
+	default:
+		return fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+func readNDJSON(r io.Reader, fn func(record)) error {
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		data, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			rec := record{line: line}
+			rec.err = decodeQuestion(data, &rec.question)
+			fn(rec)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read line %d: %w", line, err)
+		}
+	}
+}
+
+// readJSON читает массив поэлементно, не загружая его целиком.
+// Элементы сначала читаются как json.RawMessage: синтаксическая ошибка не позволяет найти начало
+// следующего элемента, а ошибка в полях одного вопроса не мешает разобрать остальные.
+func readJSON(r io.Reader, fn func(record)) error {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		if err != nil && !errors.Is(err, io.EOF) {
+			return jsonError(dec, err)
+		}
+		return errors.New("JSON input must be an array of questions")
+	}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return jsonError(dec, err)
+		}
+		var rec record
+		rec.err = decodeQuestion(raw, &rec.question)
+		fn(rec)
+	}
+	if _, err := dec.Token(); err != nil {
+		return jsonError(dec, err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("JSON input must contain a single array, found more data at byte offset %d",
+			dec.InputOffset())
+	}
+	return nil
+}
+
+// jsonError описывает ошибку чтения JSON-массива: синтаксическую или ошибку чтения входных данных.
+func jsonError(dec *json.Decoder, err error) error {
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("malformed JSON at byte offset %d: %w", syntaxErr.Offset, err)
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return errors.New("malformed JSON: unexpected end of input")
+	default:
+		return fmt.Errorf("failed to read JSON at byte offset %d: %w", dec.InputOffset(), err)
+	}
+}
+
+// decodeQuestion строго разбирает JSON-объект вопроса: неизвестные поля и лишние данные - ошибка.
+func decodeQuestion(data []byte, question *Question) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(question); err != nil {
+		return fmt.Errorf("invalid question: %w", err)
+	}
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return errors.New("invalid question: must be a single JSON object")
+	}
+	return nil
+}
+
+func readCSV(r io.Reader, fn func(record)) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(CSVColumns, name) {
+			return fmt.Errorf("unknown CSV column %q: must be one of %s", name, strings.Join(CSVColumns, ", "))
+		}
+		if _, ok := columns[name]; ok {
+			return fmt.Errorf("duplicate CSV column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return errors.New("CSV header must contain a title column")
+	}
+
+	var current *record
+	emit := func() {
+		if current != nil {
+			fn(*current)
+			current = nil
+		}
+	}
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+			// Строка прочитана целиком, но ее нельзя сопоставить с колонками
+			emit()
+			fn(record{line: parseErr.StartLine, err: err})
+			continue
+		}
+		if err != nil {
+			emit()
+			if errors.As(err, &parseErr) {
+				return fmt.Errorf("malformed CSV: %w", err)
+			}
+			return fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return row[i]
+			}
+			return ""
+		}
+		if current == nil || field("title") != current.question.Title {
+			emit()
+			line, _ := cr.FieldPos(0)
+			current = &record{line: line}
+			current.question = Question{Title: field("title"), Body: field("body")}
+			current.question.UserID, current.err = parseUUID("user_id", field("user_id"))
+			if current.err == nil {
+				current.question.CreatedAt, current.err = parseTime("created_at", field("created_at"))
+			}
+		}
+		if field("answer") == "" {
+			continue
+		}
+		answer := Answer{Text: field("answer")}
+		var answerErr error
+		answer.UserID, answerErr = parseUUID("answer_user_id", field("answer_user_id"))
+		if answerErr == nil {
+			answer.CreatedAt, answerErr = parseTime("answer_created_at", field("answer_created_at"))
+		}
+		if answerErr != nil && current.err == nil {
+			line, _ := cr.FieldPos(0)
+			current.err = fmt.Errorf("line %d: %w", line, answerErr)
+		}
+		current.question.Answers = append(current.question.Answers, answer)
+	}
+	emit()
+	return nil
+}
+
+func parseUUID(column, value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", column, value, err)
+	}
+	return &id, nil
+}
+
+func parseTime(column, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: must be an RFC 3339 timestamp", column, value)
+	}
+	return &t, nil
+}
@@ -0,0 +1,233 @@
+// Package importer загружает вопросы вместе с ответами из NDJSON, JSON-массива или CSV.
+// Каждая запись проверяется по тем же правилам, что и вопросы и ответы, созданные через API,
+// и сохраняется пачками, каждая пачка - в своей транзакции. По каждой записи составляется отчет.
+package importer
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/repository"
+)
+
+// DefaultBatchSize - сколько вопросов сохраняется в одной транзакции по умолчанию.
+const DefaultBatchSize = 100
+
+// Status - итог импорта записи.
+type Status string
+
+// Итоги импорта записи.
+const (
+	// StatusCreated - вопрос сохранен (в пробном режиме - был бы сохранен).
+	StatusCreated Status = "created"
+	// StatusSkipped - вопрос с таким заголовком уже есть в хранилище или встретился раньше во входных данных.
+	StatusSkipped Status = "skipped"
+	// StatusFailed - запись не прошла разбор или проверку, или не удалось сохранить ее пачку.
+	StatusFailed Status = "failed"
+)
+
+// Options - параметры импорта.
+type Options struct {
+	Format Format
+	// DryRun - только проверить записи, ничего не сохраняя.
+	DryRun bool
+	// BatchSize - сколько вопросов сохранять в одной транзакции; 0 означает DefaultBatchSize.
+	BatchSize int
+}
+
+// Result - итог импорта одной записи.
+type Result struct {
+	// Record - порядковый номер записи во входных данных, начиная с 1.
+	Record int `json:"record"`
+	// Line - номер строки, с которой начинается запись (для NDJSON и CSV).
+	Line       int    `json:"line,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Status     Status `json:"status"`
+	QuestionID uint   `json:"question_id,omitempty"`
+	Answers    int    `json:"answers,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// Report - отчет об импорте.
+type Report struct {
+	DryRun  bool `json:"dry_run"`
+	Created int  `json:"created"`
+	Skipped int  `json:"skipped"`
+	Failed  int  `json:"failed"`
+	// Error - почему входные данные не удалось дочитать; записи после этого места не импортированы.
+	Error   string   `json:"error,omitempty"`
+	Results []Result `json:"results"`
+}
+
+// Importer импортирует вопросы в хранилище.
+type Importer struct {
+	repo     repository.Repository
+	logger   *logrus.Logger
+	validate *validator.Validate
+}
+
+// New создает новый экземпляр импортера.
+func New(repo repository.Repository, logger *logrus.Logger) *Importer {
+	return &Importer{repo: repo, logger: logger, validate: validator.New()}
+}
+
+// pending - проверенный вопрос, ожидающий сохранения вместе со своей пачкой.
+type pending struct {
+	result   int // индекс в Report.Results
+	question *models.Question
+}
+
+// Import читает записи из r и сохраняет их пачками. Отчет возвращается и вместе с ошибкой:
+// ошибка означает, что входные данные не удалось дочитать, а пачки, сохраненные до этого, остаются.
+func (imp *Importer) Import(r io.Reader, opts Options) (*Report, error) {
+	imp.logger.Infof("Importing %s (dry run: %t)", opts.Format, opts.DryRun)
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	report := &Report{DryRun: opts.DryRun, Results: []Result{}}
+	seen := make(map[string]int) // заголовок -> номер записи, в которой он встретился
+	var batch []pending
+	err := readRecords(r, opts.Format, func(rec record) {
+		result := Result{Record: len(report.Results) + 1, Line: rec.line, Title: rec.question.Title}
+		var model *models.Question
+		err := rec.err
+		if err == nil {
+			model, err = imp.toModel(&rec.question)
+		}
+
+		switch {
+		case err != nil:
+			result.Status = StatusFailed
+			result.Reason = err.Error()
+		case seen[model.Title] != 0:
+			result.Status = StatusSkipped
+			result.Reason = fmt.Sprintf("duplicates the title of record %d", seen[model.Title])
+		default:
+			seen[model.Title] = result.Record
+			batch = append(batch, pending{result: len(report.Results), question: model})
+		}
+		report.Results = append(report.Results, result)
+
+		if len(batch) >= batchSize {
+			imp.flush(batch, report)
+			batch = batch[:0]
+		}
+	})
+	imp.flush(batch, report)
+
+	for _, result := range report.Results {
+		switch result.Status {
+		case StatusCreated:
+			report.Created++
+		case StatusSkipped:
+			report.Skipped++
+		case StatusFailed:
+			report.Failed++
+		}
+	}
+	imp.logger.Infof("Import finished: %d created, %d skipped, %d failed",
+		report.Created, report.Skipped, report.Failed)
+	if err != nil {
+		imp.logger.Warnf("Import stopped after record %d: %v", len(report.Results), err)
+		report.Error = err.Error()
+		return report, err
+	}
+	return report, nil
+}
+
+// toModel проверяет вопрос и его ответы по правилам моделей и преобразует их в модели.
+func (imp *Importer) toModel(q *Question) (*models.Question, error) {
+	question := &models.Question{
+		Title:  q.Title,
+		Body:   q.Body,
+		UserID: q.UserID,
+		Status: models.QuestionStatusOpen,
+	}
+	if q.CreatedAt != nil {
+		question.CreatedAt = *q.CreatedAt
+	}
+	if err := imp.validate.Struct(question); err != nil {
+		return nil, err
+	}
+
+	for i, a := range q.Answers {
+		answer := models.Answer{Text: a.Text}
+		if a.UserID != nil {
+			answer.UserID = *a.UserID
+		} else {
+			answer.UserID = uuid.New() // как при создании ответа через API
+		}
+		if a.CreatedAt != nil {
+			answer.CreatedAt = *a.CreatedAt
+			if q.CreatedAt != nil && answer.CreatedAt.Before(question.CreatedAt) {
+				return nil, fmt.Errorf("answer %d: created before its question", i+1)
+			}
+		}
+		if err := imp.validate.Struct(&answer); err != nil {
+			return nil, fmt.Errorf("answer %d: %w", i+1, err)
+		}
+		question.Answers = append(question.Answers, answer)
+	}
+	return question, nil
+}
+
+// flush сохраняет пачку в одной транзакции и записывает итоги в отчет.
+// Вопросы с заголовками, которые уже есть в хранилище, пропускаются.
+// В пробном режиме хранилище только читается.
+func (imp *Importer) flush(batch []pending, report *Report) {
+	if len(batch) == 0 {
+		return
+	}
+	titles := make([]string, len(batch))
+	for i, p := range batch {
+		titles[i] = p.question.Title
+	}
+	existing, err := imp.repo.FindQuestionIDsByTitle(titles)
+	if err != nil {
+		imp.logger.Errorf("Failed to find existing questions: %v", err)
+		failAll(batch, report, fmt.Sprintf("failed to check existing questions: %v", err))
+		return
+	}
+
+	var create []pending
+	questions := make([]*models.Question, 0, len(batch))
+	for _, p := range batch {
+		if id, ok := existing[p.question.Title]; ok {
+			result := &report.Results[p.result]
+			result.Status = StatusSkipped
+			result.QuestionID = id
+			result.Reason = "a question with this title already exists"
+			continue
+		}
+		create = append(create, p)
+		questions = append(questions, p.question)
+	}
+
+	if !report.DryRun {
+		if err := imp.repo.CreateQuestions(questions); err != nil {
+			imp.logger.Errorf("Failed to save a batch of %d questions: %v", len(questions), err)
+			failAll(create, report, fmt.Sprintf("failed to save batch: %v", err))
+			return
+		}
+	}
+	for _, p := range create {
+		result := &report.Results[p.result]
+		result.Status = StatusCreated
+		result.QuestionID = p.question.ID
+		result.Answers = len(p.question.Answers)
+	}
+}
+
+func failAll(batch []pending, report *Report, reason string) {
+	for _, p := range batch {
+		report.Results[p.result].Status = StatusFailed
+		report.Results[p.result].Reason = reason
+	}
+}
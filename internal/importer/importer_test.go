@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/repository"
+)
+
+var withAnswers = query.Projection{Include: []string{query.IncludeAnswers}}
+
+// failingRepository не сохраняет пачки после первых ok.
+type failingRepository struct {
+	repository.Repository
+	ok int
+}
+
+func (r *failingRepository) CreateQuestions(questions []*models.Question) error {
+	if r.ok == 0 {
+		return errors.New("connection reset")
+	}
+	r.ok--
+	return r.Repository.CreateQuestions(questions)
+}
+
+func newRepository(t *testing.T, titles ...string) repository.Repository {
+	repo := repository.NewMemoryRepository(logrus.New())
+	for _, title := range titles {
+		assert.NoError(t, repo.CreateQuestion(&models.Question{Title: title, Status: models.QuestionStatusOpen}))
+	}
+	return repo
+}
+
+func statuses(report *Report) []Status {
+	var result []Status
+	for _, r := range report.Results {
+		result = append(result, r.Status)
+	}
+	return result
+}
+
+func TestImportNDJSON(t *testing.T) {
+	repo := newRepository(t, "Existing question")
+	author := uuid.New()
+	input := `{"title":"How to import?","body":"Body","user_id":"` + author.String() + `",` +
+		`"created_at":"2020-01-02T03:04:05Z","answers":[{"text":"Like this","created_at":"2020-01-03T00:00:00Z"}]}
+{"title":"No"}
+
+{"title":"Broken"
+{"title":"Existing question"}
+{"title":"How to import?"}
+{"title":"Unknown field","tags":["go"]}
+`
+	report, err := New(repo, logrus.New()).Import(strings.NewReader(input), Options{Format: FormatNDJSON})
+	assert.NoError(t, err)
+	assert.Equal(t, []Status{StatusCreated, StatusFailed, StatusFailed, StatusSkipped, StatusSkipped, StatusFailed},
+		statuses(report))
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 3, report.Failed)
+
+	// Номера строк учитывают пустые строки
+	assert.Equal(t, []int{1, 2, 4, 5, 6, 7}, []int{report.Results[0].Line, report.Results[1].Line,
+		report.Results[2].Line, report.Results[3].Line, report.Results[4].Line, report.Results[5].Line})
+	assert.Contains(t, report.Results[1].Reason, "'min' tag")
+	assert.Equal(t, uint(1), report.Results[3].QuestionID)
+	assert.Equal(t, "duplicates the title of record 1", report.Results[4].Reason)
+	assert.Contains(t, report.Results[5].Reason, `unknown field "tags"`)
+
+	created := report.Results[0]
+	assert.Equal(t, 1, created.Answers)
+	question, err := repo.GetQuestion(created.QuestionID, withAnswers)
+	assert.NoError(t, err)
+	assert.Equal(t, &author, question.UserID)
+	assert.Equal(t, models.QuestionStatusOpen, question.Status)
+	assert.True(t, question.CreatedAt.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.Len(t, question.Answers, 1)
+	assert.Equal(t, "Like this", question.Answers[0].Text)
+	assert.NotEqual(t, uuid.Nil, question.Answers[0].UserID)
+}
+
+func TestImportJSONArray(t *testing.T) {
+	repo := newRepository(t)
+	input := `[
+		{"title":"First question","answers":[{"text":"ok"}]},
+		{"title":"Second question","answers":[{"text":"Answer"}]},
+		{"title":42}
+	]`
+	report, err := New(repo, logrus.New()).Import(strings.NewReader(input), Options{Format: FormatJSON})
+	assert.NoError(t, err)
+	assert.Equal(t, []Status{StatusFailed, StatusCreated, StatusFailed}, statuses(report))
+	assert.Contains(t, report.Results[0].Reason, "answer 1:")
+	assert.Zero(t, report.Results[0].Line)
+	assert.Equal(t, 3, report.Results[2].Record)
+}
+
+func TestImportMalformedJSONKeepsSavedRecords(t *testing.T) {
+	repo := newRepository(t)
+	input := `[{"title":"First question"}, {"title":"Second question"}, {"title": ]`
+	report, err := New(repo, logrus.New()).Import(strings.NewReader(input), Options{Format: FormatJSON})
+	assert.ErrorContains(t, err, "malformed JSON")
+	assert.Equal(t, err.Error(), report.Error)
+	assert.Equal(t, 2, report.Created)
+
+	_, err = repo.GetQuestion(2, query.Projection{})
+	assert.NoError(t, err)
+
+	_, err = New(repo, logrus.New()).Import(strings.NewReader(`{"title":"Not an array"}`), Options{Format: FormatJSON})
+	assert.EqualError(t, err, "JSON input must be an array of questions")
+}
+
+func TestImportCSV(t *testing.T) {
+	repo := newRepository(t)
+	input := "title,body,answer,answer_user_id\n" +
+		"First question,Body,First answer,\n" +
+		"First question,ignored,Second answer,\n" +
+		"Second question,,,\n" +
+		"Third question,,Answer,not-a-uuid\n" +
+		"Fourth question,Body\n" +
+		"\"Multi\nline question\",,,\n"
+	report, err := New(repo, logrus.New()).Import(strings.NewReader(input), Options{Format: FormatCSV})
+	assert.NoError(t, err)
+	assert.Equal(t, []Status{StatusCreated, StatusCreated, StatusFailed, StatusFailed, StatusCreated},
+		statuses(report))
+	assert.Equal(t, []int{2, 4, 5, 6, 7}, []int{report.Results[0].Line, report.Results[1].Line,
+		report.Results[2].Line, report.Results[3].Line, report.Results[4].Line})
+	assert.Contains(t, report.Results[2].Reason, `line 5: invalid answer_user_id "not-a-uuid"`)
+	assert.Contains(t, report.Results[3].Reason, "wrong number of fields")
+
+	question, err := repo.GetQuestion(report.Results[0].QuestionID, withAnswers)
+	assert.NoError(t, err)
+	assert.Equal(t, "Body", question.Body)
+	assert.Len(t, question.Answers, 2)
+}
+
+func TestImportCSVHeader(t *testing.T) {
+	imp := New(newRepository(t), logrus.New())
+
+	_, err := imp.Import(strings.NewReader("title,tags\n"), Options{Format: FormatCSV})
+	assert.ErrorContains(t, err, `unknown CSV column "tags"`)
+	_, err = imp.Import(strings.NewReader("body\n"), Options{Format: FormatCSV})
+	assert.EqualError(t, err, "CSV header must contain a title column")
+
+	report, err := imp.Import(strings.NewReader(""), Options{Format: FormatCSV})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Results)
+}
+
+func TestImportDryRun(t *testing.T) {
+	repo := newRepository(t, "Existing question")
+	input := `{"title":"New question"}
+{"title":"Existing question"}`
+	report, err := New(repo, logrus.New()).Import(strings.NewReader(input), Options{Format: FormatNDJSON, DryRun: true})
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []Status{StatusCreated, StatusSkipped}, statuses(report))
+	assert.Zero(t, report.Results[0].QuestionID)
+
+	_, err = repo.GetQuestion(2, query.Projection{})
+	assert.Error(t, err)
+}
+
+func TestImportAnswerBeforeQuestion(t *testing.T) {
+	input := `{"title":"Question","created_at":"2020-01-02T00:00:00Z",` +
+		`"answers":[{"text":"Answer","created_at":"2020-01-01T00:00:00Z"}]}`
+	report, err := New(newRepository(t), logrus.New()).Import(strings.NewReader(input), Options{Format: FormatNDJSON})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusFailed, report.Results[0].Status)
+	assert.Equal(t, "answer 1: created before its question", report.Results[0].Reason)
+}
+
+func TestImportBatches(t *testing.T) {
+	repo := &failingRepository{Repository: newRepository(t), ok: 1}
+	input := `{"title":"Question 1"}
+{"title":"Question 2"}
+{"title":"x"}
+{"title":"Question 3"}
+{"title":"Question 4"}
+{"title":"Question 5"}`
+	report, err := New(repo, logrus.New()).Import(strings.NewReader(input), Options{Format: FormatNDJSON, BatchSize: 2})
+	assert.NoError(t, err)
+	// Вторая пачка не сохранилась целиком, третья тоже
+	assert.Equal(t, []Status{StatusCreated, StatusCreated, StatusFailed, StatusFailed, StatusFailed, StatusFailed},
+		statuses(report))
+	assert.Equal(t, "failed to save batch: connection reset", report.Results[3].Reason)
+	assert.Equal(t, "failed to save batch: connection reset", report.Results[5].Reason)
+	assert.Equal(t, 2, report.Created)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+// Package markdown преобразует markdown-текст вопросов и ответов в безопасный HTML.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// renderer - конвертер markdown в HTML. Без html.WithUnsafe сырой HTML из
+// исходного текста не попадает в результат, а содержимое блоков кода экранируется.
+var renderer = goldmark.New(
+	goldmark.WithExtensions(
+		extension.Table,
+		extension.Strikethrough,
+		extension.Linkify,
+	),
+)
+
+// policy - строгий allowlist тегов и атрибутов, допустимых в итоговом HTML.
+var policy = newPolicy()
+
+// newPolicy создает политику санитизации HTML.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+
+	p.AllowElements(
+		"p", "br", "hr",
+		"h1", "h2", "h3", "h4", "h5", "h6",
+		"strong", "em", "del",
+		"blockquote", "ul", "ol", "li",
+		"pre", "code",
+		"table", "thead", "tbody", "tr", "th", "td",
+	)
+	p.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	// Подсветку синтаксиса на клиенте разрешаем только через класс language-*
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return p
+}
+
+// Render преобразует markdown в санитизированный HTML.
+// Если markdown не удалось разобрать, возвращается экранированный исходный текст.
+func Render(src string) string {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(src), &buf); err != nil {
+		return "<p>" + html.EscapeString(src) + "</p>"
+	}
+	return policy.Sanitize(buf.String())
+}
@@ -0,0 +1,46 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderBasicMarkdown(t *testing.T) {
+	out := Render("# Title\n\nSome **bold** and *italic* text")
+
+	assert.Contains(t, out, "<h1>Title</h1>")
+	assert.Contains(t, out, "<strong>bold</strong>")
+	assert.Contains(t, out, "<em>italic</em>")
+}
+
+func TestRenderStripsRawHTML(t *testing.T) {
+	out := Render("Hello <script>alert(1)</script> <img src=x onerror=alert(1)>")
+
+	assert.NotContains(t, out, "<script")
+	assert.NotContains(t, out, "<img")
+	assert.NotContains(t, out, "onerror")
+}
+
+func TestRenderEscapesCodeBlocks(t *testing.T) {
+	out := Render("```go\nif a < b && c > d { fmt.Println(\"<b>\") }\n```")
+
+	assert.Contains(t, out, `<pre><code class="language-go">`)
+	assert.Contains(t, out, "a &lt; b &amp;&amp; c &gt; d")
+	assert.Contains(t, out, "&lt;b&gt;")
+	assert.NotContains(t, out, "<b>")
+}
+
+func TestRenderRejectsUnsafeCodeClass(t *testing.T) {
+	out := Render("```\" onclick=\"alert(1)\nx\n```")
+
+	assert.NotContains(t, out, "onclick")
+}
+
+func TestRenderSanitizesLinks(t *testing.T) {
+	out := Render("[safe](https://go.dev) [unsafe](javascript:alert(1))")
+
+	assert.Contains(t, out, `href="https://go.dev"`)
+	assert.Contains(t, out, `rel="nofollow noreferrer noopener"`)
+	assert.NotContains(t, out, "javascript:")
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// TextArray - строки в колонке text[] PostgreSQL. Элементы записываются без экранирования,
+// поэтому в них не должно быть запятых, кавычек, обратной косой черты и фигурных скобок.
+type TextArray []string
+
+// Value записывает строки литералом массива PostgreSQL. Элементы берутся в кавычки, чтобы строка "null"
+// не превратилась в NULL.
+func (a TextArray) Value() (driver.Value, error) {
+	quoted := make([]string, len(a))
+	for i, element := range a {
+		quoted[i] = `"` + element + `"`
+	}
+	return "{" + strings.Join(quoted, ",") + "}", nil
+}
+
+// Scan читает строки из литерала массива PostgreSQL.
+func (a *TextArray) Scan(src any) error {
+	var literal string
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		literal = v
+	case []byte:
+		literal = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, a)
+	}
+	literal = strings.TrimSuffix(strings.TrimPrefix(literal, "{"), "}")
+	if literal == "" {
+		*a = TextArray{}
+		return nil
+	}
+	elements := strings.Split(literal, ",")
+	for i, element := range elements {
+		elements[i] = strings.Trim(element, `"`)
+	}
+	*a = elements
+	return nil
+}
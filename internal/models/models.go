@@ -6,19 +6,141 @@ import (
 	"github.com/google/uuid"
 )
 
-// Question представляет модель вопроса
+// Статусы жизненного цикла вопроса
+const (
+	QuestionStatusOpen     = "open"
+	QuestionStatusClosed   = "closed"
+	QuestionStatusLocked   = "locked"
+	QuestionStatusArchived = "archived"
+)
+
+// CloseReasonDuplicate - причина закрытия вопроса как дубликата
+const CloseReasonDuplicate = "duplicate"
+
+// QuestionStatuses - все допустимые статусы вопроса
+var QuestionStatuses = []string{
+	QuestionStatusOpen, QuestionStatusClosed, QuestionStatusLocked, QuestionStatusArchived,
+}
+
+// Question представляет модель вопроса. Body хранит markdown, Tags - нормализованные теги (см. NormalizeTags).
+// UserID - автор вопроса; у вопросов, заданных анонимно, он не заполнен.
+// Score - рейтинг вопроса по голосам пользователей.
+// DuplicateOf указывает на исходный вопрос, если этот вопрос закрыт как дубликат.
+// AcceptedAnswerID - ответ, принятый автором вопроса (сохраняется при импорте из StackExchange).
+// CloseReason, ClosedBy и ClosedAt заполняются при закрытии или блокировке вопроса.
+// UpdatedAt - время последнего изменения вопроса, его ответов или комментариев к ним;
+// в БД его поддерживают триггеры. По нему строится версия представления вопроса (ETag).
+// Version увеличивается при каждом изменении самого вопроса и используется для оптимистичной блокировки.
+// Answers и Comments подгружаются только по запросу.
+// CommentCount, AnswerCount и LastActivityAt (время последнего ответа или создания вопроса)
+// вычисляются при чтении и в таблице не хранятся.
 type Question struct {
-	ID        uint      `gorm:"primaryKey"`
-	Text      string    `gorm:"not null" validate:"required,min=3,max=500"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	Answers   []Answer  `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE;"`
+	ID               uint       `gorm:"primaryKey"`
+	UserID           *uuid.UUID `gorm:"type:uuid;index"`
+	Title            string     `gorm:"not null" validate:"required,min=3,max=250"`
+	Body             string     `gorm:"not null" validate:"max=30000"`
+	Tags             Tags       `gorm:"type:text[];not null;default:'{}'"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime"`
+	Version          int        `gorm:"not null;default:1"`
+	Score            int        `gorm:"not null"`
+	Status           string     `gorm:"not null;index"`
+	CloseReason      string     `gorm:"not null"`
+	ClosedBy         *uuid.UUID `gorm:"type:uuid"`
+	ClosedAt         *time.Time `gorm:"type:timestamptz"`
+	DuplicateOf      *uint      `gorm:"index"`
+	AcceptedAnswerID *uint
+	Answers          []Answer  `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE;"`
+	Comments         []Comment `gorm:"polymorphic:Parent;polymorphicValue:question"`
+	CommentCount     int64     `gorm:"->;-:migration"`
+	AnswerCount      int64     `gorm:"->;-:migration"`
+	LastActivityAt   time.Time `gorm:"->;-:migration"`
 }
 
-// Answer представляет модель ответа
+// SimilarQuestion - вопрос, похожий на заданный заголовок, с оценкой сходства от 0 до 1.
+type SimilarQuestion struct {
+	Question
+	Similarity float64 `gorm:"->;-:migration"`
+}
+
+// Answer представляет модель ответа. Text хранит markdown.
+// UpdatedAt - время последнего изменения ответа или комментариев к нему,
+// Version - номер версии ответа для оптимистичной блокировки.
+// Comments подгружаются только по запросу, CommentCount вычисляется при чтении и в таблице не хранится.
 type Answer struct {
+	ID           uint      `gorm:"primaryKey"`
+	QuestionID   uint      `gorm:"not null"`
+	UserID       uuid.UUID `gorm:"not null"`
+	Text         string    `gorm:"not null" validate:"required,min=3,max=10000"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+	Version      int       `gorm:"not null;default:1"`
+	Comments     []Comment `gorm:"polymorphic:Parent;polymorphicValue:answer"`
+	CommentCount int64     `gorm:"->;-:migration"`
+}
+
+// Типы родительских сущностей комментария
+const (
+	CommentParentQuestion = "question"
+	CommentParentAnswer   = "answer"
+)
+
+// Comment представляет модель комментария к вопросу или ответу
+type Comment struct {
 	ID         uint      `gorm:"primaryKey"`
-	QuestionID uint      `gorm:"not null"`
+	ParentType string    `gorm:"not null"`
+	ParentID   uint      `gorm:"not null"`
 	UserID     uuid.UUID `gorm:"not null"`
-	Text       string    `gorm:"not null" validate:"required,min=3,max=500"`
+	Text       string    `gorm:"not null" validate:"required,min=3,max=200"`
 	CreatedAt  time.Time `gorm:"autoCreateTime"`
 }
+
+// Типы постов внешнего источника
+const (
+	ImportedKindQuestion = "question"
+	ImportedKindAnswer   = "answer"
+)
+
+// ImportedPost связывает пост внешнего источника (вопрос или ответ) с созданной из него записью.
+// По этим связям повторный импорт того же источника пропускает уже импортированные посты.
+type ImportedPost struct {
+	Source     string    `gorm:"primaryKey"`
+	ExternalID int64     `gorm:"primaryKey;autoIncrement:false"`
+	Kind       string    `gorm:"not null"`
+	EntityID   uint      `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// ImportedUser связывает пользователя внешнего источника с ID, под которым сохраняются его посты.
+// Профилей пользователей в сервисе нет, поэтому отображаемое имя хранится только здесь.
+type ImportedUser struct {
+	Source      string    `gorm:"primaryKey"`
+	ExternalID  int64     `gorm:"primaryKey;autoIncrement:false"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null"`
+	DisplayName string    `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+// OutboxMessage - событие, записанное в таблицу outbox в одной транзакции с изменением, которое его вызвало.
+// Агрегат - вопрос вместе с его ответами: сообщения одного агрегата доставляются в порядке ID.
+// Payload - событие в JSON. DeliveredSinks - имена получателей, которые уже приняли сообщение: после
+// неудачной попытки следующая откладывается до AvailableAt и отправляет сообщение только остальным.
+// ProcessedAt заполняется, когда сообщение доставлено всем получателям.
+type OutboxMessage struct {
+	ID             uint64    `gorm:"primaryKey"`
+	AggregateType  string    `gorm:"not null"`
+	AggregateID    uint      `gorm:"not null"`
+	EventType      string    `gorm:"not null"`
+	Payload        []byte    `gorm:"not null"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	AvailableAt    time.Time `gorm:"not null"`
+	ProcessedAt    *time.Time
+	Attempts       int       `gorm:"not null"`
+	LastError      string    `gorm:"not null"`
+	DeliveredSinks TextArray `gorm:"type:text[];not null;default:'{}'"`
+}
+
+// TableName задает имя таблицы для OutboxMessage.
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
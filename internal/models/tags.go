@@ -0,0 +1,55 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MaxTags - сколько тегов может быть у вопроса.
+const MaxTags = 5
+
+// tagPattern - допустимый тег: строчные латинские буквы, цифры и символы . + # -, до 35 символов.
+// Запятых, пробелов и кавычек в тегах нет, поэтому их можно перечислять через запятую в параметрах
+// запроса и хранить в массиве PostgreSQL без экранирования.
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.+#-]{0,34}$`)
+
+// Tags - теги вопроса. В PostgreSQL хранятся в колонке text[].
+type Tags []string
+
+// NormalizeTag приводит тег к нижнему регистру без пробелов по краям и сообщает, допустим ли он.
+func NormalizeTag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	return tag, tagPattern.MatchString(tag)
+}
+
+// NormalizeTags нормализует теги (см. NormalizeTag) и убирает повторы, сохраняя порядок.
+// Возвращает ошибку, если тег недопустим или тегов больше MaxTags.
+func NormalizeTags(tags []string) (Tags, error) {
+	normalized := make(Tags, 0, len(tags))
+	for _, tag := range tags {
+		name, ok := NormalizeTag(tag)
+		if !ok {
+			return nil, fmt.Errorf("invalid tag %q: must be 1-35 lowercase letters, digits or . + # -", tag)
+		}
+		if !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	if len(normalized) > MaxTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", MaxTags)
+	}
+	return normalized, nil
+}
+
+// Value записывает теги литералом массива PostgreSQL (см. TextArray).
+func (t Tags) Value() (driver.Value, error) {
+	return TextArray(t).Value()
+}
+
+// Scan читает теги из литерала массива PostgreSQL.
+func (t *Tags) Scan(src any) error {
+	return (*TextArray)(t).Scan(src)
+}
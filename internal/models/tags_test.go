@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Go ", "c++", "go", "node.js"})
+	assert.NoError(t, err)
+	assert.Equal(t, Tags{"go", "c++", "node.js"}, tags)
+
+	_, err = NormalizeTags([]string{"go", "hello world"})
+	assert.ErrorContains(t, err, `"hello world"`)
+	_, err = NormalizeTags([]string{""})
+	assert.Error(t, err)
+	_, err = NormalizeTags([]string{"a", "b", "c", "d", "e", "f"})
+	assert.EqualError(t, err, "at most 5 tags are allowed")
+}
+
+func TestTagsValueAndScan(t *testing.T) {
+	value, err := Tags{"go", "null"}.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"go","null"}`, value)
+	value, err = Tags(nil).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", value)
+
+	var tags Tags
+	assert.NoError(t, tags.Scan(`{go,c++,"null"}`))
+	assert.Equal(t, Tags{"go", "c++", "null"}, tags)
+	assert.NoError(t, tags.Scan([]byte("{}")))
+	assert.Equal(t, Tags{}, tags)
+	assert.Error(t, tags.Scan(42))
+}
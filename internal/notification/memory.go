@@ -0,0 +1,108 @@
+package notification
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryStore - хранилище уведомлений в памяти процесса для тестов и режима STORAGE=memory.
+type memoryStore struct {
+	mu            sync.Mutex
+	notifications map[uint]Notification
+	nextID        uint
+}
+
+// NewMemoryStore создает хранилище уведомлений в памяти.
+func NewMemoryStore() Store {
+	return &memoryStore{notifications: make(map[uint]Notification)}
+}
+
+func (s *memoryStore) Create(notifications []Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for i := range notifications {
+		if s.exists(notifications[i].UserID, notifications[i].Key) {
+			continue
+		}
+		s.nextID++
+		notifications[i].ID, notifications[i].CreatedAt = s.nextID, now
+		s.notifications[s.nextID] = notifications[i]
+	}
+	return nil
+}
+
+// exists сообщает, есть ли у пользователя уведомление с ключом key. Вызывающий должен удерживать блокировку.
+func (s *memoryStore) exists(userID uuid.UUID, key string) bool {
+	for _, n := range s.notifications {
+		if n.UserID == userID && n.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *memoryStore) List(userID uuid.UUID, unreadOnly bool, limit int) ([]Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var notifications []Notification
+	for _, n := range s.notifications {
+		if n.UserID == userID && (!unreadOnly || n.ReadAt == nil) {
+			notifications = append(notifications, n)
+		}
+	}
+	slices.SortFunc(notifications, func(a, b Notification) int { return cmp.Compare(b.ID, a.ID) })
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+func (s *memoryStore) CountUnread(userID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	for _, n := range s.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *memoryStore) MarkRead(userID uuid.UUID, id uint, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, ok := s.notifications[id]
+	if !ok || n.UserID != userID {
+		return ErrNotFound
+	}
+	if n.ReadAt == nil {
+		n.ReadAt = &now
+		s.notifications[id] = n
+	}
+	return nil
+}
+
+func (s *memoryStore) MarkAllRead(userID uuid.UUID, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	for id, n := range s.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			n.ReadAt = &now
+			s.notifications[id] = n
+			count++
+		}
+	}
+	return count, nil
+}
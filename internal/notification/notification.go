@@ -0,0 +1,90 @@
+// Package notification ведет входящие уведомления пользователей: об ответе на их вопрос, о принятии
+// их ответа и об упоминании в вопросе или ответе. Notifier создает уведомления по событиям сервиса
+// отдельно от изменения, вызвавшего событие: через outbox в PostgreSQL или фоновой очередью в памяти.
+package notification
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы уведомлений.
+const (
+	// TypeNewAnswer - на вопрос пользователя ответили.
+	TypeNewAnswer = "new_answer"
+	// TypeAnswerAccepted - автор вопроса принял ответ пользователя.
+	TypeAnswerAccepted = "answer_accepted"
+	// TypeMention - пользователя упомянули в вопросе или ответе.
+	TypeMention = "mention"
+)
+
+// MaxMentions - сколько первых упоминаний в одном тексте создают уведомления.
+const MaxMentions = 10
+
+// ErrNotFound возвращается, если у пользователя нет такого уведомления.
+var ErrNotFound = errors.New("not found")
+
+// Notification - уведомление пользователя UserID о событии вопроса QuestionID.
+// AnswerID заполняется, если событие относится к ответу, ActorID - если известен пользователь,
+// который его вызвал. Key однозначно определяет уведомление среди уведомлений пользователя:
+// повторная обработка того же события не создает второе уведомление.
+type Notification struct {
+	ID         uint      `gorm:"primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null"`
+	Type       string    `gorm:"not null"`
+	QuestionID uint      `gorm:"not null"`
+	AnswerID   *uint
+	ActorID    *uuid.UUID `gorm:"type:uuid"`
+	Key        string     `gorm:"not null"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	// ReadAt - когда пользователь прочитал уведомление; nil для непрочитанных.
+	ReadAt *time.Time
+}
+
+// TableName задает имя таблицы для Notification.
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// Store хранит уведомления.
+type Store interface {
+	// Create сохраняет уведомления. Уведомление с ключом, который уже есть у пользователя, пропускается.
+	Create(notifications []Notification) error
+	// List возвращает до limit последних уведомлений пользователя, новые первыми.
+	// unreadOnly оставляет только непрочитанные.
+	List(userID uuid.UUID, unreadOnly bool, limit int) ([]Notification, error)
+	// CountUnread возвращает число непрочитанных уведомлений пользователя.
+	CountUnread(userID uuid.UUID) (int64, error)
+	// MarkRead отмечает уведомление пользователя прочитанным в now или возвращает ErrNotFound.
+	// Время прочтения уже прочитанного уведомления не меняется.
+	MarkRead(userID uuid.UUID, id uint, now time.Time) error
+	// MarkAllRead отмечает все непрочитанные уведомления пользователя прочитанными в now
+	// и возвращает их число.
+	MarkAllRead(userID uuid.UUID, now time.Time) (int64, error)
+}
+
+// mentionPattern - упоминание пользователя: "@" и его ID, например @0b0a7a9e-2a8f-4c55-9a8e-1f0b3c6d2e41.
+// У пользователей нет имен, поэтому упоминают по ID.
+var mentionPattern = regexp.MustCompile(
+	`(?:^|[^\w@])@([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})\b`)
+
+// Mentions возвращает пользователей, упомянутых в тексте, в порядке первого упоминания,
+// без повторов и не больше MaxMentions.
+func Mentions(text string) []uuid.UUID {
+	var users []uuid.UUID
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		userID, err := uuid.Parse(match[1])
+		if err != nil || slices.Contains(users, userID) {
+			continue
+		}
+		users = append(users, userID)
+		if len(users) == MaxMentions {
+			break
+		}
+	}
+	return users
+}
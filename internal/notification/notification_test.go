@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMentions(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	text := "Thanks @" + first.String() + ", see also (@" + strings.ToUpper(second.String()) + ") and @" +
+		first.String() + ". Not mentions: mail@" + second.String() + ", @not-a-uuid, @@" + uuid.NewString()
+
+	assert.Equal(t, []uuid.UUID{first, second}, Mentions(text))
+	assert.Empty(t, Mentions("No mentions here"))
+}
+
+func TestMentionsLimit(t *testing.T) {
+	var text strings.Builder
+	for range MaxMentions + 5 {
+		text.WriteString("@" + uuid.NewString() + " ")
+	}
+	assert.Len(t, Mentions(text.String()), MaxMentions)
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	user, other := uuid.New(), uuid.New()
+
+	notifications := []Notification{
+		{UserID: user, Type: TypeNewAnswer, QuestionID: 1, Key: "answer:1"},
+		{UserID: user, Type: TypeMention, QuestionID: 1, Key: "mention:answer:1"},
+		{UserID: other, Type: TypeNewAnswer, QuestionID: 2, Key: "answer:1"},
+	}
+	assert.NoError(t, store.Create(notifications))
+	// Повторное событие не создает уведомление с тем же ключом
+	assert.NoError(t, store.Create([]Notification{{UserID: user, Type: TypeNewAnswer, QuestionID: 1, Key: "answer:1"}}))
+
+	list, err := store.List(user, false, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, TypeMention, list[0].Type)
+		assert.Equal(t, TypeNewAnswer, list[1].Type)
+	}
+
+	readAt := time.Now()
+	assert.NoError(t, store.MarkRead(user, notifications[0].ID, readAt))
+	assert.NoError(t, store.MarkRead(user, notifications[0].ID, readAt.Add(time.Hour)))
+	assert.ErrorIs(t, store.MarkRead(other, notifications[0].ID, readAt), ErrNotFound)
+	unread, err := store.List(user, true, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, unread, 1) {
+		assert.Equal(t, notifications[1].ID, unread[0].ID)
+	}
+	list, err = store.List(user, false, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) && assert.NotNil(t, list[1].ReadAt) {
+		assert.Equal(t, readAt, *list[1].ReadAt)
+	}
+
+	count, err := store.MarkAllRead(user, readAt)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = store.CountUnread(user)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+	count, err = store.CountUnread(other)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
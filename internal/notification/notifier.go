@@ -0,0 +1,210 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+)
+
+// DefaultQueueSize - сколько событий Notifier держит в очереди, пока по ним не созданы уведомления.
+const DefaultQueueSize = 1024
+
+// Questions читает вопросы, к которым относятся события об ответах, например repository.Repository.
+// Для несуществующего вопроса возвращается gorm.ErrRecordNotFound.
+type Questions interface {
+	GetQuestion(id uint, proj query.Projection) (*models.Question, error)
+}
+
+// Notifier получает события сервиса и создает по ним уведомления:
+//   - answer.created - автору вопроса и упомянутым в ответе;
+//   - answer.updated - упомянутым в ответе;
+//   - answer.accepted - автору ответа;
+//   - question.created - упомянутым в теле вопроса.
+//
+// Уведомления о собственных действиях не создаются. Publish не блокирует изменение, вызвавшее событие:
+// уведомления создаются отдельной горутиной, как и доставки в webhook.Dispatcher.
+type Notifier struct {
+	store     Store
+	questions Questions
+	logger    *logrus.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan events.Event
+	done   chan struct{}
+}
+
+// NewNotifier создает Notifier и запускает обработку очереди событий.
+// queueSize - размер очереди событий (0 - DefaultQueueSize).
+func NewNotifier(store Store, questions Questions, logger *logrus.Logger, queueSize int) *Notifier {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	n := &Notifier{
+		store:     store,
+		questions: questions,
+		logger:    logger,
+		queue:     make(chan events.Event, queueSize),
+		done:      make(chan struct{}),
+	}
+	go n.run()
+	return n
+}
+
+// Publish ставит событие в очередь. Если очередь заполнена, уведомления о событии не создаются
+// и об этом пишется в журнал: ожидание остановило бы запрос, изменивший данные.
+func (n *Notifier) Publish(event events.Event) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		return
+	}
+	select {
+	case n.queue <- event:
+	default:
+		n.logger.Errorf("Notification queue is full, no notifications for event %s of question %d",
+			event.Type, event.QuestionID)
+	}
+}
+
+// Close перестает принимать события и ждет, пока по событиям из очереди будут созданы уведомления.
+func (n *Notifier) Close() {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+	<-n.done
+}
+
+// Send сразу создает уведомления о событии, не используя очередь, и возвращает ошибку хранилища.
+// Так Notifier используется как получатель outbox: при ошибке событие будет отправлено повторно,
+// а уже созданные уведомления не повторятся благодаря Notification.Key.
+func (n *Notifier) Send(event events.Event) error {
+	return n.notify(event)
+}
+
+func (n *Notifier) run() {
+	defer close(n.done)
+	for event := range n.queue {
+		if err := n.notify(event); err != nil {
+			n.logger.Errorf("Failed to create notifications for event %s: %v", event.Type, err)
+		}
+	}
+}
+
+// notify создает уведомления о событии.
+func (n *Notifier) notify(event events.Event) error {
+	notifications, err := n.build(event)
+	if err != nil || len(notifications) == 0 {
+		return err
+	}
+	if err := n.store.Create(notifications); err != nil {
+		return err
+	}
+	n.logger.Debugf("Created %d notifications for event %s", len(notifications), event.Type)
+	return nil
+}
+
+// build возвращает уведомления о событии. Если вопрос события уже удален, уведомлять не о чем.
+func (n *Notifier) build(event events.Event) ([]Notification, error) {
+	switch {
+	case event.Type == events.TypeQuestionCreated && event.Question != nil:
+		b := batch{actor: event.Question.UserID}
+		b.mention(event.Question.Body, event.QuestionID, nil, fmt.Sprintf("mention:question:%d", event.QuestionID))
+		return b.notifications, nil
+
+	case event.Type == events.TypeAnswerCreated && event.Answer != nil:
+		question, err := n.question(event.QuestionID)
+		if question == nil {
+			return nil, err
+		}
+		answer := event.Answer
+		b := batch{actor: &answer.UserID}
+		if question.UserID != nil {
+			b.add(Notification{
+				UserID:     *question.UserID,
+				Type:       TypeNewAnswer,
+				QuestionID: question.ID,
+				AnswerID:   &answer.ID,
+				Key:        fmt.Sprintf("answer:%d", answer.ID),
+			})
+		}
+		b.mention(answer.Text, question.ID, &answer.ID, fmt.Sprintf("mention:answer:%d", answer.ID))
+		return b.notifications, nil
+
+	case event.Type == events.TypeAnswerUpdated && event.Answer != nil:
+		answer := event.Answer
+		b := batch{actor: &answer.UserID}
+		b.mention(answer.Text, event.QuestionID, &answer.ID, fmt.Sprintf("mention:answer:%d", answer.ID))
+		return b.notifications, nil
+
+	case event.Type == events.TypeAnswerAccepted && event.Answer != nil:
+		// Принять ответ может только автор вопроса, поэтому он и есть тот, кто принял ответ
+		question, err := n.question(event.QuestionID)
+		if question == nil {
+			return nil, err
+		}
+		answer := event.Answer
+		b := batch{actor: question.UserID}
+		b.add(Notification{
+			UserID:     answer.UserID,
+			Type:       TypeAnswerAccepted,
+			QuestionID: question.ID,
+			AnswerID:   &answer.ID,
+			Key:        fmt.Sprintf("accepted:%d", answer.ID),
+		})
+		return b.notifications, nil
+	}
+	return nil, nil
+}
+
+// question читает вопрос события. Для удаленного вопроса возвращает nil без ошибки.
+func (n *Notifier) question(id uint) (*models.Question, error) {
+	question, err := n.questions.GetQuestion(id, query.Projection{})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		n.logger.Debugf("Question %d no longer exists, skipping notifications", id)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question %d: %w", id, err)
+	}
+	return question, nil
+}
+
+// batch собирает уведомления об одном событии: пользователь получает о событии не больше одного
+// уведомления и не получает уведомлений о собственных действиях.
+type batch struct {
+	// actor - пользователь, вызвавший событие; nil, если он неизвестен.
+	actor         *uuid.UUID
+	notifications []Notification
+}
+
+func (b *batch) add(notification Notification) {
+	if b.actor != nil && notification.UserID == *b.actor {
+		return
+	}
+	for _, existing := range b.notifications {
+		if existing.UserID == notification.UserID {
+			return
+		}
+	}
+	notification.ActorID = b.actor
+	b.notifications = append(b.notifications, notification)
+}
+
+// mention добавляет уведомления пользователям, упомянутым в text.
+func (b *batch) mention(text string, questionID uint, answerID *uint, key string) {
+	for _, userID := range Mentions(text) {
+		b.add(Notification{UserID: userID, Type: TypeMention, QuestionID: questionID, AnswerID: answerID, Key: key})
+	}
+}
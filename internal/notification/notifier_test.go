@@ -0,0 +1,133 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+)
+
+// testQuestions - вопросы по ID; для отсутствующего вопроса возвращается err или gorm.ErrRecordNotFound.
+type testQuestions struct {
+	questions map[uint]*models.Question
+	err       error
+}
+
+func (q testQuestions) GetQuestion(id uint, _ query.Projection) (*models.Question, error) {
+	if question, ok := q.questions[id]; ok {
+		return question, nil
+	}
+	if q.err != nil {
+		return nil, q.err
+	}
+	return &models.Question{}, gorm.ErrRecordNotFound
+}
+
+func TestNotifierAnswerCreated(t *testing.T) {
+	store := NewMemoryStore()
+	asker, answerer, mentioned := uuid.New(), uuid.New(), uuid.New()
+	questions := testQuestions{questions: map[uint]*models.Question{1: {ID: 1, UserID: &asker}}}
+	n := NewNotifier(store, questions, logrus.New(), 0)
+
+	// Упоминание автора вопроса и самого себя не создает лишних уведомлений
+	answer := &models.Answer{ID: 5, QuestionID: 1, UserID: answerer,
+		Text: "@" + mentioned.String() + " @" + asker.String() + " @" + answerer.String()}
+	event := events.Event{Type: events.TypeAnswerCreated, QuestionID: 1, AnswerID: 5, Answer: answer}
+	assert.NoError(t, n.Send(event))
+	// Повторная доставка события из outbox ничего не меняет
+	assert.NoError(t, n.Send(event))
+	n.Close()
+
+	forAsker, err := store.List(asker, false, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, forAsker, 1) {
+		assert.Equal(t, TypeNewAnswer, forAsker[0].Type)
+		assert.Equal(t, uint(1), forAsker[0].QuestionID)
+		assert.Equal(t, &answer.ID, forAsker[0].AnswerID)
+		assert.Equal(t, &answerer, forAsker[0].ActorID)
+	}
+	forMentioned, err := store.List(mentioned, false, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, forMentioned, 1) {
+		assert.Equal(t, TypeMention, forMentioned[0].Type)
+	}
+	forAnswerer, err := store.List(answerer, false, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, forAnswerer)
+}
+
+func TestNotifierAnswerAccepted(t *testing.T) {
+	store := NewMemoryStore()
+	asker, answerer := uuid.New(), uuid.New()
+	questions := testQuestions{questions: map[uint]*models.Question{1: {ID: 1, UserID: &asker}}}
+	n := NewNotifier(store, questions, logrus.New(), 0)
+
+	n.Publish(events.Event{Type: events.TypeAnswerAccepted, QuestionID: 1, AnswerID: 5,
+		Answer: &models.Answer{ID: 5, QuestionID: 1, UserID: answerer}})
+	// Автор, принявший собственный ответ, уведомление не получает
+	n.Publish(events.Event{Type: events.TypeAnswerAccepted, QuestionID: 1, AnswerID: 6,
+		Answer: &models.Answer{ID: 6, QuestionID: 1, UserID: asker}})
+	n.Close()
+	// После остановки события не принимаются
+	n.Publish(events.Event{Type: events.TypeAnswerAccepted, QuestionID: 1, AnswerID: 7,
+		Answer: &models.Answer{ID: 7, QuestionID: 1, UserID: answerer}})
+
+	list, err := store.List(answerer, false, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, list, 1) {
+		assert.Equal(t, TypeAnswerAccepted, list[0].Type)
+		assert.Equal(t, &asker, list[0].ActorID)
+	}
+	list, err = store.List(asker, false, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestNotifierMentions(t *testing.T) {
+	store := NewMemoryStore()
+	n := NewNotifier(store, testQuestions{}, logrus.New(), 0)
+	defer n.Close()
+	mentioned := uuid.New()
+
+	// Анонимный вопрос: автор упоминания неизвестен
+	assert.NoError(t, n.Send(events.Event{Type: events.TypeQuestionCreated, QuestionID: 2,
+		Question: &models.Question{ID: 2, Body: "Any ideas, @" + mentioned.String() + "?"}}))
+	// Изменение ответа уведомляет только о новом упоминании
+	answer := &models.Answer{ID: 5, QuestionID: 1, UserID: uuid.New(), Text: "cc @" + mentioned.String()}
+	update := events.Event{Type: events.TypeAnswerUpdated, QuestionID: 1, AnswerID: 5, Answer: answer}
+	assert.NoError(t, n.Send(update))
+	assert.NoError(t, n.Send(update))
+
+	list, err := store.List(mentioned, false, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, &answer.ID, list[0].AnswerID)
+		assert.Equal(t, &answer.UserID, list[0].ActorID)
+		assert.Equal(t, uint(2), list[1].QuestionID)
+		assert.Nil(t, list[1].AnswerID)
+		assert.Nil(t, list[1].ActorID)
+	}
+}
+
+func TestNotifierQuestionLookup(t *testing.T) {
+	store := NewMemoryStore()
+	answer := &models.Answer{ID: 5, QuestionID: 1, UserID: uuid.New()}
+	event := events.Event{Type: events.TypeAnswerCreated, QuestionID: 1, AnswerID: 5, Answer: answer}
+
+	// Удаленный вопрос: уведомлять не о ком, повтор не нужен
+	n := NewNotifier(store, testQuestions{}, logrus.New(), 0)
+	defer n.Close()
+	assert.NoError(t, n.Send(event))
+
+	// Ошибка чтения вопроса возвращается, чтобы outbox повторил событие
+	failing := NewNotifier(store, testQuestions{err: errors.New("connection reset")}, logrus.New(), 0)
+	defer failing.Close()
+	assert.ErrorContains(t, failing.Send(event), "connection reset")
+}
@@ -0,0 +1,59 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dbStore хранит уведомления в таблице notifications.
+type dbStore struct {
+	db *gorm.DB
+}
+
+// NewStore создает хранилище уведомлений в PostgreSQL.
+func NewStore(db *gorm.DB) Store {
+	return &dbStore{db: db}
+}
+
+// Create пропускает уведомления, ключ которых уже есть у пользователя, с помощью уникального индекса:
+// так повторная доставка события из outbox ничего не меняет.
+func (s *dbStore) Create(notifications []Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&notifications).Error
+}
+
+func (s *dbStore) List(userID uuid.UUID, unreadOnly bool, limit int) ([]Notification, error) {
+	query := s.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	var notifications []Notification
+	err := query.Order("id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (s *dbStore) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := s.db.Model(&Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (s *dbStore) MarkRead(userID uuid.UUID, id uint, now time.Time) error {
+	result := s.db.Model(&Notification{}).Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", now))
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return result.Error
+}
+
+func (s *dbStore) MarkAllRead(userID uuid.UUID, now time.Time) (int64, error) {
+	result := s.db.Model(&Notification{}).Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", now)
+	return result.RowsAffected, result.Error
+}
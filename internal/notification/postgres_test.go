@@ -0,0 +1,96 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqldb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: sqldb,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	return gormDB, mock
+}
+
+func TestStoreCreateSkipsExisting(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	userID, answerID := uuid.New(), uint(5)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "notifications" \("user_id","type","question_id","answer_id","actor_id","key",`+
+		`"created_at","read_at"\) VALUES \(.+\) ON CONFLICT DO NOTHING RETURNING "id"`).
+		WithArgs(userID, TypeNewAnswer, 1, 5, nil, "answer:5", sqlmock.AnyArg(), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	err := store.Create([]Notification{
+		{UserID: userID, Type: TypeNewAnswer, QuestionID: 1, AnswerID: &answerID, Key: "answer:5"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Create(nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreListUnread(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	userID := uuid.New()
+
+	mock.ExpectQuery(
+		`SELECT \* FROM "notifications" WHERE user_id = \$1 AND read_at IS NULL ORDER BY id DESC LIMIT \$2`).
+		WithArgs(userID, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type"}).AddRow(3, userID, TypeMention))
+
+	notifications, err := store.List(userID, true, 20)
+	assert.NoError(t, err)
+	if assert.Len(t, notifications, 1) {
+		assert.Equal(t, uint(3), notifications[0].ID)
+		assert.Equal(t, TypeMention, notifications[0].Type)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreMarkRead(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	userID, now := uuid.New(), time.Now()
+
+	// Уведомление другого пользователя не найдется
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "notifications" SET "read_at"=COALESCE\(read_at, \$1\) WHERE id = \$2 AND user_id = \$3`).
+		WithArgs(now, 7, userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.ErrorIs(t, store.MarkRead(userID, 7, now), ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreMarkAllRead(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	userID, now := uuid.New(), time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "notifications" SET "read_at"=\$1 WHERE user_id = \$2 AND read_at IS NULL`).
+		WithArgs(now, userID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	count, err := store.MarkAllRead(userID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,95 @@
+// Package outbox доставляет события из таблицы outbox (transactional outbox).
+// Сервисный слой записывает событие в outbox в одной транзакции с изменением, поэтому событие
+// не теряется, если процесс остановится сразу после фиксации. Relay читает недоставленные сообщения
+// и передает их получателям (Sink): шине событий внутри процесса, webhook и журналу.
+// Сообщение доставляется хотя бы один раз: после ошибки получателя оно отправляется повторно только тем
+// получателям, которые его еще не приняли.
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/models"
+)
+
+// AggregateQuestion - тип агрегата для событий о вопросе и его ответах.
+const AggregateQuestion = "question"
+
+// Sink получает события из outbox. Ошибка означает, что событие нужно отправить повторно.
+type Sink interface {
+	Send(event events.Event) error
+}
+
+// SinkFunc позволяет использовать функцию как Sink.
+type SinkFunc func(event events.Event) error
+
+// Send вызывает f.
+func (f SinkFunc) Send(event events.Event) error {
+	return f(event)
+}
+
+// BusSink передает события получателям внутри процесса (events.Hub, events.Broadcaster).
+// Publish не возвращает ошибок, поэтому отправка всегда успешна.
+func BusSink(bus interface{ Publish(event events.Event) }) Sink {
+	return SinkFunc(func(event events.Event) error {
+		bus.Publish(event)
+		return nil
+	})
+}
+
+// LogSink пишет события в журнал приложения.
+func LogSink(logger *logrus.Logger) Sink {
+	return SinkFunc(func(event events.Event) error {
+		logger.Infof("Event %s for question %d (answer %d) at %s",
+			event.Type, event.QuestionID, event.AnswerID, event.Time.Format(time.RFC3339Nano))
+		return nil
+	})
+}
+
+// Store читает сообщения outbox для Relay.
+type Store interface {
+	// Claim выбирает до limit сообщений, готовых к отправке к now, в порядке ID и откладывает их
+	// на lease (FOR UPDATE SKIP LOCKED): пока сообщение отправляется, другие экземпляры сервиса его
+	// не выбирают, а если экземпляр остановится, не вызвав Complete, сообщение будет выбрано снова.
+	// Выбирается только первое недоставленное сообщение каждого агрегата, поэтому сообщения агрегата
+	// обрабатываются по порядку даже несколькими экземплярами сервиса.
+	Claim(now time.Time, limit int, lease time.Duration) ([]models.OutboxMessage, error)
+	// Complete сохраняет результат отправки: поля ProcessedAt, Attempts, LastError, AvailableAt и DeliveredSinks.
+	Complete(msg *models.OutboxMessage) error
+	// DeleteProcessed удаляет сообщения, доставленные раньше before, и возвращает их количество.
+	DeleteProcessed(before time.Time) (int64, error)
+}
+
+// NewMessage превращает событие в сообщение outbox.
+func NewMessage(event events.Event) (*models.OutboxMessage, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+	return &models.OutboxMessage{
+		AggregateType: AggregateQuestion,
+		AggregateID:   event.QuestionID,
+		EventType:     event.Type,
+		Payload:       payload,
+		AvailableAt:   event.Time,
+	}, nil
+}
+
+// Decode восстанавливает событие из сообщения outbox. Сообщения, записанные до появления
+// Event.UUID, получают идентификатор, вычисленный из ID сообщения: он тоже одинаков при каждой отправке.
+func Decode(msg *models.OutboxMessage) (events.Event, error) {
+	var event events.Event
+	if err := json.Unmarshal(msg.Payload, &event); err != nil {
+		return events.Event{}, fmt.Errorf("failed to decode outbox message %d: %w", msg.ID, err)
+	}
+	if event.UUID == uuid.Nil {
+		event.UUID = uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("outbox:%d", msg.ID)))
+	}
+	return event, nil
+}
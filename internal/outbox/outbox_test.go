@@ -0,0 +1,314 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/models"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	event := events.Event{
+		UUID: uuid.New(), Type: events.TypeAnswerCreated, QuestionID: 3, AnswerID: 5, Time: now,
+		Answer: &models.Answer{ID: 5, QuestionID: 3, Text: "Answer"},
+	}
+
+	msg, err := NewMessage(event)
+	assert.NoError(t, err)
+	assert.Equal(t, AggregateQuestion, msg.AggregateType)
+	assert.Equal(t, uint(3), msg.AggregateID)
+	assert.Equal(t, events.TypeAnswerCreated, msg.EventType)
+	assert.Equal(t, now, msg.AvailableAt)
+
+	decoded, err := Decode(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, event.UUID, decoded.UUID)
+	assert.Equal(t, event.Type, decoded.Type)
+	assert.Equal(t, event.AnswerID, decoded.AnswerID)
+	assert.True(t, now.Equal(decoded.Time))
+	if assert.NotNil(t, decoded.Answer) {
+		assert.Equal(t, "Answer", decoded.Answer.Text)
+	}
+}
+
+func TestDecodeMessageWithoutUUID(t *testing.T) {
+	first := &models.OutboxMessage{ID: 7, Payload: []byte(`{"Type":"answer.created","QuestionID":3}`)}
+	second := &models.OutboxMessage{ID: 8, Payload: first.Payload}
+
+	// Идентификатор вычисляется из ID сообщения и одинаков при каждой отправке
+	decoded, err := Decode(first)
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, decoded.UUID)
+	again, err := Decode(first)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded.UUID, again.UUID)
+	other, err := Decode(second)
+	assert.NoError(t, err)
+	assert.NotEqual(t, decoded.UUID, other.UUID)
+}
+
+func TestDecodeInvalidPayload(t *testing.T) {
+	_, err := Decode(&models.OutboxMessage{ID: 4, Payload: []byte("{")})
+	assert.ErrorContains(t, err, "outbox message 4")
+}
+
+// memoryStore - хранилище для тестов Relay с той же семантикой выбора, что и в PostgreSQL.
+type memoryStore struct {
+	messages    []models.OutboxMessage
+	deleted     []time.Time
+	completeErr error
+}
+
+func (s *memoryStore) add(aggregateID uint, eventType string, availableAt time.Time) {
+	msg, _ := NewMessage(events.Event{Type: eventType, QuestionID: aggregateID, Time: availableAt})
+	msg.ID = uint64(len(s.messages) + 1)
+	s.messages = append(s.messages, *msg)
+}
+
+func (s *memoryStore) Claim(now time.Time, limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	blocked := map[uint]bool{}
+	var claimed []models.OutboxMessage
+	for i := range s.messages {
+		msg := &s.messages[i]
+		if msg.ProcessedAt != nil || blocked[msg.AggregateID] {
+			continue
+		}
+		blocked[msg.AggregateID] = true
+		if msg.AvailableAt.After(now) || len(claimed) == limit {
+			continue
+		}
+		msg.AvailableAt = now.Add(lease)
+		claimed = append(claimed, *msg)
+	}
+	return claimed, nil
+}
+
+func (s *memoryStore) Complete(msg *models.OutboxMessage) error {
+	if s.completeErr != nil {
+		return s.completeErr
+	}
+	s.messages[msg.ID-1] = *msg
+	return nil
+}
+
+func (s *memoryStore) DeleteProcessed(before time.Time) (int64, error) {
+	s.deleted = append(s.deleted, before)
+	return 0, nil
+}
+
+// recordingSink запоминает полученные события и возвращает err.
+type recordingSink struct {
+	events []events.Event
+	err    error
+}
+
+func (s *recordingSink) Send(event events.Event) error {
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func TestRelayDeliversInOrderPerAggregate(t *testing.T) {
+	now := time.Now()
+	store := &memoryStore{}
+	store.add(1, events.TypeQuestionCreated, now)
+	store.add(2, events.TypeQuestionCreated, now)
+	store.add(1, events.TypeAnswerCreated, now)
+	sink := &recordingSink{}
+	relay := NewRelay(store, map[string]Sink{"bus": sink}, Options{}, logrus.New())
+
+	// Второе событие вопроса 1 ждет, пока будет доставлено первое
+	processed, err := relay.ProcessPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	processed, err = relay.ProcessPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	if assert.Len(t, sink.events, 3) {
+		assert.Equal(t, events.TypeQuestionCreated, sink.events[0].Type)
+		assert.Equal(t, uint(1), sink.events[0].QuestionID)
+		assert.Equal(t, uint(2), sink.events[1].QuestionID)
+		assert.Equal(t, events.TypeAnswerCreated, sink.events[2].Type)
+	}
+	for _, msg := range store.messages {
+		assert.NotNil(t, msg.ProcessedAt)
+	}
+}
+
+func TestRelayRetriesWithBackoff(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := &memoryStore{}
+	store.add(1, events.TypeQuestionCreated, now)
+	store.add(1, events.TypeAnswerCreated, now)
+	failing := &recordingSink{err: errors.New("connection refused")}
+	working := &recordingSink{}
+	relay := NewRelay(store, map[string]Sink{"webhook": failing, "bus": working},
+		Options{Backoff: time.Second, MaxBackoff: 3 * time.Second}, logrus.New())
+	relay.now = func() time.Time { return now }
+
+	for attempt, delay := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		processed, err := relay.ProcessPending()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		msg := store.messages[0]
+		assert.Nil(t, msg.ProcessedAt)
+		assert.Equal(t, attempt+1, msg.Attempts)
+		assert.Equal(t, "webhook: connection refused", msg.LastError)
+		assert.Equal(t, now.Add(delay), msg.AvailableAt)
+		assert.Equal(t, models.TextArray{"bus"}, msg.DeliveredSinks)
+
+		// До срока повтора сообщение не выбирается, следующее сообщение агрегата ждет
+		processed, err = relay.ProcessPending()
+		assert.NoError(t, err)
+		assert.Zero(t, processed)
+		now = msg.AvailableAt
+	}
+
+	// Повторы отправляются только получателю, который еще не принял сообщение
+	assert.Len(t, working.events, 1)
+	assert.Len(t, failing.events, 3)
+
+	failing.err = nil
+	processed, err := relay.ProcessPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.NotNil(t, store.messages[0].ProcessedAt)
+	assert.Empty(t, store.messages[0].LastError)
+	assert.Equal(t, models.TextArray{"bus", "webhook"}, store.messages[0].DeliveredSinks)
+	assert.Nil(t, store.messages[1].ProcessedAt)
+	assert.Len(t, working.events, 1)
+	assert.Len(t, failing.events, 4)
+}
+
+func TestRelaySkipsUndecodableMessage(t *testing.T) {
+	store := &memoryStore{}
+	store.messages = append(store.messages, models.OutboxMessage{
+		ID: 1, AggregateType: AggregateQuestion, AggregateID: 1, Payload: []byte("{"), AvailableAt: time.Now(),
+	})
+	store.add(1, events.TypeAnswerCreated, time.Now())
+	sink := &recordingSink{}
+	relay := NewRelay(store, map[string]Sink{"bus": sink}, Options{}, logrus.New())
+
+	_, err := relay.ProcessPending()
+	assert.NoError(t, err)
+	_, err = relay.ProcessPending()
+	assert.NoError(t, err)
+
+	assert.NotNil(t, store.messages[0].ProcessedAt)
+	assert.NotEmpty(t, store.messages[0].LastError)
+	if assert.Len(t, sink.events, 1) {
+		assert.Equal(t, events.TypeAnswerCreated, sink.events[0].Type)
+	}
+}
+
+func TestRelayRunWakesOnPublish(t *testing.T) {
+	store := &memoryStore{}
+	store.add(1, events.TypeQuestionCreated, time.Now())
+	delivered := make(chan events.Event, 1)
+	sink := SinkFunc(func(event events.Event) error {
+		delivered <- event
+		return nil
+	})
+	relay := NewRelay(store, map[string]Sink{"bus": sink}, Options{PollInterval: time.Hour}, logrus.New())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	relay.Publish(events.Event{})
+	select {
+	case event := <-delivered:
+		assert.Equal(t, events.TypeQuestionCreated, event.Type)
+	case <-time.After(time.Second):
+		t.Error("relay was not woken by Publish")
+	}
+	cancel()
+	<-done
+}
+
+func TestRelayRunDrainsAggregate(t *testing.T) {
+	store := &memoryStore{}
+	for range 5 {
+		store.add(1, events.TypeAnswerCreated, time.Now())
+	}
+	delivered := make(chan events.Event, 5)
+	sink := SinkFunc(func(event events.Event) error {
+		delivered <- event
+		return nil
+	})
+	relay := NewRelay(store, map[string]Sink{"bus": sink}, Options{PollInterval: time.Hour}, logrus.New())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	// Все события вопроса доставляются после одного пробуждения, не дожидаясь PollInterval
+	relay.Publish(events.Event{})
+wait:
+	for i := range 5 {
+		select {
+		case <-delivered:
+		case <-time.After(time.Second):
+			t.Errorf("only %d of 5 events delivered", i)
+			break wait
+		}
+	}
+	cancel()
+	<-done
+}
+
+func TestRelayClaimsMessagesForLease(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store := &memoryStore{}
+	store.add(1, events.TypeQuestionCreated, now)
+	store.add(1, events.TypeAnswerCreated, now)
+	store.completeErr = errors.New("connection reset")
+	sink := &recordingSink{}
+	relay := NewRelay(store, map[string]Sink{"bus": sink}, Options{Lease: time.Minute}, logrus.New())
+	relay.now = func() time.Time { return now }
+
+	// Результат не сохранился: сообщение остается выбранным до конца аренды, следующее его ждет
+	processed, err := relay.ProcessPending()
+	assert.Equal(t, 1, processed)
+	assert.ErrorContains(t, err, "message 1: connection reset")
+	assert.Nil(t, store.messages[0].ProcessedAt)
+	assert.Equal(t, now.Add(time.Minute), store.messages[0].AvailableAt)
+	processed, err = relay.ProcessPending()
+	assert.NoError(t, err)
+	assert.Zero(t, processed)
+
+	// После аренды сообщение отправляется повторно
+	store.completeErr = nil
+	now = now.Add(time.Minute)
+	processed, err = relay.ProcessPending()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.NotNil(t, store.messages[0].ProcessedAt)
+	if assert.Len(t, sink.events, 2) {
+		assert.Equal(t, sink.events[0].UUID, sink.events[1].UUID)
+	}
+}
+
+func TestRelayCleanup(t *testing.T) {
+	now := time.Now()
+	store := &memoryStore{}
+	relay := NewRelay(store, nil, Options{Retention: time.Hour}, logrus.New())
+	relay.now = func() time.Time { return now }
+
+	relay.cleanup()
+	assert.Equal(t, []time.Time{now.Add(-time.Hour)}, store.deleted)
+}
@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/models"
+)
+
+// dbStore читает сообщения из таблицы outbox.
+type dbStore struct {
+	db *gorm.DB
+}
+
+// NewStore создает хранилище сообщений outbox в PostgreSQL.
+func NewStore(db *gorm.DB) Store {
+	return &dbStore{db: db}
+}
+
+// Claim выбирает первые недоставленные сообщения агрегатов и одним запросом переносит их available_at
+// на now+lease. Сообщение, перед которым в агрегате есть недоставленное (в том числе выбранное другим
+// экземпляром или отложенное после ошибки), ждет его. Блокировки строк снимаются сразу после запроса.
+func (s *dbStore) Claim(now time.Time, limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	err := s.db.Raw(`UPDATE outbox SET available_at = ?
+WHERE id IN (
+	SELECT o.id FROM outbox o
+	WHERE o.processed_at IS NULL AND o.available_at <= ?
+		AND NOT EXISTS (
+			SELECT 1 FROM outbox p
+			WHERE p.aggregate_type = o.aggregate_type AND p.aggregate_id = o.aggregate_id
+				AND p.processed_at IS NULL AND p.id < o.id
+		)
+	ORDER BY o.id
+	LIMIT ?
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING *`, now.Add(lease), now, limit).Scan(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING не сохраняет порядок подзапроса
+	slices.SortFunc(messages, func(a, b models.OutboxMessage) int { return cmp.Compare(a.ID, b.ID) })
+	return messages, nil
+}
+
+func (s *dbStore) Complete(msg *models.OutboxMessage) error {
+	return s.db.Model(msg).
+		Select("processed_at", "attempts", "last_error", "available_at", "delivered_sinks").
+		Updates(msg).Error
+}
+
+func (s *dbStore) DeleteProcessed(before time.Time) (int64, error) {
+	result := s.db.Where("processed_at < ?", before).Delete(&models.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
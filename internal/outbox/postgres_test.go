@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/models"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqldb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: sqldb,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	return gormDB, mock
+}
+
+func TestStoreClaim(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	now := time.Now()
+
+	// Сообщения выбираются и откладываются одним запросом, без транзакции на время отправки
+	mock.ExpectQuery(`UPDATE outbox SET available_at = \$1\s+WHERE id IN \(\s*SELECT o.id FROM outbox o\s+`+
+		`WHERE o.processed_at IS NULL AND o.available_at <= \$2\s+AND NOT EXISTS \(.+\)\s+ORDER BY o.id\s+`+
+		`LIMIT \$3\s+FOR UPDATE SKIP LOCKED\s*\)\s*RETURNING \*`).
+		WithArgs(now.Add(time.Minute), now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "aggregate_type", "aggregate_id", "event_type", "payload"}).
+			AddRow(9, AggregateQuestion, 4, events.TypeQuestionCreated, []byte(`{}`)).
+			AddRow(7, AggregateQuestion, 3, events.TypeAnswerCreated, []byte(`{}`)))
+
+	messages, err := store.Claim(now, 10, time.Minute)
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 2) {
+		assert.Equal(t, uint64(7), messages[0].ID)
+		assert.Equal(t, uint64(9), messages[1].ID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreComplete(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "outbox" SET "available_at"=\$1,"processed_at"=\$2,"attempts"=\$3,"last_error"=\$4,`+
+		`"delivered_sinks"=\$5 WHERE "id" = \$6`).
+		WithArgs(sqlmock.AnyArg(), now, 0, "", `{"bus","webhook"}`, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := store.Complete(&models.OutboxMessage{
+		ID: 7, ProcessedAt: &now, AvailableAt: now, DeliveredSinks: models.TextArray{"bus", "webhook"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreDeleteProcessed(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	before := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "outbox" WHERE processed_at < \$1`).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	deleted, err := store.DeleteProcessed(before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,216 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/models"
+)
+
+// Значения Options по умолчанию.
+const (
+	DefaultPollInterval    = time.Second
+	DefaultBatchSize       = 100
+	DefaultLease           = time.Minute
+	DefaultBackoff         = time.Second
+	DefaultMaxBackoff      = 5 * time.Minute
+	DefaultRetention       = 7 * 24 * time.Hour
+	DefaultCleanupInterval = time.Hour
+)
+
+// Options - параметры Relay. Нулевые значения заменяются значениями по умолчанию.
+type Options struct {
+	// PollInterval - как часто проверять outbox, если Relay не разбудили раньше.
+	PollInterval time.Duration
+	// BatchSize - сколько сообщений выбирается за один запрос.
+	BatchSize int
+	// Lease - на сколько выбранное сообщение закрепляется за экземпляром. Должно превышать время отправки
+	// пачки получателям: иначе сообщение выберет и отправит повторно другой экземпляр.
+	Lease time.Duration
+	// Backoff - задержка после первой неудачной попытки; после каждой следующей она удваивается
+	// до MaxBackoff. Попытки не прекращаются: следующие сообщения агрегата ждут, пока это не будет доставлено.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Retention - сколько хранятся доставленные сообщения, CleanupInterval - как часто они удаляются.
+	Retention       time.Duration
+	CleanupInterval time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultPollInterval
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultBatchSize
+	}
+	if o.Lease <= 0 {
+		o.Lease = DefaultLease
+	}
+	if o.Backoff <= 0 {
+		o.Backoff = DefaultBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultMaxBackoff
+	}
+	if o.Retention <= 0 {
+		o.Retention = DefaultRetention
+	}
+	if o.CleanupInterval <= 0 {
+		o.CleanupInterval = DefaultCleanupInterval
+	}
+	return o
+}
+
+// namedSink - получатель с именем для сообщений об ошибках.
+type namedSink struct {
+	name string
+	sink Sink
+}
+
+// Relay передает сообщения outbox получателям.
+type Relay struct {
+	store  Store
+	sinks  []namedSink
+	opts   Options
+	logger *logrus.Logger
+	wake   chan struct{}
+	now    func() time.Time
+}
+
+// NewRelay создает Relay. sinks - получатели по именам; каждое сообщение передается всем.
+func NewRelay(store Store, sinks map[string]Sink, opts Options, logger *logrus.Logger) *Relay {
+	r := &Relay{
+		store:  store,
+		opts:   opts.withDefaults(),
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+	}
+	for name, sink := range sinks {
+		r.sinks = append(r.sinks, namedSink{name: name, sink: sink})
+	}
+	slices.SortFunc(r.sinks, func(a, b namedSink) int { return strings.Compare(a.name, b.name) })
+	return r
+}
+
+// Publish будит Relay, чтобы событие, только что записанное в outbox, было доставлено без ожидания
+// PollInterval. Само событие не используется: оно будет прочитано из outbox. Не блокируется,
+// поэтому Relay можно передать сервису как service.Publisher.
+func (r *Relay) Publish(events.Event) {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run доставляет сообщения и удаляет доставленные сообщения старше Retention, пока ctx не будет отменен.
+func (r *Relay) Run(ctx context.Context) {
+	poll := time.NewTicker(r.opts.PollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(r.opts.CleanupInterval)
+	defer cleanup.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cleanup.C:
+			r.cleanup()
+			continue
+		case <-poll.C:
+		case <-r.wake:
+		}
+		// За один раз выбирается только первое сообщение каждого агрегата, поэтому outbox читается,
+		// пока находятся сообщения: иначе события одного вопроса доставлялись бы по одному за PollInterval
+		for ctx.Err() == nil {
+			processed, err := r.ProcessPending()
+			if err != nil {
+				r.logger.Errorf("Failed to process outbox messages: %v", err)
+			}
+			if processed == 0 {
+				break
+			}
+		}
+	}
+}
+
+// ProcessPending доставляет одну пачку сообщений и возвращает количество выбранных сообщений.
+// Получатели вызываются вне транзакции, выбравшей сообщения, поэтому медленный получатель
+// не держит блокировки строк outbox.
+func (r *Relay) ProcessPending() (int, error) {
+	started := r.now()
+	messages, err := r.store.Claim(started, r.opts.BatchSize, r.opts.Lease)
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	for i := range messages {
+		r.handle(&messages[i])
+		if err := r.store.Complete(&messages[i]); err != nil {
+			errs = append(errs, fmt.Errorf("message %d: %w", messages[i].ID, err))
+		}
+	}
+	if elapsed := r.now().Sub(started); elapsed > r.opts.Lease {
+		r.logger.Warnf("Delivering %d outbox messages took %s, longer than the %s lease: "+
+			"they may be delivered again", len(messages), elapsed, r.opts.Lease)
+	}
+	return len(messages), errors.Join(errs...)
+}
+
+// handle передает сообщение получателям, которые его еще не приняли, и отмечает результат в сообщении.
+func (r *Relay) handle(msg *models.OutboxMessage) {
+	now := r.now()
+	event, err := Decode(msg)
+	if err != nil {
+		// Повтор не поможет, а ожидание остановило бы все следующие события агрегата
+		r.logger.Errorf("Skipping outbox message: %v", err)
+		msg.ProcessedAt, msg.LastError = &now, err.Error()
+		return
+	}
+
+	var errs []error
+	for _, s := range r.sinks {
+		if slices.Contains(msg.DeliveredSinks, s.name) {
+			continue
+		}
+		if err := s.sink.Send(event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		msg.DeliveredSinks = append(msg.DeliveredSinks, s.name)
+	}
+	if len(errs) == 0 {
+		msg.ProcessedAt, msg.LastError = &now, ""
+		return
+	}
+	msg.Attempts++
+	msg.LastError = errors.Join(errs...).Error()
+	msg.AvailableAt = now.Add(r.backoff(msg.Attempts))
+	r.logger.Warnf("Failed to deliver outbox message %d (%s, attempt %d), retrying at %s: %s",
+		msg.ID, msg.EventType, msg.Attempts, msg.AvailableAt.Format(time.RFC3339), msg.LastError)
+}
+
+// backoff возвращает задержку после attempts неудачных попыток.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.opts.Backoff
+	for i := 1; i < attempts && delay < r.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.opts.MaxBackoff)
+}
+
+// cleanup удаляет доставленные сообщения старше Retention.
+func (r *Relay) cleanup() {
+	deleted, err := r.store.DeleteProcessed(r.now().Add(-r.opts.Retention))
+	if err != nil {
+		r.logger.Errorf("Failed to delete processed outbox messages: %v", err)
+		return
+	}
+	r.logger.Debugf("Deleted %d processed outbox messages", deleted)
+}
@@ -0,0 +1,191 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/shenikar/question-service/internal/models"
+)
+
+// Параметры строки запроса, из которых строится QuestionSpec
+const (
+	ParamStatus        = "status"
+	ParamCreatedAfter  = "created_after"
+	ParamCreatedBefore = "created_before"
+	ParamAuthor        = "author"
+	ParamHasAnswers    = "has_answers"
+	ParamMinAnswers    = "min_answers"
+	ParamMaxAnswers    = "max_answers"
+	ParamText          = "q"
+	ParamSort          = "sort"
+	ParamTag           = "tag"
+)
+
+// maxTextLength - максимальная длина строки полнотекстового фильтра.
+const maxTextLength = 200
+
+// ParamError описывает ошибку в одном параметре запроса.
+type ParamError struct {
+	Param   string
+	Message string
+}
+
+// Error - ошибка разбора параметров запроса. Содержит все некорректные параметры сразу,
+// чтобы клиент мог исправить их за один раз.
+type Error struct {
+	Params []ParamError
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Params))
+	for _, p := range e.Params {
+		msgs = append(msgs, p.Param+": "+p.Message)
+	}
+	return "invalid query parameters: " + strings.Join(msgs, "; ")
+}
+
+// parser накапливает ошибки разбора отдельных параметров.
+type parser struct {
+	values url.Values
+	errs   []ParamError
+}
+
+func (p *parser) fail(param, format string, args ...any) {
+	p.errs = append(p.errs, ParamError{Param: param, Message: fmt.Sprintf(format, args...)})
+}
+
+// ParseQuestionSpec строит спецификацию списка вопросов из параметров строки запроса.
+// Даты принимаются в формате RFC 3339 или YYYY-MM-DD. При ошибках возвращается *Error.
+func ParseQuestionSpec(values url.Values) (QuestionSpec, error) {
+	p := &parser{values: values}
+	spec := QuestionSpec{
+		Statuses:      p.list(ParamStatus, models.QuestionStatuses),
+		CreatedAfter:  p.time(ParamCreatedAfter),
+		CreatedBefore: p.time(ParamCreatedBefore),
+		AuthorID:      p.uuid(ParamAuthor),
+		HasAnswers:    p.bool(ParamHasAnswers),
+		MinAnswers:    p.count(ParamMinAnswers),
+		MaxAnswers:    p.count(ParamMaxAnswers),
+		Text:          strings.TrimSpace(values.Get(ParamText)),
+		Tags:          p.tags(ParamTag),
+		Sort:          SortNewest,
+	}
+
+	if sorts := p.list(ParamSort, QuestionSorts); len(sorts) > 1 {
+		p.fail(ParamSort, "only one sort order is allowed")
+	} else if len(sorts) == 1 {
+		spec.Sort = sorts[0]
+	}
+	if len([]rune(spec.Text)) > maxTextLength {
+		p.fail(ParamText, "must be at most %d characters", maxTextLength)
+	}
+	if spec.CreatedAfter != nil && spec.CreatedBefore != nil && !spec.CreatedAfter.Before(*spec.CreatedBefore) {
+		p.fail(ParamCreatedBefore, "must be later than %s", ParamCreatedAfter)
+	}
+	if spec.MinAnswers != nil && spec.MaxAnswers != nil && *spec.MinAnswers > *spec.MaxAnswers {
+		p.fail(ParamMaxAnswers, "must not be less than %s", ParamMinAnswers)
+	}
+
+	if len(p.errs) > 0 {
+		return QuestionSpec{}, &Error{Params: p.errs}
+	}
+	return spec, nil
+}
+
+// list разбирает значения, переданные через запятую или повторением параметра.
+func (p *parser) list(param string, allowed []string) []string {
+	var result []string
+	for _, value := range p.values[param] {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			if !contains(allowed, item) {
+				p.fail(param, "unknown value %q, expected one of: %s", item, strings.Join(allowed, ", "))
+				continue
+			}
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// tags разбирает теги, переданные через запятую или повторением параметра, и нормализует их.
+func (p *parser) tags(param string) []string {
+	var result []string
+	for _, value := range p.values[param] {
+		for _, item := range strings.Split(value, ",") {
+			if strings.TrimSpace(item) == "" {
+				continue
+			}
+			tag, ok := models.NormalizeTag(item)
+			if !ok {
+				p.fail(param, "invalid tag %q", item)
+				continue
+			}
+			if !contains(result, tag) {
+				result = append(result, tag)
+			}
+		}
+	}
+	return result
+}
+
+func (p *parser) time(param string) *time.Time {
+	value := p.values.Get(param)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	p.fail(param, "invalid date %q, expected RFC 3339 or YYYY-MM-DD", value)
+	return nil
+}
+
+func (p *parser) uuid(param string) *uuid.UUID {
+	value := p.values.Get(param)
+	if value == "" {
+		return nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		p.fail(param, "invalid UUID %q", value)
+		return nil
+	}
+	return &id
+}
+
+func (p *parser) bool(param string) *bool {
+	value := p.values.Get(param)
+	if value == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		p.fail(param, "invalid boolean %q", value)
+		return nil
+	}
+	return &b
+}
+
+func (p *parser) count(param string) *int64 {
+	value := p.values.Get(param)
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		p.fail(param, "invalid count %q, expected a non-negative integer", value)
+		return nil
+	}
+	return &n
+}
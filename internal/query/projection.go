@@ -0,0 +1,107 @@
+package query
+
+import "net/url"
+
+// Параметры строки запроса, из которых строится Projection
+const (
+	ParamFields  = "fields"
+	ParamInclude = "include"
+)
+
+// Связанные данные, которые можно встроить в представление ресурса
+const (
+	IncludeAnswers  = "answers"
+	IncludeComments = "comments"
+	IncludeAuthor   = "author"
+)
+
+// Resource описывает поля и связанные данные ресурса, которые клиент может запросить.
+// Имена полей совпадают с именами полей JSON в ответах API.
+type Resource struct {
+	Fields         []string
+	Includes       []string
+	DefaultInclude []string
+}
+
+var (
+	// QuestionResource - поля вопроса. По умолчанию вопрос отдается вместе с ответами.
+	QuestionResource = Resource{
+		Fields: []string{
+			"id", "user_id", "title", "body", "body_html", "tags", "created_at", "updated_at", "version",
+			"last_activity_at", "score", "status", "close_reason", "closed_by", "closed_at", "duplicate_of",
+			"accepted_answer_id", "answer_count", "comment_count",
+		},
+		Includes:       []string{IncludeAnswers, IncludeComments, IncludeAuthor},
+		DefaultInclude: []string{IncludeAnswers},
+	}
+	// AnswerResource - поля ответа.
+	AnswerResource = Resource{
+		Fields: []string{
+			"id", "question_id", "user_id", "text", "text_html", "created_at", "updated_at", "version", "comment_count",
+		},
+		Includes: []string{IncludeComments, IncludeAuthor},
+	}
+)
+
+// Projection описывает, какие поля ресурса и какие связанные данные нужны клиенту.
+// Пустой Fields означает все поля. Fields относится только к самому ресурсу:
+// встроенные ответы и комментарии отдаются целиком.
+type Projection struct {
+	Fields  []string
+	Include []string
+}
+
+// HasField сообщает, запрошено ли поле.
+func (p Projection) HasField(field string) bool {
+	return len(p.Fields) == 0 || contains(p.Fields, field)
+}
+
+// Includes сообщает, нужно ли встроить связанные данные.
+func (p Projection) Includes(include string) bool {
+	return contains(p.Include, include)
+}
+
+// ParseProjection строит Projection для ресурса из параметров fields и include.
+// Если include не передан, используются res.DefaultInclude; пустой include отключает встраивание.
+// При ошибках возвращается *Error.
+func ParseProjection(values url.Values, res Resource) (Projection, error) {
+	p := &parser{values: values}
+	proj := Projection{
+		Fields:  p.list(ParamFields, res.Fields),
+		Include: p.list(ParamInclude, res.Includes),
+	}
+	if _, ok := values[ParamInclude]; !ok {
+		proj.Include = res.DefaultInclude
+	}
+	if _, ok := values[ParamFields]; ok && len(proj.Fields) == 0 && len(p.errs) == 0 {
+		p.fail(ParamFields, "at least one field is required")
+	}
+
+	if len(p.errs) > 0 {
+		return Projection{}, &Error{Params: p.errs}
+	}
+	return proj, nil
+}
+
+// Join объединяет ошибки разбора разных групп параметров в одну *Error.
+// Ошибки другого типа возвращаются как есть.
+func Join(errs ...error) error {
+	var joined *Error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		queryErr, ok := err.(*Error)
+		if !ok {
+			return err
+		}
+		if joined == nil {
+			joined = &Error{}
+		}
+		joined.Params = append(joined.Params, queryErr.Params...)
+	}
+	if joined == nil {
+		return nil
+	}
+	return joined
+}
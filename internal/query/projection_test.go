@@ -0,0 +1,68 @@
+package query
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProjection(t *testing.T) {
+	proj, err := ParseProjection(url.Values{
+		"fields":  {"id,title", "status"},
+		"include": {"comments,author"},
+	}, QuestionResource)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id", "title", "status"}, proj.Fields)
+	assert.True(t, proj.HasField("title"))
+	assert.False(t, proj.HasField("body"))
+	assert.True(t, proj.Includes(IncludeComments))
+	assert.False(t, proj.Includes(IncludeAnswers))
+}
+
+func TestParseProjectionDefaults(t *testing.T) {
+	proj, err := ParseProjection(url.Values{}, QuestionResource)
+	assert.NoError(t, err)
+	assert.True(t, proj.HasField("body"))
+	assert.Equal(t, []string{IncludeAnswers}, proj.Include)
+
+	// Пустой include отключает встраивание по умолчанию
+	proj, err = ParseProjection(url.Values{"include": {""}}, QuestionResource)
+	assert.NoError(t, err)
+	assert.Empty(t, proj.Include)
+
+	proj, err = ParseProjection(url.Values{}, AnswerResource)
+	assert.NoError(t, err)
+	assert.Equal(t, Projection{}, proj)
+}
+
+func TestParseProjectionErrors(t *testing.T) {
+	_, err := ParseProjection(url.Values{
+		"fields":  {"id,password"},
+		"include": {"answers"},
+	}, AnswerResource)
+	var queryErr *Error
+	assert.True(t, errors.As(err, &queryErr))
+	assert.Len(t, queryErr.Params, 2)
+	assert.Equal(t, ParamFields, queryErr.Params[0].Param)
+	assert.Equal(t, ParamInclude, queryErr.Params[1].Param)
+
+	_, err = ParseProjection(url.Values{"fields": {""}}, QuestionResource)
+	assert.True(t, errors.As(err, &queryErr))
+	assert.Equal(t, ParamFields, queryErr.Params[0].Param)
+}
+
+func TestJoin(t *testing.T) {
+	assert.NoError(t, Join(nil, nil))
+
+	first := &Error{Params: []ParamError{{Param: ParamSort, Message: "bad"}}}
+	second := &Error{Params: []ParamError{{Param: ParamFields, Message: "bad"}}}
+	var queryErr *Error
+	assert.True(t, errors.As(Join(first, nil, second), &queryErr))
+	assert.Equal(t, ParamSort, queryErr.Params[0].Param)
+	assert.Equal(t, ParamFields, queryErr.Params[1].Param)
+
+	other := errors.New("boom")
+	assert.Equal(t, other, Join(first, other))
+}
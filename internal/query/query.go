@@ -0,0 +1,120 @@
+// Package query описывает параметры выборки списков, общие для всех хранилищ.
+package query
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/shenikar/question-service/internal/models"
+)
+
+// Порядок сортировки списка вопросов
+const (
+	SortNewest       = "newest"
+	SortOldest       = "oldest"
+	SortMostAnswered = "answers"
+	SortActive       = "activity"
+	SortVotes        = "votes"
+)
+
+// QuestionSorts - все допустимые порядки сортировки вопросов
+var QuestionSorts = []string{SortNewest, SortOldest, SortMostAnswered, SortActive, SortVotes}
+
+// QuestionSpec описывает фильтры и сортировку списка вопросов.
+// Пустое значение поля означает отсутствие фильтра, пустой Sort - SortNewest.
+// CreatedAfter включает границу, CreatedBefore - нет. Tags - нормализованные теги, вопрос должен иметь их все.
+// Limit - максимальное число вопросов, 0 - без ограничения; из строки запроса не разбирается.
+type QuestionSpec struct {
+	Statuses      []string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	AuthorID      *uuid.UUID
+	HasAnswers    *bool
+	MinAnswers    *int64
+	MaxAnswers    *int64
+	Text          string
+	Tags          []string
+	Sort          string
+	Limit         int
+}
+
+// Matches проверяет, удовлетворяет ли вопрос фильтрам спецификации.
+// Используется хранилищами, которые не умеют строить SQL-запросы;
+// AnswerCount вопроса должен быть уже заполнен.
+func (s QuestionSpec) Matches(q *models.Question) bool {
+	switch {
+	case len(s.Statuses) > 0 && !contains(s.Statuses, q.Status):
+		return false
+	case s.CreatedAfter != nil && q.CreatedAt.Before(*s.CreatedAfter):
+		return false
+	case s.CreatedBefore != nil && !q.CreatedAt.Before(*s.CreatedBefore):
+		return false
+	case s.AuthorID != nil && (q.UserID == nil || *q.UserID != *s.AuthorID):
+		return false
+	case s.HasAnswers != nil && *s.HasAnswers != (q.AnswerCount > 0):
+		return false
+	case s.MinAnswers != nil && q.AnswerCount < *s.MinAnswers:
+		return false
+	case s.MaxAnswers != nil && q.AnswerCount > *s.MaxAnswers:
+		return false
+	case s.Text != "" && !containsFold(q.Title, s.Text) && !containsFold(q.Body, s.Text):
+		return false
+	case !containsAll(q.Tags, s.Tags):
+		return false
+	}
+	return true
+}
+
+// Less сообщает, должен ли вопрос a стоять в списке раньше b.
+// При равенстве ключа сортировки более новые вопросы идут первыми (для SortOldest - наоборот).
+func (s QuestionSpec) Less(a, b *models.Question) bool {
+	switch s.Sort {
+	case SortOldest:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	case SortMostAnswered:
+		if a.AnswerCount != b.AnswerCount {
+			return a.AnswerCount > b.AnswerCount
+		}
+	case SortActive:
+		if !a.LastActivityAt.Equal(b.LastActivityAt) {
+			return a.LastActivityAt.After(b.LastActivityAt)
+		}
+	case SortVotes:
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+	default:
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+	}
+	return a.ID > b.ID
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(values, required []string) bool {
+	for _, v := range required {
+		if !contains(values, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold проверяет вхождение substr в s без учета регистра, как ILIKE в PostgreSQL.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
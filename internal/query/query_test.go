@@ -0,0 +1,157 @@
+package query
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/models"
+)
+
+func TestParseQuestionSpec(t *testing.T) {
+	author := uuid.New()
+	values := url.Values{
+		"status":         {"open,closed"},
+		"created_after":  {"2026-01-01"},
+		"created_before": {"2026-02-01T12:00:00Z"},
+		"author":         {author.String()},
+		"has_answers":    {"true"},
+		"min_answers":    {"1"},
+		"max_answers":    {"10"},
+		"q":              {"  goroutine  "},
+		"sort":           {"activity"},
+	}
+
+	spec, err := ParseQuestionSpec(values)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{models.QuestionStatusOpen, models.QuestionStatusClosed}, spec.Statuses)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), *spec.CreatedAfter)
+	assert.Equal(t, time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC), *spec.CreatedBefore)
+	assert.Equal(t, author, *spec.AuthorID)
+	assert.True(t, *spec.HasAnswers)
+	assert.Equal(t, int64(1), *spec.MinAnswers)
+	assert.Equal(t, int64(10), *spec.MaxAnswers)
+	assert.Equal(t, "goroutine", spec.Text)
+	assert.Equal(t, SortActive, spec.Sort)
+}
+
+func TestParseQuestionSpecDefaults(t *testing.T) {
+	spec, err := ParseQuestionSpec(url.Values{})
+	assert.NoError(t, err)
+	assert.Equal(t, QuestionSpec{Sort: SortNewest}, spec)
+}
+
+func TestParseQuestionSpecCollectsAllErrors(t *testing.T) {
+	values := url.Values{
+		"status":        {"open,deleted"},
+		"created_after": {"yesterday"},
+		"author":        {"admin"},
+		"has_answers":   {"maybe"},
+		"min_answers":   {"-1"},
+		"sort":          {"newest", "votes"},
+	}
+
+	_, err := ParseQuestionSpec(values)
+	var queryErr *Error
+	assert.True(t, errors.As(err, &queryErr))
+
+	params := make([]string, 0, len(queryErr.Params))
+	for _, p := range queryErr.Params {
+		params = append(params, p.Param)
+	}
+	assert.Equal(t, []string{
+		ParamStatus, ParamCreatedAfter, ParamAuthor, ParamHasAnswers, ParamMinAnswers, ParamSort,
+	}, params)
+}
+
+func TestParseQuestionSpecTags(t *testing.T) {
+	spec, err := ParseQuestionSpec(url.Values{"tag": {"Go, linux", "go", "c++"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "linux", "c++"}, spec.Tags)
+
+	_, err = ParseQuestionSpec(url.Values{"tag": {"go,hello world"}})
+	var queryErr *Error
+	if assert.True(t, errors.As(err, &queryErr)) && assert.Len(t, queryErr.Params, 1) {
+		assert.Equal(t, ParamTag, queryErr.Params[0].Param)
+		assert.Contains(t, queryErr.Params[0].Message, "hello world")
+	}
+}
+
+func TestParseQuestionSpecInvalidRanges(t *testing.T) {
+	_, err := ParseQuestionSpec(url.Values{
+		"created_after":  {"2026-02-01"},
+		"created_before": {"2026-01-01"},
+		"min_answers":    {"5"},
+		"max_answers":    {"2"},
+	})
+	var queryErr *Error
+	assert.True(t, errors.As(err, &queryErr))
+	assert.Len(t, queryErr.Params, 2)
+	assert.Equal(t, ParamCreatedBefore, queryErr.Params[0].Param)
+	assert.Equal(t, ParamMaxAnswers, queryErr.Params[1].Param)
+}
+
+func TestQuestionSpecMatches(t *testing.T) {
+	author := uuid.New()
+	created := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	question := &models.Question{
+		UserID:      &author,
+		Title:       "Goroutine leak",
+		Body:        "How to find a **leaking** goroutine?",
+		CreatedAt:   created,
+		Status:      models.QuestionStatusOpen,
+		Tags:        models.Tags{"go", "linux"},
+		AnswerCount: 2,
+	}
+	yes, no := true, false
+	one, three := int64(1), int64(3)
+	before, after := created.Add(-time.Hour), created.Add(time.Hour)
+	other := uuid.New()
+
+	tests := []struct {
+		name    string
+		spec    QuestionSpec
+		matches bool
+	}{
+		{"empty", QuestionSpec{}, true},
+		{"status", QuestionSpec{Statuses: []string{models.QuestionStatusClosed}}, false},
+		{"created after inclusive", QuestionSpec{CreatedAfter: &created}, true},
+		{"created after", QuestionSpec{CreatedAfter: &after}, false},
+		{"created before exclusive", QuestionSpec{CreatedBefore: &created}, false},
+		{"created before", QuestionSpec{CreatedBefore: &after, CreatedAfter: &before}, true},
+		{"author", QuestionSpec{AuthorID: &author}, true},
+		{"other author", QuestionSpec{AuthorID: &other}, false},
+		{"has answers", QuestionSpec{HasAnswers: &yes}, true},
+		{"no answers", QuestionSpec{HasAnswers: &no}, false},
+		{"answer range", QuestionSpec{MinAnswers: &one, MaxAnswers: &three}, true},
+		{"too few answers", QuestionSpec{MinAnswers: &three}, false},
+		{"too many answers", QuestionSpec{MaxAnswers: &one}, false},
+		{"text in body", QuestionSpec{Text: "LEAKING"}, true},
+		{"text missing", QuestionSpec{Text: "channel"}, false},
+		{"all tags", QuestionSpec{Tags: []string{"linux", "go"}}, true},
+		{"missing tag", QuestionSpec{Tags: []string{"go", "windows"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, tt.spec.Matches(question))
+		})
+	}
+}
+
+func TestQuestionSpecLess(t *testing.T) {
+	now := time.Now()
+	older := &models.Question{ID: 1, CreatedAt: now.Add(-time.Hour), AnswerCount: 3, LastActivityAt: now, Score: 1}
+	newer := &models.Question{ID: 2, CreatedAt: now, AnswerCount: 1, LastActivityAt: now, Score: 5}
+
+	assert.True(t, QuestionSpec{Sort: SortNewest}.Less(newer, older))
+	assert.True(t, QuestionSpec{Sort: SortOldest}.Less(older, newer))
+	assert.True(t, QuestionSpec{Sort: SortMostAnswered}.Less(older, newer))
+	assert.True(t, QuestionSpec{Sort: SortVotes}.Less(newer, older))
+	// При равной активности первым идет более новый вопрос
+	assert.True(t, QuestionSpec{Sort: SortActive}.Less(newer, older))
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval - как часто из памяти удаляются заполнившиеся корзины.
+const sweepInterval = time.Minute
+
+// bucket хранит время, когда корзина станет полной. Пока оно в прошлом, корзина полна;
+// каждый взятый токен сдвигает его на интервал пополнения. Это эквивалентно хранению числа токенов
+// и времени последнего пополнения, но не требует дробных токенов.
+type bucket struct {
+	fullAt time.Time
+}
+
+// memoryStore хранит корзины в памяти процесса.
+type memoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore создает хранилище корзин в памяти процесса.
+func NewMemoryStore() Store {
+	return &memoryStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *memoryStore) Take(keys []string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	interval := limit.interval()
+	buckets := make([]*bucket, len(keys))
+	var result Result
+	denied := false
+	for i, key := range keys {
+		b, ok := s.buckets[key]
+		if !ok {
+			b = &bucket{}
+			s.buckets[key] = b
+		}
+		if b.fullAt.Before(now) {
+			b.fullAt = now
+		}
+		buckets[i] = b
+
+		// В корзине нет целого токена, если до ее заполнения осталось больше Period - interval
+		deficit := b.fullAt.Sub(now)
+		if retryAfter := deficit + interval - limit.Period; retryAfter > 0 {
+			if !denied || retryAfter > result.RetryAfter {
+				result = Result{RetryAfter: retryAfter, ResetAfter: deficit}
+			}
+			denied = true
+		}
+	}
+	if denied {
+		return result, nil
+	}
+
+	for i, b := range buckets {
+		b.fullAt = b.fullAt.Add(interval)
+		deficit := b.fullAt.Sub(now)
+		remaining := int((limit.Period - deficit) / interval)
+		if i == 0 || remaining < result.Remaining {
+			result = Result{Allowed: true, Remaining: remaining, ResetAfter: deficit}
+		}
+	}
+	return result, nil
+}
+
+// sweep удаляет заполнившиеся корзины: они неотличимы от отсутствующих.
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !b.fullAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+	now := time.Now()
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		result, err := store.Take([]string{"key"}, limit, now)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, remaining, result.Remaining)
+	}
+
+	result, err := store.Take([]string{"key"}, limit, now)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.ResetAfter)
+
+	// Через секунду появляется один токен
+	result, _ = store.Take([]string{"key"}, limit, now.Add(time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Корзина не переполняется сверх емкости
+	result, _ = store.Take([]string{"key"}, limit, now.Add(time.Hour))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+	assert.Equal(t, time.Second, result.ResetAfter)
+}
+
+func TestMemoryStoreSeparateKeys(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Period: time.Minute}
+	now := time.Now()
+
+	first, _ := store.Take([]string{"first"}, limit, now)
+	second, _ := store.Take([]string{"second"}, limit, now)
+	assert.True(t, first.Allowed)
+	assert.True(t, second.Allowed)
+
+	first, _ = store.Take([]string{"first"}, limit, now)
+	assert.False(t, first.Allowed)
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	store := NewMemoryStore().(*memoryStore)
+	limit := Limit{Requests: 1, Period: time.Second}
+	now := time.Now()
+
+	_, _ = store.Take([]string{"idle"}, limit, now)
+	_, _ = store.Take([]string{"active"}, limit, now.Add(2*sweepInterval))
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "active")
+}
+
+func TestMemoryStoreTakesFromAllKeysOrNone(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 2, Period: 2 * time.Second}
+	now := time.Now()
+
+	_, _ = store.Take([]string{"user"}, limit, now)
+	result, err := store.Take([]string{"ip", "user"}, limit, now)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining, "the stricter bucket is reported")
+	assert.Equal(t, 2*time.Second, result.ResetAfter)
+
+	// Корзина пользователя пуста: токен из корзины адреса не берется
+	result, _ = store.Take([]string{"ip", "user"}, limit, now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	result, _ = store.Take([]string{"ip"}, limit, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
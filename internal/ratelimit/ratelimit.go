@@ -0,0 +1,181 @@
+// Package ratelimit ограничивает частоту запросов алгоритмом token bucket.
+// Все запросы учитываются по IP-адресу клиента, запросы аутентифицированных пользователей - еще и по
+// пользователю. Чтение и запись ограничиваются раздельно.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/auth"
+)
+
+// Заголовки ответа (draft-ietf-httpapi-ratelimit-headers).
+const (
+	HeaderLimit     = "RateLimit-Limit"
+	HeaderRemaining = "RateLimit-Remaining"
+	HeaderReset     = "RateLimit-Reset"
+	HeaderPolicy    = "RateLimit-Policy"
+)
+
+// Limit - емкость корзины и период, за который она заполняется полностью.
+// Нулевой Requests отключает ограничение.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// PerMinute возвращает ограничение в n запросов в минуту.
+func PerMinute(n int) Limit {
+	return Limit{Requests: n, Period: time.Minute}
+}
+
+// interval - время, за которое в корзину добавляется один токен.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result - результат попытки взять токен из корзины.
+type Result struct {
+	Allowed bool
+	// Remaining - сколько токенов осталось в корзине.
+	Remaining int
+	// RetryAfter - через сколько появится следующий токен, если запрос отклонен.
+	RetryAfter time.Duration
+	// ResetAfter - через сколько корзина снова будет полной.
+	ResetAfter time.Duration
+}
+
+// Store хранит корзины токенов. Реализация в памяти процесса подходит для одного экземпляра сервиса;
+// для нескольких экземпляров нужна общая реализация (например, в Redis).
+type Store interface {
+	// Take атомарно берет по токену из каждой корзины keys с ограничением limit на момент now,
+	// но только если токен есть во всех корзинах: отклоненный запрос не расходует ни одну из них.
+	// Возвращается результат самой строгой корзины: с наибольшим RetryAfter, если запрос отклонен,
+	// и с наименьшим Remaining, если пропущен.
+	Take(keys []string, limit Limit, now time.Time) (Result, error)
+}
+
+// Limiter - middleware, ограничивающее частоту запросов.
+type Limiter struct {
+	store          Store
+	read           Limit
+	write          Limit
+	trustedProxies []netip.Prefix
+	logger         *logrus.Logger
+	now            func() time.Time
+}
+
+// NewLimiter создает middleware с ограничениями для чтения (GET, HEAD, OPTIONS) и записи (остальные методы).
+// trustedProxies - сети прокси (например, шлюза), которым можно верить в X-Forwarded-For;
+// без них IP-адресом клиента считается адрес соединения.
+func NewLimiter(store Store, read, write Limit, trustedProxies []netip.Prefix, logger *logrus.Logger) *Limiter {
+	return &Limiter{
+		store: store, read: read, write: write, trustedProxies: trustedProxies, logger: logger, now: time.Now,
+	}
+}
+
+// Handler оборачивает обработчик. Каждый ответ получает заголовки RateLimit-*,
+// запрос сверх ограничения отклоняется с 429 и заголовком Retry-After.
+func (l *Limiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		class, limit := "write", l.write
+		if isRead(r.Method) {
+			class, limit = "read", l.read
+		}
+		if limit.Requests <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		result, err := l.take(class, r, limit)
+		if err != nil {
+			// Недоступность хранилища не должна останавливать сервис
+			l.logger.Errorf("Rate limit store failed, allowing request: %v", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Set(HeaderLimit, strconv.Itoa(limit.Requests))
+		header.Set(HeaderRemaining, strconv.Itoa(result.Remaining))
+		header.Set(HeaderReset, strconv.Itoa(seconds(result.ResetAfter)))
+		header.Set(HeaderPolicy, fmt.Sprintf("%d;w=%d", limit.Requests, seconds(limit.Period)))
+		if !result.Allowed {
+			header.Set("Retry-After", strconv.Itoa(seconds(result.RetryAfter)))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isRead(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// take берет токен из корзины IP-адреса клиента и, если запрос аутентифицирован, из корзины пользователя.
+// X-User-ID не проверяется сервисом, поэтому корзина пользователя только дополняет корзину адреса:
+// новый ID в каждом запросе не обходит ограничение. Токены берутся, только если запрос пропускают
+// обе корзины, иначе запрос, отклоненный по пользователю, расходовал бы лимит адреса.
+func (l *Limiter) take(class string, r *http.Request, limit Limit) (Result, error) {
+	keys := []string{class + ":ip:" + l.ClientIP(r)}
+	if identity, ok := auth.FromContext(r.Context()); ok {
+		keys = append(keys, class+":user:"+identity.UserID.String())
+	}
+	return l.store.Take(keys, limit, l.now())
+}
+
+// ClientIP возвращает IP-адрес клиента. Если соединение пришло от доверенного прокси, адрес берется
+// из X-Forwarded-For: справа налево пропускаются доверенные прокси, первый недоверенный адрес
+// и есть клиент. Адреса левее него мог подставить сам клиент, поэтому они не учитываются.
+func (l *Limiter) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !l.trusted(host) {
+		return host
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if _, err := netip.ParseAddr(addr); err != nil {
+			// Некорректную запись добавил не доверенный прокси: дальше верить заголовку нельзя
+			return host
+		}
+		host = addr
+		if !l.trusted(addr) {
+			break
+		}
+	}
+	return host
+}
+
+// trusted сообщает, принадлежит ли адрес доверенному прокси.
+func (l *Limiter) trusted(host string) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range l.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// seconds округляет длительность вверх до целых секунд.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
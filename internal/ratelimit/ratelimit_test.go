@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/auth"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func serve(handler http.Handler, method, remoteAddr string, identity *auth.Identity) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/questions", nil)
+	req.RemoteAddr = remoteAddr
+	if identity != nil {
+		req = req.WithContext(auth.WithIdentity(req.Context(), *identity))
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestLimiterHeadersAndTooManyRequests(t *testing.T) {
+	limiter := NewLimiter(NewMemoryStore(), PerMinute(10), PerMinute(2), nil, logrus.New())
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	handler := limiter.Handler(okHandler)
+
+	rr := serve(handler, http.MethodPost, "192.0.2.1:1234", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get(HeaderLimit))
+	assert.Equal(t, "1", rr.Header().Get(HeaderRemaining))
+	assert.Equal(t, "30", rr.Header().Get(HeaderReset))
+	assert.Equal(t, "2;w=60", rr.Header().Get(HeaderPolicy))
+
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodPost, "192.0.2.1:1234", nil).Code)
+
+	rr = serve(handler, http.MethodPost, "192.0.2.1:5678", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rr.Header().Get(HeaderRemaining))
+
+	// Чтение ограничивается отдельно от записи
+	rr = serve(handler, http.MethodGet, "192.0.2.1:1234", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "10", rr.Header().Get(HeaderLimit))
+
+	// Другой клиент не затронут
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodPost, "192.0.2.2:1234", nil).Code)
+
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodPost, "192.0.2.1:1234", nil).Code)
+}
+
+func TestLimiterKeysByUserAndIP(t *testing.T) {
+	handler := NewLimiter(NewMemoryStore(), PerMinute(10), PerMinute(1), nil, logrus.New()).Handler(okHandler)
+	identity := &auth.Identity{UserID: uuid.New(), Role: auth.RoleUser}
+
+	// Пользователь учитывается по ID независимо от адреса
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodPost, "192.0.2.1:1234", identity).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(handler, http.MethodPost, "192.0.2.2:1234", identity).Code)
+
+	// и по адресу: новый X-User-ID не дает новой корзины
+	other := &auth.Identity{UserID: uuid.New(), Role: auth.RoleUser}
+	assert.Equal(t, http.StatusTooManyRequests, serve(handler, http.MethodPost, "192.0.2.1:1234", other).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(handler, http.MethodPost, "192.0.2.1:1234", nil).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodPost, "192.0.2.3:1234", other).Code)
+
+	// Запрос, отклоненный по пользователю, не расходует лимит адреса
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodPost, "192.0.2.2:1234", nil).Code)
+}
+
+func TestLimiterTrustedProxies(t *testing.T) {
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	handler := NewLimiter(NewMemoryStore(), PerMinute(10), PerMinute(1), proxies, logrus.New()).Handler(okHandler)
+
+	post := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/questions", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// Клиенты за шлюзом учитываются раздельно, адреса левее последнего недоверенного не учитываются
+	assert.Equal(t, http.StatusOK, post("10.0.0.1:1234", "203.0.113.1"))
+	assert.Equal(t, http.StatusOK, post("10.0.0.1:1234", "203.0.113.2, 10.0.0.2"))
+	assert.Equal(t, http.StatusTooManyRequests, post("10.0.0.1:1234", "198.51.100.9, 203.0.113.1"))
+
+	// Недоверенный клиент не может подменить адрес заголовком
+	assert.Equal(t, http.StatusOK, post("192.0.2.1:1234", "203.0.113.3"))
+	assert.Equal(t, http.StatusTooManyRequests, post("192.0.2.1:1234", "203.0.113.4"))
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	handler := NewLimiter(NewMemoryStore(), PerMinute(0), PerMinute(1), nil, logrus.New()).Handler(okHandler)
+
+	for range 3 {
+		rr := serve(handler, http.MethodGet, "192.0.2.1:1234", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get(HeaderLimit))
+	}
+}
+
+// failingStore - хранилище, которое всегда возвращает ошибку.
+type failingStore struct{}
+
+func (failingStore) Take([]string, Limit, time.Time) (Result, error) {
+	return Result{}, errors.New("store is unavailable")
+}
+
+func TestLimiterAllowsWhenStoreFails(t *testing.T) {
+	handler := NewLimiter(failingStore{}, PerMinute(1), PerMinute(1), nil, logrus.New()).Handler(okHandler)
+
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodGet, "192.0.2.1:1234", nil).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, http.MethodGet, "192.0.2.1:1234", nil).Code)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import "fmt"
+
+// VersionConflictError возвращается при условном изменении записи, если ее версия в хранилище
+// уже не равна ожидаемой: с момента чтения запись изменили или удалили.
+type VersionConflictError struct {
+	Entity  string
+	ID      uint
+	Version int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s with ID %d is no longer at version %d", e.Entity, e.ID, e.Version)
+}
@@ -0,0 +1,746 @@
+package repository
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+)
+
+// memoryRepository - реализация Repository, хранящая данные в памяти процесса.
+// Используется в тестах и для локального запуска без PostgreSQL.
+type memoryRepository struct {
+	mu     sync.RWMutex
+	logger *logrus.Logger
+
+	questions map[uint]models.Question
+	answers   map[uint]models.Answer
+	comments  map[uint]models.Comment
+
+	importedPosts map[importKey]models.ImportedPost
+	importedUsers map[importKey]models.ImportedUser
+
+	lastQuestionID uint
+	lastAnswerID   uint
+	lastCommentID  uint
+}
+
+// NewMemoryRepository создает новый экземпляр репозитория в памяти.
+func NewMemoryRepository(logger *logrus.Logger) Repository {
+	return &memoryRepository{
+		logger:    logger,
+		questions: make(map[uint]models.Question),
+		answers:   make(map[uint]models.Answer),
+		comments:  make(map[uint]models.Comment),
+
+		importedPosts: make(map[importKey]models.ImportedPost),
+		importedUsers: make(map[importKey]models.ImportedUser),
+	}
+}
+
+// importKey - ключ связи с записью внешнего источника.
+type importKey struct {
+	source string
+	id     int64
+}
+
+// CreateQuestion сохраняет новый вопрос в памяти.
+func (r *memoryRepository) CreateQuestion(question *models.Question) error {
+	r.logger.Debugf("Creating question in memory: %+v", question)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastQuestionID++
+	question.ID = r.lastQuestionID
+	question.CreatedAt = time.Now()
+	question.UpdatedAt = question.CreatedAt
+	question.Version = 1
+
+	stored := *question
+	stored.Tags = slices.Clone(question.Tags)
+	stored.Answers = nil
+	r.questions[stored.ID] = stored
+	return nil
+}
+
+// CreateQuestions сохраняет вопросы вместе с их ответами.
+// Заданное время создания сохраняется, незаданное заполняется текущим временем.
+func (r *memoryRepository) CreateQuestions(questions []*models.Question) error {
+	r.logger.Debugf("Creating %d questions in memory", len(questions))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, question := range questions {
+		r.lastQuestionID++
+		question.ID = r.lastQuestionID
+		setCreated(&question.CreatedAt, &question.UpdatedAt, now)
+		question.Version = 1
+		for i := range question.Answers {
+			answer := &question.Answers[i]
+			r.lastAnswerID++
+			answer.ID = r.lastAnswerID
+			answer.QuestionID = question.ID
+			setCreated(&answer.CreatedAt, &answer.UpdatedAt, now)
+			answer.Version = 1
+			r.answers[answer.ID] = *answer
+		}
+
+		stored := *question
+		stored.Tags = slices.Clone(question.Tags)
+		stored.Answers = nil
+		r.questions[stored.ID] = stored
+	}
+	return nil
+}
+
+// FindQuestionIDsByTitle возвращает ID вопросов с заголовками из titles (заголовок -> ID).
+// Если вопросов с одним заголовком несколько, возвращается самый ранний.
+func (r *memoryRepository) FindQuestionIDsByTitle(titles []string) (map[string]uint, error) {
+	r.logger.Debugf("Finding questions by %d titles in memory", len(titles))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(titles))
+	for _, title := range titles {
+		wanted[title] = true
+	}
+	ids := make(map[string]uint)
+	for id, question := range r.questions {
+		if existing, ok := ids[question.Title]; wanted[question.Title] && (!ok || id < existing) {
+			ids[question.Title] = id
+		}
+	}
+	return ids, nil
+}
+
+// FindQuestionsInBatches передает fn вопросы, удовлетворяющие фильтрам спецификации, вместе с ответами
+// пачками по batchSize в порядке возрастания ID. Ошибка fn прекращает выборку и возвращается.
+func (r *memoryRepository) FindQuestionsInBatches(
+	spec query.QuestionSpec, batchSize int, fn func([]models.Question) error,
+) error {
+	r.logger.Debugf("Finding questions in memory in batches of %d: %+v", batchSize, spec)
+	r.mu.RLock()
+	questions := make([]models.Question, 0, len(r.questions))
+	for _, question := range r.questions {
+		r.fillQuestion(&question, query.Projection{Include: []string{query.IncludeAnswers}})
+		if spec.Matches(&question) {
+			questions = append(questions, question)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(questions, func(i, j int) bool { return questions[i].ID < questions[j].ID })
+	for start := 0; start < len(questions); start += batchSize {
+		if err := fn(questions[start:min(start+batchSize, len(questions))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateExternalPosts сохраняет вопросы и ответы внешнего источника source и связи с ними.
+// Как и в БД, либо сохраняется вся пачка, либо ничего: ответ к несуществующему вопросу отклоняет ее целиком.
+func (r *memoryRepository) CreateExternalPosts(source string, posts []ExternalPost) error {
+	r.logger.Debugf("Creating %d posts from %s in memory", len(posts), source)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, post := range posts {
+		if post.Answer != nil && post.Parent == nil {
+			if _, ok := r.questions[post.Answer.QuestionID]; !ok {
+				return gorm.ErrForeignKeyViolated
+			}
+		}
+	}
+
+	// Вопросы сохраняются раньше ответов, чтобы ответы получили ID своих вопросов из той же пачки
+	now := time.Now()
+	for _, post := range posts {
+		if question := post.Question; question != nil {
+			r.lastQuestionID++
+			question.ID = r.lastQuestionID
+			setCreated(&question.CreatedAt, &question.UpdatedAt, now)
+			question.Version = 1
+			r.questions[question.ID] = *question
+			r.importedPosts[importKey{source: source, id: post.ExternalID}] = models.ImportedPost{Source: source,
+				ExternalID: post.ExternalID, Kind: models.ImportedKindQuestion, EntityID: question.ID, CreatedAt: now}
+		}
+	}
+	for _, post := range posts {
+		answer := post.Answer
+		if answer == nil {
+			continue
+		}
+		if post.Parent != nil {
+			answer.QuestionID = post.Parent.ID
+		}
+		r.lastAnswerID++
+		answer.ID = r.lastAnswerID
+		setCreated(&answer.CreatedAt, &answer.UpdatedAt, now)
+		answer.Version = 1
+		r.answers[answer.ID] = *answer
+		if post.Accepted {
+			question := r.questions[answer.QuestionID]
+			id := answer.ID
+			question.AcceptedAnswerID = &id
+			r.questions[answer.QuestionID] = question
+		}
+		r.importedPosts[importKey{source: source, id: post.ExternalID}] = models.ImportedPost{Source: source,
+			ExternalID: post.ExternalID, Kind: models.ImportedKindAnswer, EntityID: answer.ID, CreatedAt: now}
+	}
+	return nil
+}
+
+// FindImportedPosts возвращает связи постов источника source с ID из externalIDs (ID поста -> связь).
+func (r *memoryRepository) FindImportedPosts(
+	source string, externalIDs []int64,
+) (map[int64]models.ImportedPost, error) {
+	r.logger.Debugf("Finding %d imported posts from %s in memory", len(externalIDs), source)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	posts := make(map[int64]models.ImportedPost, len(externalIDs))
+	for _, id := range externalIDs {
+		if post, ok := r.importedPosts[importKey{source: source, id: id}]; ok {
+			posts[id] = post
+		}
+	}
+	return posts, nil
+}
+
+// CreateImportedUsers сохраняет связи пользователей внешнего источника.
+func (r *memoryRepository) CreateImportedUsers(users []models.ImportedUser) error {
+	r.logger.Debugf("Creating %d imported users in memory", len(users))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range users {
+		if _, ok := r.importedUsers[importKey{source: user.Source, id: user.ExternalID}]; ok {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	now := time.Now()
+	for _, user := range users {
+		user.CreatedAt = now
+		r.importedUsers[importKey{source: user.Source, id: user.ExternalID}] = user
+	}
+	return nil
+}
+
+// FindImportedUsers возвращает ID пользователей источника source с ID из externalIDs
+// (ID во внешнем источнике -> ID пользователя).
+func (r *memoryRepository) FindImportedUsers(source string, externalIDs []int64) (map[int64]uuid.UUID, error) {
+	r.logger.Debugf("Finding %d imported users from %s in memory", len(externalIDs), source)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make(map[int64]uuid.UUID, len(externalIDs))
+	for _, id := range externalIDs {
+		if user, ok := r.importedUsers[importKey{source: source, id: id}]; ok {
+			users[id] = user.UserID
+		}
+	}
+	return users, nil
+}
+
+// GetQuestion получает вопрос по ID вместе с запрошенными связанными данными.
+// Хранилище в памяти всегда возвращает все поля: выборка колонок имеет смысл только для БД.
+func (r *memoryRepository) GetQuestion(id uint, proj query.Projection) (*models.Question, error) {
+	r.logger.Debugf("Getting question from memory with ID: %d (%+v)", id, proj)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	question, ok := r.questions[id]
+	if !ok {
+		return &models.Question{}, gorm.ErrRecordNotFound
+	}
+	r.fillQuestion(&question, proj)
+	return &question, nil
+}
+
+// GetAllQuestions получает все вопросы, удовлетворяющие спецификации, в заданном ею порядке.
+func (r *memoryRepository) GetAllQuestions(spec query.QuestionSpec, proj query.Projection) ([]models.Question, error) {
+	r.logger.Debugf("Getting all questions from memory: %+v (%+v)", spec, proj)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	questions := make([]models.Question, 0, len(r.questions))
+	for _, question := range r.questions {
+		r.fillQuestion(&question, proj)
+		if spec.Matches(&question) {
+			questions = append(questions, question)
+		}
+	}
+	sort.Slice(questions, func(i, j int) bool { return spec.Less(&questions[i], &questions[j]) })
+	if spec.Limit > 0 && len(questions) > spec.Limit {
+		questions = questions[:spec.Limit]
+	}
+	return questions, nil
+}
+
+// FindQuestionsByIDs получает вопросы с ID из ids вместе со счетчиками (ID -> вопрос).
+func (r *memoryRepository) FindQuestionsByIDs(ids []uint) (map[uint]models.Question, error) {
+	r.logger.Debugf("Finding %d questions by ID in memory", len(ids))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	questions := make(map[uint]models.Question, len(ids))
+	for _, id := range ids {
+		if question, ok := r.questions[id]; ok {
+			r.fillQuestion(&question, query.Projection{})
+			questions[id] = question
+		}
+	}
+	return questions, nil
+}
+
+// UpdateQuestion сохраняет заголовок и тело вопроса, если его версия не изменилась с момента чтения.
+func (r *memoryRepository) UpdateQuestion(question *models.Question) error {
+	r.logger.Debugf("Updating question %d at version %d in memory", question.ID, question.Version)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.questions[question.ID]
+	if !ok || stored.Version != question.Version {
+		return &VersionConflictError{Entity: "question", ID: question.ID, Version: question.Version}
+	}
+	stored.Title = question.Title
+	stored.Body = question.Body
+	r.saveQuestion(question, stored)
+	return nil
+}
+
+// DeleteQuestion удаляет вопрос вместе с ответами и комментариями.
+// Если version не равна нулю, вопрос удаляется, только если он все еще в этой версии.
+func (r *memoryRepository) DeleteQuestion(id uint, version int) error {
+	r.logger.Debugf("Deleting question from memory with ID: %d (version %d)", id, version)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if question, ok := r.questions[id]; version != 0 && (!ok || question.Version != version) {
+		return &VersionConflictError{Entity: "question", ID: id, Version: version}
+	}
+	delete(r.questions, id)
+	for answerID, answer := range r.answers {
+		if answer.QuestionID == id {
+			r.deleteAnswer(answerID)
+		}
+	}
+	r.deleteComments(models.CommentParentQuestion, id)
+	for qid, question := range r.questions {
+		if question.DuplicateOf != nil && *question.DuplicateOf == id {
+			question.DuplicateOf = nil
+			question.UpdatedAt = time.Now()
+			r.questions[qid] = question
+		}
+	}
+	return nil
+}
+
+// CreateAnswer сохраняет новый ответ в памяти.
+func (r *memoryRepository) CreateAnswer(answer *models.Answer) error {
+	r.logger.Debugf("Creating answer in memory: %+v", answer)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.questions[answer.QuestionID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+
+	r.lastAnswerID++
+	answer.ID = r.lastAnswerID
+	answer.CreatedAt = time.Now()
+	answer.UpdatedAt = answer.CreatedAt
+	answer.Version = 1
+	r.answers[answer.ID] = *answer
+	r.touchQuestion(answer.QuestionID)
+	return nil
+}
+
+// GetAnswer получает ответ по ID вместе с запрошенными связанными данными.
+func (r *memoryRepository) GetAnswer(id uint, proj query.Projection) (*models.Answer, error) {
+	r.logger.Debugf("Getting answer from memory with ID: %d (%+v)", id, proj)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	answer, ok := r.answers[id]
+	if !ok {
+		return &models.Answer{}, gorm.ErrRecordNotFound
+	}
+	r.fillAnswer(&answer, proj)
+	return &answer, nil
+}
+
+// FindAnswersByQuestionIDs получает ответы на вопросы с ID из questionIDs
+// (ID вопроса -> ответы в порядке создания).
+func (r *memoryRepository) FindAnswersByQuestionIDs(questionIDs []uint) (map[uint][]models.Answer, error) {
+	r.logger.Debugf("Finding answers to %d questions in memory", len(questionIDs))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[uint]bool, len(questionIDs))
+	for _, id := range questionIDs {
+		wanted[id] = true
+	}
+	answers := make(map[uint][]models.Answer, len(questionIDs))
+	for _, answer := range r.answers {
+		if wanted[answer.QuestionID] {
+			r.fillAnswer(&answer, query.Projection{})
+			answers[answer.QuestionID] = append(answers[answer.QuestionID], answer)
+		}
+	}
+	for _, list := range answers {
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+	return answers, nil
+}
+
+// UpdateAnswer сохраняет текст ответа, если его версия не изменилась с момента чтения.
+func (r *memoryRepository) UpdateAnswer(answer *models.Answer) error {
+	r.logger.Debugf("Updating answer %d at version %d in memory", answer.ID, answer.Version)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.answers[answer.ID]
+	if !ok || stored.Version != answer.Version {
+		return &VersionConflictError{Entity: "answer", ID: answer.ID, Version: answer.Version}
+	}
+	stored.Text = answer.Text
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	r.answers[answer.ID] = stored
+	r.touchQuestion(stored.QuestionID)
+	answer.Version = stored.Version
+	answer.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// DeleteAnswer удаляет ответ вместе с комментариями.
+// Если version не равна нулю, ответ удаляется, только если он все еще в этой версии.
+func (r *memoryRepository) DeleteAnswer(id uint, version int) error {
+	r.logger.Debugf("Deleting answer from memory with ID: %d (version %d)", id, version)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if answer, ok := r.answers[id]; version != 0 && (!ok || answer.Version != version) {
+		return &VersionConflictError{Entity: "answer", ID: id, Version: version}
+	}
+	r.deleteAnswer(id)
+	return nil
+}
+
+// CreateComment сохраняет новый комментарий в памяти.
+func (r *memoryRepository) CreateComment(comment *models.Comment) error {
+	r.logger.Debugf("Creating comment in memory: %+v", comment)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastCommentID++
+	comment.ID = r.lastCommentID
+	comment.CreatedAt = time.Now()
+	r.comments[comment.ID] = *comment
+	r.touchParent(comment.ParentType, comment.ParentID)
+	return nil
+}
+
+// GetComment получает комментарий по ID.
+func (r *memoryRepository) GetComment(id uint) (*models.Comment, error) {
+	r.logger.Debugf("Getting comment from memory with ID: %d", id)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comment, ok := r.comments[id]
+	if !ok {
+		return &models.Comment{}, gorm.ErrRecordNotFound
+	}
+	return &comment, nil
+}
+
+// GetComments получает комментарии к вопросу или ответу в порядке создания.
+func (r *memoryRepository) GetComments(parentType string, parentID uint) ([]models.Comment, error) {
+	r.logger.Debugf("Getting comments from memory for %s with ID: %d", parentType, parentID)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.listComments(parentType, parentID), nil
+}
+
+// DeleteComment удаляет комментарий по ID.
+func (r *memoryRepository) DeleteComment(id uint) error {
+	r.logger.Debugf("Deleting comment from memory with ID: %d", id)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if comment, ok := r.comments[id]; ok {
+		delete(r.comments, id)
+		r.touchParent(comment.ParentType, comment.ParentID)
+	}
+	return nil
+}
+
+// FindSimilarQuestions ищет вопросы с похожим заголовком по коэффициенту Жаккара
+// для триграмм - так же, как это делает pg_trgm в PostgreSQL.
+func (r *memoryRepository) FindSimilarQuestions(
+	title string, threshold float64, limit int,
+) ([]models.SimilarQuestion, error) {
+	r.logger.Debugf("Finding questions similar to %q in memory", title)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var similar []models.SimilarQuestion
+	for _, question := range r.questions {
+		if question.DuplicateOf != nil {
+			continue
+		}
+		score := similarity(title, question.Title)
+		if score < threshold {
+			continue
+		}
+		question.CommentCount = r.countComments(models.CommentParentQuestion, question.ID)
+		similar = append(similar, models.SimilarQuestion{Question: question, Similarity: score})
+	}
+
+	sort.Slice(similar, func(i, j int) bool {
+		if similar[i].Similarity != similar[j].Similarity {
+			return similar[i].Similarity > similar[j].Similarity
+		}
+		return similar[i].ID < similar[j].ID
+	})
+	if limit > 0 && len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
+
+// MarkDuplicate сохраняет закрытие вопроса как дубликата question.DuplicateOf.
+func (r *memoryRepository) MarkDuplicate(question *models.Question) error {
+	r.logger.Debugf("Marking question %d as duplicate of %v in memory", question.ID, question.DuplicateOf)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkQuestionVersion(question); err != nil {
+		return err
+	}
+	for qid, other := range r.questions {
+		if other.DuplicateOf != nil && *other.DuplicateOf == question.ID {
+			target := *question.DuplicateOf
+			other.DuplicateOf = &target
+			other.UpdatedAt = time.Now()
+			other.Version++
+			r.questions[qid] = other
+		}
+	}
+	r.updateStatus(question)
+	return nil
+}
+
+// GetQuestionStatusForUpdate возвращает статус вопроса. Транзакций в памяти нет, поэтому строка не блокируется.
+func (r *memoryRepository) GetQuestionStatusForUpdate(id uint) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	question, ok := r.questions[id]
+	if !ok {
+		return "", gorm.ErrRecordNotFound
+	}
+	return question.Status, nil
+}
+
+// UpdateQuestionStatus сохраняет статус вопроса и сведения о его закрытии.
+func (r *memoryRepository) UpdateQuestionStatus(question *models.Question) error {
+	r.logger.Debugf("Updating status of question %d to %s in memory", question.ID, question.Status)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkQuestionVersion(question); err != nil {
+		return err
+	}
+	r.updateStatus(question)
+	return nil
+}
+
+// AcceptAnswer сохраняет принятый ответ question.AcceptedAnswerID, если версия вопроса не изменилась.
+func (r *memoryRepository) AcceptAnswer(question *models.Question) error {
+	r.logger.Debugf("Accepting answer %v of question %d in memory", question.AcceptedAnswerID, question.ID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkQuestionVersion(question); err != nil {
+		return err
+	}
+	stored := r.questions[question.ID]
+	stored.AcceptedAnswerID = question.AcceptedAnswerID
+	r.saveQuestion(question, stored)
+	return nil
+}
+
+// checkQuestionVersion проверяет, что сохраненный вопрос все еще в версии question.Version.
+// Вызывающий должен удерживать блокировку.
+func (r *memoryRepository) checkQuestionVersion(question *models.Question) error {
+	if stored, ok := r.questions[question.ID]; !ok || stored.Version != question.Version {
+		return &VersionConflictError{Entity: "question", ID: question.ID, Version: question.Version}
+	}
+	return nil
+}
+
+// updateStatus копирует поля жизненного цикла в сохраненный вопрос.
+// Вызывающий должен удерживать блокировку и проверить версию вопроса.
+func (r *memoryRepository) updateStatus(question *models.Question) {
+	stored := r.questions[question.ID]
+	stored.Status = question.Status
+	stored.CloseReason = question.CloseReason
+	stored.ClosedBy = question.ClosedBy
+	stored.ClosedAt = question.ClosedAt
+	stored.DuplicateOf = question.DuplicateOf
+	r.saveQuestion(question, stored)
+}
+
+// saveQuestion сохраняет измененный вопрос с новой версией и сообщает ее вызывающему через question.
+// Вызывающий должен удерживать блокировку.
+func (r *memoryRepository) saveQuestion(question *models.Question, stored models.Question) {
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	r.questions[stored.ID] = stored
+	question.Version = stored.Version
+	question.UpdatedAt = stored.UpdatedAt
+}
+
+// setCreated заполняет незаданное время создания значением now, а время изменения - временем создания.
+func setCreated(createdAt, updatedAt *time.Time, now time.Time) {
+	if createdAt.IsZero() {
+		*createdAt = now
+	}
+	if updatedAt.IsZero() {
+		*updatedAt = *createdAt
+	}
+}
+
+// touchQuestion обновляет время изменения вопроса, как это делают триггеры в БД.
+// Вызывающий должен удерживать блокировку.
+func (r *memoryRepository) touchQuestion(id uint) {
+	if question, ok := r.questions[id]; ok {
+		question.UpdatedAt = time.Now()
+		r.questions[id] = question
+	}
+}
+
+// touchParent обновляет время изменения родителя комментария, а для ответа - и его вопроса.
+// Вызывающий должен удерживать блокировку.
+func (r *memoryRepository) touchParent(parentType string, parentID uint) {
+	if parentType == models.CommentParentQuestion {
+		r.touchQuestion(parentID)
+		return
+	}
+	if answer, ok := r.answers[parentID]; ok {
+		answer.UpdatedAt = time.Now()
+		r.answers[parentID] = answer
+		r.touchQuestion(answer.QuestionID)
+	}
+}
+
+// fillQuestion дополняет вопрос вычисляемыми счетчиками и запрошенными связанными данными.
+// Вызывающий должен удерживать блокировку.
+func (r *memoryRepository) fillQuestion(question *models.Question, proj query.Projection) {
+	answers := make([]models.Answer, 0)
+	question.LastActivityAt = question.CreatedAt
+	for _, answer := range r.answers {
+		if answer.QuestionID == question.ID {
+			r.fillAnswer(&answer, proj)
+			answers = append(answers, answer)
+			if answer.CreatedAt.After(question.LastActivityAt) {
+				question.LastActivityAt = answer.CreatedAt
+			}
+		}
+	}
+	sort.Slice(answers, func(i, j int) bool { return answers[i].ID < answers[j].ID })
+	question.AnswerCount = int64(len(answers))
+	question.CommentCount = r.countComments(models.CommentParentQuestion, question.ID)
+
+	question.Answers = nil
+	if proj.Includes(query.IncludeAnswers) {
+		question.Answers = answers
+	}
+	question.Comments = nil
+	if proj.Includes(query.IncludeComments) {
+		question.Comments = r.listComments(models.CommentParentQuestion, question.ID)
+	}
+}
+
+// fillAnswer дополняет ответ количеством комментариев и, если они запрошены, самими комментариями.
+// Вызывающий должен удерживать блокировку.
+func (r *memoryRepository) fillAnswer(answer *models.Answer, proj query.Projection) {
+	answer.CommentCount = r.countComments(models.CommentParentAnswer, answer.ID)
+	answer.Comments = nil
+	if proj.Includes(query.IncludeComments) {
+		answer.Comments = r.listComments(models.CommentParentAnswer, answer.ID)
+	}
+}
+
+// listComments возвращает комментарии к родителю в порядке создания.
+// Вызывающий должен удерживать блокировку.
+func (r *memoryRepository) listComments(parentType string, parentID uint) []models.Comment {
+	comments := make([]models.Comment, 0)
+	for _, comment := range r.comments {
+		if comment.ParentType == parentType && comment.ParentID == parentID {
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].ID < comments[j].ID })
+	return comments
+}
+
+// countComments подсчитывает комментарии к родителю. Вызывающий должен удерживать блокировку.
+func (r *memoryRepository) countComments(parentType string, parentID uint) int64 {
+	var count int64
+	for _, comment := range r.comments {
+		if comment.ParentType == parentType && comment.ParentID == parentID {
+			count++
+		}
+	}
+	return count
+}
+
+// deleteAnswer удаляет ответ и его комментарии. Вызывающий должен удерживать блокировку.
+func (r *memoryRepository) deleteAnswer(id uint) {
+	answer, ok := r.answers[id]
+	if !ok {
+		return
+	}
+	delete(r.answers, id)
+	question := r.questions[answer.QuestionID]
+	if question.AcceptedAnswerID != nil && *question.AcceptedAnswerID == id {
+		question.AcceptedAnswerID = nil
+		r.questions[answer.QuestionID] = question
+	}
+	r.deleteComments(models.CommentParentAnswer, id)
+	r.touchQuestion(answer.QuestionID)
+}
+
+// deleteComments удаляет комментарии к родителю. Вызывающий должен удерживать блокировку.
+func (r *memoryRepository) deleteComments(parentType string, parentID uint) {
+	for id, comment := range r.comments {
+		if comment.ParentType == parentType && comment.ParentID == parentID {
+			delete(r.comments, id)
+		}
+	}
+}
+
+// Transaction выполняет fn. Транзакций в памяти нет: изменения применяются сразу и при ошибке fn
+// не откатываются.
+func (r *memoryRepository) Transaction(fn func(repo Repository) error) error {
+	return fn(r)
+}
+
+// CreateOutboxMessage ничего не делает: с хранилищем в памяти outbox не используется,
+// события публикуются сразу после изменения.
+func (r *memoryRepository) CreateOutboxMessage(*models.OutboxMessage) error {
+	return nil
+}
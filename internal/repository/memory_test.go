@@ -0,0 +1,452 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+)
+
+func TestMemoryRepositoryQuestionLifecycle(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+
+	question := &models.Question{Title: "How to install Go?"}
+	assert.NoError(t, repo.CreateQuestion(question))
+	assert.Equal(t, uint(1), question.ID)
+
+	answer := &models.Answer{QuestionID: question.ID, UserID: uuid.New(), Text: "Download it"}
+	assert.NoError(t, repo.CreateAnswer(answer))
+	assert.NoError(t, repo.CreateComment(&models.Comment{
+		ParentType: models.CommentParentAnswer, ParentID: answer.ID, Text: "Thanks",
+	}))
+
+	got, err := repo.GetQuestion(question.ID, withAnswers)
+	assert.NoError(t, err)
+	assert.Len(t, got.Answers, 1)
+	assert.Equal(t, int64(1), got.Answers[0].CommentCount)
+
+	assert.NoError(t, repo.DeleteQuestion(question.ID, 0))
+	_, err = repo.GetQuestion(question.ID, query.Projection{})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = repo.GetAnswer(answer.ID, query.Projection{})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	comments, err := repo.GetComments(models.CommentParentAnswer, answer.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, comments)
+}
+
+func TestMemoryRepositoryGetComment(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+
+	comment := &models.Comment{ParentType: models.CommentParentQuestion, ParentID: 1, Text: "Which OS?"}
+	assert.NoError(t, repo.CreateComment(comment))
+
+	got, err := repo.GetComment(comment.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Which OS?", got.Text)
+	assert.Equal(t, uint(1), got.ParentID)
+
+	_, err = repo.GetComment(42)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestMemoryRepositoryCreateAnswerUnknownQuestion(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+
+	err := repo.CreateAnswer(&models.Answer{QuestionID: 42, Text: "Orphan"})
+	assert.Error(t, err)
+}
+
+func TestMemoryRepositoryCreateQuestions(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+	assert.NoError(t, repo.CreateQuestion(&models.Question{Title: "Existing question"}))
+
+	createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	questions := []*models.Question{
+		{Title: "Imported question", CreatedAt: createdAt, Answers: []models.Answer{
+			{UserID: uuid.New(), Text: "Imported answer", CreatedAt: createdAt.Add(time.Hour)},
+		}},
+		{Title: "Existing question"},
+	}
+	assert.NoError(t, repo.CreateQuestions(questions))
+	assert.Equal(t, uint(2), questions[0].ID)
+	assert.Equal(t, uint(3), questions[1].ID)
+
+	got, err := repo.GetQuestion(questions[0].ID, withAnswers)
+	assert.NoError(t, err)
+	assert.Equal(t, createdAt, got.CreatedAt)
+	assert.Equal(t, createdAt, got.UpdatedAt)
+	assert.Equal(t, 1, got.Version)
+	assert.Len(t, got.Answers, 1)
+	assert.Equal(t, createdAt.Add(time.Hour), got.Answers[0].CreatedAt)
+
+	ids, err := repo.FindQuestionIDsByTitle([]string{"Existing question", "Imported question", "Missing"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uint{"Existing question": 1, "Imported question": 2}, ids)
+}
+
+func TestMemoryRepositoryFindQuestionsInBatches(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+	for _, status := range []string{
+		models.QuestionStatusOpen, models.QuestionStatusClosed, models.QuestionStatusOpen, models.QuestionStatusOpen,
+	} {
+		assert.NoError(t, repo.CreateQuestion(&models.Question{Title: "Question", Status: status}))
+	}
+	assert.NoError(t, repo.CreateAnswer(&models.Answer{QuestionID: 3, UserID: uuid.New(), Text: "Answer"}))
+
+	var batches [][]uint
+	err := repo.FindQuestionsInBatches(query.QuestionSpec{Statuses: []string{models.QuestionStatusOpen}}, 2,
+		func(questions []models.Question) error {
+			var ids []uint
+			for _, q := range questions {
+				ids = append(ids, q.ID)
+			}
+			batches = append(batches, ids)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]uint{{1, 3}, {4}}, batches)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.FindQuestionsInBatches(query.QuestionSpec{}, 1, func(questions []models.Question) error {
+		calls++
+		if questions[0].ID == 3 {
+			assert.Len(t, questions[0].Answers, 1)
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 3, calls)
+}
+
+func TestMemoryRepositoryCreateExternalPosts(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+	question := &models.Question{Title: "Imported question", Status: models.QuestionStatusOpen}
+	answer := &models.Answer{UserID: uuid.New(), Text: "Imported answer"}
+	assert.NoError(t, repo.CreateExternalPosts("qa", []ExternalPost{
+		{ExternalID: 10, Question: question},
+		{ExternalID: 11, Answer: answer, Parent: question, Accepted: true},
+	}))
+
+	got, err := repo.GetQuestion(question.ID, withAnswers)
+	assert.NoError(t, err)
+	assert.Equal(t, &answer.ID, got.AcceptedAnswerID)
+	assert.Len(t, got.Answers, 1)
+
+	posts, err := repo.FindImportedPosts("qa", []int64{10, 11, 12})
+	assert.NoError(t, err)
+	assert.Len(t, posts, 2)
+	assert.Equal(t, models.ImportedKindAnswer, posts[11].Kind)
+	assert.Equal(t, answer.ID, posts[11].EntityID)
+	posts, err = repo.FindImportedPosts("other", []int64{10})
+	assert.NoError(t, err)
+	assert.Empty(t, posts)
+
+	// Ответ к несуществующему вопросу отклоняет всю пачку
+	err = repo.CreateExternalPosts("qa", []ExternalPost{
+		{ExternalID: 20, Question: &models.Question{Title: "Another question"}},
+		{ExternalID: 21, Answer: &models.Answer{QuestionID: 99, UserID: uuid.New(), Text: "Orphan answer"}},
+	})
+	assert.ErrorIs(t, err, gorm.ErrForeignKeyViolated)
+	posts, err = repo.FindImportedPosts("qa", []int64{20})
+	assert.NoError(t, err)
+	assert.Empty(t, posts)
+
+	// Удаление принятого ответа снимает отметку
+	assert.NoError(t, repo.DeleteAnswer(answer.ID, 0))
+	got, err = repo.GetQuestion(question.ID, withAnswers)
+	assert.NoError(t, err)
+	assert.Nil(t, got.AcceptedAnswerID)
+}
+
+func TestMemoryRepositoryImportedUsers(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+	userID := uuid.New()
+	assert.NoError(t, repo.CreateImportedUsers([]models.ImportedUser{{Source: "qa", ExternalID: 1, UserID: userID}}))
+	assert.ErrorIs(t, repo.CreateImportedUsers([]models.ImportedUser{{Source: "qa", ExternalID: 1}}),
+		gorm.ErrDuplicatedKey)
+
+	users, err := repo.FindImportedUsers("qa", []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]uuid.UUID{1: userID}, users)
+}
+
+func TestMemoryRepositoryFindByIDs(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+	for _, title := range []string{"First question", "Second question", "Third question"} {
+		assert.NoError(t, repo.CreateQuestion(&models.Question{Title: title}))
+	}
+	for _, answer := range []models.Answer{{QuestionID: 3, Text: "Answer A"}, {QuestionID: 1, Text: "Answer B"},
+		{QuestionID: 3, Text: "Answer C"}} {
+		assert.NoError(t, repo.CreateAnswer(&answer))
+	}
+
+	questions, err := repo.FindQuestionsByIDs([]uint{3, 4})
+	assert.NoError(t, err)
+	assert.Len(t, questions, 1)
+	assert.Equal(t, "Third question", questions[3].Title)
+	assert.Equal(t, int64(2), questions[3].AnswerCount)
+
+	answers, err := repo.FindAnswersByQuestionIDs([]uint{2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, answers, 1)
+	assert.Equal(t, []string{"Answer A", "Answer C"}, []string{answers[3][0].Text, answers[3][1].Text})
+}
+
+func TestMemoryRepositoryFindSimilarQuestions(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+
+	for _, title := range []string{
+		"How to install Go on Ubuntu?",
+		"How do I install Go on Ubuntu",
+		"Why is my PostgreSQL query slow?",
+	} {
+		assert.NoError(t, repo.CreateQuestion(&models.Question{Title: title}))
+	}
+
+	similar, err := repo.FindSimilarQuestions("how to install go on ubuntu", 0.4, 5)
+	assert.NoError(t, err)
+	assert.Len(t, similar, 2)
+	assert.Equal(t, uint(1), similar[0].ID)
+	assert.InDelta(t, 1.0, similar[0].Similarity, 0.001)
+	assert.Greater(t, similar[0].Similarity, similar[1].Similarity)
+}
+
+func TestMemoryRepositoryMarkDuplicate(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+
+	for _, title := range []string{"Install Go", "Install Go please", "Install Golang"} {
+		assert.NoError(t, repo.CreateQuestion(&models.Question{Title: title}))
+	}
+
+	assert.NoError(t, repo.MarkDuplicate(closedAsDuplicate(3, 2)))
+	assert.NoError(t, repo.MarkDuplicate(closedAsDuplicate(2, 1)))
+
+	// Дубликат дубликата перенаправляется на новый исходный вопрос
+	q3, err := repo.GetQuestion(3, query.Projection{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), *q3.DuplicateOf)
+
+	assert.Equal(t, models.QuestionStatusClosed, q3.Status)
+
+	// Закрытые как дубликаты вопросы не предлагаются повторно
+	similar, err := repo.FindSimilarQuestions("Install Go", 0.1, 5)
+	assert.NoError(t, err)
+	assert.Len(t, similar, 1)
+	assert.Equal(t, uint(1), similar[0].ID)
+}
+
+// closedAsDuplicate возвращает вопрос id, закрытый как дубликат вопроса duplicateOf.
+func closedAsDuplicate(id, duplicateOf uint) *models.Question {
+	return &models.Question{
+		ID:          id,
+		Version:     1,
+		Status:      models.QuestionStatusClosed,
+		CloseReason: models.CloseReasonDuplicate,
+		DuplicateOf: &duplicateOf,
+	}
+}
+
+func TestMemoryRepositoryStatusFilter(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+
+	for _, title := range []string{"Open question", "Closed question", "Locked question"} {
+		assert.NoError(t, repo.CreateQuestion(&models.Question{Title: title, Status: models.QuestionStatusOpen}))
+	}
+	assert.NoError(t, repo.UpdateQuestionStatus(&models.Question{
+		ID: 2, Version: 1, Status: models.QuestionStatusClosed,
+	}))
+	assert.NoError(t, repo.UpdateQuestionStatus(&models.Question{
+		ID: 3, Version: 1, Status: models.QuestionStatusLocked,
+	}))
+
+	questions, err := repo.GetAllQuestions(query.QuestionSpec{
+		Statuses: []string{models.QuestionStatusClosed, models.QuestionStatusLocked},
+	}, query.Projection{})
+	assert.NoError(t, err)
+	assert.Len(t, questions, 2)
+	assert.Equal(t, uint(3), questions[0].ID)
+	assert.Equal(t, uint(2), questions[1].ID)
+
+	all, err := repo.GetAllQuestions(query.QuestionSpec{}, query.Projection{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+}
+
+func TestMemoryRepositoryAcceptAnswer(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+	question := &models.Question{Title: "Question", Status: models.QuestionStatusOpen}
+	assert.NoError(t, repo.CreateQuestion(question))
+	answer := &models.Answer{QuestionID: question.ID, UserID: uuid.New(), Text: "Answer"}
+	assert.NoError(t, repo.CreateAnswer(answer))
+
+	question.AcceptedAnswerID = &answer.ID
+	assert.NoError(t, repo.AcceptAnswer(question))
+	assert.Equal(t, 2, question.Version)
+	got, err := repo.GetQuestion(question.ID, query.Projection{})
+	assert.NoError(t, err)
+	assert.Equal(t, &answer.ID, got.AcceptedAnswerID)
+
+	// Устаревшая версия не перезаписывает принятый ответ
+	var conflict *VersionConflictError
+	assert.ErrorAs(t, repo.AcceptAnswer(&models.Question{ID: question.ID, Version: 1}), &conflict)
+}
+
+func TestMemoryRepositoryFilterAndSort(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+
+	author := uuid.New()
+	for _, title := range []string{"Unanswered question", "Popular question", "Recently answered question"} {
+		assert.NoError(t, repo.CreateQuestion(&models.Question{Title: title, UserID: &author}))
+	}
+	assert.NoError(t, repo.CreateQuestion(&models.Question{Title: "Anonymous question"}))
+	for _, questionID := range []uint{2, 2, 3} {
+		assert.NoError(t, repo.CreateAnswer(&models.Answer{QuestionID: questionID, Text: "Answer"}))
+	}
+
+	hasAnswers := true
+	questions, err := repo.GetAllQuestions(query.QuestionSpec{
+		AuthorID:   &author,
+		HasAnswers: &hasAnswers,
+		Sort:       query.SortMostAnswered,
+	}, query.Projection{})
+	assert.NoError(t, err)
+	assert.Len(t, questions, 2)
+	assert.Equal(t, uint(2), questions[0].ID)
+	assert.Equal(t, int64(2), questions[0].AnswerCount)
+	assert.Equal(t, uint(3), questions[1].ID)
+
+	// Последний ответ оставлен на вопрос 3, поэтому он самый активный
+	questions, err = repo.GetAllQuestions(query.QuestionSpec{Sort: query.SortActive}, query.Projection{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), questions[0].ID)
+	assert.True(t, questions[0].LastActivityAt.After(questions[0].CreatedAt))
+
+	questions, err = repo.GetAllQuestions(
+		query.QuestionSpec{Text: "POPULAR", Sort: query.SortOldest}, query.Projection{})
+	assert.NoError(t, err)
+	assert.Len(t, questions, 1)
+	assert.Equal(t, uint(2), questions[0].ID)
+	questions, err = repo.GetAllQuestions(query.QuestionSpec{Limit: 2}, query.Projection{})
+	assert.NoError(t, err)
+	if assert.Len(t, questions, 2) {
+		assert.Equal(t, uint(4), questions[0].ID)
+		assert.Equal(t, uint(3), questions[1].ID)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.InDelta(t, 1.0, similarity("Install Go", "install go!"), 0.001)
+	assert.InDelta(t, 0.0, similarity("Install Go", "PostgreSQL"), 0.001)
+	assert.InDelta(t, 0.0, similarity("", "PostgreSQL"), 0.001)
+	// Значение совпадает с SELECT similarity('word', 'words') в pg_trgm
+	assert.InDelta(t, 4.0/7.0, similarity("word", "words"), 0.001)
+}
+
+func TestMemoryRepositoryProjectionIncludes(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+
+	assert.NoError(t, repo.CreateQuestion(&models.Question{Title: "How to install Go?"}))
+	answer := &models.Answer{QuestionID: 1, Text: "Download it"}
+	assert.NoError(t, repo.CreateAnswer(answer))
+	assert.NoError(t, repo.CreateComment(&models.Comment{
+		ParentType: models.CommentParentQuestion, ParentID: 1, Text: "Which OS?",
+	}))
+	assert.NoError(t, repo.CreateComment(&models.Comment{
+		ParentType: models.CommentParentAnswer, ParentID: answer.ID, Text: "Thanks",
+	}))
+
+	// Без include связанные данные не подгружаются, но счетчики считаются всегда
+	bare, err := repo.GetQuestion(1, query.Projection{})
+	assert.NoError(t, err)
+	assert.Nil(t, bare.Answers)
+	assert.Nil(t, bare.Comments)
+	assert.Equal(t, int64(1), bare.AnswerCount)
+	assert.Equal(t, int64(1), bare.CommentCount)
+
+	full, err := repo.GetQuestion(1, query.Projection{
+		Include: []string{query.IncludeAnswers, query.IncludeComments},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, full.Comments, 1)
+	assert.Len(t, full.Answers, 1)
+	assert.Len(t, full.Answers[0].Comments, 1)
+
+	got, err := repo.GetAnswer(answer.ID, query.Projection{Include: []string{query.IncludeComments}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Thanks", got.Comments[0].Text)
+}
+
+func TestMemoryRepositoryTouchesParents(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+
+	question := &models.Question{Title: "How to install Go?"}
+	assert.NoError(t, repo.CreateQuestion(question))
+	versions := []time.Time{question.UpdatedAt}
+	version := func() time.Time {
+		got, err := repo.GetQuestion(question.ID, query.Projection{})
+		assert.NoError(t, err)
+		return got.UpdatedAt
+	}
+
+	answer := &models.Answer{QuestionID: question.ID, Text: "Download it"}
+	assert.NoError(t, repo.CreateAnswer(answer))
+	versions = append(versions, version())
+
+	comment := &models.Comment{ParentType: models.CommentParentAnswer, ParentID: answer.ID, Text: "Thanks"}
+	assert.NoError(t, repo.CreateComment(comment))
+	versions = append(versions, version())
+	got, err := repo.GetAnswer(answer.ID, query.Projection{})
+	assert.NoError(t, err)
+	assert.True(t, got.UpdatedAt.After(answer.UpdatedAt))
+
+	assert.NoError(t, repo.DeleteComment(comment.ID))
+	versions = append(versions, version())
+	assert.NoError(t, repo.DeleteAnswer(answer.ID, 0))
+	versions = append(versions, version())
+
+	for i := 1; i < len(versions); i++ {
+		assert.True(t, versions[i].After(versions[i-1]), "version %d", i)
+	}
+}
+
+func TestMemoryRepositoryVersions(t *testing.T) {
+	repo := NewMemoryRepository(logrus.New())
+
+	question := &models.Question{Title: "Question"}
+	assert.NoError(t, repo.CreateQuestion(question))
+	assert.Equal(t, 1, question.Version)
+
+	question.Title = "Edited question"
+	assert.NoError(t, repo.UpdateQuestion(question))
+	assert.Equal(t, 2, question.Version)
+
+	// Изменение по устаревшей версии отклоняется
+	stale := &models.Question{ID: question.ID, Version: 1, Title: "Stale edit"}
+	var conflict *VersionConflictError
+	assert.ErrorAs(t, repo.UpdateQuestion(stale), &conflict)
+	assert.ErrorAs(t, repo.DeleteQuestion(question.ID, 1), &conflict)
+
+	stored, err := repo.GetQuestion(question.ID, query.Projection{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Edited question", stored.Title)
+	assert.Equal(t, 2, stored.Version)
+
+	answer := &models.Answer{QuestionID: question.ID, Text: "Answer"}
+	assert.NoError(t, repo.CreateAnswer(answer))
+	answer.Text = "Edited answer"
+	assert.NoError(t, repo.UpdateAnswer(answer))
+	assert.Equal(t, 2, answer.Version)
+	assert.NoError(t, repo.DeleteAnswer(answer.ID, 2))
+
+	assert.NoError(t, repo.DeleteQuestion(question.ID, 2))
+}
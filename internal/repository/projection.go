@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/query"
+)
+
+// Выборки всех полей вопросов и ответов вместе с вычисляемыми счетчиками.
+const (
+	questionWithCounters = "questions.*, " + questionCommentCountExpr + " AS comment_count, " +
+		answerCountExpr + " AS answer_count, " + lastActivityExpr + " AS last_activity_at"
+	answerWithCommentCount = "answers.*, " + answerCommentCountExpr + " AS comment_count"
+)
+
+// questionColumns - выражения SELECT для полей query.QuestionResource.
+var questionColumns = map[string]string{
+	"id":                 "questions.id",
+	"user_id":            "questions.user_id",
+	"title":              "questions.title",
+	"body":               "questions.body",
+	"body_html":          "questions.body",
+	"tags":               "questions.tags",
+	"created_at":         "questions.created_at",
+	"updated_at":         "questions.updated_at",
+	"version":            "questions.version",
+	"last_activity_at":   lastActivityExpr + " AS last_activity_at",
+	"score":              "questions.score",
+	"status":             "questions.status",
+	"close_reason":       "questions.close_reason",
+	"closed_by":          "questions.closed_by",
+	"closed_at":          "questions.closed_at",
+	"duplicate_of":       "questions.duplicate_of",
+	"accepted_answer_id": "questions.accepted_answer_id",
+	"answer_count":       answerCountExpr + " AS answer_count",
+	"comment_count":      questionCommentCountExpr + " AS comment_count",
+}
+
+// answerColumns - выражения SELECT для полей query.AnswerResource.
+var answerColumns = map[string]string{
+	"id":            "answers.id",
+	"question_id":   "answers.question_id",
+	"user_id":       "answers.user_id",
+	"text":          "answers.text",
+	"text_html":     "answers.text",
+	"created_at":    "answers.created_at",
+	"updated_at":    "answers.updated_at",
+	"version":       "answers.version",
+	"comment_count": answerCommentCountExpr + " AS comment_count",
+}
+
+// questionProjection выбирает запрошенные поля вопроса и подгружает запрошенные связанные данные.
+// ID нужен для подгрузки связей, duplicate_of - для перенаправления с дубликата,
+// а version и updated_at - для ETag, поэтому они выбираются всегда.
+func questionProjection(proj query.Projection) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		required := []string{"id", "duplicate_of", "version", "updated_at"}
+		if proj.Includes(query.IncludeAuthor) {
+			required = append(required, "user_id")
+		}
+		db = db.Select(selectColumns(proj, questionColumns, questionWithCounters, required))
+
+		if proj.Includes(query.IncludeAnswers) {
+			db = db.Preload("Answers", preloadAnswers)
+			if proj.Includes(query.IncludeComments) {
+				db = db.Preload("Answers.Comments", preloadComments)
+			}
+		}
+		if proj.Includes(query.IncludeComments) {
+			db = db.Preload("Comments", preloadComments)
+		}
+		return db
+	}
+}
+
+// answerProjection выбирает запрошенные поля ответа и подгружает запрошенные связанные данные.
+// ID, version и updated_at выбираются всегда.
+func answerProjection(proj query.Projection) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		required := []string{"id", "version", "updated_at"}
+		if proj.Includes(query.IncludeAuthor) {
+			required = append(required, "user_id")
+		}
+		db = db.Select(selectColumns(proj, answerColumns, answerWithCommentCount, required))
+
+		if proj.Includes(query.IncludeComments) {
+			db = db.Preload("Comments", preloadComments)
+		}
+		return db
+	}
+}
+
+// selectColumns строит список SELECT для запрошенных полей. Если поля не указаны, возвращается all.
+func selectColumns(proj query.Projection, columns map[string]string, all string, required []string) string {
+	if len(proj.Fields) == 0 {
+		return all
+	}
+
+	var exprs []string
+	seen := make(map[string]bool)
+	for _, field := range append(required, proj.Fields...) {
+		expr, ok := columns[field]
+		if !ok || seen[expr] {
+			continue
+		}
+		seen[expr] = true
+		exprs = append(exprs, expr)
+	}
+	return strings.Join(exprs, ", ")
+}
+
+// preloadAnswers подгружает ответы вместе с количеством комментариев к ним.
+func preloadAnswers(db *gorm.DB) *gorm.DB {
+	return db.Select(answerWithCommentCount).Order("answers.created_at, answers.id")
+}
+
+// preloadComments подгружает комментарии в порядке создания.
+func preloadComments(db *gorm.DB) *gorm.DB {
+	return db.Order("comments.created_at, comments.id")
+}
@@ -1,21 +1,63 @@
 package repository
 
 import (
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
 )
 
 // Repository определяет интерфейс для работы с хранилищем данных.
 type Repository interface {
 	CreateQuestion(question *models.Question) error
-	GetQuestion(id uint) (*models.Question, error)
-	GetAllQuestions() ([]models.Question, error)
-	DeleteQuestion(id uint) error
+	GetQuestion(id uint, proj query.Projection) (*models.Question, error)
+	GetAllQuestions(spec query.QuestionSpec, proj query.Projection) ([]models.Question, error)
+	FindQuestionsByIDs(ids []uint) (map[uint]models.Question, error)
+	UpdateQuestion(question *models.Question) error
+	DeleteQuestion(id uint, version int) error
 	CreateAnswer(answer *models.Answer) error
-	GetAnswer(id uint) (*models.Answer, error)
-	DeleteAnswer(id uint) error
+	GetAnswer(id uint, proj query.Projection) (*models.Answer, error)
+	FindAnswersByQuestionIDs(questionIDs []uint) (map[uint][]models.Answer, error)
+	UpdateAnswer(answer *models.Answer) error
+	DeleteAnswer(id uint, version int) error
+	CreateComment(comment *models.Comment) error
+	GetComment(id uint) (*models.Comment, error)
+	GetComments(parentType string, parentID uint) ([]models.Comment, error)
+	DeleteComment(id uint) error
+	FindSimilarQuestions(title string, threshold float64, limit int) ([]models.SimilarQuestion, error)
+	MarkDuplicate(question *models.Question) error
+	UpdateQuestionStatus(question *models.Question) error
+	// GetQuestionStatusForUpdate возвращает статус вопроса и внутри Transaction блокирует строку вопроса
+	// до конца транзакции, чтобы статус нельзя было изменить, пока транзакция не завершится.
+	GetQuestionStatusForUpdate(id uint) (string, error)
+	AcceptAnswer(question *models.Question) error
+	CreateQuestions(questions []*models.Question) error
+	FindQuestionIDsByTitle(titles []string) (map[string]uint, error)
+	FindQuestionsInBatches(spec query.QuestionSpec, batchSize int, fn func([]models.Question) error) error
+	CreateExternalPosts(source string, posts []ExternalPost) error
+	FindImportedPosts(source string, externalIDs []int64) (map[int64]models.ImportedPost, error)
+	CreateImportedUsers(users []models.ImportedUser) error
+	FindImportedUsers(source string, externalIDs []int64) (map[int64]uuid.UUID, error)
+	// Transaction выполняет fn в транзакции: изменения через переданный fn репозиторий сохраняются,
+	// только если fn не вернула ошибку.
+	Transaction(fn func(repo Repository) error) error
+	// CreateOutboxMessage записывает событие в outbox; вызывается внутри Transaction вместе с изменением.
+	CreateOutboxMessage(msg *models.OutboxMessage) error
+}
+
+// ExternalPost - вопрос или ответ внешнего источника, который сохраняется вместе со связью с ним.
+// Заполнен либо Question, либо Answer. Ответ относится к вопросу Parent из той же пачки,
+// а если Parent не задан - к уже сохраненному вопросу Answer.QuestionID.
+// Accepted отмечает ответ, принятый автором вопроса.
+type ExternalPost struct {
+	ExternalID int64
+	Question   *models.Question
+	Answer     *models.Answer
+	Parent     *models.Question
+	Accepted   bool
 }
 
 // dbRepository - реализация Repository для работы с базой данных.
@@ -36,10 +78,11 @@ func (r *dbRepository) CreateQuestion(question *models.Question) error {
 }
 
 // GetQuestion получает вопрос из базы данных по его ID.
-func (r *dbRepository) GetQuestion(id uint) (*models.Question, error) {
-	r.logger.Debugf("Getting question with ID: %d", id)
+// Выбираются только запрошенные поля и подгружаются только запрошенные связанные данные.
+func (r *dbRepository) GetQuestion(id uint, proj query.Projection) (*models.Question, error) {
+	r.logger.Debugf("Getting question with ID: %d (%+v)", id, proj)
 	var question models.Question
-	err := r.db.Preload("Answers").First(&question, id).Error
+	err := r.db.Scopes(questionProjection(proj)).First(&question, id).Error
 	return &question, err
 }
 
@@ -49,30 +92,388 @@ func (r *dbRepository) CreateAnswer(answer *models.Answer) error {
 	return r.db.Create(answer).Error
 }
 
-// GetAllQuestions получает все вопросы из базы данных.
-func (r *dbRepository) GetAllQuestions() ([]models.Question, error) {
-	r.logger.Debug("Getting all questions")
+// GetAllQuestions получает из базы данных все вопросы, удовлетворяющие спецификации.
+func (r *dbRepository) GetAllQuestions(spec query.QuestionSpec, proj query.Projection) ([]models.Question, error) {
+	r.logger.Debugf("Getting all questions: %+v (%+v)", spec, proj)
 	var questions []models.Question
-	err := r.db.Preload("Answers").Find(&questions).Error
+	err := r.db.Scopes(questionScopes(spec)...).
+		Scopes(questionProjection(proj)).
+		Find(&questions).Error
 	return questions, err
 }
 
+// FindQuestionsByIDs получает из базы данных вопросы с ID из ids вместе со счетчиками (ID -> вопрос).
+// Несуществующие вопросы в результат не попадают.
+func (r *dbRepository) FindQuestionsByIDs(ids []uint) (map[uint]models.Question, error) {
+	r.logger.Debugf("Finding %d questions by ID", len(ids))
+	questions := make(map[uint]models.Question, len(ids))
+	if len(ids) == 0 {
+		return questions, nil
+	}
+	var rows []models.Question
+	err := r.db.Select(questionWithCounters).Where("questions.id IN ?", ids).Find(&rows).Error
+	for _, row := range rows {
+		questions[row.ID] = row
+	}
+	return questions, err
+}
+
+// UpdateQuestion сохраняет заголовок и тело вопроса, если его версия не изменилась с момента чтения.
+func (r *dbRepository) UpdateQuestion(question *models.Question) error {
+	r.logger.Debugf("Updating question %d at version %d", question.ID, question.Version)
+	return updateVersioned(r.db, question, "question", question.ID, &question.Version, map[string]any{
+		"title": question.Title,
+		"body":  question.Body,
+	})
+}
+
 // DeleteQuestion удаляет вопрос из базы данных по его ID.
-func (r *dbRepository) DeleteQuestion(id uint) error {
-	r.logger.Debugf("Deleting question with ID: %d", id)
-	return r.db.Delete(&models.Question{}, id).Error
+// Если version не равна нулю, вопрос удаляется, только если он все еще в этой версии.
+func (r *dbRepository) DeleteQuestion(id uint, version int) error {
+	r.logger.Debugf("Deleting question with ID: %d (version %d)", id, version)
+	return r.deleteVersioned(&models.Question{}, "question", id, version)
 }
 
 // GetAnswer получает ответ из базы данных по его ID.
-func (r *dbRepository) GetAnswer(id uint) (*models.Answer, error) {
-	r.logger.Debugf("Getting answer with ID: %d", id)
+func (r *dbRepository) GetAnswer(id uint, proj query.Projection) (*models.Answer, error) {
+	r.logger.Debugf("Getting answer with ID: %d (%+v)", id, proj)
 	var answer models.Answer
-	err := r.db.First(&answer, id).Error
+	err := r.db.Scopes(answerProjection(proj)).First(&answer, id).Error
 	return &answer, err
 }
 
+// FindAnswersByQuestionIDs получает из базы данных одним запросом ответы на вопросы с ID из questionIDs
+// (ID вопроса -> ответы в порядке создания). Вопросы без ответов в результат не попадают.
+func (r *dbRepository) FindAnswersByQuestionIDs(questionIDs []uint) (map[uint][]models.Answer, error) {
+	r.logger.Debugf("Finding answers to %d questions", len(questionIDs))
+	answers := make(map[uint][]models.Answer, len(questionIDs))
+	if len(questionIDs) == 0 {
+		return answers, nil
+	}
+	var rows []models.Answer
+	err := r.db.Scopes(preloadAnswers).Where("answers.question_id IN ?", questionIDs).Find(&rows).Error
+	for _, row := range rows {
+		answers[row.QuestionID] = append(answers[row.QuestionID], row)
+	}
+	return answers, err
+}
+
+// UpdateAnswer сохраняет текст ответа, если его версия не изменилась с момента чтения.
+func (r *dbRepository) UpdateAnswer(answer *models.Answer) error {
+	r.logger.Debugf("Updating answer %d at version %d", answer.ID, answer.Version)
+	return updateVersioned(r.db, answer, "answer", answer.ID, &answer.Version, map[string]any{
+		"text": answer.Text,
+	})
+}
+
 // DeleteAnswer удаляет ответ из базы данных по его ID.
-func (r *dbRepository) DeleteAnswer(id uint) error {
-	r.logger.Debugf("Deleting answer with ID: %d", id)
-	return r.db.Delete(&models.Answer{}, id).Error
+// Если version не равна нулю, ответ удаляется, только если он все еще в этой версии.
+func (r *dbRepository) DeleteAnswer(id uint, version int) error {
+	r.logger.Debugf("Deleting answer with ID: %d (version %d)", id, version)
+	return r.deleteVersioned(&models.Answer{}, "answer", id, version)
+}
+
+// CreateComment создает новый комментарий в базе данных.
+func (r *dbRepository) CreateComment(comment *models.Comment) error {
+	r.logger.Debugf("Creating comment: %+v", comment)
+	return r.db.Create(comment).Error
+}
+
+// GetComment получает комментарий по ID.
+func (r *dbRepository) GetComment(id uint) (*models.Comment, error) {
+	r.logger.Debugf("Getting comment with ID: %d", id)
+	var comment models.Comment
+	err := r.db.First(&comment, id).Error
+	return &comment, err
+}
+
+// GetComments получает комментарии к вопросу или ответу в порядке создания.
+func (r *dbRepository) GetComments(parentType string, parentID uint) ([]models.Comment, error) {
+	r.logger.Debugf("Getting comments for %s with ID: %d", parentType, parentID)
+	var comments []models.Comment
+	err := r.db.Where("parent_type = ? AND parent_id = ?", parentType, parentID).
+		Order("created_at, id").
+		Find(&comments).Error
+	return comments, err
+}
+
+// DeleteComment удаляет комментарий из базы данных по его ID.
+func (r *dbRepository) DeleteComment(id uint) error {
+	r.logger.Debugf("Deleting comment with ID: %d", id)
+	return r.db.Delete(&models.Comment{}, id).Error
+}
+
+// FindSimilarQuestions ищет вопросы с похожим заголовком с помощью pg_trgm.
+// Вопросы, уже закрытые как дубликаты, в выборку не попадают.
+func (r *dbRepository) FindSimilarQuestions(
+	title string, threshold float64, limit int,
+) ([]models.SimilarQuestion, error) {
+	r.logger.Debugf("Finding questions similar to %q", title)
+	var similar []models.SimilarQuestion
+	err := r.db.Model(&models.Question{}).
+		Select("questions.*, similarity(title, ?) AS similarity", title).
+		Where("duplicate_of IS NULL").
+		Where("title % ?", title). // использует GIN-индекс по триграммам
+		Where("similarity(title, ?) >= ?", title, threshold).
+		Order("similarity DESC").
+		Limit(limit).
+		Find(&similar).Error
+	return similar, err
+}
+
+// CreateQuestions создает вопросы вместе с их ответами в одной транзакции:
+// либо сохраняются все вопросы, либо ни одного. Заданные время создания и авторы сохраняются.
+func (r *dbRepository) CreateQuestions(questions []*models.Question) error {
+	r.logger.Debugf("Creating %d questions", len(questions))
+	if len(questions) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(questions).Error
+	})
+}
+
+// FindQuestionIDsByTitle возвращает ID вопросов с заголовками из titles (заголовок -> ID).
+// Если вопросов с одним заголовком несколько, возвращается самый ранний.
+func (r *dbRepository) FindQuestionIDsByTitle(titles []string) (map[string]uint, error) {
+	r.logger.Debugf("Finding questions by %d titles", len(titles))
+	ids := make(map[string]uint, len(titles))
+	if len(titles) == 0 {
+		return ids, nil
+	}
+	var rows []struct {
+		ID    uint
+		Title string
+	}
+	err := r.db.Model(&models.Question{}).
+		Select("MIN(id) AS id, title").
+		Where("title IN ?", titles).
+		Group("title").
+		Find(&rows).Error
+	for _, row := range rows {
+		ids[row.Title] = row.ID
+	}
+	return ids, err
+}
+
+// FindQuestionsInBatches передает fn вопросы, удовлетворяющие фильтрам спецификации, вместе с ответами
+// пачками по batchSize в порядке возрастания ID. Сортировка спецификации не учитывается: каждая пачка
+// выбирается по ключу (ID больше последнего в предыдущей пачке), и в памяти находится только она.
+// Ошибка fn прекращает выборку и возвращается.
+func (r *dbRepository) FindQuestionsInBatches(
+	spec query.QuestionSpec, batchSize int, fn func([]models.Question) error,
+) error {
+	r.logger.Debugf("Finding questions in batches of %d: %+v", batchSize, spec)
+	var batch []models.Question
+	return r.db.Scopes(questionFilters(spec)...).
+		Preload("Answers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("answers.id")
+		}).
+		FindInBatches(&batch, batchSize, func(*gorm.DB, int) error {
+			return fn(batch)
+		}).Error
+}
+
+// CreateExternalPosts сохраняет вопросы и ответы внешнего источника source и связи с ними в одной транзакции:
+// сначала вопросы, затем ответы (в том числе к только что созданным вопросам), затем принятые ответы.
+// Заданные время создания и авторы сохраняются.
+func (r *dbRepository) CreateExternalPosts(source string, posts []ExternalPost) error {
+	r.logger.Debugf("Creating %d posts from %s", len(posts), source)
+	if len(posts) == 0 {
+		return nil
+	}
+	var questions []*models.Question
+	var answers []*models.Answer
+	for _, post := range posts {
+		if post.Question != nil {
+			questions = append(questions, post.Question)
+		} else {
+			answers = append(answers, post.Answer)
+		}
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(questions) > 0 {
+			if err := tx.Create(questions).Error; err != nil {
+				return err
+			}
+		}
+		for _, post := range posts {
+			if post.Parent != nil {
+				post.Answer.QuestionID = post.Parent.ID
+			}
+		}
+		if len(answers) > 0 {
+			if err := tx.Create(answers).Error; err != nil {
+				return err
+			}
+		}
+
+		mappings := make([]models.ImportedPost, 0, len(posts))
+		for _, post := range posts {
+			mapping := models.ImportedPost{Source: source, ExternalID: post.ExternalID}
+			if post.Question != nil {
+				mapping.Kind, mapping.EntityID = models.ImportedKindQuestion, post.Question.ID
+			} else {
+				mapping.Kind, mapping.EntityID = models.ImportedKindAnswer, post.Answer.ID
+			}
+			mappings = append(mappings, mapping)
+			if !post.Accepted {
+				continue
+			}
+			// Только колонка: принятие ответа не меняет версию вопроса
+			err := tx.Model(&models.Question{}).Where("id = ?", post.Answer.QuestionID).
+				UpdateColumn("accepted_answer_id", post.Answer.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Create(&mappings).Error
+	})
+}
+
+// FindImportedPosts возвращает связи постов источника source с ID из externalIDs (ID поста -> связь).
+func (r *dbRepository) FindImportedPosts(source string, externalIDs []int64) (map[int64]models.ImportedPost, error) {
+	r.logger.Debugf("Finding %d imported posts from %s", len(externalIDs), source)
+	posts := make(map[int64]models.ImportedPost, len(externalIDs))
+	if len(externalIDs) == 0 {
+		return posts, nil
+	}
+	var rows []models.ImportedPost
+	err := r.db.Where("source = ? AND external_id IN ?", source, externalIDs).Find(&rows).Error
+	for _, row := range rows {
+		posts[row.ExternalID] = row
+	}
+	return posts, err
+}
+
+// CreateImportedUsers сохраняет связи пользователей внешнего источника.
+func (r *dbRepository) CreateImportedUsers(users []models.ImportedUser) error {
+	r.logger.Debugf("Creating %d imported users", len(users))
+	if len(users) == 0 {
+		return nil
+	}
+	return r.db.Create(&users).Error
+}
+
+// FindImportedUsers возвращает ID пользователей источника source с ID из externalIDs
+// (ID во внешнем источнике -> ID пользователя).
+func (r *dbRepository) FindImportedUsers(source string, externalIDs []int64) (map[int64]uuid.UUID, error) {
+	r.logger.Debugf("Finding %d imported users from %s", len(externalIDs), source)
+	users := make(map[int64]uuid.UUID, len(externalIDs))
+	if len(externalIDs) == 0 {
+		return users, nil
+	}
+	var rows []models.ImportedUser
+	err := r.db.Select("external_id", "user_id").
+		Where("source = ? AND external_id IN ?", source, externalIDs).Find(&rows).Error
+	for _, row := range rows {
+		users[row.ExternalID] = row.UserID
+	}
+	return users, err
+}
+
+// MarkDuplicate сохраняет закрытие вопроса как дубликата question.DuplicateOf,
+// если версия вопроса не изменилась с момента чтения.
+// Вопросы, ранее помеченные дубликатами закрываемого, перенаправляются на новый исходный вопрос.
+func (r *dbRepository) MarkDuplicate(question *models.Question) error {
+	r.logger.Debugf("Marking question %d as duplicate of %v", question.ID, question.DuplicateOf)
+	version := question.Version
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Question{}).
+			Where("duplicate_of = ?", question.ID).
+			Updates(map[string]any{
+				"duplicate_of": question.DuplicateOf,
+				"version":      gorm.Expr("version + 1"),
+			}).Error; err != nil {
+			return err
+		}
+		return updateVersioned(tx, question, "question", question.ID, &version, questionStatusValues(question))
+	})
+	if err == nil {
+		question.Version = version
+	}
+	return err
+}
+
+// UpdateQuestionStatus сохраняет статус вопроса и сведения о его закрытии,
+// если версия вопроса не изменилась с момента чтения.
+func (r *dbRepository) UpdateQuestionStatus(question *models.Question) error {
+	r.logger.Debugf("Updating status of question %d to %s", question.ID, question.Status)
+	return updateVersioned(r.db, question, "question", question.ID, &question.Version,
+		questionStatusValues(question))
+}
+
+// GetQuestionStatusForUpdate читает статус вопроса с блокировкой строки (SELECT ... FOR UPDATE).
+func (r *dbRepository) GetQuestionStatusForUpdate(id uint) (string, error) {
+	r.logger.Debugf("Locking question %d to check its status", id)
+	var question models.Question
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("status").Where("id = ?", id).Take(&question).Error
+	return question.Status, err
+}
+
+// AcceptAnswer сохраняет принятый ответ question.AcceptedAnswerID, если версия вопроса не изменилась
+// с момента чтения.
+func (r *dbRepository) AcceptAnswer(question *models.Question) error {
+	r.logger.Debugf("Accepting answer %v of question %d", question.AcceptedAnswerID, question.ID)
+	return updateVersioned(r.db, question, "question", question.ID, &question.Version, map[string]any{
+		"accepted_answer_id": question.AcceptedAnswerID,
+	})
+}
+
+// questionStatusValues - значения колонок, описывающих жизненный цикл вопроса.
+// Пустые значения тоже записываются, чтобы при повторном открытии сбросить сведения о закрытии.
+func questionStatusValues(question *models.Question) map[string]any {
+	return map[string]any{
+		"status":       question.Status,
+		"close_reason": question.CloseReason,
+		"closed_by":    question.ClosedBy,
+		"closed_at":    question.ClosedAt,
+		"duplicate_of": question.DuplicateOf,
+	}
+}
+
+// updateVersioned сохраняет values, только если запись все еще в версии *version, и увеличивает версию.
+// При успехе *version тоже увеличивается, иначе возвращается *VersionConflictError.
+func updateVersioned(
+	db *gorm.DB, model any, entity string, id uint, version *int, values map[string]any,
+) error {
+	values["version"] = gorm.Expr("version + 1")
+	result := db.Model(model).Where("version = ?", *version).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &VersionConflictError{Entity: entity, ID: id, Version: *version}
+	}
+	*version++
+	return nil
+}
+
+// deleteVersioned удаляет запись по ID. Если version не равна нулю, запись удаляется,
+// только если она все еще в этой версии, иначе возвращается *VersionConflictError.
+func (r *dbRepository) deleteVersioned(model any, entity string, id uint, version int) error {
+	if version == 0 {
+		return r.db.Delete(model, id).Error
+	}
+	result := r.db.Where("version = ?", version).Delete(model, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &VersionConflictError{Entity: entity, ID: id, Version: version}
+	}
+	return nil
+}
+
+// Transaction выполняет fn в транзакции базы данных.
+func (r *dbRepository) Transaction(fn func(repo Repository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&dbRepository{db: tx, logger: r.logger})
+	})
+}
+
+// CreateOutboxMessage записывает событие в таблицу outbox.
+func (r *dbRepository) CreateOutboxMessage(msg *models.OutboxMessage) error {
+	r.logger.Debugf("Creating outbox message %s for %s %d", msg.EventType, msg.AggregateType, msg.AggregateID)
+	return r.db.Create(msg).Error
 }
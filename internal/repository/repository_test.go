@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -12,8 +13,19 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
 )
 
+// Выборки вопросов и ответов вместе с подсчетом комментариев.
+const (
+	selectQuestions = `SELECT questions\.\*, \(SELECT COUNT\(\*\) FROM comments .+\) AS comment_count, ` +
+		`.+ AS answer_count, .+ AS last_activity_at FROM "questions"`
+	selectAnswers = `SELECT answers\.\*, \(SELECT COUNT\(\*\) FROM comments .+\) AS comment_count FROM "answers"`
+)
+
+// withAnswers - проекция вопроса со встроенными ответами, как в API по умолчанию.
+var withAnswers = query.Projection{Include: []string{query.IncludeAnswers}}
+
 func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
 	sqldb, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -31,12 +43,16 @@ func TestCreateQuestion(t *testing.T) {
 	repo := NewRepository(gormDB, logrus.New())
 
 	question := &models.Question{
-		Text: "Test Question",
+		Title:  "Test Question",
+		Body:   "Test **body**",
+		Tags:   models.Tags{"go", "null"},
+		Status: models.QuestionStatusOpen,
 	}
 
 	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO "questions"`).
-		WithArgs(question.Text, sqlmock.AnyArg()).
+		WithArgs(nil, question.Title, question.Body, `{"go","null"}`, sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 0,
+			question.Status, "", nil, nil, nil, nil).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
 	mock.ExpectCommit()
 
@@ -52,28 +68,29 @@ func TestGetQuestion(t *testing.T) {
 
 	expectedQuestion := &models.Question{
 		ID:        1,
-		Text:      "Test Question",
+		Title:     "Test Question",
 		CreatedAt: time.Now(),
 	}
 
 	mock.ExpectQuery(
-		`SELECT \* FROM "questions" WHERE "questions"."id" = \$1 ORDER BY "questions"."id" LIMIT \$2`).
+		selectQuestions+` WHERE "questions"."id" = \$1 ORDER BY "questions"."id" LIMIT \$2`).
 		WithArgs(1, 1).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "text", "created_at"}).
-			AddRow(expectedQuestion.ID, expectedQuestion.Text, expectedQuestion.CreatedAt))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "body", "created_at", "comment_count"}).
+			AddRow(expectedQuestion.ID, expectedQuestion.Title, "", expectedQuestion.CreatedAt, 3))
 
 	mock.ExpectQuery(
-		`SELECT \* FROM "answers" WHERE "answers"."question_id" = \$1`).
+		selectAnswers + ` WHERE "answers"."question_id" = \$1`).
 		WithArgs(1).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "question_id", "user_id", "text", "created_at",
 		})) // пустой результат
 
-	question, err := repo.GetQuestion(1)
+	question, err := repo.GetQuestion(1, withAnswers)
 	assert.NoError(t, err)
 	assert.NotNil(t, question)
 	assert.Equal(t, expectedQuestion.ID, question.ID)
-	assert.Equal(t, expectedQuestion.Text, question.Text)
+	assert.Equal(t, expectedQuestion.Title, question.Title)
+	assert.Equal(t, int64(3), question.CommentCount)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -82,14 +99,14 @@ func TestGetQuestionNotFound(t *testing.T) {
 	repo := NewRepository(gormDB, logrus.New())
 
 	mock.ExpectQuery(
-		`SELECT \* FROM "questions" WHERE "questions"."id" = \$1 ORDER BY "questions"."id" LIMIT \$2`). // <-- Изменено
+		selectQuestions+` WHERE "questions"."id" = \$1 ORDER BY "questions"."id" LIMIT \$2`). // <-- Изменено
 		WithArgs(
 			999,
 			1,
 		).
 		WillReturnError(gorm.ErrRecordNotFound) // Возвращаем ошибку GORM
 
-	question, err := repo.GetQuestion(999)
+	question, err := repo.GetQuestion(999, withAnswers)
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
 	assert.NotNil(t, question)            // GORM возвращает пустой объект, не nil
@@ -97,31 +114,188 @@ func TestGetQuestionNotFound(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetQuestionProjection(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectQuery(
+		`SELECT questions.id, questions.duplicate_of, questions.version, questions.updated_at, questions.title `+
+			`FROM "questions" `+
+			`WHERE "questions"."id" = \$1`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "duplicate_of", "title"}).AddRow(1, nil, "Q1"))
+	mock.ExpectQuery(
+		`SELECT \* FROM "comments" WHERE "parent_type" = \$1 AND "comments"."parent_id" = \$2 `+
+			`ORDER BY comments.created_at, comments.id`).
+		WithArgs(models.CommentParentQuestion, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_type", "parent_id", "text"}).
+			AddRow(7, models.CommentParentQuestion, 1, "Which OS?"))
+
+	question, err := repo.GetQuestion(1, query.Projection{
+		Fields:  []string{"title"},
+		Include: []string{query.IncludeComments},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Q1", question.Title)
+	assert.Nil(t, question.Answers)
+	assert.Len(t, question.Comments, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetAllQuestions(t *testing.T) {
 	gormDB, mock := newMockDB(t)
 	repo := NewRepository(gormDB, logrus.New())
 
-	q1 := models.Question{ID: 1, Text: "Q1", CreatedAt: time.Now()}
-	q2 := models.Question{ID: 2, Text: "Q2", CreatedAt: time.Now()}
+	q1 := models.Question{ID: 1, Title: "Q1", CreatedAt: time.Now()}
+	q2 := models.Question{ID: 2, Title: "Q2", CreatedAt: time.Now()}
 
 	mock.ExpectQuery(
-		`SELECT \* FROM "questions"`).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "text", "created_at"}).
-			AddRow(q1.ID, q1.Text, q1.CreatedAt).
-			AddRow(q2.ID, q2.Text, q2.CreatedAt))
+		selectQuestions).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "created_at"}).
+			AddRow(q1.ID, q1.Title, q1.CreatedAt).
+			AddRow(q2.ID, q2.Title, q2.CreatedAt))
 
 	mock.ExpectQuery(
-		`SELECT \* FROM "answers" WHERE "answers"."question_id" IN \(\$1,\$2\)`).
+		selectAnswers+` WHERE "answers"."question_id" IN \(\$1,\$2\)`).
 		WithArgs(1, 2).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "question_id", "user_id", "text", "created_at",
 		})) // пустой результат
 
-	questions, err := repo.GetAllQuestions()
+	questions, err := repo.GetAllQuestions(query.QuestionSpec{}, withAnswers)
+	assert.NoError(t, err)
+	assert.Len(t, questions, 2)
+	assert.Equal(t, q1.Title, questions[0].Title)
+	assert.Equal(t, q2.Title, questions[1].Title)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllQuestionsByStatus(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectQuery(
+		selectQuestions+` WHERE questions.status IN \(\$1,\$2\)`).
+		WithArgs(models.QuestionStatusClosed, models.QuestionStatusLocked).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "status"}).
+			AddRow(1, "Q1", models.QuestionStatusClosed))
+	mock.ExpectQuery(
+		selectAnswers + ` WHERE "answers"."question_id" = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question_id"}))
+
+	questions, err := repo.GetAllQuestions(query.QuestionSpec{
+		Statuses: []string{models.QuestionStatusClosed, models.QuestionStatusLocked},
+	}, withAnswers)
+	assert.NoError(t, err)
+	assert.Len(t, questions, 1)
+	assert.Equal(t, models.QuestionStatusClosed, questions[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllQuestionsFilteredAndSorted(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	author := uuid.New()
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	hasAnswers, minAnswers := true, int64(2)
+
+	mock.ExpectQuery(
+		selectQuestions+` WHERE questions.created_at >= \$1 AND questions.user_id = \$2 `+
+			`AND EXISTS \(SELECT 1 FROM answers WHERE answers.question_id = questions.id\) `+
+			`AND \(SELECT COUNT\(\*\) FROM answers WHERE answers.question_id = questions.id\) >= \$3 `+
+			`AND \(questions.title ILIKE \$4 OR questions.body ILIKE \$5\) `+
+			`ORDER BY COALESCE\(.+\) DESC, questions.id DESC`).
+		WithArgs(after, author, minAnswers, `%100\%%`, `%100\%%`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}))
+
+	questions, err := repo.GetAllQuestions(query.QuestionSpec{
+		CreatedAfter: &after,
+		AuthorID:     &author,
+		HasAnswers:   &hasAnswers,
+		MinAnswers:   &minAnswers,
+		Text:         "100%",
+		Sort:         query.SortActive,
+	}, query.Projection{})
+	assert.NoError(t, err)
+	assert.Empty(t, questions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllQuestionsByTags(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectQuery(
+		selectQuestions + ` WHERE questions.tags @> \$1 ORDER BY`).
+		WithArgs(`{"go","postgresql"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "tags"}).
+			AddRow(1, "Q1", `{go,postgresql,"null"}`))
+
+	questions, err := repo.GetAllQuestions(query.QuestionSpec{Tags: []string{"go", "postgresql"}}, query.Projection{})
+	assert.NoError(t, err)
+	if assert.Len(t, questions, 1) {
+		assert.Equal(t, models.Tags{"go", "postgresql", "null"}, questions[0].Tags)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllQuestionsLimit(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectQuery(
+		selectQuestions + ` ORDER BY questions.created_at DESC, questions.id DESC LIMIT \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(3, "Q3").AddRow(2, "Q2"))
+
+	questions, err := repo.GetAllQuestions(query.QuestionSpec{Sort: query.SortNewest, Limit: 2}, query.Projection{})
 	assert.NoError(t, err)
 	assert.Len(t, questions, 2)
-	assert.Equal(t, q1.Text, questions[0].Text)
-	assert.Equal(t, q2.Text, questions[1].Text)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindQuestionsInBatches(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Каждая следующая пачка выбирается по ключу: ID больше последнего в предыдущей
+	mock.ExpectQuery(
+		`SELECT \* FROM "questions" WHERE questions.created_at >= \$1 ORDER BY "questions"."id" LIMIT \$2`).
+		WithArgs(after, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "Q1").AddRow(2, "Q2"))
+	mock.ExpectQuery(
+		`SELECT \* FROM "answers" WHERE "answers"."question_id" IN \(\$1,\$2\) ORDER BY answers.id`).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question_id", "text"}).AddRow(1, 2, "A1"))
+	mock.ExpectQuery(
+		`SELECT \* FROM "questions" WHERE "questions"."id" > \$1 AND questions.created_at >= \$2 `+
+			`ORDER BY "questions"."id" LIMIT \$3`).
+		WithArgs(2, after, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(3, "Q3"))
+	mock.ExpectQuery(
+		`SELECT \* FROM "answers" WHERE "answers"."question_id" = \$1 ORDER BY answers.id`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question_id"}))
+
+	var batches [][]string
+	err := repo.FindQuestionsInBatches(query.QuestionSpec{CreatedAfter: &after}, 2,
+		func(questions []models.Question) error {
+			var titles []string
+			for _, q := range questions {
+				titles = append(titles, q.Title)
+			}
+			batches = append(batches, titles)
+			if len(questions) == 2 {
+				assert.Len(t, questions[1].Answers, 1)
+			}
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Q1", "Q2"}, {"Q3"}}, batches)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -135,11 +309,63 @@ func TestDeleteQuestion(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
-	err := repo.DeleteQuestion(1)
+	err := repo.DeleteQuestion(1, 0)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUpdateQuestion(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "questions" SET "body"=\$1,"title"=\$2,"version"=version \+ 1,"updated_at"=\$3 `+
+		`WHERE version = \$4 AND "id" = \$5`).
+		WithArgs("New body", "New title", sqlmock.AnyArg(), 2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	question := &models.Question{ID: 1, Version: 2, Title: "New title", Body: "New body"}
+	err := repo.UpdateQuestion(question)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, question.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateQuestionVersionConflict(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "questions" SET`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	question := &models.Question{ID: 1, Version: 2, Title: "New title"}
+	err := repo.UpdateQuestion(question)
+	var conflict *VersionConflictError
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, VersionConflictError{Entity: "question", ID: 1, Version: 2}, *conflict)
+	assert.Equal(t, 2, question.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteQuestionVersioned(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "questions" WHERE version = \$1 AND "questions"."id" = \$2`).
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.DeleteQuestion(1, 2)
+	var conflict *VersionConflictError
+	assert.ErrorAs(t, err, &conflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCreateAnswer(t *testing.T) {
 	gormDB, mock := newMockDB(t)
 	repo := NewRepository(gormDB, logrus.New())
@@ -152,7 +378,7 @@ func TestCreateAnswer(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectQuery(`INSERT INTO "answers"`).
-		WithArgs(answer.QuestionID, sqlmock.AnyArg(), answer.Text, sqlmock.AnyArg()).
+		WithArgs(answer.QuestionID, sqlmock.AnyArg(), answer.Text, sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
 	mock.ExpectCommit()
 
@@ -175,7 +401,7 @@ func TestGetAnswer(t *testing.T) {
 	}
 
 	mock.ExpectQuery(
-		`SELECT \* FROM "answers" WHERE "answers"."id" = \$1 ORDER BY "answers"."id" LIMIT \$2`). // <-- Изменено
+		selectAnswers+` WHERE "answers"."id" = \$1 ORDER BY "answers"."id" LIMIT \$2`). // <-- Изменено
 		WithArgs(
 			1,
 			1,
@@ -184,7 +410,7 @@ func TestGetAnswer(t *testing.T) {
 			AddRow(expectedAnswer.ID, expectedAnswer.QuestionID, expectedAnswer.UserID,
 				expectedAnswer.Text, expectedAnswer.CreatedAt))
 
-	answer, err := repo.GetAnswer(1)
+	answer, err := repo.GetAnswer(1, query.Projection{})
 	assert.NoError(t, err)
 	assert.NotNil(t, answer)
 	assert.Equal(t, expectedAnswer.ID, answer.ID)
@@ -202,7 +428,367 @@ func TestDeleteAnswer(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
-	err := repo.DeleteAnswer(1)
+	err := repo.DeleteAnswer(1, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateComment(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	comment := &models.Comment{
+		ParentType: models.CommentParentAnswer,
+		ParentID:   1,
+		UserID:     uuid.New(),
+		Text:       "Test Comment",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "comments"`).
+		WithArgs(comment.ParentType, comment.ParentID, sqlmock.AnyArg(), comment.Text, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+	mock.ExpectCommit()
+
+	err := repo.CreateComment(comment)
 	assert.NoError(t, err)
+	assert.Equal(t, uint(1), comment.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetComments(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectQuery(
+		`SELECT \* FROM "comments" WHERE parent_type = \$1 AND parent_id = \$2 ORDER BY created_at, id`).
+		WithArgs(models.CommentParentQuestion, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_type", "parent_id", "user_id", "text", "created_at"}).
+			AddRow(1, models.CommentParentQuestion, 1, uuid.New(), "C1", time.Now()).
+			AddRow(2, models.CommentParentQuestion, 1, uuid.New(), "C2", time.Now()))
+
+	comments, err := repo.GetComments(models.CommentParentQuestion, 1)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "C1", comments[0].Text)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetComment(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectQuery(`SELECT \* FROM "comments" WHERE "comments"."id" = \$1 ORDER BY "comments"."id" LIMIT \$2`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_type", "parent_id", "user_id", "text", "created_at"}).
+			AddRow(1, models.CommentParentAnswer, 2, uuid.New(), "C1", time.Now()))
+
+	comment, err := repo.GetComment(1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.CommentParentAnswer, comment.ParentType)
+	assert.Equal(t, uint(2), comment.ParentID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteComment(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "comments" WHERE "comments"."id" = \$1`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.DeleteComment(1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateQuestions(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	questions := []*models.Question{
+		{Title: "First question", Status: models.QuestionStatusOpen, CreatedAt: createdAt, UpdatedAt: createdAt,
+			Answers: []models.Answer{{UserID: uuid.New(), Text: "Answer"}}},
+		{Title: "Second question", Status: models.QuestionStatusOpen},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "questions" .+ VALUES \(.+\),\(.+\) RETURNING "id"`).
+		WithArgs(nil, "First question", "", "{}", createdAt, createdAt, 1, 0, models.QuestionStatusOpen, "", nil, nil,
+			nil, nil, nil, "Second question", "", "{}", sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 0,
+			models.QuestionStatusOpen, "", nil, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectQuery(`INSERT INTO "answers" .+ ON CONFLICT`).
+		WithArgs(1, sqlmock.AnyArg(), "Answer", sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.CreateQuestions(questions))
+	assert.Equal(t, uint(1), questions[0].ID)
+	assert.Equal(t, uint(2), questions[1].ID)
+	assert.Equal(t, uint(1), questions[0].Answers[0].QuestionID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindQuestionIDsByTitle(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectQuery(`SELECT MIN\(id\) AS id, title FROM "questions" WHERE title IN \(\$1,\$2\) GROUP BY "title"`).
+		WithArgs("First question", "Second question").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(3, "First question"))
+
+	ids, err := repo.FindQuestionIDsByTitle([]string{"First question", "Second question"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uint{"First question": 3}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindSimilarQuestions(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	title := "How to install Go?"
+
+	mock.ExpectQuery(
+		`SELECT questions\.\*, similarity\(title, \$1\) AS similarity FROM "questions" `+
+			`WHERE duplicate_of IS NULL AND title % \$2 AND similarity\(title, \$3\) >= \$4 `+
+			`ORDER BY similarity DESC LIMIT \$5`).
+		WithArgs(title, title, title, 0.4, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "created_at", "similarity"}).
+			AddRow(3, "How do I install Go?", time.Now(), 0.75))
+
+	similar, err := repo.FindSimilarQuestions(title, 0.4, 5)
+	assert.NoError(t, err)
+	assert.Len(t, similar, 1)
+	assert.Equal(t, uint(3), similar[0].ID)
+	assert.InDelta(t, 0.75, similar[0].Similarity, 0.001)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkDuplicate(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	moderator, closedAt, original := uuid.New(), time.Now(), uint(1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(
+		`UPDATE "questions" SET "duplicate_of"=\$1,"version"=version \+ 1,"updated_at"=\$2 WHERE duplicate_of = \$3`).
+		WithArgs(1, sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(
+		`UPDATE "questions" SET "close_reason"=\$1,"closed_at"=\$2,"closed_by"=\$3,"duplicate_of"=\$4,"status"=\$5,`+
+			`"version"=version \+ 1,"updated_at"=\$6 WHERE version = \$7 AND "id" = \$8`).
+		WithArgs(
+			models.CloseReasonDuplicate, closedAt, moderator, 1, models.QuestionStatusClosed, sqlmock.AnyArg(), 3, 2,
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.MarkDuplicate(&models.Question{
+		ID:          2,
+		Version:     3,
+		Status:      models.QuestionStatusClosed,
+		CloseReason: models.CloseReasonDuplicate,
+		ClosedBy:    &moderator,
+		ClosedAt:    &closedAt,
+		DuplicateOf: &original,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateQuestionStatus(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	// Пустые поля тоже записываются, чтобы при повторном открытии сбросить сведения о закрытии
+	mock.ExpectBegin()
+	mock.ExpectExec(
+		`UPDATE "questions" SET "close_reason"=\$1,"closed_at"=\$2,"closed_by"=\$3,"duplicate_of"=\$4,"status"=\$5,`+
+			`"version"=version \+ 1,"updated_at"=\$6 WHERE version = \$7 AND "id" = \$8`).
+		WithArgs("", nil, nil, nil, models.QuestionStatusOpen, sqlmock.AnyArg(), 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	question := &models.Question{ID: 1, Version: 1, Status: models.QuestionStatusOpen}
+	err := repo.UpdateQuestionStatus(question)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, question.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetQuestionStatusForUpdate(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectQuery(`SELECT "status" FROM "questions" WHERE id = \$1 LIMIT \$2 FOR UPDATE`).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.QuestionStatusClosed))
+	mock.ExpectQuery(`SELECT "status" FROM "questions" WHERE id = \$1 LIMIT \$2 FOR UPDATE`).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}))
+
+	status, err := repo.GetQuestionStatusForUpdate(1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.QuestionStatusClosed, status)
+	_, err = repo.GetQuestionStatusForUpdate(2)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcceptAnswer(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(
+		`UPDATE "questions" SET "accepted_answer_id"=\$1,"version"=version \+ 1,"updated_at"=\$2 `+
+			`WHERE version = \$3 AND "id" = \$4`).
+		WithArgs(5, sqlmock.AnyArg(), 1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	answerID := uint(5)
+	question := &models.Question{ID: 1, Version: 1, AcceptedAnswerID: &answerID}
+	var conflict *VersionConflictError
+	assert.ErrorAs(t, repo.AcceptAnswer(question), &conflict)
+	assert.Equal(t, 1, question.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateExternalPosts(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	createdAt := time.Date(2010, 2, 1, 9, 0, 0, 0, time.UTC)
+	question := &models.Question{Title: "Imported question", Status: models.QuestionStatusOpen, CreatedAt: createdAt}
+	author := uuid.New()
+	posts := []ExternalPost{
+		{ExternalID: 10, Question: question},
+		{ExternalID: 11, Answer: &models.Answer{UserID: author, Text: "New answer"}, Parent: question, Accepted: true},
+		{ExternalID: 12, Answer: &models.Answer{QuestionID: 3, UserID: author, Text: "Old answer"}},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "questions" .+ RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(`INSERT INTO "answers" .+ VALUES \(.+\),\(.+\) RETURNING "id"`).
+		WithArgs(7, author, "New answer", sqlmock.AnyArg(), sqlmock.AnyArg(), 1,
+			3, author, "Old answer", sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20).AddRow(21))
+	mock.ExpectExec(`UPDATE "questions" SET "accepted_answer_id"=\$1 WHERE id = \$2`).
+		WithArgs(20, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "imported_posts" \("source","external_id","kind","entity_id","created_at"\) `+
+		`VALUES \(.+\),\(.+\),\(.+\)`).
+		WithArgs("qa", 10, models.ImportedKindQuestion, 7, sqlmock.AnyArg(),
+			"qa", 11, models.ImportedKindAnswer, 20, sqlmock.AnyArg(),
+			"qa", 12, models.ImportedKindAnswer, 21, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.CreateExternalPosts("qa", posts))
+	assert.Equal(t, uint(7), posts[1].Answer.QuestionID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindImportedPosts(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectQuery(`SELECT \* FROM "imported_posts" WHERE source = \$1 AND external_id IN \(\$2,\$3\)`).
+		WithArgs("qa", 10, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"source", "external_id", "kind", "entity_id"}).
+			AddRow("qa", 10, models.ImportedKindQuestion, 7))
+
+	posts, err := repo.FindImportedPosts("qa", []int64{10, 11})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]models.ImportedPost{
+		10: {Source: "qa", ExternalID: 10, Kind: models.ImportedKindQuestion, EntityID: 7},
+	}, posts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindImportedUsers(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	userID := uuid.New()
+	mock.ExpectQuery(
+		`SELECT "external_id","user_id" FROM "imported_users" WHERE source = \$1 AND external_id IN \(\$2\)`).
+		WithArgs("qa", -1).
+		WillReturnRows(sqlmock.NewRows([]string{"external_id", "user_id"}).AddRow(-1, userID))
+
+	users, err := repo.FindImportedUsers("qa", []int64{-1})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]uuid.UUID{-1: userID}, users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindQuestionsByIDs(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectQuery(selectQuestions+` WHERE questions.id IN \(\$1,\$2\)`).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "answer_count"}).AddRow(2, "Second", 4))
+
+	questions, err := repo.FindQuestionsByIDs([]uint{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, questions, 1)
+	assert.Equal(t, "Second", questions[2].Title)
+	assert.Equal(t, int64(4), questions[2].AnswerCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindAnswersByQuestionIDs(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	// Ответы на все вопросы выбираются одним запросом
+	mock.ExpectQuery(selectAnswers+
+		` WHERE answers.question_id IN \(\$1,\$2,\$3\) ORDER BY answers.created_at, answers.id`).
+		WithArgs(1, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "question_id", "text"}).
+			AddRow(10, 1, "First").AddRow(11, 3, "Second").AddRow(12, 1, "Third"))
+
+	answers, err := repo.FindAnswersByQuestionIDs([]uint{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, answers, 2)
+	assert.Equal(t, []uint{10, 12}, []uint{answers[1][0].ID, answers[1][1].ID})
+	assert.Equal(t, "Second", answers[3][0].Text)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	answers, err = repo.FindAnswersByQuestionIDs(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, answers)
+}
+
+func TestTransactionRollsBackOutboxMessage(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	repo := NewRepository(gormDB, logrus.New())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "outbox"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+	mock.ExpectRollback()
+
+	failure := errors.New("question is locked")
+	err := repo.Transaction(func(tx Repository) error {
+		if err := tx.CreateOutboxMessage(&models.OutboxMessage{
+			AggregateType: "question", AggregateID: 1, EventType: "answer.created", Payload: []byte(`{}`),
+			AvailableAt: time.Now(),
+		}); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
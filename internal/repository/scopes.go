@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+)
+
+// Вычисляемые характеристики вопросов и ответов.
+// Фильтры и сортировка повторяют выражения целиком: PostgreSQL не видит псевдонимы SELECT в WHERE.
+const (
+	questionCommentCountExpr = "(SELECT COUNT(*) FROM comments " +
+		"WHERE comments.parent_type = 'question' AND comments.parent_id = questions.id)"
+	answerCommentCountExpr = "(SELECT COUNT(*) FROM comments " +
+		"WHERE comments.parent_type = 'answer' AND comments.parent_id = answers.id)"
+	answerCountExpr  = "(SELECT COUNT(*) FROM answers WHERE answers.question_id = questions.id)"
+	lastActivityExpr = "COALESCE((SELECT MAX(answers.created_at) FROM answers " +
+		"WHERE answers.question_id = questions.id), questions.created_at)"
+)
+
+// questionOrders - выражения ORDER BY для порядков сортировки вопросов.
+// Последний ключ делает порядок однозначным при равенстве основного.
+var questionOrders = map[string]string{
+	query.SortNewest:       "questions.created_at DESC, questions.id DESC",
+	query.SortOldest:       "questions.created_at, questions.id",
+	query.SortMostAnswered: answerCountExpr + " DESC, questions.id DESC",
+	query.SortActive:       lastActivityExpr + " DESC, questions.id DESC",
+	query.SortVotes:        "questions.score DESC, questions.id DESC",
+}
+
+// likeEscaper экранирует спецсимволы шаблона LIKE.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// questionScopes преобразует спецификацию выборки вопросов в gorm scopes: фильтры, сортировку и ограничение.
+func questionScopes(spec query.QuestionSpec) []func(*gorm.DB) *gorm.DB {
+	order, ok := questionOrders[spec.Sort]
+	if !ok {
+		order = questionOrders[query.SortNewest]
+	}
+	return append(questionFilters(spec), func(db *gorm.DB) *gorm.DB {
+		if spec.Limit > 0 {
+			db = db.Limit(spec.Limit)
+		}
+		return db.Order(order)
+	})
+}
+
+// questionFilters преобразует фильтры спецификации выборки вопросов в gorm scopes, без сортировки.
+func questionFilters(spec query.QuestionSpec) []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	where := func(cond string, args ...any) {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where(cond, args...)
+		})
+	}
+
+	if len(spec.Statuses) > 0 {
+		where("questions.status IN ?", spec.Statuses)
+	}
+	if spec.CreatedAfter != nil {
+		where("questions.created_at >= ?", *spec.CreatedAfter)
+	}
+	if spec.CreatedBefore != nil {
+		where("questions.created_at < ?", *spec.CreatedBefore)
+	}
+	if spec.AuthorID != nil {
+		where("questions.user_id = ?", *spec.AuthorID)
+	}
+	if spec.HasAnswers != nil {
+		if *spec.HasAnswers {
+			where("EXISTS (SELECT 1 FROM answers WHERE answers.question_id = questions.id)")
+		} else {
+			where("NOT EXISTS (SELECT 1 FROM answers WHERE answers.question_id = questions.id)")
+		}
+	}
+	if spec.MinAnswers != nil {
+		where(answerCountExpr+" >= ?", *spec.MinAnswers)
+	}
+	if spec.MaxAnswers != nil {
+		where(answerCountExpr+" <= ?", *spec.MaxAnswers)
+	}
+	if spec.Text != "" {
+		pattern := "%" + likeEscaper.Replace(spec.Text) + "%"
+		where("questions.title ILIKE ? OR questions.body ILIKE ?", pattern, pattern)
+	}
+	if len(spec.Tags) > 0 {
+		where("questions.tags @> ?", models.Tags(spec.Tags))
+	}
+	return scopes
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"strings"
+	"unicode"
+)
+
+// trigrams разбивает текст на множество триграмм по тем же правилам, что и pg_trgm:
+// текст приводится к нижнему регистру, делится на слова из букв и цифр,
+// каждое слово дополняется двумя пробелами в начале и одним в конце.
+func trigrams(text string) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	set := make(map[string]struct{})
+	for _, word := range words {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			set[string(runes[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// similarity вычисляет коэффициент Жаккара для множеств триграмм двух текстов.
+// Результат совпадает с функцией similarity() из pg_trgm.
+func similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	common := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
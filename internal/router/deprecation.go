@@ -0,0 +1,28 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Сроки вывода из эксплуатации маршрутов без префикса версии.
+var (
+	rootDeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	rootSunsetAt     = time.Date(2027, time.April, 18, 0, 0, 0, 0, time.UTC)
+)
+
+// Deprecated помечает маршруты как устаревшие: добавляет заголовки Deprecation (RFC 9745)
+// и Sunset (RFC 8594), а также Link на тот же ресурс с префиксом successor.
+func Deprecated(successor string, deprecatedAt, sunsetAt time.Time) func(http.Handler) http.Handler {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	sunset := sunsetAt.UTC().Format(http.TimeFormat)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunset)
+			w.Header().Add("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successor, r.URL.RequestURI()))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
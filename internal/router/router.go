@@ -5,28 +5,53 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/cors"
+	"github.com/shenikar/question-service/internal/graphql"
 	"github.com/shenikar/question-service/internal/handler"
+	"github.com/shenikar/question-service/internal/idempotency"
+	"github.com/shenikar/question-service/internal/ratelimit"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func NewRouter(h *handler.Handler) http.Handler {
+// Префиксы версий API.
+const (
+	PrefixV1 = "/api/v1"
+)
+
+func NewRouter(
+	h *handler.Handler, admin *handler.AdminHandler, gql *graphql.Handler, ev *handler.EventsHandler,
+	ws *handler.WebSocketHandler, wh *handler.WebhookHandler, fd *handler.FeedHandler,
+	nt *handler.NotificationHandler, idem *idempotency.Middleware, limiter *ratelimit.Limiter, c *cors.CORS,
+) http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	// CORS раньше остальных middleware: предварительные запросы не требуют аутентификации,
+	// а отказы (401, 429) тоже должны быть доступны странице
+	r.Use(c.Handler)
+	r.Use(auth.Middleware)
+	r.Use(limiter.Handler)
+
+	// Swagger: у каждой версии API своя документация
+	r.Get("/swagger", http.RedirectHandler("/swagger/v1/index.html", http.StatusFound).ServeHTTP)
+	r.Get("/swagger/v1/*", httpSwagger.Handler(
+		httpSwagger.InstanceName("v1"),
+		httpSwagger.URL("/swagger/v1/doc.json"),
+	))
 
-	// Swagger
-	r.Get("/swagger/*", httpSwagger.WrapHandler)
+	// Лента событий по WebSocket не версионируется: формат сообщений описан в README
+	r.Get("/ws", ws.Connect)
 
-	// Маршруты для вопросов
-	r.Get("/questions", h.GetQuestions)
-	r.Post("/questions", h.CreateQuestion)
-	r.Get("/questions/{id}", h.GetQuestion)
-	r.Delete("/questions/{id}", h.DeleteQuestion)
+	r.Route(PrefixV1, func(r chi.Router) {
+		v1Routes(r, h, admin, gql, ev, wh, fd, nt, idem)
+	})
 
-	// Маршруты для ответов
-	r.Post("/questions/{id}/answers", h.CreateAnswer)
-	r.Get("/answers/{id}", h.GetAnswer)
-	r.Delete("/answers/{id}", h.DeleteAnswer)
+	// Маршруты без префикса версии - устаревшие псевдонимы v1
+	r.Group(func(r chi.Router) {
+		r.Use(Deprecated(PrefixV1, rootDeprecatedAt, rootSunsetAt))
+		v1Routes(r, h, admin, gql, ev, wh, fd, nt, idem)
+	})
 
 	return r
 }
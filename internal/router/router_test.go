@@ -0,0 +1,227 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/config"
+	"github.com/shenikar/question-service/internal/cors"
+	"github.com/shenikar/question-service/internal/events"
+	"github.com/shenikar/question-service/internal/exporter"
+	"github.com/shenikar/question-service/internal/graphql"
+	"github.com/shenikar/question-service/internal/handler"
+	"github.com/shenikar/question-service/internal/idempotency"
+	"github.com/shenikar/question-service/internal/importer"
+	"github.com/shenikar/question-service/internal/notification"
+	"github.com/shenikar/question-service/internal/ratelimit"
+	"github.com/shenikar/question-service/internal/repository"
+	"github.com/shenikar/question-service/internal/service"
+	"github.com/shenikar/question-service/internal/webhook"
+)
+
+func newTestRouter() http.Handler {
+	logger := logrus.New()
+	repo := repository.NewMemoryRepository(logger)
+	hub := events.NewHub(events.DefaultReplaySize, events.DefaultClientBuffer)
+	broadcaster := events.NewBroadcaster(events.DefaultClientBuffer)
+	notifications := notification.NewMemoryStore()
+	notifier := notification.NewNotifier(notifications, repo, logger, notification.DefaultQueueSize)
+	s := service.NewService(repo, logger, service.Publishers{hub, broadcaster, notifier})
+	corsMiddleware := cors.New(config.CORS{})
+	return NewRouter(
+		handler.NewHandler(s, logger, config.DefaultMaxBodyBytes),
+		handler.NewAdminHandler(importer.New(repo, logger), exporter.New(repo, logger), logger,
+			config.DefaultMaxImportBytes),
+		graphql.NewHandler(s, logger, config.DefaultMaxBodyBytes, false),
+		handler.NewEventsHandler(s, hub, logger, handler.DefaultHeartbeatInterval),
+		handler.NewWebSocketHandler(broadcaster, corsMiddleware.OriginAllowed, logger, handler.DefaultPingInterval),
+		handler.NewWebhookHandler(webhook.NewMemoryStore(), logger, config.DefaultMaxBodyBytes),
+		handler.NewFeedHandler(s, "", PrefixV1, logger),
+		handler.NewNotificationHandler(notifications, logger),
+		idempotency.NewMiddleware(idempotency.NewMemoryStore(), time.Hour, config.DefaultMaxBodyBytes, logger),
+		ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.PerMinute(0), ratelimit.PerMinute(0), nil, logger),
+		corsMiddleware,
+	)
+}
+
+func TestVersionedRoutes(t *testing.T) {
+	router := newTestRouter()
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/questions", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Deprecation"))
+	assert.Empty(t, rr.Header().Get("Sunset"))
+}
+
+func TestDeprecatedRootRoutes(t *testing.T) {
+	router := newTestRouter()
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/questions?sort=oldest", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "@1792281600", rr.Header().Get("Deprecation"))
+	assert.Equal(t, "Sun, 18 Apr 2027 00:00:00 GMT", rr.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/questions?sort=oldest>; rel="successor-version"`, rr.Header().Get("Link"))
+
+	// Ошибки устаревших маршрутов тоже помечаются
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/answers/42", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Equal(t, `</api/v1/answers/42>; rel="successor-version"`, rr.Header().Get("Link"))
+}
+
+func TestAdminRoutesRequireModerator(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/import", strings.NewReader(`{"title":"Question"}`))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set(auth.HeaderUserID, uuid.NewString())
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/admin/import", strings.NewReader(`{"title":"Question"}`))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set(auth.HeaderUserID, uuid.NewString())
+	req.Header.Set(auth.HeaderUserRole, auth.RoleModerator)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/export", nil)
+	req.Header.Set(auth.HeaderUserID, uuid.NewString())
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	req.Header.Set(auth.HeaderUserRole, auth.RoleModerator)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/webhooks", nil)
+	req.Header.Set(auth.HeaderUserID, uuid.NewString())
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	req.Header.Set(auth.HeaderUserRole, auth.RoleModerator)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestGraphQLRoute(t *testing.T) {
+	router := newTestRouter()
+
+	// Автор вопроса берется из заголовков, которые разбирает auth.Middleware
+	author := uuid.NewString()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(
+		`{"query": "mutation { createQuestion(input: {title: \"How to install Go?\"}) { question { authorId } } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(auth.HeaderUserID, author)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"data": {"createQuestion": {"question": {"authorId": "`+author+`"}}}}`, rr.Body.String())
+
+	// GraphiQL выключен вне окружения разработки
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/graphql", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestQuestionEventsRoute(t *testing.T) {
+	router := newTestRouter()
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/questions/1/events", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestFeedRoutes(t *testing.T) {
+	router := newTestRouter()
+
+	for target, contentType := range map[string]string{
+		"/api/v1/feeds/questions.atom": "application/atom+xml; charset=utf-8",
+		"/api/v1/feeds/questions.rss":  "application/rss+xml; charset=utf-8",
+	} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusOK, rr.Code, target)
+		assert.Equal(t, contentType, rr.Header().Get("Content-Type"), target)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/questions/1/feed.atom", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestNotificationRoutes(t *testing.T) {
+	router := newTestRouter()
+	asker, answerer := uuid.New(), uuid.New()
+	request := func(method, target, body string, userID uuid.UUID) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(auth.HeaderUserID, userID.String())
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	// unread возвращает число непрочитанных уведомлений пользователя
+	unread := func(userID uuid.UUID) int64 {
+		var resp handler.NotificationListResponse
+		rr := request(http.MethodGet, "/api/v1/me/notifications?unread=true", "", userID)
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		return resp.UnreadCount
+	}
+
+	rr := request(http.MethodPost, "/api/v1/questions", `{"title":"How to install Go?","body":"Body"}`, asker)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	rr = request(http.MethodPost, "/api/v1/questions/1/answers", `{"text":"Use the installer"}`, answerer)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	// Уведомления создаются в фоне после ответа на запрос
+	assert.Eventually(t, func() bool { return unread(asker) == 1 }, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/api/v1/answers/1/accept", "", answerer).Code)
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "/api/v1/answers/1/accept", "", asker).Code)
+	assert.Eventually(t, func() bool { return unread(answerer) == 1 }, time.Second, 10*time.Millisecond)
+
+	rr = request(http.MethodPost, "/api/v1/me/notifications/read-all", "", asker)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Zero(t, unread(asker))
+}
+
+func TestSwaggerRedirect(t *testing.T) {
+	rr := httptest.NewRecorder()
+	newTestRouter().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/swagger/v1/index.html", rr.Header().Get("Location"))
+}
+
+func TestWebSocketRoute(t *testing.T) {
+	server := httptest.NewServer(newTestRouter())
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if assert.NoError(t, err) {
+		defer func() { _ = conn.Close() }()
+		assert.NoError(t, conn.WriteJSON(handler.WebSocketCommand{Action: "subscribe", Topic: "questions"}))
+		var msg handler.WebSocketMessage
+		assert.NoError(t, conn.ReadJSON(&msg))
+		assert.Equal(t, "subscribed", msg.Type)
+	}
+}
@@ -0,0 +1,77 @@
+package router
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/shenikar/question-service/internal/auth"
+	"github.com/shenikar/question-service/internal/graphql"
+	"github.com/shenikar/question-service/internal/handler"
+	"github.com/shenikar/question-service/internal/idempotency"
+)
+
+// v1Routes регистрирует маршруты API версии 1.
+func v1Routes(
+	r chi.Router, h *handler.Handler, admin *handler.AdminHandler, gql *graphql.Handler, ev *handler.EventsHandler,
+	wh *handler.WebhookHandler, fd *handler.FeedHandler, nt *handler.NotificationHandler, idem *idempotency.Middleware,
+) {
+	// Маршруты для вопросов
+	r.Get("/questions", h.GetQuestions)
+	r.With(idem.Handler).Post("/questions", h.CreateQuestion)
+	r.Get("/questions/{id}", h.GetQuestion)
+	r.Delete("/questions/{id}", h.DeleteQuestion)
+
+	// Модерация вопросов и ответов
+	r.Group(func(r chi.Router) {
+		r.Use(auth.RequireRole(auth.RoleModerator))
+		r.Patch("/questions/{id}", h.UpdateQuestion)
+		r.Patch("/answers/{id}", h.UpdateAnswer)
+		r.Post("/questions/{id}/duplicate-of/{otherID}", h.MarkDuplicate)
+		r.Post("/questions/{id}/close", h.CloseQuestion)
+		r.Post("/questions/{id}/reopen", h.ReopenQuestion)
+		r.Post("/questions/{id}/lock", h.LockQuestion)
+		r.Post("/questions/{id}/archive", h.ArchiveQuestion)
+	})
+
+	// Маршруты для ответов
+	r.With(idem.Handler).Post("/questions/{id}/answers", h.CreateAnswer)
+	r.Get("/answers/{id}", h.GetAnswer)
+	r.Delete("/answers/{id}", h.DeleteAnswer)
+	r.Post("/answers/{id}/accept", h.AcceptAnswer)
+	r.Get("/questions/{id}/events", ev.QuestionEvents)
+
+	// Ленты Atom и RSS
+	r.Get("/feeds/questions.atom", fd.QuestionsAtom)
+	r.Get("/feeds/questions.rss", fd.QuestionsRSS)
+	r.Get("/questions/{id}/feed.atom", fd.QuestionAtom)
+
+	// Маршруты для комментариев
+	r.Post("/questions/{id}/comments", h.CreateQuestionComment)
+	r.Get("/questions/{id}/comments", h.GetQuestionComments)
+	r.Post("/answers/{id}/comments", h.CreateAnswerComment)
+	r.Get("/answers/{id}/comments", h.GetAnswerComments)
+	r.Delete("/comments/{id}", h.DeleteComment)
+
+	// Уведомления текущего пользователя
+	r.Get("/me/notifications", nt.ListNotifications)
+	r.Post("/me/notifications/{id}/read", nt.MarkNotificationRead)
+	r.Post("/me/notifications/read-all", nt.MarkAllNotificationsRead)
+
+	// GraphQL; GET отдает страницу GraphiQL в окружении разработки
+	r.Post("/graphql", gql.Query)
+	r.Get("/graphql", gql.GraphiQL)
+
+	// Администрирование
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(auth.RequireRole(auth.RoleModerator))
+		r.Post("/import", admin.Import)
+		r.Get("/export", admin.Export)
+
+		r.Post("/webhooks", wh.CreateWebhook)
+		r.Get("/webhooks", wh.ListWebhooks)
+		r.Get("/webhooks/{id}", wh.GetWebhook)
+		r.Patch("/webhooks/{id}", wh.UpdateWebhook)
+		r.Delete("/webhooks/{id}", wh.DeleteWebhook)
+		r.Get("/webhooks/{id}/deliveries", wh.ListDeliveries)
+		r.Get("/webhooks/{id}/deliveries/{deliveryID}", wh.GetDelivery)
+		r.Post("/webhooks/{id}/deliveries/{deliveryID}/replay", wh.ReplayDelivery)
+	})
+}
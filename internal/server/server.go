@@ -2,18 +2,29 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
-	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 )
 
-// Server представляет HTTP-сервер.
+// shutdownTimeout - сколько ждать завершения текущих запросов при остановке.
+// По истечении срока оставшиеся соединения закрываются принудительно.
+const shutdownTimeout = 5 * time.Second
+
+// Server представляет HTTP-сервер и, если он подключен, gRPC-сервер, которые запускаются
+// и останавливаются вместе.
 type Server struct {
 	httpServer *http.Server
+	grpcServer *grpc.Server
+	grpcAddr   string
 	logger     *logrus.Logger
 }
 
@@ -29,33 +40,112 @@ func NewServer(handler http.Handler, logger *logrus.Logger) *Server {
 	}
 }
 
-// Run запускает сервер и настраивает graceful shutdown.
+// WithGRPC подключает gRPC-сервер, который будет слушать addr вместе с HTTP-сервером.
+func (s *Server) WithGRPC(addr string, grpcServer *grpc.Server) *Server {
+	s.grpcAddr = addr
+	s.grpcServer = grpcServer
+	return s
+}
+
+// OnShutdown регистрирует функцию, которая вызывается в начале остановки HTTP-сервера.
+// Нужна для долгих соединений (потоков событий): Shutdown не закрывает их сам и ждал бы до истечения
+// срока, поэтому f должна завершить их обработчики.
+func (s *Server) OnShutdown(f func()) {
+	s.httpServer.RegisterOnShutdown(f)
+}
+
+// Run запускает сервер и настраивает graceful shutdown по SIGTERM и SIGINT.
 func (s *Server) Run() error {
-	// Канал для получения сигналов ОС
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+	// Контекст отменяется при получении сигнала завершения
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+	return s.Serve(ctx)
+}
 
-	// Запускаем сервер в отдельной горутине
+// Serve открывает порты, обслуживает запросы до отмены ctx и корректно останавливает серверы.
+func (s *Server) Serve(ctx context.Context) error {
+	httpListener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.httpServer.Addr, err)
+	}
+	var grpcListener net.Listener
+	if s.grpcServer != nil {
+		if grpcListener, err = net.Listen("tcp", s.grpcAddr); err != nil {
+			_ = httpListener.Close()
+			return fmt.Errorf("failed to listen on %s: %w", s.grpcAddr, err)
+		}
+	}
+	return s.serve(ctx, httpListener, grpcListener)
+}
+
+// serve обслуживает запросы на открытых портах. Если один из серверов упал, останавливается и второй.
+func (s *Server) serve(ctx context.Context, httpListener, grpcListener net.Listener) error {
+	errs := make(chan error, 2)
 	go func() {
-		s.logger.Infof("Starting server on %s", s.httpServer.Addr)
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			s.logger.Fatalf("Server failed to start: %v", err)
+		s.logger.Infof("Starting HTTP server on %s", httpListener.Addr())
+		if err := s.httpServer.Serve(httpListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- fmt.Errorf("HTTP server failed: %w", err)
 		}
 	}()
+	if grpcListener != nil {
+		go func() {
+			s.logger.Infof("Starting gRPC server on %s", grpcListener.Addr())
+			if err := s.grpcServer.Serve(grpcListener); err != nil {
+				errs <- fmt.Errorf("gRPC server failed: %w", err)
+			}
+		}()
+	}
 
-	// Ожидаем сигнал завершения
-	<-stop
+	// Ожидаем сигнал завершения или падение одного из серверов
+	var serveErr error
+	select {
+	case <-ctx.Done():
+	case serveErr = <-errs:
+		s.logger.Errorf("%v", serveErr)
+	}
 	s.logger.Info("Shutting down server...")
 
-	// Создаем контекст с таймаутом для graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := s.shutdown(); err != nil {
+		return errors.Join(serveErr, err)
+	}
+	s.logger.Info("Server gracefully stopped.")
+	return serveErr
+}
+
+// shutdown одновременно останавливает HTTP- и gRPC-серверы, давая текущим запросам shutdownTimeout
+// на завершение.
+func (s *Server) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
-	// Корректно завершаем работу сервера
-	if err := s.httpServer.Shutdown(ctx); err != nil {
-		s.logger.Fatalf("Server shutdown failed: %v", err)
+	var wg sync.WaitGroup
+	if s.grpcServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.stopGRPC(ctx)
+		}()
+	}
+	err := s.httpServer.Shutdown(ctx)
+	wg.Wait()
+	if err != nil {
+		return fmt.Errorf("HTTP server shutdown failed: %w", err)
 	}
-
-	s.logger.Info("Server gracefully stopped.")
 	return nil
 }
+
+// stopGRPC ждет завершения текущих вызовов gRPC, а по истечении ctx закрывает соединения принудительно.
+func (s *Server) stopGRPC(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.logger.Warn("gRPC server did not stop in time, closing remaining connections")
+		s.grpcServer.Stop()
+		<-stopped
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1 << 20
+
+// newTestServer создает сервер с HTTP-обработчиком handler и gRPC-сервером с сервисом проверки здоровья.
+func newTestServer(handler http.Handler) *Server {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	return NewServer(handler, logger).WithGRPC("bufnet", grpcServer)
+}
+
+func httpClient(listener *bufconn.Listener) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) { return listener.DialContext(ctx) },
+	}}
+}
+
+func grpcConn(t *testing.T, listener *bufconn.Listener) *grpc.ClientConn {
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestServeRunsHTTPAndGRPC(t *testing.T) {
+	srv := newTestServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	httpListener, grpcListener := bufconn.Listen(bufSize), bufconn.Listen(bufSize)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.serve(ctx, httpListener, grpcListener) }()
+
+	resp, err := httpClient(httpListener).Get("http://bufnet/")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		assert.Equal(t, "ok", string(body))
+	}
+	health, err := healthpb.NewHealthClient(grpcConn(t, grpcListener)).
+		Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.GetStatus())
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(shutdownTimeout):
+		t.Fatal("server did not stop")
+	}
+
+	// После остановки оба сервера больше не принимают запросы
+	_, err = httpClient(httpListener).Get("http://bufnet/")
+	assert.Error(t, err)
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(grpcConn(t, grpcListener)).Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Error(t, err)
+}
+
+func TestServeWaitsForInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	srv := newTestServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte("finished"))
+	}))
+	httpListener, grpcListener := bufconn.Listen(bufSize), bufconn.Listen(bufSize)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.serve(ctx, httpListener, grpcListener) }()
+
+	responses := make(chan string, 1)
+	go func() {
+		resp, err := httpClient(httpListener).Get("http://bufnet/")
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		responses <- string(body)
+	}()
+
+	<-started
+	cancel()
+	assert.Equal(t, "finished", <-responses)
+	assert.NoError(t, <-done)
+}
+
+func TestServeStopsWhenServerFails(t *testing.T) {
+	srv := newTestServer(http.NotFoundHandler())
+	httpListener, grpcListener := bufconn.Listen(bufSize), bufconn.Listen(bufSize)
+	// gRPC-сервер не сможет принять соединение на закрытом порту
+	_ = grpcListener.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- srv.serve(context.Background(), httpListener, grpcListener) }()
+
+	select {
+	case err := <-done:
+		assert.ErrorContains(t, err, "gRPC server failed")
+	case <-time.After(shutdownTimeout):
+		t.Fatal("server did not stop")
+	}
+	_, err := httpClient(httpListener).Get("http://bufnet/")
+	assert.Error(t, err)
+}
+
+func TestServeRunsShutdownHooks(t *testing.T) {
+	// Обработчик держит соединение, пока его не завершит функция остановки, как поток событий
+	closing := make(chan struct{})
+	started := make(chan struct{})
+	srv := newTestServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_ = http.NewResponseController(w).Flush()
+		close(started)
+		<-closing
+	}))
+	srv.OnShutdown(func() { close(closing) })
+	httpListener, grpcListener := bufconn.Listen(bufSize), bufconn.Listen(bufSize)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.serve(ctx, httpListener, grpcListener) }()
+
+	go func() {
+		if resp, err := httpClient(httpListener).Get("http://bufnet/"); err == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+	}()
+	<-started
+
+	start := time.Now()
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Less(t, time.Since(start), shutdownTimeout)
+}
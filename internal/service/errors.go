@@ -0,0 +1,28 @@
+package service
+
+import "errors"
+
+var (
+	// ErrNotFound возвращается, если запрошенный вопрос или ответ не существует.
+	ErrNotFound = errors.New("not found")
+	// ErrDuplicateQuestion возвращается в строгом режиме, если найдены похожие вопросы.
+	ErrDuplicateQuestion = errors.New("similar questions already exist")
+	// ErrInvalidDuplicate возвращается при попытке закрыть вопрос как дубликат
+	// самого себя или вопроса, который сам является его дубликатом.
+	ErrInvalidDuplicate = errors.New("invalid duplicate target")
+	// ErrQuestionNotOpen возвращается при попытке ответить на закрытый, заблокированный или архивный вопрос
+	// или принять ответ на него.
+	ErrQuestionNotOpen = errors.New("question is not open")
+	// ErrInvalidStatusTransition возвращается, если вопрос нельзя перевести в запрошенный статус
+	// из текущего.
+	ErrInvalidStatusTransition = errors.New("invalid question status transition")
+	// ErrVersionConflict возвращается, если вопрос или ответ изменился с тех пор, как его прочитал
+	// клиент (версия не совпала с If-Match) или сам сервис (конкурентное изменение).
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrInvalidTags возвращается, если теги вопроса недопустимы или их слишком много.
+	ErrInvalidTags = errors.New("invalid tags")
+	// ErrNotQuestionAuthor возвращается, если принять ответ пытается не автор вопроса.
+	ErrNotQuestionAuthor = errors.New("only the question author can do this")
+	// ErrNotCommentAuthor возвращается, если удалить комментарий пытается не автор и не модератор.
+	ErrNotCommentAuthor = errors.New("only the comment author or a moderator can do this")
+)
@@ -1,84 +1,668 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 
+	"github.com/shenikar/question-service/internal/events"
 	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/outbox"
+	"github.com/shenikar/question-service/internal/query"
 	"github.com/shenikar/question-service/internal/repository"
 )
 
 // Service определяет интерфейс для бизнес-логики приложения.
 type Service interface {
-	CreateQuestion(question *models.Question) error
-	GetQuestion(id uint) (*models.Question, error)
-	GetAllQuestions() ([]models.Question, error)
-	DeleteQuestion(id uint) error
+	CreateQuestion(question *models.Question, strict bool) ([]models.SimilarQuestion, error)
+	GetQuestion(id uint, proj query.Projection) (*models.Question, error)
+	GetAllQuestions(spec query.QuestionSpec, proj query.Projection) ([]models.Question, error)
+	GetQuestionsByIDs(ids []uint) (map[uint]models.Question, error)
+	UpdateQuestion(id uint, ifMatch []int, title, body *string) (*models.Question, error)
+	DeleteQuestion(id uint, ifMatch []int) error
 	CreateAnswer(questionID uint, answer *models.Answer) error
-	GetAnswer(id uint) (*models.Answer, error)
-	DeleteAnswer(id uint) error
+	GetAnswer(id uint, proj query.Projection) (*models.Answer, error)
+	GetAnswersByQuestionIDs(questionIDs []uint) (map[uint][]models.Answer, error)
+	UpdateAnswer(id uint, ifMatch []int, text string) (*models.Answer, error)
+	DeleteAnswer(id uint, ifMatch []int) error
+	AcceptAnswer(id uint, userID uuid.UUID) (*models.Question, error)
+	CreateComment(parentType string, parentID uint, comment *models.Comment) error
+	GetComments(parentType string, parentID uint) ([]models.Comment, error)
+	DeleteComment(id uint, userID uuid.UUID, moderator bool) error
+	MarkDuplicate(id, duplicateOf uint, moderator uuid.UUID) error
+	CloseQuestion(id uint, moderator uuid.UUID, reason string) (*models.Question, error)
+	ReopenQuestion(id uint) (*models.Question, error)
+	LockQuestion(id uint, moderator uuid.UUID) (*models.Question, error)
+	ArchiveQuestion(id uint, moderator uuid.UUID) (*models.Question, error)
+}
+
+// Параметры поиска возможных дубликатов при создании вопроса.
+const (
+	duplicateSimilarityThreshold = 0.45
+	maxPossibleDuplicates        = 5
+)
+
+// Publisher получает события об изменениях вопросов и ответов, например events.Hub.
+// Publish вызывается после фиксации изменения и не должен блокироваться. Событие к этому моменту
+// уже записано в outbox, поэтому в PostgreSQL publisher может только будить outbox.Relay.
+type Publisher interface {
+	Publish(event events.Event)
+}
+
+// Publishers передает каждое событие всем получателям по очереди.
+type Publishers []Publisher
+
+// Publish передает событие всем получателям.
+func (p Publishers) Publish(event events.Event) {
+	for _, publisher := range p {
+		publisher.Publish(event)
+	}
 }
 
 // questionAnswerService - реализация Service.
 type questionAnswerService struct {
-	repo   repository.Repository
-	logger *logrus.Logger
+	repo      repository.Repository
+	logger    *logrus.Logger
+	publisher Publisher
+}
+
+// NewService создает новый экземпляр сервиса. publisher может быть nil, если события не нужны.
+func NewService(repo repository.Repository, logger *logrus.Logger, publisher Publisher) Service {
+	return &questionAnswerService{repo: repo, logger: logger, publisher: publisher}
+}
+
+// answerEvent создает событие об ответе. В событие попадает копия ответа, чтобы подписчики
+// не видели последующих изменений модели вызывающим кодом.
+func answerEvent(eventType string, questionID, answerID uint, answer *models.Answer) events.Event {
+	event := events.Event{Type: eventType, QuestionID: questionID, AnswerID: answerID, Time: time.Now()}
+	if answer != nil {
+		answerCopy := *answer
+		event.Answer = &answerCopy
+	}
+	return event
+}
+
+// questionEvent создает событие о вопросе с копией вопроса и его тегами, если он передан.
+func questionEvent(eventType string, questionID uint, question *models.Question) events.Event {
+	event := events.Event{Type: eventType, QuestionID: questionID, Time: time.Now()}
+	if question != nil {
+		questionCopy := *question
+		questionCopy.Tags = slices.Clone(question.Tags)
+		event.Question = &questionCopy
+		event.Tags = slices.Clone(question.Tags)
+	}
+	return event
+}
+
+// withTags добавляет к событию теги вопроса, к которому оно относится.
+func withTags(event events.Event, tags models.Tags) events.Event {
+	event.Tags = slices.Clone(tags)
+	return event
+}
+
+// commentEvent создает событие о комментарии с копией комментария, если он передан.
+func commentEvent(eventType string, questionID, commentID uint, comment *models.Comment) events.Event {
+	event := events.Event{Type: eventType, QuestionID: questionID, CommentID: commentID, Time: time.Now()}
+	if comment != nil {
+		commentCopy := *comment
+		event.Comment = &commentCopy
+	}
+	return event
 }
 
-// NewService создает новый экземпляр сервиса.
-func NewService(repo repository.Repository, logger *logrus.Logger) Service {
-	return &questionAnswerService{repo: repo, logger: logger}
+// commit выполняет change и записывает событие в outbox в одной транзакции: событие сохраняется,
+// только если изменение зафиксировано. event вызывается после change, когда известны ID новых записей.
+// После фиксации событие передается publisher.
+func (s *questionAnswerService) commit(
+	change func(repo repository.Repository) error, event func() events.Event,
+) error {
+	var published events.Event
+	err := s.repo.Transaction(func(tx repository.Repository) error {
+		if err := change(tx); err != nil {
+			return err
+		}
+		published = event()
+		published.UUID = uuid.New()
+		msg, err := outbox.NewMessage(published)
+		if err != nil {
+			return err
+		}
+		return tx.CreateOutboxMessage(msg)
+	})
+	if err != nil {
+		return err
+	}
+	if s.publisher != nil {
+		s.publisher.Publish(published)
+	}
+	return nil
 }
 
-// CreateQuestion создает новый вопрос.
-func (s *questionAnswerService) CreateQuestion(question *models.Question) error {
+// CreateQuestion создает новый вопрос и возвращает похожие на него существующие вопросы.
+// В строгом режиме при наличии похожих вопросов вопрос не создается и возвращается ErrDuplicateQuestion.
+// Теги нормализуются; недопустимые теги - ErrInvalidTags.
+func (s *questionAnswerService) CreateQuestion(
+	question *models.Question, strict bool,
+) ([]models.SimilarQuestion, error) {
 	s.logger.Debugf("Creating question: %+v", question)
-	return s.repo.CreateQuestion(question)
+	tags, err := models.NormalizeTags(question.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTags, err)
+	}
+	question.Tags = tags
+	question.Status = models.QuestionStatusOpen
+	duplicates, err := s.repo.FindSimilarQuestions(question.Title, duplicateSimilarityThreshold, maxPossibleDuplicates)
+	if err != nil {
+		if strict {
+			return nil, fmt.Errorf("failed to find similar questions: %w", err)
+		}
+		// Бизнес-логика: поиск дубликатов не должен мешать задать вопрос.
+		s.logger.Warnf("Failed to find similar questions, skipping duplicate detection: %v", err)
+		duplicates = nil
+	}
+
+	if strict && len(duplicates) > 0 {
+		s.logger.Infof("Rejected question %q: %d possible duplicates found", question.Title, len(duplicates))
+		return duplicates, ErrDuplicateQuestion
+	}
+
+	err = s.commit(
+		func(repo repository.Repository) error { return repo.CreateQuestion(question) },
+		func() events.Event { return questionEvent(events.TypeQuestionCreated, question.ID, question) },
+	)
+	if err != nil {
+		return nil, err
+	}
+	return duplicates, nil
 }
 
-// GetQuestion получает вопрос по ID.
-func (s *questionAnswerService) GetQuestion(id uint) (*models.Question, error) {
+// GetQuestion получает вопрос по ID с запрошенными полями и связанными данными.
+func (s *questionAnswerService) GetQuestion(id uint, proj query.Projection) (*models.Question, error) {
 	s.logger.Debugf("Getting question with ID: %d", id)
-	return s.repo.GetQuestion(id)
+	return s.repo.GetQuestion(id, proj)
 }
 
-// GetAllQuestions получает все вопросы.
-func (s *questionAnswerService) GetAllQuestions() ([]models.Question, error) {
-	s.logger.Debug("Getting all questions")
-	return s.repo.GetAllQuestions()
+// GetAllQuestions получает все вопросы, удовлетворяющие спецификации.
+func (s *questionAnswerService) GetAllQuestions(
+	spec query.QuestionSpec, proj query.Projection,
+) ([]models.Question, error) {
+	s.logger.Debugf("Getting all questions: %+v", spec)
+	return s.repo.GetAllQuestions(spec, proj)
+}
+
+// GetQuestionsByIDs получает вопросы по списку ID одним обращением к хранилищу (ID -> вопрос).
+// Несуществующие вопросы в результат не попадают.
+func (s *questionAnswerService) GetQuestionsByIDs(ids []uint) (map[uint]models.Question, error) {
+	s.logger.Debugf("Getting questions with IDs: %v", ids)
+	return s.repo.FindQuestionsByIDs(ids)
+}
+
+// UpdateQuestion изменяет заголовок и (или) тело вопроса. nil-поля не изменяются.
+// ifMatch - версии из If-Match, в одной из которых должен быть вопрос; nil означает отсутствие условия.
+func (s *questionAnswerService) UpdateQuestion(
+	id uint, ifMatch []int, title, body *string,
+) (*models.Question, error) {
+	s.logger.Debugf("Updating question with ID: %d", id)
+	question, err := s.repo.GetQuestion(id, query.Projection{})
+	if err != nil {
+		return nil, notFound("question", id, err)
+	}
+	if err := checkVersion("question", id, question.Version, ifMatch); err != nil {
+		return nil, err
+	}
+
+	if title != nil {
+		question.Title = *title
+	}
+	if body != nil {
+		question.Body = *body
+	}
+	err = s.commit(
+		func(repo repository.Repository) error { return versionConflict(repo.UpdateQuestion(question)) },
+		func() events.Event { return questionEvent(events.TypeQuestionUpdated, question.ID, question) },
+	)
+	if err != nil {
+		return nil, err
+	}
+	return question, nil
 }
 
 // DeleteQuestion удаляет вопрос по ID.
-func (s *questionAnswerService) DeleteQuestion(id uint) error {
+// Если передан ifMatch, вопрос удаляется, только если он в одной из перечисленных версий.
+func (s *questionAnswerService) DeleteQuestion(id uint, ifMatch []int) error {
 	s.logger.Debugf("Deleting question with ID: %d", id)
-	return s.repo.DeleteQuestion(id)
+	// Вопрос читается и для удаления без условия: событие публикуется только об удалении существующего вопроса
+	question, err := s.repo.GetQuestion(id, query.Projection{})
+	if err != nil {
+		if ifMatch == nil && errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.repo.DeleteQuestion(id, 0)
+		}
+		return notFound("question", id, err)
+	}
+	if err := checkVersion("question", id, question.Version, ifMatch); err != nil {
+		return err
+	}
+
+	version := 0
+	if ifMatch != nil {
+		version = question.Version
+	}
+	return s.commit(
+		func(repo repository.Repository) error { return versionConflict(repo.DeleteQuestion(id, version)) },
+		func() events.Event {
+			return withTags(questionEvent(events.TypeQuestionDeleted, id, nil), question.Tags)
+		},
+	)
 }
 
-// CreateAnswer создает новый ответ.
+// CreateAnswer создает новый ответ. Если автор ответа не задан, ответ создается от имени нового пользователя.
 func (s *questionAnswerService) CreateAnswer(questionID uint, answer *models.Answer) error {
 	s.logger.Debugf("Creating answer for question ID %d: %+v", questionID, answer)
 	// Бизнес-логика: Нельзя создать ответ к несуществующему вопросу.
-	_, err := s.repo.GetQuestion(questionID)
+	question, err := s.repo.GetQuestion(questionID, query.Projection{})
 	if err != nil {
 		s.logger.Warnf("Attempted to create answer for non-existent question ID %d", questionID)
 		return fmt.Errorf("question with ID %d not found: %w", questionID, err)
 	}
+	// Бизнес-логика: отвечать можно только на открытые вопросы.
+	if err := questionOpen(questionID, question.Status); err != nil {
+		s.logger.Warnf("Attempted to create answer for %s question ID %d", question.Status, questionID)
+		return err
+	}
 
 	answer.QuestionID = questionID
-	answer.UserID = uuid.New() // Бизнес-логика: ID пользователя генерируется здесь
-	return s.repo.CreateAnswer(answer)
+	if answer.UserID == uuid.Nil {
+		answer.UserID = uuid.New() // Бизнес-логика: ID анонимного пользователя генерируется здесь
+	}
+	return s.commit(
+		func(repo repository.Repository) error {
+			// Вопрос могли закрыть после проверки выше: статус перечитывается с блокировкой строки вопроса,
+			// и закрыть его, пока ответ не сохранен, уже нельзя
+			status, err := repo.GetQuestionStatusForUpdate(questionID)
+			if err != nil {
+				return notFound("question", questionID, err)
+			}
+			if err := questionOpen(questionID, status); err != nil {
+				return err
+			}
+			return repo.CreateAnswer(answer)
+		},
+		func() events.Event {
+			return withTags(answerEvent(events.TypeAnswerCreated, questionID, answer.ID, answer), question.Tags)
+		},
+	)
 }
 
-// GetAnswer получает ответ по ID.
-func (s *questionAnswerService) GetAnswer(id uint) (*models.Answer, error) {
+// GetAnswer получает ответ по ID с запрошенными полями и связанными данными.
+func (s *questionAnswerService) GetAnswer(id uint, proj query.Projection) (*models.Answer, error) {
 	s.logger.Debugf("Getting answer with ID: %d", id)
-	return s.repo.GetAnswer(id)
+	return s.repo.GetAnswer(id, proj)
+}
+
+// GetAnswersByQuestionIDs получает ответы на несколько вопросов одним обращением к хранилищу
+// (ID вопроса -> ответы в порядке создания).
+func (s *questionAnswerService) GetAnswersByQuestionIDs(questionIDs []uint) (map[uint][]models.Answer, error) {
+	s.logger.Debugf("Getting answers to questions with IDs: %v", questionIDs)
+	return s.repo.FindAnswersByQuestionIDs(questionIDs)
+}
+
+// UpdateAnswer изменяет текст ответа.
+// ifMatch - версии из If-Match, в одной из которых должен быть ответ; nil означает отсутствие условия.
+func (s *questionAnswerService) UpdateAnswer(id uint, ifMatch []int, text string) (*models.Answer, error) {
+	s.logger.Debugf("Updating answer with ID: %d", id)
+	answer, err := s.repo.GetAnswer(id, query.Projection{})
+	if err != nil {
+		return nil, notFound("answer", id, err)
+	}
+	if err := checkVersion("answer", id, answer.Version, ifMatch); err != nil {
+		return nil, err
+	}
+	tags, err := s.questionTags(answer.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	answer.Text = text
+	err = s.commit(
+		func(repo repository.Repository) error { return versionConflict(repo.UpdateAnswer(answer)) },
+		func() events.Event {
+			return withTags(answerEvent(events.TypeAnswerUpdated, answer.QuestionID, answer.ID, answer), tags)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return answer, nil
 }
 
 // DeleteAnswer удаляет ответ по ID.
-func (s *questionAnswerService) DeleteAnswer(id uint) error {
+// Если передан ifMatch, ответ удаляется, только если он в одной из перечисленных версий.
+func (s *questionAnswerService) DeleteAnswer(id uint, ifMatch []int) error {
 	s.logger.Debugf("Deleting answer with ID: %d", id)
-	return s.repo.DeleteAnswer(id)
+	// Ответ читается и для удаления без условия: событию нужен ID вопроса
+	answer, err := s.repo.GetAnswer(id, query.Projection{})
+	if err != nil {
+		if ifMatch == nil && errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.repo.DeleteAnswer(id, 0)
+		}
+		return notFound("answer", id, err)
+	}
+	if err := checkVersion("answer", id, answer.Version, ifMatch); err != nil {
+		return err
+	}
+	tags, err := s.questionTags(answer.QuestionID)
+	if err != nil {
+		return err
+	}
+
+	version := 0
+	if ifMatch != nil {
+		version = answer.Version
+	}
+	return s.commit(
+		func(repo repository.Repository) error { return versionConflict(repo.DeleteAnswer(id, version)) },
+		func() events.Event {
+			return withTags(answerEvent(events.TypeAnswerDeleted, answer.QuestionID, id, nil), tags)
+		},
+	)
+}
+
+// AcceptAnswer отмечает ответ принятым от имени пользователя userID и возвращает вопрос.
+// Принять ответ может только автор открытого вопроса; ранее принятый ответ заменяется. Повторное принятие того же
+// ответа ничего не меняет и не публикует событие.
+func (s *questionAnswerService) AcceptAnswer(id uint, userID uuid.UUID) (*models.Question, error) {
+	s.logger.Debugf("Accepting answer %d by user %s", id, userID)
+	answer, err := s.repo.GetAnswer(id, query.Projection{})
+	if err != nil {
+		return nil, notFound("answer", id, err)
+	}
+	question, err := s.repo.GetQuestion(answer.QuestionID, query.Projection{})
+	if err != nil {
+		return nil, notFound("question", answer.QuestionID, err)
+	}
+	if question.UserID == nil || *question.UserID != userID {
+		s.logger.Warnf("User %s attempted to accept answer %d to question %d", userID, id, question.ID)
+		return nil, fmt.Errorf("accept answer %d: %w", id, ErrNotQuestionAuthor)
+	}
+	// Бизнес-логика: как и отвечать, принимать ответ можно только на открытом вопросе. Смена статуса
+	// меняет версию вопроса, поэтому закрытие после этой проверки даст конфликт версий при сохранении.
+	if err := questionOpen(question.ID, question.Status); err != nil {
+		s.logger.Warnf("Attempted to accept answer %d to %s question %d", id, question.Status, question.ID)
+		return nil, err
+	}
+	if question.AcceptedAnswerID != nil && *question.AcceptedAnswerID == id {
+		return question, nil
+	}
+
+	question.AcceptedAnswerID = &answer.ID
+	err = s.commit(
+		func(repo repository.Repository) error { return versionConflict(repo.AcceptAnswer(question)) },
+		func() events.Event {
+			return withTags(answerEvent(events.TypeAnswerAccepted, question.ID, answer.ID, answer), question.Tags)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// checkVersion проверяет условие If-Match: текущая версия должна быть одной из ifMatch.
+func checkVersion(entity string, id uint, version int, ifMatch []int) error {
+	if ifMatch == nil || slices.Contains(ifMatch, version) {
+		return nil
+	}
+	return fmt.Errorf("%s with ID %d is at version %d: %w", entity, id, version, ErrVersionConflict)
+}
+
+// questionOpen возвращает ErrQuestionNotOpen, если вопрос со статусом status не открыт.
+func questionOpen(id uint, status string) error {
+	if status != models.QuestionStatusOpen {
+		return fmt.Errorf("question with ID %d is %s: %w", id, status, ErrQuestionNotOpen)
+	}
+	return nil
+}
+
+// notFound заменяет gorm.ErrRecordNotFound на ErrNotFound; остальные ошибки репозитория
+// возвращаются без изменений.
+func notFound(entity string, id uint, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%s with ID %d: %w", entity, id, ErrNotFound)
+	}
+	return err
+}
+
+// versionConflict помечает конфликт версий из репозитория как ErrVersionConflict.
+func versionConflict(err error) error {
+	var conflict *repository.VersionConflictError
+	if errors.As(err, &conflict) {
+		return fmt.Errorf("%w: %w", ErrVersionConflict, err)
+	}
+	return err
+}
+
+// CreateComment создает комментарий к вопросу или ответу. Автор - comment.UserID;
+// если он не задан, генерируется новый ID пользователя.
+func (s *questionAnswerService) CreateComment(parentType string, parentID uint, comment *models.Comment) error {
+	s.logger.Debugf("Creating comment for %s ID %d: %+v", parentType, parentID, comment)
+	// Бизнес-логика: Нельзя прокомментировать несуществующий вопрос или ответ.
+	question, err := s.commentQuestion(parentType, parentID)
+	if err != nil {
+		s.logger.Warnf("Attempted to create comment for non-existent %s ID %d", parentType, parentID)
+		return err
+	}
+
+	comment.ParentType = parentType
+	comment.ParentID = parentID
+	if comment.UserID == uuid.Nil {
+		// Анонимный комментарий получает новый ID пользователя, как и анонимный ответ
+		comment.UserID = uuid.New()
+	}
+	return s.commit(
+		func(repo repository.Repository) error { return repo.CreateComment(comment) },
+		func() events.Event {
+			return withTags(commentEvent(events.TypeCommentCreated, question.ID, comment.ID, comment), question.Tags)
+		},
+	)
+}
+
+// GetComments получает комментарии к вопросу или ответу.
+func (s *questionAnswerService) GetComments(parentType string, parentID uint) ([]models.Comment, error) {
+	s.logger.Debugf("Getting comments for %s ID %d", parentType, parentID)
+	if _, err := s.commentQuestion(parentType, parentID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetComments(parentType, parentID)
+}
+
+// DeleteComment удаляет комментарий по ID. Удалить комментарий может его автор userID
+// или модератор.
+func (s *questionAnswerService) DeleteComment(id uint, userID uuid.UUID, moderator bool) error {
+	s.logger.Debugf("Deleting comment with ID: %d", id)
+	// Комментарий читается перед удалением: нужно проверить автора, а событию - ID и теги вопроса
+	comment, err := s.repo.GetComment(id)
+	if err != nil {
+		return notFound("comment", id, err)
+	}
+	if !moderator && comment.UserID != userID {
+		return ErrNotCommentAuthor
+	}
+	question, err := s.commentQuestion(comment.ParentType, comment.ParentID)
+	if err != nil {
+		return err
+	}
+	return s.commit(
+		func(repo repository.Repository) error { return repo.DeleteComment(id) },
+		func() events.Event {
+			return withTags(commentEvent(events.TypeCommentDeleted, question.ID, id, nil), question.Tags)
+		},
+	)
+}
+
+// commentQuestion проверяет, что родитель комментария существует, и возвращает вопрос,
+// к которому относится комментарий: события о комментариях публикуются для этого вопроса.
+func (s *questionAnswerService) commentQuestion(parentType string, parentID uint) (*models.Question, error) {
+	questionID := parentID
+	switch parentType {
+	case models.CommentParentQuestion:
+		// ID родителя - это ID вопроса
+	case models.CommentParentAnswer:
+		answer, err := s.repo.GetAnswer(parentID, query.Projection{})
+		if err != nil {
+			return nil, fmt.Errorf("%s with ID %d not found: %w", parentType, parentID, err)
+		}
+		questionID = answer.QuestionID
+	default:
+		return nil, fmt.Errorf("unknown comment parent type %q", parentType)
+	}
+	question, err := s.repo.GetQuestion(questionID, query.Projection{})
+	if err != nil {
+		return nil, fmt.Errorf("%s with ID %d not found: %w", parentType, parentID, err)
+	}
+	return question, nil
+}
+
+// questionTags возвращает теги вопроса для событий о его ответах.
+func (s *questionAnswerService) questionTags(questionID uint) (models.Tags, error) {
+	question, err := s.repo.GetQuestion(questionID, query.Projection{})
+	if err != nil {
+		return nil, fmt.Errorf("question with ID %d: %w", questionID, err)
+	}
+	return question.Tags, nil
+}
+
+// MarkDuplicate закрывает вопрос как дубликат другого вопроса.
+func (s *questionAnswerService) MarkDuplicate(id, duplicateOf uint, moderator uuid.UUID) error {
+	s.logger.Debugf("Marking question %d as duplicate of %d", id, duplicateOf)
+	if id == duplicateOf {
+		return fmt.Errorf("question %d cannot duplicate itself: %w", id, ErrInvalidDuplicate)
+	}
+
+	question, err := s.repo.GetQuestion(id, query.Projection{})
+	if err != nil {
+		s.logger.Warnf("Attempted to mark non-existent question ID %d as duplicate: %v", id, err)
+		return notFound("question", id, err)
+	}
+	if question.Status == models.QuestionStatusLocked || question.Status == models.QuestionStatusArchived {
+		return fmt.Errorf("question with ID %d is %s: %w", id, question.Status, ErrInvalidStatusTransition)
+	}
+	original, err := s.repo.GetQuestion(duplicateOf, query.Projection{})
+	if err != nil {
+		s.logger.Warnf("Attempted to mark question as duplicate of non-existent question ID %d: %v", duplicateOf, err)
+		return notFound("question", duplicateOf, err)
+	}
+
+	// Бизнес-логика: если исходный вопрос сам закрыт как дубликат, ссылаемся на его оригинал.
+	if original.DuplicateOf != nil {
+		duplicateOf = *original.DuplicateOf
+		if duplicateOf == id {
+			return fmt.Errorf("question %d is already a duplicate of %d: %w", original.ID, id, ErrInvalidDuplicate)
+		}
+	}
+
+	now := time.Now()
+	question.Status = models.QuestionStatusClosed
+	question.CloseReason = models.CloseReasonDuplicate
+	question.ClosedBy = &moderator
+	question.ClosedAt = &now
+	question.DuplicateOf = &duplicateOf
+	return s.commit(
+		func(repo repository.Repository) error { return versionConflict(repo.MarkDuplicate(question)) },
+		func() events.Event { return questionEvent(events.TypeQuestionMarkedDuplicate, question.ID, question) },
+	)
+}
+
+// CloseQuestion закрывает открытый вопрос с указанием причины.
+func (s *questionAnswerService) CloseQuestion(id uint, moderator uuid.UUID, reason string) (*models.Question, error) {
+	s.logger.Debugf("Closing question %d: %s", id, reason)
+	return s.changeStatus(id, models.QuestionStatusClosed, events.TypeQuestionClosed, func(q *models.Question) {
+		now := time.Now()
+		q.CloseReason = reason
+		q.ClosedBy = &moderator
+		q.ClosedAt = &now
+	})
+}
+
+// ReopenQuestion снова открывает закрытый или заблокированный вопрос.
+// Сведения о закрытии и ссылка на исходный вопрос сбрасываются.
+func (s *questionAnswerService) ReopenQuestion(id uint) (*models.Question, error) {
+	s.logger.Debugf("Reopening question %d", id)
+	return s.changeStatus(id, models.QuestionStatusOpen, events.TypeQuestionReopened, func(q *models.Question) {
+		q.CloseReason = ""
+		q.ClosedBy = nil
+		q.ClosedAt = nil
+		q.DuplicateOf = nil
+	})
+}
+
+// LockQuestion блокирует вопрос: на него нельзя отвечать, и его нельзя закрыть повторно.
+// Причина закрытия, если она была, сохраняется.
+func (s *questionAnswerService) LockQuestion(id uint, moderator uuid.UUID) (*models.Question, error) {
+	s.logger.Debugf("Locking question %d", id)
+	return s.changeStatus(id, models.QuestionStatusLocked, events.TypeQuestionLocked, func(q *models.Question) {
+		now := time.Now()
+		q.ClosedBy = &moderator
+		q.ClosedAt = &now
+	})
+}
+
+// ArchiveQuestion переводит вопрос в архив: он остается доступным только для чтения, на него нельзя отвечать,
+// и его статус больше не меняется. Причина закрытия, если она была, сохраняется.
+func (s *questionAnswerService) ArchiveQuestion(id uint, moderator uuid.UUID) (*models.Question, error) {
+	s.logger.Debugf("Archiving question %d", id)
+	return s.changeStatus(id, models.QuestionStatusArchived, events.TypeQuestionArchived, func(q *models.Question) {
+		now := time.Now()
+		q.ClosedBy = &moderator
+		q.ClosedAt = &now
+	})
+}
+
+// statusTransitions - статусы, из которых вопрос можно перевести в заданный.
+var statusTransitions = map[string][]string{
+	models.QuestionStatusOpen:   {models.QuestionStatusClosed, models.QuestionStatusLocked},
+	models.QuestionStatusClosed: {models.QuestionStatusOpen},
+	models.QuestionStatusLocked: {models.QuestionStatusOpen, models.QuestionStatusClosed},
+	models.QuestionStatusArchived: {
+		models.QuestionStatusOpen, models.QuestionStatusClosed, models.QuestionStatusLocked,
+	},
+}
+
+// changeStatus проверяет допустимость перехода, применяет apply, сохраняет новый статус
+// и публикует событие eventType.
+func (s *questionAnswerService) changeStatus(
+	id uint, status, eventType string, apply func(q *models.Question),
+) (*models.Question, error) {
+	question, err := s.repo.GetQuestion(id, query.Projection{})
+	if err != nil {
+		s.logger.Warnf("Attempted to change status of non-existent question ID %d: %v", id, err)
+		return nil, notFound("question", id, err)
+	}
+
+	allowed := false
+	for _, from := range statusTransitions[status] {
+		if question.Status == from {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("question with ID %d is %s, cannot become %s: %w",
+			id, question.Status, status, ErrInvalidStatusTransition)
+	}
+
+	question.Status = status
+	apply(question)
+	err = s.commit(
+		func(repo repository.Repository) error { return versionConflict(repo.UpdateQuestionStatus(question)) },
+		func() events.Event { return questionEvent(eventType, question.ID, question) },
+	)
+	if err != nil {
+		return nil, err
+	}
+	return question, nil
 }
@@ -5,11 +5,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 
+	"github.com/shenikar/question-service/internal/events"
 	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/outbox"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/repository"
 )
 
 // MockRepository - мок для интерфейса repository.Repository
@@ -22,24 +28,34 @@ func (m *MockRepository) CreateQuestion(question *models.Question) error {
 	return args.Error(0)
 }
 
-func (m *MockRepository) GetQuestion(id uint) (*models.Question, error) {
-	args := m.Called(id)
+func (m *MockRepository) GetQuestion(id uint, proj query.Projection) (*models.Question, error) {
+	args := m.Called(id, proj)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Question), args.Error(1)
 }
 
-func (m *MockRepository) GetAllQuestions() ([]models.Question, error) {
-	args := m.Called()
+func (m *MockRepository) GetAllQuestions(spec query.QuestionSpec, proj query.Projection) ([]models.Question, error) {
+	args := m.Called(spec, proj)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]models.Question), args.Error(1)
 }
 
-func (m *MockRepository) DeleteQuestion(id uint) error {
-	args := m.Called(id)
+func (m *MockRepository) FindQuestionsByIDs(ids []uint) (map[uint]models.Question, error) {
+	args := m.Called(ids)
+	return args.Get(0).(map[uint]models.Question), args.Error(1)
+}
+
+func (m *MockRepository) UpdateQuestion(question *models.Question) error {
+	args := m.Called(question)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteQuestion(id uint, version int) error {
+	args := m.Called(id, version)
 	return args.Error(0)
 }
 
@@ -48,59 +64,323 @@ func (m *MockRepository) CreateAnswer(answer *models.Answer) error {
 	return args.Error(0)
 }
 
-func (m *MockRepository) GetAnswer(id uint) (*models.Answer, error) {
-	args := m.Called(id)
+func (m *MockRepository) GetAnswer(id uint, proj query.Projection) (*models.Answer, error) {
+	args := m.Called(id, proj)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Answer), args.Error(1)
 }
 
-func (m *MockRepository) DeleteAnswer(id uint) error {
+func (m *MockRepository) FindAnswersByQuestionIDs(questionIDs []uint) (map[uint][]models.Answer, error) {
+	args := m.Called(questionIDs)
+	return args.Get(0).(map[uint][]models.Answer), args.Error(1)
+}
+
+func (m *MockRepository) UpdateAnswer(answer *models.Answer) error {
+	args := m.Called(answer)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteAnswer(id uint, version int) error {
+	args := m.Called(id, version)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateComment(comment *models.Comment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetComment(id uint) (*models.Comment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Comment), args.Error(1)
+}
+
+func (m *MockRepository) GetComments(parentType string, parentID uint) ([]models.Comment, error) {
+	args := m.Called(parentType, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Comment), args.Error(1)
+}
+
+func (m *MockRepository) DeleteComment(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindSimilarQuestions(
+	title string, threshold float64, limit int,
+) ([]models.SimilarQuestion, error) {
+	args := m.Called(title, threshold, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SimilarQuestion), args.Error(1)
+}
+
+func (m *MockRepository) MarkDuplicate(question *models.Question) error {
+	args := m.Called(question)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateQuestionStatus(question *models.Question) error {
+	args := m.Called(question)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetQuestionStatusForUpdate(id uint) (string, error) {
 	args := m.Called(id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) AcceptAnswer(question *models.Question) error {
+	args := m.Called(question)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateQuestions(questions []*models.Question) error {
+	args := m.Called(questions)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindQuestionsInBatches(
+	spec query.QuestionSpec, batchSize int, fn func([]models.Question) error,
+) error {
+	args := m.Called(spec, batchSize, fn)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindQuestionIDsByTitle(titles []string) (map[string]uint, error) {
+	args := m.Called(titles)
+	return args.Get(0).(map[string]uint), args.Error(1)
+}
+
+func (m *MockRepository) CreateExternalPosts(source string, posts []repository.ExternalPost) error {
+	args := m.Called(source, posts)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindImportedPosts(source string, externalIDs []int64) (map[int64]models.ImportedPost, error) {
+	args := m.Called(source, externalIDs)
+	return args.Get(0).(map[int64]models.ImportedPost), args.Error(1)
+}
+
+func (m *MockRepository) CreateImportedUsers(users []models.ImportedUser) error {
+	args := m.Called(users)
 	return args.Error(0)
 }
 
+func (m *MockRepository) FindImportedUsers(source string, externalIDs []int64) (map[int64]uuid.UUID, error) {
+	args := m.Called(source, externalIDs)
+	return args.Get(0).(map[int64]uuid.UUID), args.Error(1)
+}
+
+// Transaction выполняет fn с тем же моком: вызовы внутри транзакции проверяются как обычные.
+func (m *MockRepository) Transaction(fn func(repo repository.Repository) error) error {
+	return fn(m)
+}
+
+func (m *MockRepository) CreateOutboxMessage(msg *models.OutboxMessage) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
+
+// duplicateOf проверяет, что вопрос закрыт как дубликат вопроса с указанным ID.
+func duplicateOf(id uint) interface{} {
+	return mock.MatchedBy(func(q *models.Question) bool {
+		return q.DuplicateOf != nil && *q.DuplicateOf == id &&
+			q.Status == models.QuestionStatusClosed && q.CloseReason == models.CloseReasonDuplicate
+	})
+}
+
 func TestCreateQuestionService(t *testing.T) {
 	mockRepo := new(MockRepository)
 	logger := logrus.New()
-	service := NewService(mockRepo, logger)
+	service := NewService(mockRepo, logger, nil)
 
 	question := &models.Question{
-		Text: "Test Question",
+		Title: "Test Question",
+		Tags:  models.Tags{"Go", "linux", "go"},
+	}
+
+	mockRepo.On("FindSimilarQuestions", question.Title, mock.Anything, mock.Anything).Return(nil, nil)
+	mockRepo.On("CreateQuestion", question).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	duplicates, err := service.CreateQuestion(question, false)
+	assert.NoError(t, err)
+	assert.Empty(t, duplicates)
+	assert.Equal(t, models.QuestionStatusOpen, question.Status)
+	assert.Equal(t, models.Tags{"go", "linux"}, question.Tags)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateQuestionServiceInvalidTags(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, logrus.New(), nil)
+
+	_, err := service.CreateQuestion(&models.Question{Title: "Test Question", Tags: models.Tags{"no spaces"}}, false)
+	assert.ErrorIs(t, err, ErrInvalidTags)
+	mockRepo.AssertNotCalled(t, "CreateQuestion", mock.Anything)
+}
+
+func TestCreateQuestionServicePublishesEvent(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	question := &models.Question{Title: "Test Question"}
+	mockRepo.On("FindSimilarQuestions", question.Title, mock.Anything, mock.Anything).Return(nil, nil)
+	mockRepo.On("CreateQuestion", question).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Question).ID = 4
+	}).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	_, err := service.CreateQuestion(question, false)
+	assert.NoError(t, err)
+	if assert.Len(t, publisher.events, 1) {
+		created := publisher.events[0]
+		assert.Equal(t, events.TypeQuestionCreated, created.Type)
+		assert.Equal(t, uint(4), created.QuestionID)
+		// Событие хранит копию вопроса
+		question.Title = "Changed by caller"
+		assert.Equal(t, "Test Question", created.Question.Title)
+	}
+}
+
+func TestCommitAssignsStableEventUUID(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	var stored *models.OutboxMessage
+	mockRepo.On("FindSimilarQuestions", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockRepo.On("CreateQuestion", mock.Anything).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.OutboxMessage)
+	}).Return(nil)
+
+	_, err := service.CreateQuestion(&models.Question{Title: "First question"}, false)
+	assert.NoError(t, err)
+	_, err = service.CreateQuestion(&models.Question{Title: "Second question"}, false)
+	assert.NoError(t, err)
+
+	// Событие из outbox имеет тот же идентификатор, что и опубликованное сразу
+	if assert.Len(t, publisher.events, 2) && assert.NotNil(t, stored) {
+		assert.NotEqual(t, uuid.Nil, publisher.events[0].UUID)
+		assert.NotEqual(t, publisher.events[0].UUID, publisher.events[1].UUID)
+		decoded, err := outbox.Decode(stored)
+		assert.NoError(t, err)
+		assert.Equal(t, publisher.events[1].UUID, decoded.UUID)
+	}
+}
+
+func TestPublishers(t *testing.T) {
+	first, second := &recordingPublisher{}, &recordingPublisher{}
+	Publishers{first, second}.Publish(events.Event{Type: events.TypeQuestionDeleted, QuestionID: 1})
+	assert.Len(t, first.events, 1)
+	assert.Len(t, second.events, 1)
+}
+
+func TestCreateQuestionServiceReturnsPossibleDuplicates(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	service := NewService(mockRepo, logger, nil)
+
+	question := &models.Question{Title: "How to install Go?"}
+	similar := []models.SimilarQuestion{
+		{Question: models.Question{ID: 7, Title: "How do I install Go?"}, Similarity: 0.8},
 	}
 
+	mockRepo.On("FindSimilarQuestions", question.Title, mock.Anything, mock.Anything).Return(similar, nil)
 	mockRepo.On("CreateQuestion", question).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
 
-	err := service.CreateQuestion(question)
+	duplicates, err := service.CreateQuestion(question, false)
 	assert.NoError(t, err)
+	assert.Equal(t, similar, duplicates)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateQuestionServiceStrictRejectsDuplicates(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	service := NewService(mockRepo, logger, nil)
+
+	question := &models.Question{Title: "How to install Go?"}
+	similar := []models.SimilarQuestion{
+		{Question: models.Question{ID: 7, Title: "How do I install Go?"}, Similarity: 0.8},
+	}
+
+	mockRepo.On("FindSimilarQuestions", question.Title, mock.Anything, mock.Anything).Return(similar, nil)
+
+	duplicates, err := service.CreateQuestion(question, true)
+	assert.ErrorIs(t, err, ErrDuplicateQuestion)
+	assert.Len(t, duplicates, 1)
+	mockRepo.AssertNotCalled(t, "CreateQuestion", mock.Anything)
+}
+
+func TestCreateQuestionServiceSimilaritySearchFailure(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	service := NewService(mockRepo, logger, nil)
+
+	question := &models.Question{Title: "How to install Go?"}
+
+	// Ошибка поиска дубликатов не мешает создать вопрос в нестрогом режиме
+	mockRepo.On("FindSimilarQuestions", question.Title, mock.Anything, mock.Anything).
+		Return(nil, errors.New("pg_trgm is not installed"))
+	mockRepo.On("CreateQuestion", question).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	duplicates, err := service.CreateQuestion(question, false)
+	assert.NoError(t, err)
+	assert.Empty(t, duplicates)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestGetQuestionService(t *testing.T) {
 	mockRepo := new(MockRepository)
 	logger := logrus.New()
-	service := NewService(mockRepo, logger)
+	service := NewService(mockRepo, logger, nil)
 
 	expectedQuestion := &models.Question{
 		ID:        1,
-		Text:      "Test Question",
+		Title:     "Test Question",
 		CreatedAt: time.Now(),
 	}
 
-	mockRepo.On("GetQuestion", uint(1)).Return(expectedQuestion, nil)
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(expectedQuestion, nil)
 
-	question, err := service.GetQuestion(1)
+	question, err := service.GetQuestion(1, query.Projection{})
 	assert.NoError(t, err)
 	assert.NotNil(t, question)
 	assert.Equal(t, expectedQuestion.ID, question.ID)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetAnswersByQuestionIDsService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, logrus.New(), nil)
+
+	expected := map[uint][]models.Answer{1: {{ID: 10, QuestionID: 1}}}
+	mockRepo.On("FindAnswersByQuestionIDs", []uint{1, 2}).Return(expected, nil)
+
+	answers, err := service.GetAnswersByQuestionIDs([]uint{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, answers)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestCreateAnswerService(t *testing.T) {
 	mockRepo := new(MockRepository)
 	logger := logrus.New()
-	service := NewService(mockRepo, logger)
+	service := NewService(mockRepo, logger, nil)
 
 	questionID := uint(1)
 	answer := &models.Answer{
@@ -108,26 +388,45 @@ func TestCreateAnswerService(t *testing.T) {
 	}
 	expectedQuestion := &models.Question{
 		ID:        questionID,
-		Text:      "Existing Question",
+		Title:     "Existing Question",
+		Status:    models.QuestionStatusOpen,
 		CreatedAt: time.Now(),
 	}
 
 	// Ожидаем, что сервис сначала проверит существование вопроса
-	mockRepo.On("GetQuestion", questionID).Return(expectedQuestion, nil)
+	mockRepo.On("GetQuestion", questionID, query.Projection{}).Return(expectedQuestion, nil)
 	// Затем ожидаем создание ответа
+	mockRepo.On("GetQuestionStatusForUpdate", uint(1)).Return(models.QuestionStatusOpen, nil)
 	mockRepo.On("CreateAnswer", mock.AnythingOfType("*models.Answer")).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
 
 	err := service.CreateAnswer(questionID, answer)
 	assert.NoError(t, err)
 	assert.Equal(t, questionID, answer.QuestionID)
-	assert.NotNil(t, answer.UserID) // Проверяем, что UserID был сгенерирован
+	assert.NotEqual(t, uuid.Nil, answer.UserID) // Проверяем, что UserID был сгенерирован
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateAnswerServiceKeepsAuthor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, logrus.New(), nil)
+
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).
+		Return(&models.Question{ID: 1, Status: models.QuestionStatusOpen}, nil)
+	mockRepo.On("GetQuestionStatusForUpdate", uint(1)).Return(models.QuestionStatusOpen, nil)
+	mockRepo.On("CreateAnswer", mock.AnythingOfType("*models.Answer")).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	author := uuid.New()
+	answer := &models.Answer{UserID: author, Text: "Test Answer"}
+	assert.NoError(t, service.CreateAnswer(1, answer))
+	assert.Equal(t, author, answer.UserID)
+}
+
 func TestCreateAnswerServiceQuestionNotFound(t *testing.T) {
 	mockRepo := new(MockRepository)
 	logger := logrus.New()
-	service := NewService(mockRepo, logger)
+	service := NewService(mockRepo, logger, nil)
 
 	questionID := uint(1)
 	answer := &models.Answer{
@@ -135,7 +434,7 @@ func TestCreateAnswerServiceQuestionNotFound(t *testing.T) {
 	}
 
 	// Ожидаем, что сервис проверит существование вопроса и вернет ошибку
-	mockRepo.On("GetQuestion", questionID).Return(nil, errors.New("not found"))
+	mockRepo.On("GetQuestion", questionID, query.Projection{}).Return(nil, errors.New("not found"))
 
 	err := service.CreateAnswer(questionID, answer)
 	assert.Error(t, err)
@@ -143,47 +442,169 @@ func TestCreateAnswerServiceQuestionNotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateAnswerServiceQuestionNotOpen(t *testing.T) {
+	statuses := []string{models.QuestionStatusClosed, models.QuestionStatusLocked, models.QuestionStatusArchived}
+	for _, status := range statuses {
+		t.Run(status, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			logger := logrus.New()
+			service := NewService(mockRepo, logger, nil)
+
+			mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(&models.Question{ID: 1, Status: status}, nil)
+
+			err := service.CreateAnswer(1, &models.Answer{Text: "Test Answer"})
+			assert.ErrorIs(t, err, ErrQuestionNotOpen)
+			mockRepo.AssertNotCalled(t, "CreateAnswer", mock.Anything)
+		})
+	}
+}
+
+func TestCreateAnswerServiceQuestionClosedConcurrently(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	// Вопрос закрыли между проверкой статуса и транзакцией
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).
+		Return(&models.Question{ID: 1, Status: models.QuestionStatusOpen}, nil)
+	mockRepo.On("GetQuestionStatusForUpdate", uint(1)).Return(models.QuestionStatusClosed, nil)
+
+	err := service.CreateAnswer(1, &models.Answer{Text: "Test Answer"})
+	assert.ErrorIs(t, err, ErrQuestionNotOpen)
+	mockRepo.AssertNotCalled(t, "CreateAnswer", mock.Anything)
+	assert.Empty(t, publisher.events)
+}
+
 func TestGetAllQuestionsService(t *testing.T) {
 	mockRepo := new(MockRepository)
 	logger := logrus.New()
-	service := NewService(mockRepo, logger)
+	service := NewService(mockRepo, logger, nil)
 
 	expectedQuestions := []models.Question{
-		{ID: 1, Text: "Q1"},
-		{ID: 2, Text: "Q2"},
+		{ID: 1, Title: "Q1"},
+		{ID: 2, Title: "Q2"},
 	}
 
-	mockRepo.On("GetAllQuestions").Return(expectedQuestions, nil)
+	spec := query.QuestionSpec{Statuses: []string{models.QuestionStatusOpen}}
+	mockRepo.On("GetAllQuestions", spec, query.Projection{}).Return(expectedQuestions, nil)
 
-	questions, err := service.GetAllQuestions()
+	questions, err := service.GetAllQuestions(spec, query.Projection{})
 	assert.NoError(t, err)
 	assert.Len(t, questions, 2)
-	assert.Equal(t, expectedQuestions[0].Text, questions[0].Text)
+	assert.Equal(t, expectedQuestions[0].Title, questions[0].Title)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestDeleteQuestionService(t *testing.T) {
 	mockRepo := new(MockRepository)
 	logger := logrus.New()
-	service := NewService(mockRepo, logger)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logger, publisher)
 
-	mockRepo.On("DeleteQuestion", uint(1)).Return(nil)
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(&models.Question{ID: 1, Version: 2}, nil)
+	mockRepo.On("DeleteQuestion", uint(1), 0).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
 
-	err := service.DeleteQuestion(1)
+	err := service.DeleteQuestion(1, nil)
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
+	if assert.Len(t, publisher.events, 1) {
+		assert.Equal(t, events.TypeQuestionDeleted, publisher.events[0].Type)
+		assert.Equal(t, uint(1), publisher.events[0].QuestionID)
+		assert.Nil(t, publisher.events[0].Question)
+	}
+}
+
+func TestDeleteMissingQuestionService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("DeleteQuestion", uint(1), 0).Return(nil)
+
+	assert.NoError(t, service.DeleteQuestion(1, nil))
+	mockRepo.AssertExpectations(t)
+	assert.Empty(t, publisher.events)
+}
+
+func TestUpdateQuestionService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	stored := &models.Question{ID: 1, Version: 2, Title: "Old title", Body: "Body"}
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(stored, nil)
+	mockRepo.On("UpdateQuestion", mock.MatchedBy(func(q *models.Question) bool {
+		return q.Title == "New title" && q.Body == "Body" && q.Version == 2
+	})).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	title := "New title"
+	question, err := service.UpdateQuestion(1, []int{1, 2}, &title, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "New title", question.Title)
+	mockRepo.AssertExpectations(t)
+	if assert.Len(t, publisher.events, 1) {
+		assert.Equal(t, events.TypeQuestionUpdated, publisher.events[0].Type)
+		assert.Equal(t, "New title", publisher.events[0].Question.Title)
+	}
+}
+
+func TestUpdateQuestionServiceStaleVersion(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, logrus.New(), nil)
+
+	stored := &models.Question{ID: 1, Version: 3, Title: "Title"}
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(stored, nil)
+
+	title := "New title"
+	_, err := service.UpdateQuestion(1, []int{2}, &title, nil)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	// Пустой список версий (только чужие ETag) тоже не совпадает с текущей версией
+	_, err = service.UpdateQuestion(1, []int{}, &title, nil)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	mockRepo.AssertNotCalled(t, "UpdateQuestion", mock.Anything)
+}
+
+func TestUpdateAnswerServiceConcurrentUpdate(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, logrus.New(), nil)
+
+	mockRepo.On("GetAnswer", uint(1), query.Projection{}).Return(&models.Answer{ID: 1, QuestionID: 3, Version: 1}, nil)
+	mockRepo.On("GetQuestion", uint(3), query.Projection{}).Return(&models.Question{ID: 3}, nil)
+	mockRepo.On("UpdateAnswer", mock.Anything).
+		Return(&repository.VersionConflictError{Entity: "answer", ID: 1, Version: 1})
+
+	_, err := service.UpdateAnswer(1, nil, "New text")
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteQuestionServiceIfMatch(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, logrus.New(), nil)
+
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(&models.Question{ID: 1, Version: 4}, nil)
+	mockRepo.On("DeleteQuestion", uint(1), 4).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	assert.ErrorIs(t, service.DeleteQuestion(1, []int{3}), ErrVersionConflict)
+	assert.NoError(t, service.DeleteQuestion(1, []int{4}))
+	mockRepo.AssertExpectations(t)
 }
 
 func TestGetAnswerService(t *testing.T) {
 	mockRepo := new(MockRepository)
 	logger := logrus.New()
-	service := NewService(mockRepo, logger)
+	service := NewService(mockRepo, logger, nil)
 
 	expectedAnswer := &models.Answer{ID: 1, Text: "A1"}
 
-	mockRepo.On("GetAnswer", uint(1)).Return(expectedAnswer, nil)
+	mockRepo.On("GetAnswer", uint(1), query.Projection{}).Return(expectedAnswer, nil)
 
-	answer, err := service.GetAnswer(1)
+	answer, err := service.GetAnswer(1, query.Projection{})
 	assert.NoError(t, err)
 	assert.NotNil(t, answer)
 	assert.Equal(t, expectedAnswer.ID, answer.ID)
@@ -193,11 +614,612 @@ func TestGetAnswerService(t *testing.T) {
 func TestDeleteAnswerService(t *testing.T) {
 	mockRepo := new(MockRepository)
 	logger := logrus.New()
-	service := NewService(mockRepo, logger)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logger, publisher)
+
+	mockRepo.On("GetAnswer", uint(1), query.Projection{}).Return(&models.Answer{ID: 1, QuestionID: 3}, nil)
+	mockRepo.On("GetQuestion", uint(3), query.Projection{}).
+		Return(&models.Question{ID: 3, Tags: models.Tags{"go"}}, nil)
+	mockRepo.On("DeleteAnswer", uint(1), 0).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	err := service.DeleteAnswer(1, nil)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	if assert.Len(t, publisher.events, 1) {
+		deleted := publisher.events[0]
+		assert.Equal(t, events.TypeAnswerDeleted, deleted.Type)
+		assert.Equal(t, uint(3), deleted.QuestionID)
+		assert.Equal(t, uint(1), deleted.AnswerID)
+		assert.Nil(t, deleted.Answer)
+		assert.Equal(t, []string{"go"}, deleted.Tags)
+		assert.False(t, deleted.Time.IsZero())
+	}
+}
+
+func TestDeleteMissingAnswerService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	// Удаление без условия остается идемпотентным, но событие не публикуется
+	mockRepo.On("GetAnswer", uint(1), query.Projection{}).Return(&models.Answer{}, gorm.ErrRecordNotFound)
+	mockRepo.On("DeleteAnswer", uint(1), 0).Return(nil)
+
+	assert.NoError(t, service.DeleteAnswer(1, nil))
+	mockRepo.AssertExpectations(t)
+	assert.Empty(t, publisher.events)
+}
+
+// recordingPublisher запоминает опубликованные события.
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestAnswerEventsPublished(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).
+		Return(&models.Question{ID: 1, Status: models.QuestionStatusOpen, Tags: models.Tags{"go", "linux"}}, nil)
+	mockRepo.On("GetQuestionStatusForUpdate", uint(1)).Return(models.QuestionStatusOpen, nil)
+	mockRepo.On("CreateAnswer", mock.AnythingOfType("*models.Answer")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Answer).ID = 5
+	}).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+	mockRepo.On("GetAnswer", uint(5), query.Projection{}).
+		Return(&models.Answer{ID: 5, QuestionID: 1, Text: "Old text", Version: 1}, nil)
+	mockRepo.On("UpdateAnswer", mock.AnythingOfType("*models.Answer")).Return(nil)
+
+	answer := &models.Answer{Text: "Test Answer"}
+	assert.NoError(t, service.CreateAnswer(1, answer))
+	_, err := service.UpdateAnswer(5, nil, "New text")
+	assert.NoError(t, err)
+
+	if assert.Len(t, publisher.events, 2) {
+		created := publisher.events[0]
+		assert.Equal(t, events.TypeAnswerCreated, created.Type)
+		assert.Equal(t, uint(1), created.QuestionID)
+		assert.Equal(t, uint(5), created.AnswerID)
+		// События об ответах рассылаются по тегам вопроса
+		assert.Equal(t, []string{"go", "linux"}, created.Tags)
+		// Событие хранит копию ответа
+		answer.Text = "Changed by caller"
+		assert.Equal(t, "Test Answer", created.Answer.Text)
+
+		updated := publisher.events[1]
+		assert.Equal(t, events.TypeAnswerUpdated, updated.Type)
+		assert.Equal(t, "New text", updated.Answer.Text)
+		assert.Equal(t, []string{"go", "linux"}, updated.Tags)
+	}
+}
+
+func TestAnswerEventNotPublishedOnError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	mockRepo.On("GetAnswer", uint(1), query.Projection{}).Return(&models.Answer{ID: 1, QuestionID: 3, Version: 2}, nil)
+
+	err := service.DeleteAnswer(1, []int{1})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.Empty(t, publisher.events)
+}
+
+func TestCreateAnswerServiceWritesOutbox(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, logrus.New(), nil)
+
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).
+		Return(&models.Question{ID: 1, Status: models.QuestionStatusOpen}, nil)
+	mockRepo.On("GetQuestionStatusForUpdate", uint(1)).Return(models.QuestionStatusOpen, nil)
+	mockRepo.On("CreateAnswer", mock.AnythingOfType("*models.Answer")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Answer).ID = 5
+	}).Return(nil)
+	var written *models.OutboxMessage
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Run(func(args mock.Arguments) {
+		written = args.Get(0).(*models.OutboxMessage)
+	}).Return(nil)
+
+	assert.NoError(t, service.CreateAnswer(1, &models.Answer{Text: "Test Answer"}))
+	if assert.NotNil(t, written) {
+		assert.Equal(t, outbox.AggregateQuestion, written.AggregateType)
+		assert.Equal(t, uint(1), written.AggregateID)
+		assert.Equal(t, events.TypeAnswerCreated, written.EventType)
+		event, err := outbox.Decode(written)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(5), event.AnswerID)
+		assert.Equal(t, "Test Answer", event.Answer.Text)
+	}
+}
+
+func TestCreateAnswerServiceOutboxFailure(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).
+		Return(&models.Question{ID: 1, Status: models.QuestionStatusOpen}, nil)
+	mockRepo.On("GetQuestionStatusForUpdate", uint(1)).Return(models.QuestionStatusOpen, nil)
+	mockRepo.On("CreateAnswer", mock.AnythingOfType("*models.Answer")).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(errors.New("connection reset"))
+
+	// Ошибка записи в outbox отменяет транзакцию вместе с ответом
+	assert.Error(t, service.CreateAnswer(1, &models.Answer{Text: "Test Answer"}))
+	assert.Empty(t, publisher.events)
+}
+
+func TestCreateCommentServiceOnAnswer(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logger, publisher)
+
+	comment := &models.Comment{Text: "Test Comment"}
+
+	mockRepo.On("GetAnswer", uint(2), query.Projection{}).Return(&models.Answer{ID: 2, QuestionID: 1}, nil)
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).
+		Return(&models.Question{ID: 1, Tags: models.Tags{"go"}}, nil)
+	mockRepo.On("CreateComment", mock.AnythingOfType("*models.Comment")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Comment).ID = 5
+	}).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	err := service.CreateComment(models.CommentParentAnswer, 2, comment)
+	assert.NoError(t, err)
+	assert.Equal(t, models.CommentParentAnswer, comment.ParentType)
+	assert.Equal(t, uint(2), comment.ParentID)
+	assert.NotEqual(t, uuid.Nil, comment.UserID)
+	mockRepo.AssertExpectations(t)
+	// Событие о комментарии к ответу относится к вопросу этого ответа
+	if assert.Len(t, publisher.events, 1) {
+		created := publisher.events[0]
+		assert.Equal(t, events.TypeCommentCreated, created.Type)
+		assert.Equal(t, uint(1), created.QuestionID)
+		assert.Equal(t, uint(5), created.CommentID)
+		assert.Equal(t, "Test Comment", created.Comment.Text)
+		assert.Equal(t, []string{"go"}, created.Tags)
+	}
+}
+
+func TestCreateCommentServiceKeepsAuthor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, logrus.New(), nil)
+
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(&models.Question{ID: 1}, nil)
+	mockRepo.On("CreateComment", mock.AnythingOfType("*models.Comment")).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	author := uuid.New()
+	comment := &models.Comment{UserID: author, Text: "Test Comment"}
+	assert.NoError(t, service.CreateComment(models.CommentParentQuestion, 1, comment))
+	assert.Equal(t, author, comment.UserID)
+}
+
+func TestCreateCommentServiceParentNotFound(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	service := NewService(mockRepo, logger, nil)
+
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(nil, errors.New("not found"))
+
+	err := service.CreateComment(models.CommentParentQuestion, 1, &models.Comment{Text: "Test Comment"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "question with ID 1 not found")
+	mockRepo.AssertNotCalled(t, "CreateComment", mock.Anything)
+}
+
+func TestGetCommentsService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	service := NewService(mockRepo, logger, nil)
+
+	expectedComments := []models.Comment{{ID: 1, Text: "C1"}}
+
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(&models.Question{ID: 1}, nil)
+	mockRepo.On("GetComments", models.CommentParentQuestion, uint(1)).Return(expectedComments, nil)
+
+	comments, err := service.GetComments(models.CommentParentQuestion, 1)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteCommentService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logger, publisher)
+
+	author := uuid.New()
+	mockRepo.On("GetComment", uint(1)).
+		Return(&models.Comment{ID: 1, ParentType: models.CommentParentQuestion, ParentID: 3, UserID: author}, nil)
+	mockRepo.On("GetQuestion", uint(3), query.Projection{}).Return(&models.Question{ID: 3}, nil)
+	mockRepo.On("DeleteComment", uint(1)).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	err := service.DeleteComment(1, author, false)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	if assert.Len(t, publisher.events, 1) {
+		assert.Equal(t, events.TypeCommentDeleted, publisher.events[0].Type)
+		assert.Equal(t, uint(3), publisher.events[0].QuestionID)
+		assert.Equal(t, uint(1), publisher.events[0].CommentID)
+		assert.Nil(t, publisher.events[0].Comment)
+	}
+}
+
+func TestDeleteMissingCommentService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	mockRepo.On("GetComment", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	err := service.DeleteComment(1, uuid.New(), true)
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "DeleteComment", mock.Anything)
+	assert.Empty(t, publisher.events)
+}
+
+func TestDeleteCommentServiceStorageError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, logrus.New(), nil)
+
+	storageErr := errors.New("connection refused")
+	mockRepo.On("GetComment", uint(1)).Return(nil, storageErr)
+
+	err := service.DeleteComment(1, uuid.New(), true)
+	assert.ErrorIs(t, err, storageErr)
+	assert.NotErrorIs(t, err, ErrNotFound)
+	mockRepo.AssertNotCalled(t, "DeleteComment", mock.Anything)
+}
+
+func TestDeleteCommentServiceNotAuthor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	mockRepo.On("GetComment", uint(1)).
+		Return(&models.Comment{ID: 1, ParentType: models.CommentParentQuestion, ParentID: 3, UserID: uuid.New()}, nil)
+
+	err := service.DeleteComment(1, uuid.New(), false)
+	assert.ErrorIs(t, err, ErrNotCommentAuthor)
+	mockRepo.AssertNotCalled(t, "DeleteComment", mock.Anything)
+	assert.Empty(t, publisher.events)
+}
+
+func TestDeleteCommentServiceModerator(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo, logrus.New(), nil)
+
+	mockRepo.On("GetComment", uint(1)).
+		Return(&models.Comment{ID: 1, ParentType: models.CommentParentQuestion, ParentID: 3, UserID: uuid.New()}, nil)
+	mockRepo.On("GetQuestion", uint(3), query.Projection{}).Return(&models.Question{ID: 3}, nil)
+	mockRepo.On("DeleteComment", uint(1)).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	assert.NoError(t, service.DeleteComment(1, uuid.New(), true))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMarkDuplicateService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logger, publisher)
+
+	mockRepo.On("GetQuestion", uint(2), query.Projection{}).Return(&models.Question{ID: 2}, nil)
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(&models.Question{ID: 1}, nil)
+	mockRepo.On("MarkDuplicate", duplicateOf(1)).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	err := service.MarkDuplicate(2, 1, uuid.New())
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	if assert.Len(t, publisher.events, 1) {
+		assert.Equal(t, events.TypeQuestionMarkedDuplicate, publisher.events[0].Type)
+		assert.Equal(t, uint(2), publisher.events[0].QuestionID)
+	}
+}
+
+func TestMarkDuplicateServiceFollowsOriginal(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	service := NewService(mockRepo, logger, nil)
+
+	original := uint(1)
+	mockRepo.On("GetQuestion", uint(3), query.Projection{}).Return(&models.Question{ID: 3}, nil)
+	mockRepo.On("GetQuestion", uint(2), query.Projection{}).Return(&models.Question{ID: 2, DuplicateOf: &original}, nil)
+	mockRepo.On("MarkDuplicate", duplicateOf(1)).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	err := service.MarkDuplicate(3, 2, uuid.New())
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMarkDuplicateServiceInvalidTarget(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	service := NewService(mockRepo, logger, nil)
+
+	err := service.MarkDuplicate(1, 1, uuid.New())
+	assert.ErrorIs(t, err, ErrInvalidDuplicate)
+
+	duplicateOfFirst := uint(1)
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(&models.Question{ID: 1}, nil)
+	mockRepo.On("GetQuestion", uint(2), query.Projection{}).
+		Return(&models.Question{ID: 2, DuplicateOf: &duplicateOfFirst}, nil)
+
+	err = service.MarkDuplicate(1, 2, uuid.New())
+	assert.ErrorIs(t, err, ErrInvalidDuplicate)
+	mockRepo.AssertNotCalled(t, "MarkDuplicate", mock.Anything)
+}
+
+func TestMarkDuplicateServiceNotFound(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	service := NewService(mockRepo, logger, nil)
+
+	mockRepo.On("GetQuestion", uint(2), query.Projection{}).Return(&models.Question{ID: 2}, nil)
+	mockRepo.On("GetQuestion", uint(999), query.Projection{}).Return(nil, gorm.ErrRecordNotFound)
+
+	err := service.MarkDuplicate(2, 999, uuid.New())
+	assert.ErrorIs(t, err, ErrNotFound)
+	mockRepo.AssertNotCalled(t, "MarkDuplicate", mock.Anything)
+}
+
+func TestCloseQuestionService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logger, publisher)
+
+	moderator := uuid.New()
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).
+		Return(&models.Question{ID: 1, Status: models.QuestionStatusOpen}, nil)
+	mockRepo.On("UpdateQuestionStatus", mock.AnythingOfType("*models.Question")).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	question, err := service.CloseQuestion(1, moderator, "Off-topic")
+	assert.NoError(t, err)
+	assert.Equal(t, models.QuestionStatusClosed, question.Status)
+	assert.Equal(t, "Off-topic", question.CloseReason)
+	assert.Equal(t, &moderator, question.ClosedBy)
+	assert.NotNil(t, question.ClosedAt)
+	mockRepo.AssertExpectations(t)
+	if assert.Len(t, publisher.events, 1) {
+		assert.Equal(t, events.TypeQuestionClosed, publisher.events[0].Type)
+		assert.Equal(t, "Off-topic", publisher.events[0].Question.CloseReason)
+	}
+}
+
+func TestArchiveQuestionService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	moderator := uuid.New()
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(&models.Question{
+		ID: 1, Status: models.QuestionStatusClosed, CloseReason: "Off-topic",
+	}, nil)
+	mockRepo.On("UpdateQuestionStatus", mock.AnythingOfType("*models.Question")).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	question, err := service.ArchiveQuestion(1, moderator)
+	assert.NoError(t, err)
+	assert.Equal(t, models.QuestionStatusArchived, question.Status)
+	assert.Equal(t, "Off-topic", question.CloseReason)
+	assert.Equal(t, &moderator, question.ClosedBy)
+	assert.NotNil(t, question.ClosedAt)
+	mockRepo.AssertExpectations(t)
+	if assert.Len(t, publisher.events, 1) {
+		assert.Equal(t, events.TypeQuestionArchived, publisher.events[0].Type)
+	}
+}
+
+func TestReopenQuestionServiceClearsCloseDetails(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	service := NewService(mockRepo, logger, nil)
 
-	mockRepo.On("DeleteAnswer", uint(1)).Return(nil)
+	moderator, closedAt, original := uuid.New(), time.Now(), uint(2)
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(&models.Question{
+		ID:          1,
+		Status:      models.QuestionStatusClosed,
+		CloseReason: models.CloseReasonDuplicate,
+		ClosedBy:    &moderator,
+		ClosedAt:    &closedAt,
+		DuplicateOf: &original,
+	}, nil)
+	mockRepo.On("UpdateQuestionStatus", mock.AnythingOfType("*models.Question")).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
 
-	err := service.DeleteAnswer(1)
+	question, err := service.ReopenQuestion(1)
 	assert.NoError(t, err)
+	assert.Equal(t, models.QuestionStatusOpen, question.Status)
+	assert.Empty(t, question.CloseReason)
+	assert.Nil(t, question.ClosedBy)
+	assert.Nil(t, question.ClosedAt)
+	assert.Nil(t, question.DuplicateOf)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestChangeStatusServiceInvalidTransition(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		change func(s Service) (*models.Question, error)
+	}{
+		{"close closed", models.QuestionStatusClosed, func(s Service) (*models.Question, error) {
+			return s.CloseQuestion(1, uuid.New(), "Off-topic")
+		}},
+		{"close locked", models.QuestionStatusLocked, func(s Service) (*models.Question, error) {
+			return s.CloseQuestion(1, uuid.New(), "Off-topic")
+		}},
+		{"reopen open", models.QuestionStatusOpen, func(s Service) (*models.Question, error) {
+			return s.ReopenQuestion(1)
+		}},
+		{"lock locked", models.QuestionStatusLocked, func(s Service) (*models.Question, error) {
+			return s.LockQuestion(1, uuid.New())
+		}},
+		{"reopen archived", models.QuestionStatusArchived, func(s Service) (*models.Question, error) {
+			return s.ReopenQuestion(1)
+		}},
+		{"lock archived", models.QuestionStatusArchived, func(s Service) (*models.Question, error) {
+			return s.LockQuestion(1, uuid.New())
+		}},
+		{"archive archived", models.QuestionStatusArchived, func(s Service) (*models.Question, error) {
+			return s.ArchiveQuestion(1, uuid.New())
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			logger := logrus.New()
+			service := NewService(mockRepo, logger, nil)
+
+			mockRepo.On("GetQuestion", uint(1), query.Projection{}).
+				Return(&models.Question{ID: 1, Status: tt.status}, nil)
+
+			_, err := tt.change(service)
+			assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+			mockRepo.AssertNotCalled(t, "UpdateQuestionStatus", mock.Anything)
+		})
+	}
+}
+
+func TestChangeStatusServiceNotFound(t *testing.T) {
+	mockRepo := new(MockRepository)
+	logger := logrus.New()
+	service := NewService(mockRepo, logger, nil)
+
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.LockQuestion(1, uuid.New())
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestServiceStorageErrorIsNotNotFound(t *testing.T) {
+	storageErr := errors.New("connection refused")
+	tests := []struct {
+		name string
+		call func(s Service) error
+	}{
+		{"update question", func(s Service) error {
+			_, err := s.UpdateQuestion(1, nil, nil, nil)
+			return err
+		}},
+		{"delete question", func(s Service) error { return s.DeleteQuestion(1, nil) }},
+		{"update answer", func(s Service) error {
+			_, err := s.UpdateAnswer(1, nil, "Text")
+			return err
+		}},
+		{"delete answer", func(s Service) error { return s.DeleteAnswer(1, nil) }},
+		{"accept answer", func(s Service) error {
+			_, err := s.AcceptAnswer(1, uuid.New())
+			return err
+		}},
+		{"mark duplicate", func(s Service) error { return s.MarkDuplicate(1, 2, uuid.New()) }},
+		{"lock question", func(s Service) error {
+			_, err := s.LockQuestion(1, uuid.New())
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(nil, storageErr)
+			mockRepo.On("GetAnswer", uint(1), query.Projection{}).Return(nil, storageErr)
+
+			err := tt.call(NewService(mockRepo, logrus.New(), nil))
+			assert.ErrorIs(t, err, storageErr)
+			assert.NotErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestAcceptAnswerService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	author := uuid.New()
+	answer := &models.Answer{ID: 5, QuestionID: 1, UserID: uuid.New(), Text: "Answer"}
+	mockRepo.On("GetAnswer", uint(5), query.Projection{}).Return(answer, nil)
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).
+		Return(&models.Question{ID: 1, UserID: &author, Status: models.QuestionStatusOpen, Version: 2}, nil)
+	mockRepo.On("AcceptAnswer", mock.AnythingOfType("*models.Question")).Return(nil)
+	mockRepo.On("CreateOutboxMessage", mock.Anything).Return(nil)
+
+	question, err := service.AcceptAnswer(5, author)
+	assert.NoError(t, err)
+	assert.Equal(t, &answer.ID, question.AcceptedAnswerID)
+	if assert.Len(t, publisher.events, 1) {
+		assert.Equal(t, events.TypeAnswerAccepted, publisher.events[0].Type)
+		assert.Equal(t, uint(1), publisher.events[0].QuestionID)
+		assert.Equal(t, answer.UserID, publisher.events[0].Answer.UserID)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAcceptAnswerServiceAlreadyAccepted(t *testing.T) {
+	mockRepo := new(MockRepository)
+	publisher := &recordingPublisher{}
+	service := NewService(mockRepo, logrus.New(), publisher)
+
+	author, accepted := uuid.New(), uint(5)
+	mockRepo.On("GetAnswer", uint(5), query.Projection{}).Return(&models.Answer{ID: 5, QuestionID: 1}, nil)
+	mockRepo.On("GetQuestion", uint(1), query.Projection{}).
+		Return(&models.Question{
+			ID: 1, UserID: &author, Status: models.QuestionStatusOpen, AcceptedAnswerID: &accepted,
+		}, nil)
+
+	_, err := service.AcceptAnswer(5, author)
+	assert.NoError(t, err)
+	assert.Empty(t, publisher.events)
+	mockRepo.AssertNotCalled(t, "AcceptAnswer", mock.Anything)
+}
+
+func TestAcceptAnswerServiceErrors(t *testing.T) {
+	author := uuid.New()
+	tests := []struct {
+		name     string
+		question *models.Question
+		answer   error
+		userID   uuid.UUID
+		want     error
+	}{
+		{name: "answer not found", answer: gorm.ErrRecordNotFound, userID: author, want: ErrNotFound},
+		{name: "not the author", question: &models.Question{ID: 1, UserID: &author}, userID: uuid.New(),
+			want: ErrNotQuestionAuthor},
+		{name: "anonymous question", question: &models.Question{ID: 1}, userID: author, want: ErrNotQuestionAuthor},
+		{name: "closed question", userID: author, want: ErrQuestionNotOpen,
+			question: &models.Question{ID: 1, UserID: &author, Status: models.QuestionStatusClosed}},
+		{name: "locked question", userID: author, want: ErrQuestionNotOpen,
+			question: &models.Question{ID: 1, UserID: &author, Status: models.QuestionStatusLocked}},
+		{name: "archived question", userID: author, want: ErrQuestionNotOpen,
+			question: &models.Question{ID: 1, UserID: &author, Status: models.QuestionStatusArchived}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewService(mockRepo, logrus.New(), nil)
+			mockRepo.On("GetAnswer", uint(5), query.Projection{}).
+				Return(&models.Answer{ID: 5, QuestionID: 1}, tt.answer)
+			mockRepo.On("GetQuestion", uint(1), query.Projection{}).Return(tt.question, nil)
+
+			_, err := service.AcceptAnswer(5, tt.userID)
+			assert.ErrorIs(t, err, tt.want)
+			mockRepo.AssertNotCalled(t, "AcceptAnswer", mock.Anything)
+		})
+	}
+}
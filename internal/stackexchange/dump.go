@@ -0,0 +1,151 @@
+package stackexchange
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Типы постов в Posts.xml. Остальные типы (вики тегов, модераторские заметки и т. п.) не импортируются.
+const (
+	postTypeQuestion = 1
+	postTypeAnswer   = 2
+)
+
+// dumpTimeLayout - формат дат дампа: время в UTC без указания зоны, с миллисекундами или без них.
+const dumpTimeLayout = "2006-01-02T15:04:05.999999999"
+
+// row - строка дампа: элемент <row> и номер строки файла, на которой он начинается.
+type row struct {
+	line  int
+	attrs map[string]string
+}
+
+// readRows читает элементы <row> из r и передает их fn по одному, не загружая файл в память.
+// Ошибка означает, что файл не удалось дочитать.
+func readRows(r io.Reader, fn func(row)) error {
+	d := xml.NewDecoder(r)
+	for {
+		line, _ := d.InputPos()
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+		attrs := make(map[string]string, len(start.Attr))
+		for _, attr := range start.Attr {
+			attrs[attr.Name.Local] = attr.Value
+		}
+		fn(row{line: line, attrs: attrs})
+		if err := d.Skip(); err != nil {
+			return err
+		}
+	}
+}
+
+// post - строка Posts.xml. Необязательные числовые атрибуты без значения равны 0.
+type post struct {
+	ID               int64
+	PostTypeID       int
+	ParentID         int64
+	AcceptedAnswerID int64
+	OwnerUserID      *int64
+	CreationDate     time.Time
+	ClosedDate       *time.Time
+	Score            int
+	Title            string
+	Body             string
+}
+
+// parsePost разбирает строку Posts.xml.
+func parsePost(r row) (post, error) {
+	p := post{Title: r.attrs["Title"], Body: r.attrs["Body"]}
+	var err error
+	if p.ID, err = intAttr(r, "Id", true); err != nil {
+		return p, err
+	}
+	postType, err := intAttr(r, "PostTypeId", true)
+	if err != nil {
+		return p, err
+	}
+	p.PostTypeID = int(postType)
+	if p.PostTypeID != postTypeQuestion && p.PostTypeID != postTypeAnswer {
+		return p, nil
+	}
+	if p.ParentID, err = intAttr(r, "ParentId", p.PostTypeID == postTypeAnswer); err != nil {
+		return p, err
+	}
+	if p.AcceptedAnswerID, err = intAttr(r, "AcceptedAnswerId", false); err != nil {
+		return p, err
+	}
+	if _, ok := r.attrs["OwnerUserId"]; ok {
+		owner, err := intAttr(r, "OwnerUserId", true)
+		if err != nil {
+			return p, err
+		}
+		p.OwnerUserID = &owner
+	}
+	score, err := intAttr(r, "Score", false)
+	if err != nil {
+		return p, err
+	}
+	p.Score = int(score)
+	if p.CreationDate, err = timeAttr(r, "CreationDate"); err != nil {
+		return p, err
+	}
+	if _, ok := r.attrs["ClosedDate"]; ok {
+		closed, err := timeAttr(r, "ClosedDate")
+		if err != nil {
+			return p, err
+		}
+		p.ClosedDate = &closed
+	}
+	return p, nil
+}
+
+// user - строка Users.xml.
+type user struct {
+	ID          int64
+	DisplayName string
+}
+
+// parseUser разбирает строку Users.xml.
+func parseUser(r row) (user, error) {
+	id, err := intAttr(r, "Id", true)
+	return user{ID: id, DisplayName: r.attrs["DisplayName"]}, err
+}
+
+func intAttr(r row, name string, required bool) (int64, error) {
+	value, ok := r.attrs[name]
+	if !ok {
+		if required {
+			return 0, fmt.Errorf("missing %s", name)
+		}
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return n, nil
+}
+
+func timeAttr(r row, name string) (time.Time, error) {
+	value, ok := r.attrs[name]
+	if !ok {
+		return time.Time{}, fmt.Errorf("missing %s", name)
+	}
+	t, err := time.Parse(dumpTimeLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,383 @@
+// Package stackexchange импортирует вопросы и ответы из дампа сайта StackExchange (Posts.xml и Users.xml).
+// Файлы читаются потоком. Пользователи и посты связываются с созданными из них записями в таблицах связей,
+// поэтому повторный импорт того же источника пропускает уже импортированное и добавляет только новое.
+package stackexchange
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/repository"
+)
+
+// DefaultBatchSize - сколько постов или пользователей сохраняется в одной транзакции по умолчанию.
+const DefaultBatchSize = 500
+
+// closeReason - причина закрытия вопросов, закрытых до импорта: сама причина в Posts.xml не хранится.
+const closeReason = "closed on StackExchange"
+
+// Options - параметры импорта.
+type Options struct {
+	// Source - имя источника, например адрес сайта. Связи хранятся отдельно для каждого источника,
+	// поэтому при повторном импорте того же дампа имя должно совпадать.
+	Source string
+	// BatchSize - сколько постов или пользователей сохранять в одной транзакции; 0 означает DefaultBatchSize.
+	BatchSize int
+}
+
+// Counts - итоги импорта записей одного вида.
+type Counts struct {
+	Created int `json:"created"`
+	// Skipped - записи, импортированные раньше.
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// Failure описывает запись, которую не удалось импортировать.
+type Failure struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	ID     int64  `json:"id,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Report - отчет об импорте. В отличие от импорта из NDJSON, JSON и CSV, дамп может содержать
+// миллионы записей, поэтому отчет перечисляет только записи, которые не удалось импортировать.
+type Report struct {
+	Source    string `json:"source"`
+	Users     Counts `json:"users"`
+	Questions Counts `json:"questions"`
+	Answers   Counts `json:"answers"`
+	// OtherPosts - посты других типов, которые не импортируются.
+	OtherPosts int `json:"other_posts"`
+	// Error - почему импорт остановлен; записи после этого места не импортированы.
+	Error    string    `json:"error,omitempty"`
+	Failures []Failure `json:"failures"`
+}
+
+// Importer импортирует дамп StackExchange в хранилище.
+type Importer struct {
+	repo      repository.Repository
+	logger    *logrus.Logger
+	validate  *validator.Validate
+	converter *md.Converter
+}
+
+// New создает новый экземпляр импортера.
+func New(repo repository.Repository, logger *logrus.Logger) *Importer {
+	return &Importer{
+		repo:      repo,
+		logger:    logger,
+		validate:  validator.New(),
+		converter: md.NewConverter("", true, nil),
+	}
+}
+
+// run - состояние одного импорта.
+type run struct {
+	*Importer
+	source    string
+	batchSize int
+	report    *Report
+	// users - ID пользователей по их ID в дампе.
+	users map[int64]uuid.UUID
+	// accepted - принятые ответы вопросов дампа (ID вопроса -> ID ответа), в том числе импортированных раньше.
+	accepted map[int64]int64
+}
+
+// Import импортирует пользователей из users (Users.xml), затем вопросы и ответы из posts (Posts.xml).
+// Отчет возвращается и вместе с ошибкой: ошибка означает, что файл не удалось дочитать или сохранить
+// пользователей, а пачки, сохраненные до этого, остаются.
+func (imp *Importer) Import(users, posts io.Reader, opts Options) (*Report, error) {
+	if opts.Source == "" {
+		return nil, errors.New("source is required")
+	}
+	imp.logger.Infof("Importing StackExchange dump %q", opts.Source)
+	r := &run{
+		Importer:  imp,
+		source:    opts.Source,
+		batchSize: opts.BatchSize,
+		report:    &Report{Source: opts.Source, Failures: []Failure{}},
+		users:     make(map[int64]uuid.UUID),
+		accepted:  make(map[int64]int64),
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = DefaultBatchSize
+	}
+
+	err := r.importUsers(users)
+	if err == nil {
+		err = r.importPosts(posts)
+	}
+	report := r.report
+	imp.logger.Infof("StackExchange import finished: users %+v, questions %+v, answers %+v",
+		report.Users, report.Questions, report.Answers)
+	if err != nil {
+		imp.logger.Warnf("StackExchange import stopped: %v", err)
+		report.Error = err.Error()
+		return report, err
+	}
+	return report, nil
+}
+
+func (r *run) fail(file string, line int, id int64, counts *Counts, reason string) {
+	if counts != nil {
+		counts.Failed++
+	}
+	r.report.Failures = append(r.report.Failures, Failure{File: file, Line: line, ID: id, Reason: reason})
+}
+
+// importUsers связывает пользователей дампа с ID пользователей: новым пользователям выдаются новые ID,
+// а импортированные раньше сохраняют свои. Без этого нельзя сохранить авторство постов,
+// поэтому ошибка хранилища останавливает импорт.
+func (r *run) importUsers(in io.Reader) error {
+	var batch []models.ImportedUser
+	var err error
+	readErr := readRows(in, func(row row) {
+		if err != nil {
+			return
+		}
+		u, parseErr := parseUser(row)
+		if parseErr != nil {
+			r.fail("Users.xml", row.line, u.ID, &r.report.Users, parseErr.Error())
+			return
+		}
+		batch = append(batch, models.ImportedUser{Source: r.source, ExternalID: u.ID, DisplayName: u.DisplayName})
+		if len(batch) >= r.batchSize {
+			err = r.flushUsers(batch)
+			batch = batch[:0]
+		}
+	})
+	if err == nil && readErr == nil {
+		err = r.flushUsers(batch)
+	}
+	if readErr != nil {
+		return fmt.Errorf("read Users.xml: %w", readErr)
+	}
+	return err
+}
+
+func (r *run) flushUsers(batch []models.ImportedUser) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(batch))
+	for i, u := range batch {
+		ids[i] = u.ExternalID
+	}
+	existing, err := r.repo.FindImportedUsers(r.source, ids)
+	if err != nil {
+		return fmt.Errorf("find imported users: %w", err)
+	}
+
+	var create []models.ImportedUser
+	for _, u := range batch {
+		if id, ok := existing[u.ExternalID]; ok {
+			r.users[u.ExternalID] = id
+			r.report.Users.Skipped++
+			continue
+		}
+		if _, ok := r.users[u.ExternalID]; ok {
+			r.report.Users.Skipped++ // повтор в самом файле
+			continue
+		}
+		u.UserID = uuid.New()
+		r.users[u.ExternalID] = u.UserID
+		create = append(create, u)
+	}
+	if err := r.repo.CreateImportedUsers(create); err != nil {
+		return fmt.Errorf("save users: %w", err)
+	}
+	r.report.Users.Created += len(create)
+	return nil
+}
+
+// pending - проверенный пост, ожидающий сохранения вместе со своей пачкой.
+type pending struct {
+	line     int
+	parentID int64
+	post     repository.ExternalPost
+}
+
+func (r *run) counts(p *repository.ExternalPost) *Counts {
+	if p.Question != nil {
+		return &r.report.Questions
+	}
+	return &r.report.Answers
+}
+
+// importPosts импортирует вопросы и ответы. Ответы в дампе идут после своих вопросов,
+// поэтому вопрос ответа либо в той же пачке, либо уже сохранен.
+func (r *run) importPosts(in io.Reader) error {
+	var batch []pending
+	questions := make(map[int64]*models.Question) // вопросы текущей пачки
+	err := readRows(in, func(row row) {
+		p, err := parsePost(row)
+		var counts *Counts
+		switch p.PostTypeID {
+		case postTypeQuestion:
+			counts = &r.report.Questions
+		case postTypeAnswer:
+			counts = &r.report.Answers
+		default:
+			if err == nil {
+				r.report.OtherPosts++
+				return
+			}
+		}
+		if err != nil {
+			r.fail("Posts.xml", row.line, p.ID, counts, err.Error())
+			return
+		}
+
+		item := pending{line: row.line, parentID: p.ParentID, post: repository.ExternalPost{ExternalID: p.ID}}
+		if p.PostTypeID == postTypeQuestion {
+			if p.AcceptedAnswerID != 0 {
+				r.accepted[p.ID] = p.AcceptedAnswerID
+			}
+			item.post.Question, err = r.toQuestion(&p)
+			if err == nil {
+				questions[p.ID] = item.post.Question
+			}
+		} else {
+			item.post.Answer, err = r.toAnswer(&p)
+			item.post.Parent = questions[p.ParentID]
+			item.post.Accepted = r.accepted[p.ParentID] == p.ID
+		}
+		if err != nil {
+			r.fail("Posts.xml", row.line, p.ID, counts, err.Error())
+			return
+		}
+
+		batch = append(batch, item)
+		if len(batch) >= r.batchSize {
+			r.flushPosts(batch)
+			batch = batch[:0]
+			clear(questions)
+		}
+	})
+	r.flushPosts(batch)
+	if err != nil {
+		return fmt.Errorf("read Posts.xml: %w", err)
+	}
+	return nil
+}
+
+// toQuestion проверяет вопрос дампа по правилам модели и преобразует его в модель.
+// Тело в дампе хранится в HTML и преобразуется в markdown.
+func (r *run) toQuestion(p *post) (*models.Question, error) {
+	body, err := r.converter.ConvertString(p.Body)
+	if err != nil {
+		return nil, fmt.Errorf("convert body: %w", err)
+	}
+	question := &models.Question{
+		Title:     p.Title,
+		Body:      body,
+		Score:     p.Score,
+		Status:    models.QuestionStatusOpen,
+		CreatedAt: p.CreationDate,
+	}
+	if p.OwnerUserID != nil {
+		if id, ok := r.users[*p.OwnerUserID]; ok {
+			question.UserID = &id
+		}
+	}
+	if p.ClosedDate != nil {
+		question.Status = models.QuestionStatusClosed
+		question.CloseReason = closeReason
+		question.ClosedAt = p.ClosedDate
+	}
+	if err := r.validate.Struct(question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// toAnswer проверяет ответ дампа по правилам модели и преобразует его в модель.
+// Ответам удаленных пользователей, как и ответам без автора в API, выдается новый ID автора.
+func (r *run) toAnswer(p *post) (*models.Answer, error) {
+	text, err := r.converter.ConvertString(p.Body)
+	if err != nil {
+		return nil, fmt.Errorf("convert body: %w", err)
+	}
+	answer := &models.Answer{Text: text, CreatedAt: p.CreationDate}
+	if p.OwnerUserID != nil {
+		answer.UserID = r.users[*p.OwnerUserID]
+	}
+	if answer.UserID == uuid.Nil {
+		answer.UserID = uuid.New()
+	}
+	if err := r.validate.Struct(answer); err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+// flushPosts сохраняет пачку в одной транзакции. Посты, импортированные раньше, пропускаются,
+// а ответы к вопросам, которые не удалось импортировать, отклоняются.
+func (r *run) flushPosts(batch []pending) {
+	if len(batch) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(batch))
+	for _, p := range batch {
+		ids = append(ids, p.post.ExternalID)
+		if p.post.Answer != nil {
+			ids = append(ids, p.parentID)
+		}
+	}
+	existing, err := r.repo.FindImportedPosts(r.source, ids)
+	if err != nil {
+		r.logger.Errorf("Failed to find imported posts: %v", err)
+		for _, p := range batch {
+			r.fail("Posts.xml", p.line, p.post.ExternalID, r.counts(&p.post),
+				fmt.Sprintf("failed to check imported posts: %v", err))
+		}
+		return
+	}
+
+	var create []pending
+	for _, p := range batch {
+		if _, ok := existing[p.post.ExternalID]; ok {
+			r.counts(&p.post).Skipped++
+			continue
+		}
+		if answer := p.post.Answer; answer != nil {
+			parent, ok := existing[p.parentID]
+			switch {
+			case ok && parent.Kind == models.ImportedKindQuestion:
+				// Вопрос из этой же пачки импортирован раньше
+				p.post.Parent = nil
+				answer.QuestionID = parent.EntityID
+			case p.post.Parent == nil:
+				r.fail("Posts.xml", p.line, p.post.ExternalID, &r.report.Answers,
+					fmt.Sprintf("question %d is not imported", p.parentID))
+				continue
+			}
+		}
+		create = append(create, p)
+	}
+
+	posts := make([]repository.ExternalPost, len(create))
+	for i, p := range create {
+		posts[i] = p.post
+	}
+	if err := r.repo.CreateExternalPosts(r.source, posts); err != nil {
+		r.logger.Errorf("Failed to save a batch of %d posts: %v", len(posts), err)
+		for _, p := range create {
+			r.fail("Posts.xml", p.line, p.post.ExternalID, r.counts(&p.post),
+				fmt.Sprintf("failed to save batch: %v", err))
+		}
+		return
+	}
+	for _, p := range create {
+		r.counts(&p.post).Created++
+	}
+}
@@ -0,0 +1,129 @@
+package stackexchange
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/models"
+	"github.com/shenikar/question-service/internal/query"
+	"github.com/shenikar/question-service/internal/repository"
+)
+
+const source = "qa.example.com"
+
+var withAnswers = query.Projection{Include: []string{query.IncludeAnswers}}
+
+func readFixture(t *testing.T, name string) string {
+	data, err := os.ReadFile("testdata/" + name)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func importDump(t *testing.T, repo repository.Repository, users, posts string) *Report {
+	report, err := New(repo, logrus.New()).Import(strings.NewReader(users), strings.NewReader(posts),
+		Options{Source: source, BatchSize: 2})
+	assert.NoError(t, err)
+	return report
+}
+
+func failedIDs(report *Report) []int64 {
+	var ids []int64
+	for _, f := range report.Failures {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+func TestImport(t *testing.T) {
+	repo := repository.NewMemoryRepository(logrus.New())
+	report := importDump(t, repo, readFixture(t, "Users.xml"), readFixture(t, "Posts.xml"))
+
+	assert.Equal(t, Counts{Created: 3, Failed: 1}, report.Users)
+	assert.Equal(t, Counts{Created: 2, Failed: 1}, report.Questions)
+	assert.Equal(t, Counts{Created: 3, Failed: 2}, report.Answers)
+	assert.Equal(t, 1, report.OtherPosts)
+	assert.Equal(t, []int64{0, 6, 7, 9}, failedIDs(report))
+	assert.Equal(t, Failure{File: "Users.xml", Line: 6, Reason: `invalid Id "oops"`}, report.Failures[0])
+	assert.Contains(t, report.Failures[1].Reason, "'Title' failed on the 'min' tag")
+	assert.Equal(t, Failure{File: "Posts.xml", Line: 9, ID: 7, Reason: "question 6 is not imported"},
+		report.Failures[2])
+	assert.Equal(t, `invalid CreationDate "yesterday"`, report.Failures[3].Reason)
+
+	questions, err := repo.GetAllQuestions(query.QuestionSpec{Sort: query.SortOldest}, withAnswers)
+	assert.NoError(t, err)
+	assert.Len(t, questions, 2)
+
+	first := questions[0]
+	assert.Equal(t, "How do I install Go?", first.Title)
+	assert.Equal(t, "How do I **install** Go?", first.Body)
+	assert.Equal(t, time.Date(2010, 2, 1, 9, 0, 0, 123e6, time.UTC), first.CreatedAt)
+	assert.Equal(t, 5, first.Score)
+	assert.NotNil(t, first.UserID)
+	assert.Len(t, first.Answers, 2)
+	// Ответ 3 сохранен в следующей пачке, но все равно отмечен принятым
+	assert.Equal(t, &first.Answers[1].ID, first.AcceptedAnswerID)
+	assert.Equal(t, *first.UserID, first.Answers[1].UserID, "the same dump user is the same author")
+	assert.NotEqual(t, *first.UserID, first.Answers[0].UserID)
+	assert.Equal(t, "Use your package manager:\n\n```\napt install golang\n\n```", first.Answers[1].Text)
+	assert.Equal(t, time.Date(2010, 2, 2, 8, 15, 0, 0, time.UTC), first.Answers[1].CreatedAt)
+
+	second := questions[1]
+	assert.Nil(t, second.UserID, "the author was deleted from the site")
+	assert.Equal(t, models.QuestionStatusClosed, second.Status)
+	assert.Equal(t, closeReason, second.CloseReason)
+	assert.Equal(t, time.Date(2010, 3, 5, 12, 0, 0, 0, time.UTC), *second.ClosedAt)
+	assert.Equal(t, -2, second.Score)
+	assert.Nil(t, second.AcceptedAnswerID)
+	assert.Len(t, second.Answers, 1)
+	assert.NotEqual(t, uuid.Nil, second.Answers[0].UserID)
+}
+
+func TestImportRerun(t *testing.T) {
+	repo := repository.NewMemoryRepository(logrus.New())
+	users, posts := readFixture(t, "Users.xml"), readFixture(t, "Posts.xml")
+	importDump(t, repo, users, posts)
+	before, err := repo.GetAllQuestions(query.QuestionSpec{Sort: query.SortOldest}, withAnswers)
+	assert.NoError(t, err)
+
+	// Повторный импорт того же дампа ничего не создает
+	report := importDump(t, repo, users, posts)
+	assert.Equal(t, Counts{Skipped: 3, Failed: 1}, report.Users)
+	assert.Equal(t, Counts{Skipped: 2, Failed: 1}, report.Questions)
+	assert.Equal(t, Counts{Skipped: 3, Failed: 2}, report.Answers)
+	after, err := repo.GetAllQuestions(query.QuestionSpec{Sort: query.SortOldest}, withAnswers)
+	assert.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	// В новом дампе появился ответ к импортированному раньше вопросу от импортированного раньше пользователя
+	posts = strings.Replace(posts, "</posts>", `  <row Id="10" PostTypeId="2" ParentId="1" `+
+		`CreationDate="2011-01-01T00:00:00.000" Score="0" Body="&lt;p&gt;Or build it from source.&lt;/p&gt;" `+
+		`OwnerUserId="2" />`+"\n</posts>", 1)
+	report = importDump(t, repo, users, posts)
+	assert.Equal(t, Counts{Created: 1, Skipped: 3, Failed: 2}, report.Answers)
+
+	question, err := repo.GetQuestion(before[0].ID, withAnswers)
+	assert.NoError(t, err)
+	assert.Len(t, question.Answers, 3)
+	assert.Equal(t, "Or build it from source.", question.Answers[2].Text)
+	assert.Equal(t, question.Answers[0].UserID, question.Answers[2].UserID)
+}
+
+func TestImportMalformedDump(t *testing.T) {
+	repo := repository.NewMemoryRepository(logrus.New())
+	report, err := New(repo, logrus.New()).Import(strings.NewReader(readFixture(t, "Users.xml")),
+		strings.NewReader(`<posts><row Id="1" PostTypeId="1" CreationDate="2010-02-01T09:00:00" `+
+			`Body="" Title="Unfinished dump" /><row Id="2"`), Options{Source: source})
+	assert.ErrorContains(t, err, "read Posts.xml")
+	assert.Equal(t, err.Error(), report.Error)
+	// Пачка, прочитанная до ошибки, сохраняется
+	assert.Equal(t, 1, report.Questions.Created)
+
+	_, err = New(repo, logrus.New()).Import(strings.NewReader(""), strings.NewReader(""), Options{})
+	assert.EqualError(t, err, "source is required")
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/shenikar/question-service/internal/events"
+)
+
+// DefaultQueueSize - сколько событий Dispatcher держит в очереди, пока они не записаны в журнал доставок.
+const DefaultQueueSize = 1024
+
+// Envelope - тело запроса с доставкой. ID - идентификатор события (events.Event.UUID), общий для всех
+// его доставок и повторных отправок события из outbox: по нему получатель отбрасывает дубликаты.
+type Envelope struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// Dispatcher получает события сервиса и создает доставки для подписок, которым они нужны.
+// Publish не блокирует изменение, вызвавшее событие: события записываются в журнал отдельной горутиной.
+type Dispatcher struct {
+	store  Store
+	data   func(events.Event) any
+	logger *logrus.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan events.Event
+	done   chan struct{}
+}
+
+// NewDispatcher создает Dispatcher и запускает запись событий в журнал. data возвращает данные события
+// в том виде, в каком их отдает REST API; queueSize - размер очереди событий (0 - DefaultQueueSize).
+func NewDispatcher(store Store, data func(events.Event) any, logger *logrus.Logger, queueSize int) *Dispatcher {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	d := &Dispatcher{
+		store:  store,
+		data:   data,
+		logger: logger,
+		queue:  make(chan events.Event, queueSize),
+		done:   make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Publish ставит событие в очередь. Если очередь заполнена, событие не доставляется подпискам
+// и об этом пишется в журнал: ожидание остановило бы запрос, изменивший данные.
+func (d *Dispatcher) Publish(event events.Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return
+	}
+	select {
+	case d.queue <- event:
+	default:
+		d.logger.Errorf("Webhook queue is full, event %s for question %d is not delivered",
+			event.Type, event.QuestionID)
+	}
+}
+
+// Close перестает принимать события и ждет, пока события из очереди будут записаны в журнал.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+	<-d.done
+}
+
+// Send сразу создает доставки события, не используя очередь, и возвращает ошибку записи в журнал.
+// Так Dispatcher используется как получатель outbox: при ошибке событие будет отправлено повторно.
+func (d *Dispatcher) Send(event events.Event) error {
+	return d.dispatch(event)
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	for event := range d.queue {
+		if err := d.dispatch(event); err != nil {
+			d.logger.Errorf("Failed to create webhook deliveries for event %s: %v", event.Type, err)
+		}
+	}
+}
+
+// dispatch создает доставки события для всех активных подписок, которым оно нужно.
+func (d *Dispatcher) dispatch(event events.Event) error {
+	subs, err := d.store.ListSubscriptions()
+	if err != nil {
+		return err
+	}
+	var deliveries []Delivery
+	var payload []byte
+	eventID := event.UUID
+	if eventID == uuid.Nil {
+		eventID = uuid.New()
+	}
+	for _, sub := range subs {
+		if !sub.Matches(event.Type) {
+			continue
+		}
+		if payload == nil {
+			envelope := Envelope{ID: eventID, Type: event.Type, CreatedAt: event.Time, Data: d.data(event)}
+			if payload, err = json.Marshal(envelope); err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, Delivery{
+			SubscriptionID: sub.ID,
+			EventID:        eventID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         StatusPending,
+			NextAttemptAt:  time.Now(),
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := d.store.CreateDeliveries(deliveries); err != nil {
+		return err
+	}
+	d.logger.Debugf("Created %d webhook deliveries for event %s", len(deliveries), event.Type)
+	return nil
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shenikar/question-service/internal/events"
+)
+
+func eventData(event events.Event) any {
+	return map[string]uint{"question_id": event.QuestionID}
+}
+
+func TestDispatcherCreatesDeliveries(t *testing.T) {
+	store := NewMemoryStore()
+	all := &Subscription{URL: "https://a.example.com", Events: []string{AllEvents}, Active: true}
+	answers := &Subscription{URL: "https://b.example.com", Events: []string{events.TypeAnswerCreated}, Active: true}
+	inactive := &Subscription{URL: "https://c.example.com", Events: []string{AllEvents}}
+	for _, sub := range []*Subscription{all, answers, inactive} {
+		assert.NoError(t, store.CreateSubscription(sub))
+	}
+
+	d := NewDispatcher(store, eventData, logrus.New(), 0)
+	created := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	d.Publish(events.Event{Type: events.TypeQuestionCreated, QuestionID: 1, Time: created})
+	d.Publish(events.Event{Type: events.TypeAnswerCreated, QuestionID: 1, AnswerID: 2, Time: created})
+	d.Close()
+	// После остановки события не принимаются
+	d.Publish(events.Event{Type: events.TypeQuestionDeleted, QuestionID: 1})
+
+	allDeliveries, err := store.ListDeliveries(all.ID, "", 10)
+	assert.NoError(t, err)
+	answerDeliveries, err := store.ListDeliveries(answers.ID, "", 10)
+	assert.NoError(t, err)
+	inactiveDeliveries, err := store.ListDeliveries(inactive.ID, "", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, inactiveDeliveries)
+
+	if assert.Len(t, allDeliveries, 2) && assert.Len(t, answerDeliveries, 1) {
+		// Новые доставки первыми
+		assert.Equal(t, events.TypeAnswerCreated, allDeliveries[0].EventType)
+		assert.Equal(t, events.TypeQuestionCreated, allDeliveries[1].EventType)
+		assert.Equal(t, StatusPending, allDeliveries[0].Status)
+		// Доставки одного события разным подпискам имеют один идентификатор и одно тело
+		assert.Equal(t, allDeliveries[0].EventID, answerDeliveries[0].EventID)
+		assert.Equal(t, allDeliveries[0].Payload, answerDeliveries[0].Payload)
+		assert.NotEqual(t, allDeliveries[0].EventID, allDeliveries[1].EventID)
+
+		var envelope map[string]any
+		assert.NoError(t, json.Unmarshal(allDeliveries[1].Payload, &envelope))
+		assert.Equal(t, map[string]any{
+			"id":         allDeliveries[1].EventID.String(),
+			"type":       events.TypeQuestionCreated,
+			"created_at": "2026-10-18T12:00:00Z",
+			"data":       map[string]any{"question_id": float64(1)},
+		}, envelope)
+	}
+}
+
+func TestDispatcherUsesEventUUID(t *testing.T) {
+	store := NewMemoryStore()
+	sub := &Subscription{URL: "https://a.example.com", Events: []string{AllEvents}, Active: true}
+	assert.NoError(t, store.CreateSubscription(sub))
+
+	d := NewDispatcher(store, eventData, logrus.New(), 0)
+	defer d.Close()
+	event := events.Event{UUID: uuid.New(), Type: events.TypeQuestionCreated, QuestionID: 1}
+	// Повторная отправка из outbox создает доставку с тем же идентификатором события
+	assert.NoError(t, d.Send(event))
+	assert.NoError(t, d.Send(event))
+
+	deliveries, err := store.ListDeliveries(sub.ID, "", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, deliveries, 2) {
+		assert.Equal(t, event.UUID, deliveries[0].EventID)
+		assert.Equal(t, event.UUID, deliveries[1].EventID)
+		var envelope Envelope
+		assert.NoError(t, json.Unmarshal(deliveries[0].Payload, &envelope))
+		assert.Equal(t, event.UUID, envelope.ID)
+	}
+}
+
+func TestDispatcherDropsEventsWhenQueueIsFull(t *testing.T) {
+	d := &Dispatcher{logger: logrus.New(), queue: make(chan events.Event, 1)}
+	d.Publish(events.Event{Type: events.TypeQuestionCreated, QuestionID: 1})
+	// Очередь заполнена: Publish не блокируется
+	d.Publish(events.Event{Type: events.TypeQuestionCreated, QuestionID: 2})
+	assert.Len(t, d.queue, 1)
+	assert.Equal(t, uint(1), (<-d.queue).QuestionID)
+}
@@ -0,0 +1,170 @@
+package webhook
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// memoryStore - хранилище подписок и доставок в памяти процесса для тестов и режима STORAGE=memory.
+type memoryStore struct {
+	mu             sync.Mutex
+	subscriptions  map[uint]Subscription
+	deliveries     map[uint]Delivery
+	nextSubID      uint
+	nextDeliveryID uint
+}
+
+// NewMemoryStore создает хранилище подписок и доставок в памяти.
+func NewMemoryStore() Store {
+	return &memoryStore{subscriptions: make(map[uint]Subscription), deliveries: make(map[uint]Delivery)}
+}
+
+func (s *memoryStore) CreateSubscription(sub *Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextSubID++
+	now := time.Now()
+	sub.ID, sub.CreatedAt, sub.UpdatedAt = s.nextSubID, now, now
+	s.subscriptions[sub.ID] = cloneSubscription(*sub)
+	return nil
+}
+
+func (s *memoryStore) GetSubscription(id uint) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	sub = cloneSubscription(sub)
+	return &sub, nil
+}
+
+func (s *memoryStore) ListSubscriptions() ([]Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := make([]Subscription, 0, len(s.subscriptions))
+	for _, sub := range s.subscriptions {
+		subs = append(subs, cloneSubscription(sub))
+	}
+	slices.SortFunc(subs, func(a, b Subscription) int { return int(a.ID) - int(b.ID) })
+	return subs, nil
+}
+
+func (s *memoryStore) UpdateSubscription(sub *Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscriptions[sub.ID]; !ok {
+		return ErrNotFound
+	}
+	sub.UpdatedAt = time.Now()
+	s.subscriptions[sub.ID] = cloneSubscription(*sub)
+	return nil
+}
+
+func (s *memoryStore) DeleteSubscription(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscriptions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.subscriptions, id)
+	for deliveryID, delivery := range s.deliveries {
+		if delivery.SubscriptionID == id {
+			delete(s.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) CreateDeliveries(deliveries []Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for i := range deliveries {
+		s.nextDeliveryID++
+		deliveries[i].ID, deliveries[i].CreatedAt, deliveries[i].UpdatedAt = s.nextDeliveryID, now, now
+		s.deliveries[deliveries[i].ID] = deliveries[i]
+	}
+	return nil
+}
+
+func (s *memoryStore) GetDelivery(id uint) (*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery, ok := s.deliveries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &delivery, nil
+}
+
+func (s *memoryStore) ListDeliveries(subscriptionID uint, status string, limit int) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deliveries []Delivery
+	for _, delivery := range s.deliveries {
+		if delivery.SubscriptionID == subscriptionID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	slices.SortFunc(deliveries, func(a, b Delivery) int { return int(b.ID) - int(a.ID) })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (s *memoryStore) ClaimDue(now time.Time, limit int, lease time.Duration) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []Delivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status == StatusPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	slices.SortFunc(due, func(a, b Delivery) int {
+		if c := a.NextAttemptAt.Compare(b.NextAttemptAt); c != 0 {
+			return c
+		}
+		return int(a.ID) - int(b.ID)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for i := range due {
+		due[i].NextAttemptAt = now.Add(lease)
+		s.deliveries[due[i].ID] = due[i]
+	}
+	return due, nil
+}
+
+func (s *memoryStore) SaveAttempt(delivery *Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Подписку могли удалить во время попытки вместе с ее доставками
+	if _, ok := s.deliveries[delivery.ID]; !ok {
+		return nil
+	}
+	delivery.UpdatedAt = time.Now()
+	s.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+// cloneSubscription копирует подписку вместе со списком событий.
+func cloneSubscription(sub Subscription) Subscription {
+	sub.Events = slices.Clone(sub.Events)
+	return sub
+}
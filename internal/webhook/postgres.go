@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// dbStore хранит подписки и доставки в таблицах webhook_subscriptions и webhook_deliveries.
+type dbStore struct {
+	db *gorm.DB
+}
+
+// NewStore создает хранилище подписок и доставок в PostgreSQL.
+func NewStore(db *gorm.DB) Store {
+	return &dbStore{db: db}
+}
+
+func (s *dbStore) CreateSubscription(sub *Subscription) error {
+	return s.db.Create(sub).Error
+}
+
+func (s *dbStore) GetSubscription(id uint) (*Subscription, error) {
+	var sub Subscription
+	if err := s.db.Take(&sub, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &sub, nil
+}
+
+func (s *dbStore) ListSubscriptions() ([]Subscription, error) {
+	var subs []Subscription
+	err := s.db.Order("id").Find(&subs).Error
+	return subs, err
+}
+
+func (s *dbStore) UpdateSubscription(sub *Subscription) error {
+	result := s.db.Model(sub).Select("url", "events", "description", "active").Updates(sub)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return result.Error
+}
+
+// DeleteSubscription удаляет подписку; ее доставки удаляет внешний ключ ON DELETE CASCADE.
+func (s *dbStore) DeleteSubscription(id uint) error {
+	result := s.db.Delete(&Subscription{}, id)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return result.Error
+}
+
+func (s *dbStore) CreateDeliveries(deliveries []Delivery) error {
+	return s.db.Create(&deliveries).Error
+}
+
+func (s *dbStore) GetDelivery(id uint) (*Delivery, error) {
+	var delivery Delivery
+	if err := s.db.Take(&delivery, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &delivery, nil
+}
+
+func (s *dbStore) ListDeliveries(subscriptionID uint, status string, limit int) ([]Delivery, error) {
+	query := s.db.Where("subscription_id = ?", subscriptionID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var deliveries []Delivery
+	err := query.Order("id DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDue выбирает доставки с FOR UPDATE SKIP LOCKED: экземпляры сервиса, опрашивающие таблицу
+// одновременно, получают разные доставки и не ждут друг друга.
+func (s *dbStore) ClaimDue(now time.Time, limit int, lease time.Duration) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := s.db.Raw(`UPDATE webhook_deliveries SET next_attempt_at = ?, updated_at = ?
+WHERE id IN (
+	SELECT id FROM webhook_deliveries
+	WHERE status = ? AND next_attempt_at <= ?
+	ORDER BY next_attempt_at, id
+	LIMIT ?
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING *`, now.Add(lease), now, StatusPending, now, limit).Scan(&deliveries).Error
+	return deliveries, err
+}
+
+func (s *dbStore) SaveAttempt(delivery *Delivery) error {
+	return s.db.Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "last_error").
+		Updates(delivery).Error
+}
+
+// notFound заменяет gorm.ErrRecordNotFound на ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqldb, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: sqldb,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+
+	return gormDB, mock
+}
+
+func TestStoreClaimDue(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	now := time.Now()
+	eventID := uuid.New()
+
+	mock.ExpectQuery(`UPDATE webhook_deliveries SET next_attempt_at = \$1, updated_at = \$2\s+WHERE id IN \(`+
+		`\s+SELECT id FROM webhook_deliveries\s+WHERE status = \$3 AND next_attempt_at <= \$4\s+`+
+		`ORDER BY next_attempt_at, id\s+LIMIT \$5\s+FOR UPDATE SKIP LOCKED\s+\)\s+RETURNING \*`).
+		WithArgs(now.Add(time.Minute), now, StatusPending, now, 10).
+		WillReturnRows(sqlmock.NewRows(
+			[]string{"id", "subscription_id", "event_id", "event_type", "payload", "status"}).
+			AddRow(7, 3, eventID, "answer.created", []byte(`{}`), StatusPending))
+
+	deliveries, err := store.ClaimDue(now, 10, time.Minute)
+	assert.NoError(t, err)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, uint(7), deliveries[0].ID)
+		assert.Equal(t, uint(3), deliveries[0].SubscriptionID)
+		assert.Equal(t, eventID, deliveries[0].EventID)
+		assert.Equal(t, []byte(`{}`), deliveries[0].Payload)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreListDeliveries(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+
+	mock.ExpectQuery(`SELECT \* FROM "webhook_deliveries" WHERE subscription_id = \$1 AND status = \$2 `+
+		`ORDER BY id DESC LIMIT \$3`).
+		WithArgs(3, StatusDead, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "status"}).AddRow(9, 3, StatusDead))
+
+	deliveries, err := store.ListDeliveries(3, StatusDead, 50)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreSubscriptionNotFound(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+
+	mock.ExpectQuery(`SELECT \* FROM "webhook_subscriptions" WHERE "webhook_subscriptions"."id" = \$1 LIMIT \$2`).
+		WithArgs(5, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err := store.GetSubscription(5)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "webhook_subscriptions" WHERE "webhook_subscriptions"."id" = \$1`).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	assert.ErrorIs(t, store.DeleteSubscription(5), ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStoreSaveAttempt(t *testing.T) {
+	gormDB, mock := newMockDB(t)
+	store := NewStore(gormDB)
+	now := time.Now()
+	delivery := &Delivery{ID: 7, Status: StatusDead, Attempts: 8, NextAttemptAt: now, LastAttemptAt: &now,
+		ResponseStatus: 500, LastError: "unexpected response status 500"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "webhook_deliveries" SET "status"=\$1,"attempts"=\$2,"next_attempt_at"=\$3,`+
+		`"last_attempt_at"=\$4,"response_status"=\$5,"last_error"=\$6,"updated_at"=\$7 WHERE "id" = \$8`).
+		WithArgs(StatusDead, 8, now, now, 500, "unexpected response status 500", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, store.SaveAttempt(delivery))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
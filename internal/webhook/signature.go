@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки запросов с доставками.
+const (
+	HeaderEventID   = "X-Webhook-Event-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// signaturePrefix - префикс подписи в HeaderSignature.
+const signaturePrefix = "sha256="
+
+// ErrInvalidSignature возвращается из Verify, если подпись не совпадает или устарела.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign подписывает тело запроса, отправленного в момент timestamp (Unix-время в секундах).
+// Подписывается строка "<timestamp>.<body>": время входит в подпись, поэтому перехваченный запрос
+// нельзя отправить повторно позже допустимого отклонения. Результат - значение HeaderSignature.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись доставки на стороне получателя: timestamp и signature - значения
+// HeaderTimestamp и HeaderSignature. Запросы, отправленные раньше или позже now больше чем на tolerance,
+// отклоняются.
+func Verify(secret, timestamp, signature string, body []byte, now time.Time, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if diff := now.Sub(time.Unix(ts, 0)); diff > tolerance || diff < -tolerance {
+		return ErrInvalidSignature
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) ||
+		!hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
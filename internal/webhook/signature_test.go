@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"question.created"}`)
+	signature := Sign("secret", now.Unix(), body)
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	assert.NoError(t, Verify("secret", timestamp, signature, body, now.Add(time.Minute), 5*time.Minute))
+
+	tests := map[string]struct {
+		secret, timestamp, signature string
+		body                         []byte
+		now                          time.Time
+	}{
+		"wrong secret":      {"other", timestamp, signature, body, now},
+		"tampered body":     {"secret", timestamp, signature, []byte(`{"type":"question.deleted"}`), now},
+		"changed timestamp": {"secret", strconv.FormatInt(now.Unix()+1, 10), signature, body, now},
+		"invalid timestamp": {"secret", "yesterday", signature, body, now},
+		"stale request":     {"secret", timestamp, signature, body, now.Add(10 * time.Minute)},
+		"request in future": {"secret", timestamp, signature, body, now.Add(-10 * time.Minute)},
+		"missing prefix":    {"secret", timestamp, signature[len("sha256="):], body, now},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, tt.now, 5*time.Minute)
+			assert.ErrorIs(t, err, ErrInvalidSignature)
+		})
+	}
+}
+
+func TestValidateEvents(t *testing.T) {
+	assert.NoError(t, ValidateEvents([]string{"question.created", "answer.deleted", "comment.created"}))
+	assert.NoError(t, ValidateEvents([]string{"*"}))
+	assert.Error(t, ValidateEvents(nil))
+	assert.ErrorContains(t, ValidateEvents([]string{"answer.voted"}), `unknown event "answer.voted"`)
+}
+
+func TestSubscriptionMatches(t *testing.T) {
+	sub := Subscription{Events: []string{"answer.created"}, Active: true}
+	assert.True(t, sub.Matches("answer.created"))
+	assert.False(t, sub.Matches("answer.deleted"))
+
+	sub.Events = []string{AllEvents}
+	assert.True(t, sub.Matches("answer.deleted"))
+
+	sub.Active = false
+	assert.False(t, sub.Matches("answer.deleted"))
+}
@@ -0,0 +1,146 @@
+// Package webhook отправляет события сервиса внешним системам (чат-ботам, системам заявок) по HTTP.
+// Dispatcher превращает события в доставки для подписок, которым они нужны, а Worker в фоне отправляет
+// доставки с подписью HMAC-SHA256 и повторяет неудачные попытки с экспоненциальной задержкой.
+// Доставки хранятся как журнал: их можно просмотреть и отправить повторно.
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/shenikar/question-service/internal/events"
+)
+
+// AllEvents в списке событий подписки означает любые события.
+const AllEvents = "*"
+
+// Статусы доставки.
+const (
+	// StatusPending - доставка ждет первой или очередной попытки.
+	StatusPending = "pending"
+	// StatusSucceeded - получатель ответил кодом 2xx.
+	StatusSucceeded = "succeeded"
+	// StatusDead - попытки исчерпаны или подписка отключена; доставку можно отправить повторно вручную.
+	StatusDead = "dead"
+)
+
+// Statuses - все статусы доставки.
+var Statuses = []string{StatusPending, StatusSucceeded, StatusDead}
+
+// ErrNotFound возвращается, если подписки или доставки нет.
+var ErrNotFound = errors.New("not found")
+
+// Subscription - подписка внешней системы на события. Secret - ключ подписи доставок.
+type Subscription struct {
+	ID          uint      `gorm:"primaryKey"`
+	URL         string    `gorm:"not null"`
+	Secret      string    `gorm:"not null"`
+	Events      []string  `gorm:"serializer:json;not null"`
+	Description string    `gorm:"not null"`
+	Active      bool      `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName задает имя таблицы для Subscription.
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Matches сообщает, нужно ли подписке событие типа eventType.
+func (s *Subscription) Matches(eventType string) bool {
+	return s.Active && (slices.Contains(s.Events, eventType) || slices.Contains(s.Events, AllEvents))
+}
+
+// Delivery - отправка одного события одной подписке и итог последней попытки.
+// Payload - тело запроса, сохраненное при создании доставки; EventID одинаков у доставок одного события
+// разным подпискам и у повторных отправок, по нему получатель может отбросить дубликаты.
+// ReplayOf указывает на доставку, которую повторно отправили вручную.
+type Delivery struct {
+	ID             uint      `gorm:"primaryKey"`
+	SubscriptionID uint      `gorm:"not null"`
+	EventID        uuid.UUID `gorm:"type:uuid;not null"`
+	EventType      string    `gorm:"not null"`
+	Payload        []byte    `gorm:"not null"`
+	Status         string    `gorm:"not null"`
+	Attempts       int       `gorm:"not null"`
+	NextAttemptAt  time.Time `gorm:"not null"`
+	LastAttemptAt  *time.Time
+	// ResponseStatus - HTTP-статус последнего ответа; 0, если ответа не было.
+	ResponseStatus int    `gorm:"not null"`
+	LastError      string `gorm:"not null"`
+	ReplayOf       *uint
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName задает имя таблицы для Delivery.
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// Replay создает новую доставку того же события, готовую к отправке в now.
+func (d *Delivery) Replay(now time.Time) *Delivery {
+	return &Delivery{
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         StatusPending,
+		NextAttemptAt:  now,
+		ReplayOf:       &d.ID,
+	}
+}
+
+// Store хранит подписки и журнал доставок.
+type Store interface {
+	CreateSubscription(sub *Subscription) error
+	// GetSubscription возвращает подписку или ErrNotFound.
+	GetSubscription(id uint) (*Subscription, error)
+	// ListSubscriptions возвращает все подписки в порядке создания.
+	ListSubscriptions() ([]Subscription, error)
+	UpdateSubscription(sub *Subscription) error
+	// DeleteSubscription удаляет подписку вместе с ее доставками или возвращает ErrNotFound.
+	DeleteSubscription(id uint) error
+
+	CreateDeliveries(deliveries []Delivery) error
+	// GetDelivery возвращает доставку или ErrNotFound.
+	GetDelivery(id uint) (*Delivery, error)
+	// ListDeliveries возвращает до limit последних доставок подписки, новые первыми.
+	// Пустой status означает доставки в любом статусе.
+	ListDeliveries(subscriptionID uint, status string, limit int) ([]Delivery, error)
+	// ClaimDue выбирает до limit ожидающих доставок, срок которых наступил к now, и откладывает
+	// их следующую попытку на lease, чтобы их не выбрал другой экземпляр сервиса. Если экземпляр
+	// остановится, не сохранив результат попытки, доставка будет отправлена снова после lease.
+	ClaimDue(now time.Time, limit int, lease time.Duration) ([]Delivery, error)
+	// SaveAttempt сохраняет итог попытки: статус, счетчик попыток, срок следующей попытки и ответ.
+	SaveAttempt(delivery *Delivery) error
+}
+
+// ValidateEvents проверяет список событий подписки.
+func ValidateEvents(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return errors.New("events must not be empty")
+	}
+	for _, eventType := range eventTypes {
+		if eventType != AllEvents && !slices.Contains(events.Types, eventType) {
+			return fmt.Errorf("unknown event %q, expected %s or one of: %v", eventType, AllEvents, events.Types)
+		}
+	}
+	return nil
+}
+
+// GenerateSecret создает случайный ключ подписи.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
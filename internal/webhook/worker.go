@@ -0,0 +1,222 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Значения Options по умолчанию.
+const (
+	DefaultPollInterval = time.Second
+	DefaultBatchSize    = 20
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxAttempts  = 8
+	DefaultBackoff      = 30 * time.Second
+	DefaultMaxBackoff   = time.Hour
+)
+
+// maxResponseSnippet - сколько байт ответа получателя сохраняется в LastError.
+const maxResponseSnippet = 512
+
+// Options - параметры отправки доставок. Нулевые значения заменяются значениями по умолчанию.
+type Options struct {
+	// PollInterval - как часто проверять, есть ли доставки, срок которых наступил.
+	PollInterval time.Duration
+	// BatchSize - сколько доставок отправляется одновременно.
+	BatchSize int
+	// Timeout - сколько ждать ответа получателя.
+	Timeout time.Duration
+	// MaxAttempts - после скольких неудачных попыток доставка получает статус dead.
+	MaxAttempts int
+	// Backoff - задержка перед второй попыткой; перед каждой следующей она удваивается до MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultPollInterval
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultBatchSize
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultMaxAttempts
+	}
+	if o.Backoff <= 0 {
+		o.Backoff = DefaultBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultMaxBackoff
+	}
+	return o
+}
+
+// Worker отправляет доставки из журнала получателям и повторяет неудачные попытки.
+// Несколько экземпляров сервиса могут работать с одним журналом: Store.ClaimDue выдает доставку только одному.
+type Worker struct {
+	store  Store
+	opts   Options
+	client *http.Client
+	logger *logrus.Logger
+	now    func() time.Time
+}
+
+// NewWorker создает Worker.
+func NewWorker(store Store, opts Options, logger *logrus.Logger) *Worker {
+	opts = opts.withDefaults()
+	return &Worker{
+		store: store,
+		opts:  opts,
+		client: &http.Client{
+			Timeout: opts.Timeout,
+			// Перенаправление считается ошибкой: подписка должна указывать на конечный адрес
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Run отправляет доставки каждые PollInterval, пока ctx не будет отменен.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// Пока доставки выбираются полными пачками, следующая пачка отправляется сразу
+		for ctx.Err() == nil {
+			processed, err := w.ProcessDue(ctx)
+			if err != nil {
+				w.logger.Errorf("Failed to claim webhook deliveries: %v", err)
+			}
+			if processed < w.opts.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+// ProcessDue отправляет доставки, срок которых наступил, не больше BatchSize одновременно,
+// и возвращает их количество.
+func (w *Worker) ProcessDue(ctx context.Context) (int, error) {
+	// Доставку не выберут повторно, пока идет попытка: ответа ждут не дольше Timeout
+	deliveries, err := w.store.ClaimDue(w.now(), w.opts.BatchSize, 2*w.opts.Timeout)
+	if err != nil {
+		return 0, err
+	}
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *Delivery) {
+			defer wg.Done()
+			w.process(ctx, delivery)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// process выполняет попытку доставки и сохраняет ее итог.
+func (w *Worker) process(ctx context.Context, delivery *Delivery) {
+	sub, err := w.store.GetSubscription(delivery.SubscriptionID)
+	if errors.Is(err, ErrNotFound) {
+		// Подписку удалили вместе с доставками
+		return
+	}
+	if err != nil {
+		w.logger.Errorf("Failed to get webhook subscription %d: %v", delivery.SubscriptionID, err)
+		return
+	}
+
+	now := w.now()
+	if !sub.Active {
+		delivery.Status, delivery.LastError = StatusDead, "subscription is inactive"
+	} else {
+		status, err := w.send(ctx, sub, delivery, now)
+		if ctx.Err() != nil {
+			// Сервис останавливается: доставка будет отправлена снова, когда истечет ее аренда
+			return
+		}
+		delivery.Attempts++
+		delivery.LastAttemptAt = &now
+		delivery.ResponseStatus = status
+		if err == nil {
+			delivery.Status, delivery.LastError = StatusSucceeded, ""
+		} else {
+			delivery.LastError = err.Error()
+			if delivery.Attempts >= w.opts.MaxAttempts {
+				delivery.Status = StatusDead
+			} else {
+				delivery.NextAttemptAt = now.Add(w.backoff(delivery.Attempts))
+			}
+		}
+	}
+
+	if err := w.store.SaveAttempt(delivery); err != nil {
+		w.logger.Errorf("Failed to save webhook delivery %d: %v", delivery.ID, err)
+		return
+	}
+	if delivery.Status == StatusPending {
+		w.logger.Warnf("Webhook delivery %d to %s failed (attempt %d), retrying at %s: %s",
+			delivery.ID, sub.URL, delivery.Attempts, delivery.NextAttemptAt.Format(time.RFC3339), delivery.LastError)
+	} else {
+		w.logger.Infof("Webhook delivery %d to %s finished with status %s after %d attempts",
+			delivery.ID, sub.URL, delivery.Status, delivery.Attempts)
+	}
+}
+
+// send отправляет доставку получателю и возвращает HTTP-статус ответа. Подпись вычисляется заново
+// при каждой попытке, чтобы время в ней соответствовало времени отправки.
+func (w *Worker) send(ctx context.Context, sub *Subscription, delivery *Delivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "question-service-webhooks")
+	req.Header.Set(HeaderEventID, delivery.EventID.String())
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, delivery.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSnippet))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d: %s",
+			resp.StatusCode, strings.ToValidUTF8(strings.TrimSpace(string(snippet)), ""))
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff возвращает задержку после attempts неудачных попыток.
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.opts.Backoff
+	for i := 1; i < attempts && delay < w.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, w.opts.MaxBackoff)
+}
@@ -0,0 +1,246 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// receiver - получатель доставок, который проверяет подпись и отвечает заданным статусом.
+type receiver struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+	verified []error
+}
+
+func newReceiver(t *testing.T, secret string) *receiver {
+	rec := &receiver{status: http.StatusOK}
+	rec.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		err := Verify(secret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body,
+			time.Now(), 24*time.Hour)
+
+		rec.mu.Lock()
+		rec.requests = append(rec.requests, r)
+		rec.bodies = append(rec.bodies, body)
+		rec.verified = append(rec.verified, err)
+		status := rec.status
+		rec.mu.Unlock()
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("receiver says hi\n"))
+	}))
+	t.Cleanup(rec.server.Close)
+	return rec
+}
+
+func (rec *receiver) respond(status int) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.status = status
+}
+
+type workerFixture struct {
+	store  Store
+	worker *Worker
+	now    time.Time
+	sub    *Subscription
+}
+
+func newWorkerFixture(t *testing.T, url string) *workerFixture {
+	f := &workerFixture{store: NewMemoryStore(), now: time.Now()}
+	f.sub = &Subscription{URL: url, Secret: "whsec_test", Events: []string{AllEvents}, Active: true}
+	assert.NoError(t, f.store.CreateSubscription(f.sub))
+	f.worker = NewWorker(f.store, Options{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: 90 * time.Second},
+		logrus.New())
+	f.worker.now = func() time.Time { return f.now }
+	return f
+}
+
+func (f *workerFixture) enqueue(t *testing.T) *Delivery {
+	deliveries := []Delivery{{
+		SubscriptionID: f.sub.ID,
+		EventID:        uuid.New(),
+		EventType:      "answer.created",
+		Payload:        []byte(`{"type":"answer.created"}`),
+		Status:         StatusPending,
+		NextAttemptAt:  f.now,
+	}}
+	assert.NoError(t, f.store.CreateDeliveries(deliveries))
+	return &deliveries[0]
+}
+
+func (f *workerFixture) process(t *testing.T) int {
+	processed, err := f.worker.ProcessDue(context.Background())
+	assert.NoError(t, err)
+	return processed
+}
+
+func (f *workerFixture) delivery(t *testing.T, id uint) *Delivery {
+	delivery, err := f.store.GetDelivery(id)
+	assert.NoError(t, err)
+	return delivery
+}
+
+func TestWorkerDeliversSignedRequest(t *testing.T) {
+	rec := newReceiver(t, "whsec_test")
+	f := newWorkerFixture(t, rec.server.URL)
+	enqueued := f.enqueue(t)
+
+	assert.Equal(t, 1, f.process(t))
+	// Доставленное событие не отправляется повторно
+	assert.Equal(t, 0, f.process(t))
+
+	if assert.Len(t, rec.requests, 1) {
+		req := rec.requests[0]
+		assert.NoError(t, rec.verified[0])
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, enqueued.EventID.String(), req.Header.Get(HeaderEventID))
+		assert.Equal(t, "answer.created", req.Header.Get(HeaderEvent))
+		assert.Equal(t, strconv.FormatUint(uint64(enqueued.ID), 10), req.Header.Get(HeaderDelivery))
+		assert.Equal(t, strconv.FormatInt(f.now.Unix(), 10), req.Header.Get(HeaderTimestamp))
+		assert.Equal(t, `{"type":"answer.created"}`, string(rec.bodies[0]))
+	}
+
+	delivery := f.delivery(t, enqueued.ID)
+	assert.Equal(t, StatusSucceeded, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusOK, delivery.ResponseStatus)
+	assert.Empty(t, delivery.LastError)
+	if assert.NotNil(t, delivery.LastAttemptAt) {
+		assert.True(t, f.now.Equal(*delivery.LastAttemptAt))
+	}
+}
+
+func TestWorkerRetriesWithBackoff(t *testing.T) {
+	rec := newReceiver(t, "whsec_test")
+	rec.respond(http.StatusServiceUnavailable)
+	f := newWorkerFixture(t, rec.server.URL)
+	enqueued := f.enqueue(t)
+	start := f.now
+
+	assert.Equal(t, 1, f.process(t))
+	delivery := f.delivery(t, enqueued.ID)
+	assert.Equal(t, StatusPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.ResponseStatus)
+	assert.Equal(t, "unexpected response status 503: receiver says hi", delivery.LastError)
+	assert.True(t, start.Add(time.Minute).Equal(delivery.NextAttemptAt))
+
+	// До наступления срока попытка не повторяется
+	f.now = start.Add(59 * time.Second)
+	assert.Equal(t, 0, f.process(t))
+
+	// Задержка удваивается, но не превышает MaxBackoff
+	f.now = start.Add(time.Minute)
+	assert.Equal(t, 1, f.process(t))
+	delivery = f.delivery(t, enqueued.ID)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.True(t, f.now.Add(90*time.Second).Equal(delivery.NextAttemptAt))
+
+	// После последней попытки доставка больше не отправляется
+	f.now = delivery.NextAttemptAt
+	assert.Equal(t, 1, f.process(t))
+	delivery = f.delivery(t, enqueued.ID)
+	assert.Equal(t, StatusDead, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	f.now = f.now.Add(time.Hour)
+	assert.Equal(t, 0, f.process(t))
+	assert.Len(t, rec.requests, 3)
+
+	// Повторная отправка вручную создает новую доставку того же события
+	rec.respond(http.StatusNoContent)
+	replay := []Delivery{*delivery.Replay(f.now)}
+	assert.NoError(t, f.store.CreateDeliveries(replay))
+	assert.Equal(t, 1, f.process(t))
+	replayed := f.delivery(t, replay[0].ID)
+	assert.Equal(t, StatusSucceeded, replayed.Status)
+	assert.Equal(t, &enqueued.ID, replayed.ReplayOf)
+	if assert.Len(t, rec.requests, 4) {
+		assert.Equal(t, enqueued.EventID.String(), rec.requests[3].Header.Get(HeaderEventID))
+		assert.NoError(t, rec.verified[3])
+	}
+}
+
+func TestWorkerDoesNotFollowRedirects(t *testing.T) {
+	target := newReceiver(t, "whsec_test")
+	redirect := httptest.NewServer(http.RedirectHandler(target.server.URL, http.StatusFound))
+	t.Cleanup(redirect.Close)
+	f := newWorkerFixture(t, redirect.URL)
+	enqueued := f.enqueue(t)
+
+	assert.Equal(t, 1, f.process(t))
+	delivery := f.delivery(t, enqueued.ID)
+	assert.Equal(t, StatusPending, delivery.Status)
+	assert.Equal(t, http.StatusFound, delivery.ResponseStatus)
+	assert.Empty(t, target.requests)
+}
+
+func TestWorkerUnreachableReceiver(t *testing.T) {
+	rec := newReceiver(t, "whsec_test")
+	f := newWorkerFixture(t, rec.server.URL)
+	rec.server.Close()
+	enqueued := f.enqueue(t)
+
+	assert.Equal(t, 1, f.process(t))
+	delivery := f.delivery(t, enqueued.ID)
+	assert.Equal(t, StatusPending, delivery.Status)
+	assert.Equal(t, 0, delivery.ResponseStatus)
+	assert.NotEmpty(t, delivery.LastError)
+}
+
+func TestWorkerInactiveSubscription(t *testing.T) {
+	rec := newReceiver(t, "whsec_test")
+	f := newWorkerFixture(t, rec.server.URL)
+	enqueued := f.enqueue(t)
+	f.sub.Active = false
+	assert.NoError(t, f.store.UpdateSubscription(f.sub))
+
+	assert.Equal(t, 1, f.process(t))
+	delivery := f.delivery(t, enqueued.ID)
+	assert.Equal(t, StatusDead, delivery.Status)
+	assert.Equal(t, 0, delivery.Attempts)
+	assert.Equal(t, "subscription is inactive", delivery.LastError)
+	assert.Empty(t, rec.requests)
+}
+
+func TestWorkerRun(t *testing.T) {
+	rec := newReceiver(t, "whsec_test")
+	store := NewMemoryStore()
+	sub := &Subscription{URL: rec.server.URL, Secret: "whsec_test", Events: []string{AllEvents}, Active: true}
+	assert.NoError(t, store.CreateSubscription(sub))
+	// Больше одной пачки: вторая отправляется без ожидания следующего опроса
+	deliveries := make([]Delivery, 3)
+	for i := range deliveries {
+		deliveries[i] = Delivery{SubscriptionID: sub.ID, EventID: uuid.New(), EventType: "question.created",
+			Payload: []byte(`{}`), Status: StatusPending, NextAttemptAt: time.Now()}
+	}
+	assert.NoError(t, store.CreateDeliveries(deliveries))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewWorker(store, Options{PollInterval: 10 * time.Millisecond, BatchSize: 2}, logrus.New()).Run(ctx)
+	}()
+	assert.Eventually(t, func() bool {
+		succeeded, err := store.ListDeliveries(sub.ID, StatusSucceeded, 10)
+		return err == nil && len(succeeded) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
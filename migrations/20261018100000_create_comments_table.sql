@@ -0,0 +1,46 @@
+-- +goose Up
+CREATE TABLE comments (
+    id SERIAL PRIMARY KEY,
+    parent_type TEXT NOT NULL CHECK (parent_type IN ('question', 'answer')),
+    parent_id INTEGER NOT NULL,
+    user_id UUID NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_comments_parent ON comments (parent_type, parent_id);
+
+-- Полиморфную связь нельзя выразить внешним ключом, поэтому каскадное
+-- удаление комментариев выполняется триггерами на родительских таблицах.
+-- +goose StatementBegin
+CREATE FUNCTION delete_question_comments() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM comments WHERE parent_type = 'question' AND parent_id = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION delete_answer_comments() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM comments WHERE parent_type = 'answer' AND parent_id = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER questions_delete_comments
+    AFTER DELETE ON questions
+    FOR EACH ROW EXECUTE FUNCTION delete_question_comments();
+
+CREATE TRIGGER answers_delete_comments
+    AFTER DELETE ON answers
+    FOR EACH ROW EXECUTE FUNCTION delete_answer_comments();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS answers_delete_comments ON answers;
+DROP TRIGGER IF EXISTS questions_delete_comments ON questions;
+DROP FUNCTION IF EXISTS delete_answer_comments();
+DROP FUNCTION IF EXISTS delete_question_comments();
+DROP TABLE IF EXISTS comments;
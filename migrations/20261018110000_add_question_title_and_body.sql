@@ -0,0 +1,33 @@
+-- +goose Up
+-- Существующий текст вопроса становится его заголовком, тело изначально пустое.
+ALTER TABLE questions RENAME COLUMN text TO title;
+ALTER TABLE questions ADD COLUMN body TEXT NOT NULL DEFAULT '';
+-- Текст вопроса мог быть до 500 символов, а заголовок - не больше 250: длинный текст целиком переносится
+-- в тело, а заголовком становится его начало, иначе такой вопрос нельзя было бы изменить.
+UPDATE questions SET body = title, title = left(title, 249) || '…' WHERE char_length(title) > 250;
+
+-- +goose Down
+-- Вопросы, заголовок которых был получен из тела при переносе, получают исходный текст обратно.
+UPDATE questions SET title = body, body = ''
+WHERE char_length(title) = 250 AND right(title, 1) = '…' AND starts_with(body, left(title, -1));
+-- Остальные вопросы склеиваются в один текст, но прежняя версия сервиса не принимает текст длиннее
+-- 500 символов, и такой вопрос нельзя было бы изменить. Обрезать вопросы откат не должен, поэтому, если
+-- склеенный текст не помещается, миграция прерывается с ошибкой и транзакция откатывается целиком.
+-- +goose StatementBegin
+DO $$
+DECLARE
+    too_long INTEGER[];
+BEGIN
+    SELECT array_agg(id ORDER BY id) INTO too_long FROM questions
+    WHERE body <> '' AND char_length(title || E'\n\n' || body) > 500;
+    IF too_long IS NOT NULL THEN
+        RAISE EXCEPTION 'cannot merge title and body of questions % into text: longer than 500 characters',
+            too_long
+            USING HINT = 'Shorten these questions before rolling back this migration.';
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+UPDATE questions SET title = title || E'\n\n' || body WHERE body <> '';
+ALTER TABLE questions DROP COLUMN body;
+ALTER TABLE questions RENAME COLUMN title TO text;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_questions_title_trgm ON questions USING GIN (title gin_trgm_ops);
+
+ALTER TABLE questions
+    ADD COLUMN duplicate_of INTEGER REFERENCES questions(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_questions_duplicate_of ON questions (duplicate_of);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_questions_duplicate_of;
+ALTER TABLE questions DROP COLUMN IF EXISTS duplicate_of;
+DROP INDEX IF EXISTS idx_questions_title_trgm;
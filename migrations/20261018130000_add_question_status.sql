@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE questions
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'closed', 'locked', 'archived')),
+    ADD COLUMN close_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN closed_by UUID,
+    ADD COLUMN closed_at TIMESTAMPTZ;
+
+CREATE INDEX idx_questions_status ON questions (status);
+
+-- Вопросы, ранее помеченные дубликатами, считаются закрытыми
+UPDATE questions
+SET status = 'closed', close_reason = 'duplicate', closed_at = NOW()
+WHERE duplicate_of IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_questions_status;
+ALTER TABLE questions
+    DROP COLUMN IF EXISTS closed_at,
+    DROP COLUMN IF EXISTS closed_by,
+    DROP COLUMN IF EXISTS close_reason,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE questions
+    ADD COLUMN user_id UUID,
+    ADD COLUMN score INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_questions_user_id ON questions (user_id);
+CREATE INDEX idx_questions_created_at ON questions (created_at);
+CREATE INDEX idx_questions_score ON questions (score);
+CREATE INDEX idx_answers_question_id_created_at ON answers (question_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_answers_question_id_created_at;
+DROP INDEX IF EXISTS idx_questions_score;
+DROP INDEX IF EXISTS idx_questions_created_at;
+DROP INDEX IF EXISTS idx_questions_user_id;
+ALTER TABLE questions
+    DROP COLUMN IF EXISTS score,
+    DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,88 @@
+-- +goose Up
+ALTER TABLE questions ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE answers ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+UPDATE answers a
+SET updated_at = GREATEST(a.created_at, COALESCE((
+    SELECT MAX(c.created_at) FROM comments c WHERE c.parent_type = 'answer' AND c.parent_id = a.id
+), a.created_at));
+
+UPDATE questions q
+SET updated_at = GREATEST(q.created_at, COALESCE((
+    SELECT MAX(a.updated_at) FROM answers a WHERE a.question_id = q.id
+), q.created_at), COALESCE((
+    SELECT MAX(c.created_at) FROM comments c WHERE c.parent_type = 'question' AND c.parent_id = q.id
+), q.created_at));
+
+-- updated_at отражает изменение всего представления: вопрос меняется вместе с его
+-- ответами и комментариями, ответ - вместе со своими комментариями. Поэтому любое
+-- изменение дочерней строки обновляет updated_at родителя, а тот - своего родителя.
+-- +goose StatementBegin
+CREATE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION touch_answer_question() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        UPDATE questions SET updated_at = NOW() WHERE id = OLD.question_id;
+    END IF;
+    IF TG_OP <> 'DELETE' AND (TG_OP = 'INSERT' OR NEW.question_id <> OLD.question_id) THEN
+        UPDATE questions SET updated_at = NOW() WHERE id = NEW.question_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION touch_comment_parent() RETURNS TRIGGER AS $$
+DECLARE
+    comment comments%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        comment := OLD;
+    ELSE
+        comment := NEW;
+    END IF;
+    IF comment.parent_type = 'question' THEN
+        UPDATE questions SET updated_at = NOW() WHERE id = comment.parent_id;
+    ELSE
+        UPDATE answers SET updated_at = NOW() WHERE id = comment.parent_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER questions_set_updated_at
+    BEFORE UPDATE ON questions
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER answers_set_updated_at
+    BEFORE UPDATE ON answers
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER answers_touch_question
+    AFTER INSERT OR UPDATE OR DELETE ON answers
+    FOR EACH ROW EXECUTE FUNCTION touch_answer_question();
+
+CREATE TRIGGER comments_touch_parent
+    AFTER INSERT OR UPDATE OR DELETE ON comments
+    FOR EACH ROW EXECUTE FUNCTION touch_comment_parent();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS comments_touch_parent ON comments;
+DROP TRIGGER IF EXISTS answers_touch_question ON answers;
+DROP TRIGGER IF EXISTS answers_set_updated_at ON answers;
+DROP TRIGGER IF EXISTS questions_set_updated_at ON questions;
+DROP FUNCTION IF EXISTS touch_comment_parent();
+DROP FUNCTION IF EXISTS touch_answer_question();
+DROP FUNCTION IF EXISTS set_updated_at();
+ALTER TABLE answers DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE questions DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Версия для оптимистичной блокировки. В отличие от updated_at, она меняется только
+-- при изменении самой записи, а не вложенных в нее ответов и комментариев.
+ALTER TABLE questions ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE answers ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE answers DROP COLUMN IF EXISTS version;
+ALTER TABLE questions DROP COLUMN IF EXISTS version;
@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE questions
+    ADD COLUMN accepted_answer_id INTEGER REFERENCES answers(id) ON DELETE SET NULL;
+
+-- Связи постов и пользователей внешних источников (например, дампа StackExchange)
+-- с созданными из них записями. По ним повторный импорт пропускает уже импортированное.
+CREATE TABLE imported_posts (
+    source TEXT NOT NULL,
+    external_id BIGINT NOT NULL,
+    kind TEXT NOT NULL,
+    entity_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source, external_id)
+);
+
+CREATE TABLE imported_users (
+    source TEXT NOT NULL,
+    external_id BIGINT NOT NULL,
+    user_id UUID NOT NULL,
+    display_name TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source, external_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS imported_users;
+DROP TABLE IF EXISTS imported_posts;
+ALTER TABLE questions DROP COLUMN IF EXISTS accepted_answer_id;
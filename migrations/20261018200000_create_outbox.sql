@@ -0,0 +1,22 @@
+-- +goose Up
+-- Transactional outbox: события записываются в одной транзакции с изменением и доставляются фоновым Relay.
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id INTEGER NOT NULL,
+    event_type TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    available_at TIMESTAMPTZ NOT NULL,
+    processed_at TIMESTAMPTZ,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT ''
+);
+
+-- Выбор первого недоставленного сообщения агрегата
+CREATE INDEX idx_outbox_pending ON outbox (aggregate_type, aggregate_id, id) WHERE processed_at IS NULL;
+-- Удаление старых доставленных сообщений
+CREATE INDEX idx_outbox_processed ON outbox (processed_at) WHERE processed_at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
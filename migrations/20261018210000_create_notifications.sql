@@ -0,0 +1,24 @@
+-- +goose Up
+-- Входящие уведомления пользователей. Уведомления не удаляются вместе с вопросом или ответом:
+-- это история того, что происходило.
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    type TEXT NOT NULL,
+    question_id INTEGER NOT NULL,
+    answer_id INTEGER,
+    actor_id UUID,
+    key TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMPTZ
+);
+
+-- Повторная доставка события не создает второе уведомление
+CREATE UNIQUE INDEX idx_notifications_user_key ON notifications (user_id, key);
+-- Лента уведомлений пользователя, новые первыми
+CREATE INDEX idx_notifications_user ON notifications (user_id, id DESC);
+-- Непрочитанные уведомления пользователя
+CREATE INDEX idx_notifications_unread ON notifications (user_id, id DESC) WHERE read_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS notifications;
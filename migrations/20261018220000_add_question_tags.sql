@@ -0,0 +1,9 @@
+-- +goose Up
+-- Теги вопроса хранятся массивом: вопрос и его теги читаются одним запросом,
+-- а фильтр по тегам (tags @> ARRAY[...]) использует GIN-индекс.
+ALTER TABLE questions ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+CREATE INDEX idx_questions_tags ON questions USING GIN (tags);
+
+-- +goose Down
+DROP INDEX idx_questions_tags;
+ALTER TABLE questions DROP COLUMN tags;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Получатели, которые уже приняли сообщение: после ошибки одного получателя остальным оно не отправляется повторно.
+ALTER TABLE outbox ADD COLUMN delivered_sinks TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE outbox DROP COLUMN IF EXISTS delivered_sinks;